@@ -20,6 +20,8 @@ SPDX-License-Identifier: Apache-2.0
 package v1
 
 import (
+	"time"
+
 	corev1 "k8s.io/api/core/v1"
 
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
@@ -75,3 +77,12 @@ func ListStatusPods(podList []corev1.Pod) map[PodStatus][]string {
 
 	return podsNames
 }
+
+// GetInterval returns the interval between two pushes of the metrics
+// to the OTLP collector, defaulting to 30 seconds
+func (config *OTLPMetricsConfiguration) GetInterval() time.Duration {
+	if config == nil || config.Interval == nil || config.Interval.Duration <= 0 {
+		return 30 * time.Second
+	}
+	return config.Interval.Duration
+}
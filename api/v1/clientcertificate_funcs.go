@@ -0,0 +1,92 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package v1
+
+import (
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// DefaultClientCertificateDuration is the default validity period of
+// the certificates issued for a ClientCertificate
+const DefaultClientCertificateDuration = 24 * time.Hour
+
+// GetSecretName returns the name of the secret storing the certificate
+func (clientCertificate *ClientCertificate) GetSecretName() string {
+	if clientCertificate.Spec.SecretName != "" {
+		return clientCertificate.Spec.SecretName
+	}
+	return clientCertificate.Name
+}
+
+// GetDuration returns the validity period of the issued certificates
+func (clientCertificate *ClientCertificate) GetDuration() time.Duration {
+	if clientCertificate.Spec.Duration != nil && clientCertificate.Spec.Duration.Duration > 0 {
+		return clientCertificate.Spec.Duration.Duration
+	}
+	return DefaultClientCertificateDuration
+}
+
+// GetRenewBefore returns how long before the expiration a certificate
+// needs to be renewed
+func (clientCertificate *ClientCertificate) GetRenewBefore() time.Duration {
+	duration := clientCertificate.GetDuration()
+	if clientCertificate.Spec.RenewBefore != nil &&
+		clientCertificate.Spec.RenewBefore.Duration > 0 &&
+		clientCertificate.Spec.RenewBefore.Duration < duration {
+		return clientCertificate.Spec.RenewBefore.Duration
+	}
+	return duration / 3
+}
+
+// GetRenewalTime returns when a certificate expiring at the passed
+// time needs to be renewed
+func (clientCertificate *ClientCertificate) GetRenewalTime(notAfter time.Time) time.Time {
+	return notAfter.Add(-clientCertificate.GetRenewBefore())
+}
+
+// SetAsFailed sets the client certificate as failed with the given error
+func (clientCertificate *ClientCertificate) SetAsFailed(err error) {
+	clientCertificate.Status.Phase = ClientCertificatePhaseFailed
+	clientCertificate.Status.Message = err.Error()
+}
+
+// SetAsRevoked sets the client certificate as revoked
+func (clientCertificate *ClientCertificate) SetAsRevoked() {
+	clientCertificate.Status.Phase = ClientCertificatePhaseRevoked
+	clientCertificate.Status.Message = ""
+	clientCertificate.Status.SecretName = ""
+	clientCertificate.Status.RenewalTime = nil
+	clientCertificate.Status.ObservedGeneration = clientCertificate.Generation
+}
+
+// SetAsReady sets the client certificate as issued, recording the
+// details of the current certificate
+func (clientCertificate *ClientCertificate) SetAsReady(serialNumber string, notBefore, notAfter time.Time) {
+	clientCertificate.Status.Phase = ClientCertificatePhaseReady
+	clientCertificate.Status.Message = ""
+	clientCertificate.Status.SecretName = clientCertificate.GetSecretName()
+	clientCertificate.Status.SerialNumber = serialNumber
+	clientCertificate.Status.NotBefore = &metav1.Time{Time: notBefore}
+	clientCertificate.Status.NotAfter = &metav1.Time{Time: notAfter}
+	clientCertificate.Status.RenewalTime = &metav1.Time{Time: clientCertificate.GetRenewalTime(notAfter)}
+	clientCertificate.Status.ObservedGeneration = clientCertificate.Generation
+}
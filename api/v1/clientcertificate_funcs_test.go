@@ -0,0 +1,81 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package v1
+
+import (
+	"errors"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ClientCertificate", func() {
+	It("defaults the secret name to the object name", func() {
+		clientCertificate := &ClientCertificate{ObjectMeta: metav1.ObjectMeta{Name: "alice"}}
+		Expect(clientCertificate.GetSecretName()).To(Equal("alice"))
+
+		clientCertificate.Spec.SecretName = "alice-tls"
+		Expect(clientCertificate.GetSecretName()).To(Equal("alice-tls"))
+	})
+
+	It("defaults the duration and the renewal window", func() {
+		clientCertificate := &ClientCertificate{}
+		Expect(clientCertificate.GetDuration()).To(Equal(DefaultClientCertificateDuration))
+		Expect(clientCertificate.GetRenewBefore()).To(Equal(8 * time.Hour))
+
+		clientCertificate.Spec.Duration = &metav1.Duration{Duration: time.Hour}
+		Expect(clientCertificate.GetRenewBefore()).To(Equal(20 * time.Minute))
+
+		clientCertificate.Spec.RenewBefore = &metav1.Duration{Duration: 10 * time.Minute}
+		Expect(clientCertificate.GetRenewBefore()).To(Equal(10 * time.Minute))
+
+		clientCertificate.Spec.RenewBefore = &metav1.Duration{Duration: 2 * time.Hour}
+		Expect(clientCertificate.GetRenewBefore()).To(Equal(20 * time.Minute))
+	})
+
+	It("records the details of the issued certificate", func() {
+		clientCertificate := &ClientCertificate{
+			ObjectMeta: metav1.ObjectMeta{Name: "alice", Generation: 2},
+			Spec:       ClientCertificateSpec{Duration: &metav1.Duration{Duration: 3 * time.Hour}},
+		}
+		clientCertificate.SetAsFailed(errors.New("boom"))
+		Expect(clientCertificate.Status.Phase).To(Equal(ClientCertificatePhaseFailed))
+		Expect(clientCertificate.Status.Message).To(Equal("boom"))
+
+		notBefore := time.Now()
+		notAfter := notBefore.Add(3 * time.Hour)
+		clientCertificate.SetAsReady("42", notBefore, notAfter)
+		Expect(clientCertificate.Status.Phase).To(Equal(ClientCertificatePhaseReady))
+		Expect(clientCertificate.Status.Message).To(BeEmpty())
+		Expect(clientCertificate.Status.SecretName).To(Equal("alice"))
+		Expect(clientCertificate.Status.SerialNumber).To(Equal("42"))
+		Expect(clientCertificate.Status.RenewalTime.Time).To(Equal(notAfter.Add(-time.Hour)))
+		Expect(clientCertificate.Status.ObservedGeneration).To(BeEquivalentTo(2))
+
+		clientCertificate.SetAsRevoked()
+		Expect(clientCertificate.Status.Phase).To(Equal(ClientCertificatePhaseRevoked))
+		Expect(clientCertificate.Status.SecretName).To(BeEmpty())
+		Expect(clientCertificate.Status.RenewalTime).To(BeNil())
+		Expect(clientCertificate.Status.NotAfter.Time).To(Equal(notAfter))
+	})
+})
@@ -0,0 +1,155 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package v1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ClientCertificatePhase is the phase of a ClientCertificate
+type ClientCertificatePhase string
+
+const (
+	// ClientCertificatePhaseReady means the certificate has been issued
+	// and is stored in the target secret
+	ClientCertificatePhaseReady ClientCertificatePhase = "Ready"
+
+	// ClientCertificatePhaseRevoked means the certificate is not renewed
+	// anymore and the target secret has been removed
+	ClientCertificatePhaseRevoked ClientCertificatePhase = "Revoked"
+
+	// ClientCertificatePhaseFailed means the certificate could not be issued
+	ClientCertificatePhaseFailed ClientCertificatePhase = "Failed"
+)
+
+// ClientCertificateSpec is the specification of a client certificate
+// for a PostgreSQL role, signed by the client CA of a Cluster
+// and renewed automatically before it expires
+// +kubebuilder:validation:XValidation:rule="!has(self.duration) || !has(self.renewBefore) || duration(self.renewBefore) < duration(self.duration)",message="renewBefore must be shorter than duration"
+type ClientCertificateSpec struct {
+	// The name of the PostgreSQL cluster whose client CA signs the certificate
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="cluster is immutable"
+	ClusterRef corev1.LocalObjectReference `json:"cluster"`
+
+	// The PostgreSQL role the certificate is issued for. It is used as the
+	// common name of the certificate. Certificates can't be issued for the
+	// roles reserved to PostgreSQL and to the operator, such as `postgres`
+	// and `streaming_replica`
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="role is immutable"
+	Role string `json:"role"`
+
+	// The name of the secret where the certificate is stored, defaults
+	// to the name of the ClientCertificate
+	// +optional
+	SecretName string `json:"secretName,omitempty"`
+
+	// The validity period of the issued certificate
+	// +kubebuilder:default:="24h"
+	// +optional
+	Duration *metav1.Duration `json:"duration,omitempty"`
+
+	// How long before the expiration the certificate is renewed, defaults
+	// to one third of the duration
+	// +optional
+	RenewBefore *metav1.Duration `json:"renewBefore,omitempty"`
+
+	// When true, the certificate is not renewed anymore and the secret
+	// storing it is removed. As PostgreSQL has no revocation list for
+	// client certificates, an already distributed certificate is still
+	// accepted until its expiration
+	// +kubebuilder:default:=false
+	// +optional
+	Revoked bool `json:"revoked,omitempty"`
+}
+
+// ClientCertificateStatus defines the observed state of a ClientCertificate
+type ClientCertificateStatus struct {
+	// A sequence number representing the latest
+	// desired state that was synchronized
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// The phase of the certificate
+	// +optional
+	Phase ClientCertificatePhase `json:"phase,omitempty"`
+
+	// Message is the reconciliation output message
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// The name of the secret storing the certificate
+	// +optional
+	SecretName string `json:"secretName,omitempty"`
+
+	// The serial number of the current certificate
+	// +optional
+	SerialNumber string `json:"serialNumber,omitempty"`
+
+	// The beginning of the validity of the current certificate
+	// +optional
+	NotBefore *metav1.Time `json:"notBefore,omitempty"`
+
+	// The expiration of the current certificate
+	// +optional
+	NotAfter *metav1.Time `json:"notAfter,omitempty"`
+
+	// When the current certificate will be renewed
+	// +optional
+	RenewalTime *metav1.Time `json:"renewalTime,omitempty"`
+}
+
+// +genclient
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:printcolumn:name="Cluster",type="string",JSONPath=".spec.cluster.name"
+// +kubebuilder:printcolumn:name="Role",type="string",JSONPath=".spec.role"
+// +kubebuilder:printcolumn:name="Expiration",type="string",JSONPath=".status.notAfter"
+// +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase"
+
+// ClientCertificate is the Schema for the clientcertificates API
+type ClientCertificate struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata"`
+
+	// Specification of the desired ClientCertificate.
+	// More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#spec-and-status
+	Spec ClientCertificateSpec `json:"spec"`
+	// Most recently observed status of the ClientCertificate. This data may not be up to
+	// date. Populated by the system. Read-only.
+	// More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#spec-and-status
+	// +optional
+	Status ClientCertificateStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ClientCertificateList contains a list of ClientCertificate
+type ClientCertificateList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ClientCertificate `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ClientCertificate{}, &ClientCertificateList{})
+}
@@ -25,6 +25,7 @@ import (
 	"maps"
 	"regexp"
 	"slices"
+	"strconv"
 	"strings"
 	"time"
 
@@ -324,6 +325,118 @@ func (r *ReplicationSlotsHAConfiguration) GetEnabled() bool {
 	return true
 }
 
+// DefaultWraparoundCheckInterval is the default interval between two
+// computations of the age of the databases
+const DefaultWraparoundCheckInterval = 5 * time.Minute
+
+// DefaultWraparoundFreezeTimeout is the default maximum duration of
+// the freeze of a relation
+const DefaultWraparoundFreezeTimeout = time.Hour
+
+// GetCheckInterval returns the interval between two computations of the
+// age of the databases, defaulting to DefaultWraparoundCheckInterval
+func (w *WraparoundProtectionConfiguration) GetCheckInterval() time.Duration {
+	if w == nil || w.CheckInterval == nil || w.CheckInterval.Duration <= 0 {
+		return DefaultWraparoundCheckInterval
+	}
+	return w.CheckInterval.Duration
+}
+
+// GetXIDAgeThreshold returns the transaction ID age threshold, defaulting
+// to DefaultWraparoundAgeThreshold
+func (w *WraparoundProtectionConfiguration) GetXIDAgeThreshold() int64 {
+	if w == nil || w.XIDAgeThreshold == nil {
+		return DefaultWraparoundAgeThreshold
+	}
+	return *w.XIDAgeThreshold
+}
+
+// GetMultiXactIDAgeThreshold returns the multixact ID age threshold,
+// defaulting to DefaultWraparoundAgeThreshold
+func (w *WraparoundProtectionConfiguration) GetMultiXactIDAgeThreshold() int64 {
+	if w == nil || w.MultiXactIDAgeThreshold == nil {
+		return DefaultWraparoundAgeThreshold
+	}
+	return *w.MultiXactIDAgeThreshold
+}
+
+// IsFreezeEnabled returns true when the targeted `VACUUM (FREEZE)` is enabled
+func (w *WraparoundProtectionConfiguration) IsFreezeEnabled() bool {
+	return w != nil && w.Freeze != nil && w.Freeze.Enabled
+}
+
+// GetFreezeMaxRelations returns the maximum number of relations frozen
+// after each check, defaulting to DefaultWraparoundFreezeMaxRelations
+func (w *WraparoundProtectionConfiguration) GetFreezeMaxRelations() int {
+	if w == nil || w.Freeze == nil || w.Freeze.MaxRelations == nil {
+		return DefaultWraparoundFreezeMaxRelations
+	}
+	return int(*w.Freeze.MaxRelations)
+}
+
+// GetFreezeTimeout returns the maximum duration of the freeze of a
+// relation, defaulting to DefaultWraparoundFreezeTimeout
+func (w *WraparoundProtectionConfiguration) GetFreezeTimeout() time.Duration {
+	if w == nil || w.Freeze == nil || w.Freeze.Timeout == nil || w.Freeze.Timeout.Duration <= 0 {
+		return DefaultWraparoundFreezeTimeout
+	}
+	return w.Freeze.Timeout.Duration
+}
+
+// IsAboveThreshold returns true when the transaction ID or multixact ID
+// age of the database crossed the thresholds
+func (w *WraparoundProtectionConfiguration) IsAboveThreshold(age DatabaseAge) bool {
+	return age.XIDAge > w.GetXIDAgeThreshold() || age.MultiXactIDAge > w.GetMultiXactIDAgeThreshold()
+}
+
+const (
+	// DefaultDiskFullCriticalFreeSpacePercentage is the default percentage of
+	// free space below which the primary instance is put in read-only mode
+	DefaultDiskFullCriticalFreeSpacePercentage = 5
+
+	// DefaultDiskFullRecoveryFreeSpacePercentage is the default percentage of
+	// free space above which the disk-full protection is lifted
+	DefaultDiskFullRecoveryFreeSpacePercentage = 10
+
+	// DefaultDiskFullCheckInterval is the default interval between two checks
+	// of the free space
+	DefaultDiskFullCheckInterval = 10 * time.Second
+)
+
+// IsEnabled returns true when the disk-full protection is enabled
+func (d *DiskFullProtectionConfiguration) IsEnabled() bool {
+	return d != nil && d.Enabled
+}
+
+// GetCriticalFreeSpacePercentage returns the percentage of free space below
+// which the primary is put in read-only mode, defaulting to
+// DefaultDiskFullCriticalFreeSpacePercentage
+func (d *DiskFullProtectionConfiguration) GetCriticalFreeSpacePercentage() int32 {
+	if d == nil || d.CriticalFreeSpacePercentage == nil {
+		return DefaultDiskFullCriticalFreeSpacePercentage
+	}
+	return *d.CriticalFreeSpacePercentage
+}
+
+// GetRecoveryFreeSpacePercentage returns the percentage of free space above
+// which the protection is lifted, defaulting to
+// DefaultDiskFullRecoveryFreeSpacePercentage
+func (d *DiskFullProtectionConfiguration) GetRecoveryFreeSpacePercentage() int32 {
+	if d == nil || d.RecoveryFreeSpacePercentage == nil {
+		return DefaultDiskFullRecoveryFreeSpacePercentage
+	}
+	return *d.RecoveryFreeSpacePercentage
+}
+
+// GetCheckInterval returns the interval between two checks of the free
+// space, defaulting to DefaultDiskFullCheckInterval
+func (d *DiskFullProtectionConfiguration) GetCheckInterval() time.Duration {
+	if d == nil || d.CheckInterval == nil || d.CheckInterval.Duration <= 0 {
+		return DefaultDiskFullCheckInterval
+	}
+	return d.CheckInterval.Duration
+}
+
 // ToPostgreSQLConfigurationKeyword returns the contained value as a valid PostgreSQL parameter to be injected
 // in the 'synchronous_standby_names' field
 func (s SynchronousReplicaConfigurationMethod) ToPostgreSQLConfigurationKeyword() string {
@@ -390,11 +503,117 @@ func (s *StorageConfiguration) GetSizeOrNil() *resource.Quantity {
 	return nil
 }
 
+const (
+	// DefaultAutoResizeUsageThreshold is the default percentage of used
+	// space in a volume triggering the expansion of its PVC
+	DefaultAutoResizeUsageThreshold = 80
+
+	// DefaultAutoResizeIncrement is the default increment of the size of
+	// the PVCs expanded automatically
+	DefaultAutoResizeIncrement = "20%"
+
+	// DefaultAutoResizeCooldown is the default minimum time between two
+	// expansions of the same PVC
+	DefaultAutoResizeCooldown = time.Hour
+)
+
+// IsEnabled returns true when the automatic expansion of the PVCs is enabled
+func (a *AutoResizeConfiguration) IsEnabled() bool {
+	return a != nil && a.Enabled
+}
+
+// GetUsageThreshold returns the percentage of used space triggering the
+// expansion, defaulting to DefaultAutoResizeUsageThreshold
+func (a *AutoResizeConfiguration) GetUsageThreshold() int32 {
+	if a == nil || a.UsageThreshold == nil {
+		return DefaultAutoResizeUsageThreshold
+	}
+	return *a.UsageThreshold
+}
+
+// GetCooldown returns the minimum time between two expansions of the same
+// PVC, defaulting to DefaultAutoResizeCooldown
+func (a *AutoResizeConfiguration) GetCooldown() time.Duration {
+	if a == nil || a.Cooldown == nil || a.Cooldown.Duration < 0 {
+		return DefaultAutoResizeCooldown
+	}
+	return a.Cooldown.Duration
+}
+
+// GetIncrement returns how much a PVC having the passed size should be
+// expanded. Percentages are rounded up to the next GiB, as many storage
+// providers allocate volumes with that granularity
+func (a *AutoResizeConfiguration) GetIncrement(currentSize resource.Quantity) (resource.Quantity, error) {
+	increment := DefaultAutoResizeIncrement
+	if a != nil && a.Increment != "" {
+		increment = a.Increment
+	}
+
+	if percentage, found := strings.CutSuffix(increment, "%"); found {
+		value, err := strconv.Atoi(percentage)
+		if err != nil || value <= 0 {
+			return resource.Quantity{}, fmt.Errorf("invalid percentage increment: %s", increment)
+		}
+
+		const gibibyte = 1 << 30
+		bytes := currentSize.Value() * int64(value) / 100
+		bytes = (bytes + gibibyte - 1) / gibibyte * gibibyte
+		return *resource.NewQuantity(max(bytes, gibibyte), resource.BinarySI), nil
+	}
+
+	quantity, err := resource.ParseQuantity(increment)
+	if err != nil {
+		return resource.Quantity{}, fmt.Errorf("invalid increment %s: %w", increment, err)
+	}
+	if quantity.Sign() <= 0 {
+		return resource.Quantity{}, fmt.Errorf("the increment must be positive: %s", increment)
+	}
+	return quantity, nil
+}
+
+// GetNextSize returns the size a PVC having the passed size should be
+// expanded to, never exceeding the maximum size
+func (a *AutoResizeConfiguration) GetNextSize(currentSize resource.Quantity) (resource.Quantity, error) {
+	maxSize, err := resource.ParseQuantity(a.MaxSize)
+	if err != nil {
+		return resource.Quantity{}, fmt.Errorf("invalid maximum size %s: %w", a.MaxSize, err)
+	}
+
+	increment, err := a.GetIncrement(currentSize)
+	if err != nil {
+		return resource.Quantity{}, err
+	}
+
+	nextSize := currentSize.DeepCopy()
+	nextSize.Add(increment)
+	if nextSize.Cmp(maxSize) > 0 {
+		return maxSize, nil
+	}
+	return nextSize, nil
+}
+
 // AreDefaultQueriesDisabled checks whether default monitoring queries should be disabled
 func (m *MonitoringConfiguration) AreDefaultQueriesDisabled() bool {
 	return m != nil && m.DisableDefaultQueries != nil && *m.DisableDefaultQueries
 }
 
+// GetBufferSize returns the maximum size of the disk buffer
+// of the OTLP log records, in bytes
+func (config *OTLPLogsConfiguration) GetBufferSize() int64 {
+	if config == nil || config.BufferSize == nil || config.BufferSize.IsZero() {
+		return 64 * 1024 * 1024
+	}
+	return config.BufferSize.Value()
+}
+
+// GetProtocol returns the protocol used to export the log records
+func (config *OTLPLogsConfiguration) GetProtocol() OTLPProtocol {
+	if config == nil || config.Protocol == "" {
+		return OTLPProtocolGRPC
+	}
+	return config.Protocol
+}
+
 // GetServerName returns the server name, defaulting to the name of the external cluster or using the one specified
 // in the BarmanObjectStore
 func (in ExternalCluster) GetServerName() string {
@@ -637,6 +856,10 @@ func (cluster *Cluster) GetServerCASecretName() string {
 	if cluster.Spec.Certificates != nil && cluster.Spec.Certificates.ServerCASecret != "" {
 		return cluster.Spec.Certificates.ServerCASecret
 	}
+	if cluster.UsesCertManager() {
+		// cert-manager stores the CA together with the certificate it issued
+		return cluster.GetServerTLSSecretName()
+	}
 	return fmt.Sprintf("%v%v", cluster.Name, DefaultServerCaSecretSuffix)
 }
 
@@ -655,9 +878,83 @@ func (cluster *Cluster) GetClientCASecretName() string {
 	if cluster.Spec.Certificates != nil && cluster.Spec.Certificates.ClientCASecret != "" {
 		return cluster.Spec.Certificates.ClientCASecret
 	}
+	if cluster.UsesCertManager() {
+		// cert-manager stores the CA together with the certificate it issued
+		return cluster.GetReplicationSecretName()
+	}
 	return fmt.Sprintf("%v%v", cluster.Name, ClientCaSecretSuffix)
 }
 
+// GetManagedCASecretName gets the name of the secret containing the CA
+// that is generated and managed by the operator, if any.
+// The server and the client CA share the same secret unless one of
+// them is provided by the user.
+func (cluster *Cluster) GetManagedCASecretName() (string, bool) {
+	if cluster.UsesCertManager() {
+		return "", false
+	}
+	if cluster.Spec.Certificates == nil || cluster.Spec.Certificates.ServerCASecret == "" {
+		return cluster.GetServerCASecretName(), true
+	}
+	if cluster.Spec.Certificates.ClientCASecret == "" {
+		return cluster.GetClientCASecretName(), true
+	}
+	return "", false
+}
+
+// GetCertificatesKeyAlgorithm gets the algorithm of the private keys
+// generated by the operator
+func (cluster *Cluster) GetCertificatesKeyAlgorithm() CertificateKeyAlgorithm {
+	if cluster.Spec.Certificates != nil && cluster.Spec.Certificates.KeyAlgorithm != "" {
+		return cluster.Spec.Certificates.KeyAlgorithm
+	}
+	return CertificateKeyAlgorithmECDSAP256
+}
+
+// DefaultCARotationPropagationDelay is the default minimum duration
+// of each phase of a CA rotation
+const DefaultCARotationPropagationDelay = 5 * time.Minute
+
+// GetCARotationPropagationDelay returns the minimum duration of each phase
+// of a CA rotation, defaulting to DefaultCARotationPropagationDelay
+func (cluster *Cluster) GetCARotationPropagationDelay() time.Duration {
+	if cluster.Spec.Certificates == nil || cluster.Spec.Certificates.CARotationPropagationDelay == nil ||
+		cluster.Spec.Certificates.CARotationPropagationDelay.Duration <= 0 {
+		return DefaultCARotationPropagationDelay
+	}
+	return cluster.Spec.Certificates.CARotationPropagationDelay.Duration
+}
+
+// UsesCertManager checks whether the certificates of the cluster
+// are issued by cert-manager
+func (cluster *Cluster) UsesCertManager() bool {
+	return cluster.Spec.Certificates != nil && cluster.Spec.Certificates.IssuerRef != nil
+}
+
+// GetKind gets the kind of the issuer, defaulting to `Issuer`
+func (issuerRef *CertManagerIssuerReference) GetKind() string {
+	if issuerRef.Kind != "" {
+		return issuerRef.Kind
+	}
+	return "Issuer"
+}
+
+// GetGroup gets the API group of the issuer, defaulting to `cert-manager.io`
+func (issuerRef *CertManagerIssuerReference) GetGroup() string {
+	if issuerRef.Group != "" {
+		return issuerRef.Group
+	}
+	return "cert-manager.io"
+}
+
+// IsInProgress checks whether a CA rotation is in progress
+func (status *CARotationStatus) IsInProgress() bool {
+	if status == nil {
+		return false
+	}
+	return status.Phase == CARotationPhaseTrusting || status.Phase == CARotationPhaseResigning
+}
+
 // GetFixedInheritedAnnotations gets the annotations that should be
 // inherited by all resources according to the cluster spec and the operator version
 func (cluster *Cluster) GetFixedInheritedAnnotations() map[string]string {
@@ -1573,3 +1870,36 @@ func (cluster *Cluster) IsFailoverQuorumActive() bool {
 
 	return cluster.Spec.PostgresConfiguration.Synchronous.FailoverQuorum
 }
+
+// IsSuspended checks if the verification of the backups is suspended
+func (configuration *BackupVerificationConfiguration) IsSuspended() bool {
+	if configuration.Suspend == nil {
+		return false
+	}
+
+	return *configuration.Suspend
+}
+
+// GetTimeout gets the maximum time allowed for a backup verification
+func (configuration *BackupVerificationConfiguration) GetTimeout() time.Duration {
+	if configuration.Timeout == nil {
+		return time.Hour
+	}
+
+	return configuration.Timeout.Duration
+}
+
+// GetChecks gets the SQL queries to be executed to verify a restored backup
+func (configuration *BackupVerificationConfiguration) GetChecks() []string {
+	if len(configuration.Checks) == 0 {
+		return []string{"SELECT true"}
+	}
+
+	return configuration.Checks
+}
+
+// GetBackupVerificationClusterName gets the name of the temporary
+// cluster used to verify the backups of this cluster
+func (cluster *Cluster) GetBackupVerificationClusterName() string {
+	return fmt.Sprintf("%s-verification", cluster.Name)
+}
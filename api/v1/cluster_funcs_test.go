@@ -487,10 +487,42 @@ var _ = Describe("look up for secrets", Ordered, func() {
 		Expect(cluster.GetServerCASecretName()).To(Equal("clustername-ca"))
 	})
 
+	It("retrieves the CA secret managed by the operator", func() {
+		name, ok := cluster.GetManagedCASecretName()
+		Expect(ok).To(BeTrue())
+		Expect(name).To(Equal("clustername-ca"))
+
+		userCluster := cluster.DeepCopy()
+		userCluster.Spec.Certificates = &CertificatesConfiguration{ServerCASecret: "server-ca"}
+		name, ok = userCluster.GetManagedCASecretName()
+		Expect(ok).To(BeTrue())
+		Expect(name).To(Equal("clustername-ca"))
+
+		userCluster.Spec.Certificates.ClientCASecret = "client-ca"
+		_, ok = userCluster.GetManagedCASecretName()
+		Expect(ok).To(BeFalse())
+	})
+
 	It("retrieves replication secret name", func() {
 		Expect(cluster.GetReplicationSecretName()).To(Equal("clustername-replication"))
 	})
 
+	It("uses the secrets generated by cert-manager as CAs", func() {
+		certManagerCluster := cluster.DeepCopy()
+		certManagerCluster.Spec.Certificates = &CertificatesConfiguration{
+			IssuerRef: &CertManagerIssuerReference{Name: "corporate-pki"},
+		}
+		Expect(certManagerCluster.UsesCertManager()).To(BeTrue())
+		Expect(certManagerCluster.GetServerCASecretName()).To(Equal("clustername-server"))
+		Expect(certManagerCluster.GetClientCASecretName()).To(Equal("clustername-replication"))
+		_, ok := certManagerCluster.GetManagedCASecretName()
+		Expect(ok).To(BeFalse())
+
+		issuerRef := certManagerCluster.Spec.Certificates.IssuerRef
+		Expect(issuerRef.GetKind()).To(Equal("Issuer"))
+		Expect(issuerRef.GetGroup()).To(Equal("cert-manager.io"))
+	})
+
 	It("retrieves replication secret name", func() {
 		Expect(cluster.GetReplicationSecretName()).To(Equal("clustername-replication"))
 	})
@@ -1013,6 +1045,89 @@ var _ = Describe("Replication slots names for instances", func() {
 	})
 })
 
+var _ = Describe("Storage auto resize", func() {
+	It("uses the defaults when not configured", func() {
+		autoResize := &AutoResizeConfiguration{MaxSize: "100Gi"}
+		Expect(autoResize.IsEnabled()).To(BeFalse())
+		Expect(autoResize.GetUsageThreshold()).To(BeEquivalentTo(DefaultAutoResizeUsageThreshold))
+		Expect(autoResize.GetCooldown()).To(Equal(DefaultAutoResizeCooldown))
+
+		nextSize, err := autoResize.GetNextSize(resource.MustParse("10Gi"))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(nextSize.Cmp(resource.MustParse("12Gi"))).To(BeZero())
+	})
+
+	It("rounds the percentage increments up to the next GiB", func() {
+		autoResize := &AutoResizeConfiguration{Increment: "10%"}
+		increment, err := autoResize.GetIncrement(resource.MustParse("15Gi"))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(increment.Cmp(resource.MustParse("2Gi"))).To(BeZero())
+
+		increment, err = autoResize.GetIncrement(resource.MustParse("100Mi"))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(increment.Cmp(resource.MustParse("1Gi"))).To(BeZero())
+	})
+
+	It("never exceeds the maximum size", func() {
+		autoResize := &AutoResizeConfiguration{Increment: "5Gi", MaxSize: "12Gi"}
+		nextSize, err := autoResize.GetNextSize(resource.MustParse("10Gi"))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(nextSize.Cmp(resource.MustParse("12Gi"))).To(BeZero())
+	})
+
+	It("rejects invalid increments", func() {
+		for _, increment := range []string{"0", "-1Gi", "abc%", "0%", "ten"} {
+			_, err := (&AutoResizeConfiguration{Increment: increment}).GetIncrement(resource.MustParse("1Gi"))
+			Expect(err).To(HaveOccurred(), increment)
+		}
+	})
+})
+
+var _ = Describe("Wraparound protection", func() {
+	It("uses the defaults when not configured", func() {
+		var config *WraparoundProtectionConfiguration
+		Expect(config.GetCheckInterval()).To(Equal(DefaultWraparoundCheckInterval))
+		Expect(config.GetXIDAgeThreshold()).To(BeEquivalentTo(DefaultWraparoundAgeThreshold))
+		Expect(config.GetMultiXactIDAgeThreshold()).To(BeEquivalentTo(DefaultWraparoundAgeThreshold))
+		Expect(config.IsFreezeEnabled()).To(BeFalse())
+		Expect(config.GetFreezeMaxRelations()).To(Equal(DefaultWraparoundFreezeMaxRelations))
+		Expect(config.GetFreezeTimeout()).To(Equal(DefaultWraparoundFreezeTimeout))
+	})
+
+	It("detects the databases over the thresholds", func() {
+		config := &WraparoundProtectionConfiguration{
+			XIDAgeThreshold:         ptr.To(int64(1000)),
+			MultiXactIDAgeThreshold: ptr.To(int64(2000)),
+		}
+		Expect(config.IsAboveThreshold(DatabaseAge{XIDAge: 1000, MultiXactIDAge: 2000})).To(BeFalse())
+		Expect(config.IsAboveThreshold(DatabaseAge{XIDAge: 1001})).To(BeTrue())
+		Expect(config.IsAboveThreshold(DatabaseAge{MultiXactIDAge: 2001})).To(BeTrue())
+	})
+})
+
+var _ = Describe("Disk-full protection", func() {
+	It("uses the defaults when not configured", func() {
+		var config *DiskFullProtectionConfiguration
+		Expect(config.IsEnabled()).To(BeFalse())
+		Expect(config.GetCriticalFreeSpacePercentage()).To(BeEquivalentTo(DefaultDiskFullCriticalFreeSpacePercentage))
+		Expect(config.GetRecoveryFreeSpacePercentage()).To(BeEquivalentTo(DefaultDiskFullRecoveryFreeSpacePercentage))
+		Expect(config.GetCheckInterval()).To(Equal(DefaultDiskFullCheckInterval))
+	})
+
+	It("uses the configured values", func() {
+		config := &DiskFullProtectionConfiguration{
+			Enabled:                     true,
+			CriticalFreeSpacePercentage: ptr.To(int32(2)),
+			RecoveryFreeSpacePercentage: ptr.To(int32(4)),
+			CheckInterval:               &metav1.Duration{Duration: time.Minute},
+		}
+		Expect(config.IsEnabled()).To(BeTrue())
+		Expect(config.GetCriticalFreeSpacePercentage()).To(BeEquivalentTo(2))
+		Expect(config.GetRecoveryFreeSpacePercentage()).To(BeEquivalentTo(4))
+		Expect(config.GetCheckInterval()).To(Equal(time.Minute))
+	})
+})
+
 var _ = Describe("Managed Roles", func() {
 	It("Verify default values", func() {
 		cluster := Cluster{
@@ -1788,3 +1903,17 @@ var _ = Describe("Failover quorum", func() {
 		Entry("with failover quorum disabled", clusterWithFailoverQuorumDisabled, false),
 	)
 })
+
+var _ = Describe("CA rotation propagation delay", func() {
+	It("uses the default when not configured", func() {
+		cluster := &Cluster{}
+		Expect(cluster.GetCARotationPropagationDelay()).To(Equal(DefaultCARotationPropagationDelay))
+	})
+
+	It("uses the configured delay", func() {
+		cluster := &Cluster{Spec: ClusterSpec{Certificates: &CertificatesConfiguration{
+			CARotationPropagationDelay: &metav1.Duration{Duration: 30 * time.Minute},
+		}}}
+		Expect(cluster.GetCARotationPropagationDelay()).To(Equal(30 * time.Minute))
+	})
+})
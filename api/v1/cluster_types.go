@@ -25,6 +25,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 )
 
 const (
@@ -169,6 +170,47 @@ type VolumeSnapshotConfiguration struct {
 	// +kubebuilder:default:={waitForArchive:true,immediateCheckpoint:false}
 	// +optional
 	OnlineConfiguration OnlineConfiguration `json:"onlineConfiguration,omitempty"`
+
+	// The retention policy applied to the completed volume snapshot backups
+	// of the cluster. When not set, backups are retained indefinitely
+	// +optional
+	RetentionPolicy *VolumeSnapshotRetentionPolicy `json:"retentionPolicy,omitempty"`
+}
+
+// VolumeSnapshotRetentionPolicy defines which volume snapshot backups are kept.
+// A backup is retained if at least one of the configured rules selects it, and
+// the most recent completed backup is never deleted.
+// +kubebuilder:validation:XValidation:rule="has(self.maxAge) || has(self.keepLast) || has(self.keepDaily) || has(self.keepWeekly) || has(self.keepMonthly)",message="at least one retention rule is required"
+type VolumeSnapshotRetentionPolicy struct {
+	// Retain the backups completed within this period, expressed in the form
+	// of `XXu` where `XX` is a positive integer and `u` is in `[dwm]` -
+	// days, weeks, months.
+	// +kubebuilder:validation:Pattern=^[1-9][0-9]*[dwm]$
+	// +optional
+	MaxAge string `json:"maxAge,omitempty"`
+
+	// Retain the given number of most recent backups
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	KeepLast *int `json:"keepLast,omitempty"`
+
+	// Retain the most recent backup of each of the given number of days
+	// having backups
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	KeepDaily *int `json:"keepDaily,omitempty"`
+
+	// Retain the most recent backup of each of the given number of weeks
+	// having backups
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	KeepWeekly *int `json:"keepWeekly,omitempty"`
+
+	// Retain the most recent backup of each of the given number of months
+	// having backups
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	KeepMonthly *int `json:"keepMonthly,omitempty"`
 }
 
 // OnlineConfiguration contains the configuration parameters for the online volume snapshot
@@ -282,6 +324,16 @@ type ClusterSpec struct {
 	// +optional
 	ReplicationSlots *ReplicationSlotsConfiguration `json:"replicationSlots,omitempty"`
 
+	// Monitoring of the transaction ID and multixact ID age of the databases,
+	// with the actions to prevent a wraparound
+	// +optional
+	WraparoundProtection *WraparoundProtectionConfiguration `json:"wraparoundProtection,omitempty"`
+
+	// Protection of the primary instance from running out of disk space in
+	// the PGDATA and WAL volumes
+	// +optional
+	DiskFullProtection *DiskFullProtectionConfiguration `json:"diskFullProtection,omitempty"`
+
 	// Instructions to bootstrap this cluster
 	// +optional
 	Bootstrap *BootstrapConfiguration `json:"bootstrap,omitempty"`
@@ -426,6 +478,18 @@ type ClusterSpec struct {
 	// +optional
 	NodeMaintenanceWindow *NodeMaintenanceWindow `json:"nodeMaintenanceWindow,omitempty"`
 
+	// Define the time windows in which the operator is allowed to perform
+	// disruptive operations, such as the rolling updates of the instances,
+	// the switchovers and the major version upgrades. When not set, these
+	// operations are performed as soon as they are needed
+	// +optional
+	MaintenanceWindow *MaintenanceWindowConfiguration `json:"maintenanceWindow,omitempty"`
+
+	// The configuration of the periodic verification of the backups,
+	// restoring them in a temporary cluster
+	// +optional
+	BackupVerification *BackupVerificationConfiguration `json:"backupVerification,omitempty"`
+
 	// The configuration of the monitoring infrastructure of this cluster
 	// +optional
 	Monitoring *MonitoringConfiguration `json:"monitoring,omitempty"`
@@ -440,6 +504,10 @@ type ClusterSpec struct {
 	// +optional
 	LogLevel string `json:"logLevel,omitempty"`
 
+	// The configuration of the destination of the PostgreSQL logs
+	// +optional
+	Logging *ClusterLoggingConfiguration `json:"logging,omitempty"`
+
 	// Template to be used to define projected volumes, projected volumes will be mounted
 	// under `/projected` base folder
 	// +optional
@@ -691,6 +759,10 @@ const (
 
 	// PhaseCannotCreateClusterObjects is set by the operator when is unable to create cluster resources
 	PhaseCannotCreateClusterObjects = "Unable to create required cluster objects"
+
+	// PhaseWaitingForCertificates is set when the certificates requested
+	// to cert-manager have not been issued yet
+	PhaseWaitingForCertificates = "Waiting for the certificates to be issued"
 )
 
 // EphemeralVolumesSizeLimitConfiguration contains the configuration of the ephemeral
@@ -1030,6 +1102,54 @@ type ClusterStatus struct {
 	// SystemID is the latest detected PostgreSQL SystemID
 	// +optional
 	SystemID string `json:"systemID,omitempty"`
+
+	// BackupVerification is the status of the periodic verification of the backups
+	// +optional
+	BackupVerification *BackupVerificationStatus `json:"backupVerification,omitempty"`
+
+	// StorageMigration is the status of the migration of the instances
+	// to PVCs of the storage classes in the specification
+	// +optional
+	StorageMigration *StorageMigrationStatus `json:"storageMigration,omitempty"`
+}
+
+// StorageMigrationPhase is the phase of a storage migration
+type StorageMigrationPhase string
+
+const (
+	// StorageMigrationPhaseRunning means that the operator is replacing
+	// the instances having PVCs of a previous storage class
+	StorageMigrationPhaseRunning StorageMigrationPhase = "Running"
+
+	// StorageMigrationPhasePaused means that the storage migration has
+	// been paused by the user
+	StorageMigrationPhasePaused StorageMigrationPhase = "Paused"
+
+	// StorageMigrationPhaseCompleted means that every instance is using
+	// PVCs of the storage classes in the specification
+	StorageMigrationPhaseCompleted StorageMigrationPhase = "Completed"
+)
+
+// StorageMigrationStatus is the status of the migration of the instances
+// to PVCs of the storage classes in the specification
+type StorageMigrationStatus struct {
+	// The phase of the storage migration
+	// +kubebuilder:validation:Enum=Running;Paused;Completed
+	// +optional
+	Phase StorageMigrationPhase `json:"phase,omitempty"`
+
+	// The instances having PVCs of a storage class different from the
+	// configured one, in the order in which they will be replaced
+	// +optional
+	PendingInstances []string `json:"pendingInstances,omitempty"`
+
+	// The time when the storage migration started
+	// +optional
+	StartedAt *metav1.Time `json:"startedAt,omitempty"`
+
+	// The time when the storage migration completed
+	// +optional
+	CompletedAt *metav1.Time `json:"completedAt,omitempty"`
 }
 
 // ImageInfo contains the information about a PostgreSQL image
@@ -1056,6 +1176,43 @@ type InstanceReportedState struct {
 	TimeLineID int `json:"timeLineID,omitempty"`
 	// IP address of the instance
 	IP string `json:"ip,omitempty"`
+	// The transaction ID and multixact ID age of the databases, as
+	// last computed by the instance
+	// +optional
+	DatabaseAges []DatabaseAge `json:"databaseAges,omitempty"`
+	// The usage of the filesystems of the volumes of the instance
+	// +optional
+	VolumesUsage []VolumeUsage `json:"volumesUsage,omitempty"`
+}
+
+// VolumeUsage is the usage of the filesystem of a volume of an instance.
+// The used space is reported as a percentage, not to update the status of
+// the cluster at every write. The metrics exporter reports the exact values
+type VolumeUsage struct {
+	// The role of the volume: `PG_DATA`, `PG_WAL`, or `PG_TABLESPACE`
+	Role string `json:"role"`
+	// The name of the tablespace, for the tablespace volumes
+	// +optional
+	Tablespace string `json:"tablespace,omitempty"`
+	// The size of the filesystem, in bytes
+	TotalBytes int64 `json:"totalBytes"`
+	// The percentage of used space. Like `df`, the space reserved to the
+	// superuser is not considered
+	UsedPercentage int32 `json:"usedPercentage"`
+	// The percentage of used inodes
+	// +optional
+	InodesUsedPercentage int32 `json:"inodesUsedPercentage,omitempty"`
+}
+
+// DatabaseAge is the age of the oldest unfrozen transaction ID and
+// multixact ID of a database
+type DatabaseAge struct {
+	// The name of the database
+	Name string `json:"name"`
+	// The age of the oldest unfrozen transaction ID, as in `age(datfrozenxid)`
+	XIDAge int64 `json:"xidAge"`
+	// The age of the oldest multixact ID, as in `mxid_age(datminmxid)`
+	MultiXactIDAge int64 `json:"multiXactIDAge"`
 }
 
 // ClusterConditionType defines types of cluster conditions
@@ -1073,6 +1230,18 @@ const (
 	// ConditionConsistentSystemID is true when the all the instances of the
 	// cluster report the same System ID.
 	ConditionConsistentSystemID ClusterConditionType = "ConsistentSystemID"
+	// ConditionMaintenancePending is true when the cluster needs a disruptive
+	// operation which is waiting for the next maintenance window to open
+	ConditionMaintenancePending ClusterConditionType = "MaintenancePending"
+	// ConditionBackupVerification represents the outcome of the last
+	// verification of the backups
+	ConditionBackupVerification ClusterConditionType = "LastBackupVerificationSucceeded"
+	// ConditionWraparoundRisk is true when the transaction ID or multixact
+	// ID age of a database crossed the configured threshold
+	ConditionWraparoundRisk ClusterConditionType = "WraparoundRisk"
+	// ConditionDiskFullProtection is true when the primary instance is in
+	// read-only mode because its volumes are running out of space
+	ConditionDiskFullProtection ClusterConditionType = "DiskFullProtection"
 )
 
 // ConditionStatus defines conditions of resources
@@ -1121,6 +1290,39 @@ const (
 
 	// DetachedVolume is the reason that is set when we do a rolling upgrade to add a PVC volume to a cluster
 	DetachedVolume ConditionReason = "DetachedVolume"
+
+	// ConditionReasonWaitingForMaintenanceWindow means that a disruptive operation
+	// is pending, waiting for the maintenance window to open
+	ConditionReasonWaitingForMaintenanceWindow ConditionReason = "WaitingForMaintenanceWindow"
+
+	// ConditionReasonNoPendingMaintenance means that there are no disruptive
+	// operations waiting for a maintenance window
+	ConditionReasonNoPendingMaintenance ConditionReason = "NoPendingMaintenance"
+
+	// ConditionReasonLastBackupVerificationSucceeded means that the last backup
+	// has been restored and the checks succeeded
+	ConditionReasonLastBackupVerificationSucceeded ConditionReason = "LastBackupVerificationSucceeded"
+
+	// ConditionReasonLastBackupVerificationFailed means that the last backup
+	// could not be restored or that the checks failed
+	ConditionReasonLastBackupVerificationFailed ConditionReason = "LastBackupVerificationFailed"
+
+	// ConditionReasonAgeThresholdExceeded means that the transaction ID or
+	// multixact ID age of a database crossed the configured threshold
+	ConditionReasonAgeThresholdExceeded ConditionReason = "AgeThresholdExceeded"
+
+	// ConditionReasonAgeBelowThreshold means that the transaction ID and
+	// multixact ID age of every database is below the configured threshold
+	ConditionReasonAgeBelowThreshold ConditionReason = "AgeBelowThreshold"
+
+	// ConditionReasonDiskSpaceCritical means that the free space in the
+	// PGDATA or WAL volume of the primary instance is below the critical
+	// threshold
+	ConditionReasonDiskSpaceCritical ConditionReason = "DiskSpaceCritical"
+
+	// ConditionReasonDiskSpaceAvailable means that the PGDATA and WAL
+	// volumes of the primary instance have enough free space
+	ConditionReasonDiskSpaceAvailable ConditionReason = "DiskSpaceAvailable"
 )
 
 // EmbeddedObjectMetadata contains metadata to be inherited by all resources related to a Cluster
@@ -1259,6 +1461,96 @@ type ReplicationSlotsHAConfiguration struct {
 	SynchronizeLogicalDecoding bool `json:"synchronizeLogicalDecoding,omitempty"`
 }
 
+// DiskFullProtectionConfiguration configures the protection of the primary
+// instance from running out of disk space. When the free space in the PGDATA
+// or WAL volume falls below the critical threshold, the instance manager sets
+// `default_transaction_read_only` on the primary, and resets it once the
+// free space grows above the recovery threshold
+type DiskFullProtectionConfiguration struct {
+	// Enables the protection
+	// +optional
+	Enabled bool `json:"enabled,omitempty"`
+
+	// The percentage of free space, or of free inodes, below which the
+	// primary instance is put in read-only mode. Defaults to 5
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=50
+	// +optional
+	CriticalFreeSpacePercentage *int32 `json:"criticalFreeSpacePercentage,omitempty"`
+
+	// The percentage of free space, and of free inodes, above which the
+	// protection is lifted. Must be greater than the critical one.
+	// Defaults to 10
+	// +kubebuilder:validation:Minimum=2
+	// +kubebuilder:validation:Maximum=100
+	// +optional
+	RecoveryFreeSpacePercentage *int32 `json:"recoveryFreeSpacePercentage,omitempty"`
+
+	// How often the free space is checked. Defaults to `10s`
+	// +optional
+	CheckInterval *metav1.Duration `json:"checkInterval,omitempty"`
+}
+
+// DefaultWraparoundAgeThreshold is the default transaction ID and multixact
+// ID age above which a database is considered at risk of wraparound
+const DefaultWraparoundAgeThreshold = 1000000000
+
+// DefaultWraparoundFreezeMaxRelations is the default number of relations
+// frozen by each check
+const DefaultWraparoundFreezeMaxRelations = 10
+
+// WraparoundProtectionConfiguration configures the monitoring of the
+// transaction ID and multixact ID age of the databases. Every instance
+// periodically computes the age of each database, reporting it in the
+// status and in the metrics, and the `WraparoundRisk` condition is set when
+// it crosses the threshold on the primary instance.
+type WraparoundProtectionConfiguration struct {
+	// The interval between two computations of the age of the databases
+	// (default 5m)
+	// +optional
+	CheckInterval *metav1.Duration `json:"checkInterval,omitempty"`
+
+	// The transaction ID age above which a database is considered at risk
+	// of wraparound (default 1000000000)
+	// +kubebuilder:validation:Minimum=100000
+	// +kubebuilder:validation:Maximum=2000000000
+	// +optional
+	XIDAgeThreshold *int64 `json:"xidAgeThreshold,omitempty"`
+
+	// The multixact ID age above which a database is considered at risk
+	// of wraparound (default 1000000000)
+	// +kubebuilder:validation:Minimum=100000
+	// +kubebuilder:validation:Maximum=2000000000
+	// +optional
+	MultiXactIDAgeThreshold *int64 `json:"multiXactIDAgeThreshold,omitempty"`
+
+	// Runs a `VACUUM (FREEZE)` on the oldest relations of the databases
+	// at risk of wraparound
+	// +optional
+	Freeze *WraparoundFreezeConfiguration `json:"freeze,omitempty"`
+}
+
+// WraparoundFreezeConfiguration configures the targeted `VACUUM (FREEZE)`
+// run by the primary instance on the relations whose age crossed the
+// thresholds
+type WraparoundFreezeConfiguration struct {
+	// When enabled, after each check the primary instance freezes the oldest
+	// relations whose transaction ID or multixact ID age crossed the
+	// threshold. Replicas never run it.
+	// +optional
+	Enabled bool `json:"enabled,omitempty"`
+
+	// The maximum number of relations frozen after each check (default 10)
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MaxRelations *int32 `json:"maxRelations,omitempty"`
+
+	// The maximum duration of the `VACUUM (FREEZE)` of a relation, after
+	// which it is cancelled (default 1h)
+	// +optional
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+}
+
 // KubernetesUpgradeStrategy tells the operator if the user want to
 // allocate more space while upgrading a k8s node which is hosting
 // the PostgreSQL Pods or just wait for the node to come up
@@ -1294,6 +1586,118 @@ type NodeMaintenanceWindow struct {
 	InProgress bool `json:"inProgress,omitempty"`
 }
 
+// MaintenanceWindowConfiguration contains the time windows in which
+// the operator is allowed to perform disruptive operations on the cluster
+type MaintenanceWindowConfiguration struct {
+	// The list of maintenance windows. Disruptive operations are allowed
+	// when at least one of them is open
+	// +kubebuilder:validation:MinItems=1
+	Windows []MaintenanceWindow `json:"windows"`
+
+	// The IANA time zone used to evaluate the schedules of the windows,
+	// e.g. `Europe/Rome`. Defaults to `UTC`
+	// +optional
+	TimeZone string `json:"timeZone,omitempty"`
+}
+
+// MaintenanceWindow is a recurring period of time in which disruptive
+// operations are allowed
+type MaintenanceWindow struct {
+	// The schedule of the opening of the window, in the same format of the
+	// ScheduledBackup resource, which includes the seconds field, e.g.
+	// `0 0 2 * * 6` for every Saturday at 2 AM,
+	// see https://pkg.go.dev/github.com/robfig/cron#hdr-CRON_Expression_Format
+	// +kubebuilder:validation:MinLength=1
+	Schedule string `json:"schedule"`
+
+	// How long the window stays open, e.g. `2h`
+	Duration metav1.Duration `json:"duration"`
+}
+
+// BackupVerificationConfiguration defines how the backups of the cluster
+// are periodically verified. The most recent completed backup is restored
+// in a temporary cluster, where the checks are executed before the
+// temporary cluster is deleted
+type BackupVerificationConfiguration struct {
+	// The schedule of the verifications, in the same format of the
+	// ScheduledBackup resource, which includes the seconds field,
+	// see https://pkg.go.dev/github.com/robfig/cron#hdr-CRON_Expression_Format
+	// +kubebuilder:validation:MinLength=1
+	Schedule string `json:"schedule"`
+
+	// If this verification is suspended or not
+	// +optional
+	Suspend *bool `json:"suspend,omitempty"`
+
+	// The database where the checks are executed. Defaults to
+	// the application database
+	// +optional
+	Database string `json:"database,omitempty"`
+
+	// The SQL queries to be executed in the restored database. The
+	// verification fails when a query raises an error or returns `false`.
+	// Defaults to a query checking the database can be connected to
+	// +optional
+	Checks []string `json:"checks,omitempty"`
+
+	// The maximum time allowed to restore the backup and execute the
+	// checks, after which the verification fails. Defaults to `1h`
+	// +optional
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+}
+
+// BackupVerificationStatus is the status of the periodic verification
+// of the backups of a cluster
+type BackupVerificationStatus struct {
+	// The moment when the last verification was scheduled
+	// +optional
+	LastScheduleTime *metav1.Time `json:"lastScheduleTime,omitempty"`
+
+	// The verification being executed, if any
+	// +optional
+	Running *BackupVerificationRun `json:"running,omitempty"`
+
+	// The outcome of the last completed verification
+	// +optional
+	LastResult *BackupVerificationResult `json:"lastResult,omitempty"`
+}
+
+// BackupVerificationRun is a verification of a backup being executed
+type BackupVerificationRun struct {
+	// The name of the temporary cluster where the backup is restored
+	ClusterName string `json:"clusterName"`
+
+	// The UID of the temporary cluster, used to never act on a cluster
+	// which hasn't been created by the verification
+	// +optional
+	ClusterUID types.UID `json:"clusterUID,omitempty"`
+
+	// The name of the backup being verified
+	BackupName string `json:"backupName"`
+
+	// When the verification started
+	StartedAt metav1.Time `json:"startedAt"`
+}
+
+// BackupVerificationResult is the outcome of a verification of a backup
+type BackupVerificationResult struct {
+	// The name of the verified backup
+	BackupName string `json:"backupName"`
+
+	// When the verification started
+	StartedAt metav1.Time `json:"startedAt"`
+
+	// When the verification completed
+	CompletedAt metav1.Time `json:"completedAt"`
+
+	// True if the backup has been restored and the checks succeeded
+	Succeeded bool `json:"succeeded"`
+
+	// A human-readable description of the outcome
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
 // PrimaryUpdateStrategy contains the strategy to follow when upgrading
 // the primary server of the cluster as part of rolling updates
 type PrimaryUpdateStrategy string
@@ -1626,8 +2030,64 @@ type CertificatesConfiguration struct {
 	// The list of the server alternative DNS names to be added to the generated server TLS certificates, when required.
 	// +optional
 	ServerAltDNSNames []string `json:"serverAltDNSNames,omitempty"`
+
+	// The algorithm of the private keys generated by the operator for the
+	// CA and for the certificates it signs. Available options are
+	// `ecdsa-p256` (default), `rsa-3072` and `ed25519`.
+	// Changing it starts a rotation of the CA managed by the operator
+	// +kubebuilder:validation:Enum=ecdsa-p256;rsa-3072;ed25519
+	// +optional
+	KeyAlgorithm CertificateKeyAlgorithm `json:"keyAlgorithm,omitempty"`
+
+	// The minimum duration of each phase of a rotation of the CA managed
+	// by the operator, giving the instances, the poolers and the
+	// applications the time to load the new content of the CA secret.
+	// Defaults to 5 minutes
+	// +optional
+	CARotationPropagationDelay *metav1.Duration `json:"caRotationPropagationDelay,omitempty"`
+
+	// The cert-manager issuer signing the server certificate and the
+	// client certificates used by the operator. When defined, the operator
+	// creates a cert-manager `Certificate` for each of them, and uses the
+	// `ca.crt` entry of the generated secrets as server and client CA.
+	// It can't be used together with the other secrets of this section
+	// +optional
+	IssuerRef *CertManagerIssuerReference `json:"issuerRef,omitempty"`
 }
 
+// CertManagerIssuerReference is a reference to a cert-manager issuer
+type CertManagerIssuerReference struct {
+	// The name of the issuer
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// The kind of the issuer, `Issuer` or `ClusterIssuer` for the
+	// issuers provided by cert-manager
+	// +kubebuilder:default:=Issuer
+	// +optional
+	Kind string `json:"kind,omitempty"`
+
+	// The API group of the issuer
+	// +kubebuilder:default:=cert-manager.io
+	// +optional
+	Group string `json:"group,omitempty"`
+}
+
+// CertificateKeyAlgorithm is the algorithm of the private keys generated
+// by the operator
+type CertificateKeyAlgorithm string
+
+const (
+	// CertificateKeyAlgorithmECDSAP256 generates ECDSA keys on the P-256 curve
+	CertificateKeyAlgorithmECDSAP256 CertificateKeyAlgorithm = "ecdsa-p256"
+
+	// CertificateKeyAlgorithmRSA3072 generates 3072 bits RSA keys
+	CertificateKeyAlgorithmRSA3072 CertificateKeyAlgorithm = "rsa-3072"
+
+	// CertificateKeyAlgorithmEd25519 generates Ed25519 keys
+	CertificateKeyAlgorithmEd25519 CertificateKeyAlgorithm = "ed25519"
+)
+
 // CertificatesStatus contains configuration certificates and related expiration dates.
 type CertificatesStatus struct {
 	// Needed configurations to handle server certificates, initialized with default values, if needed.
@@ -1636,6 +2096,55 @@ type CertificatesStatus struct {
 	// Expiration dates for all certificates.
 	// +optional
 	Expirations map[string]string `json:"expirations,omitempty"`
+
+	// The status of the rotation of the CA managed by the operator
+	// +optional
+	CARotation *CARotationStatus `json:"caRotation,omitempty"`
+}
+
+// CARotationPhase is the phase of the rotation of the CA managed by the
+// operator
+// +kubebuilder:validation:Enum=Trusting;Resigning;Completed
+type CARotationPhase string
+
+const (
+	// CARotationPhaseTrusting means that a new CA has been issued and is
+	// being distributed together with the old one, which is still used
+	// to sign the certificates
+	CARotationPhaseTrusting CARotationPhase = "Trusting"
+
+	// CARotationPhaseResigning means that the new CA is signing the
+	// certificates while the old one is still trusted
+	CARotationPhaseResigning CARotationPhase = "Resigning"
+
+	// CARotationPhaseCompleted means that the old CA has been retired
+	CARotationPhaseCompleted CARotationPhase = "Completed"
+)
+
+// CARotationStatus contains the progress of the rotation of the CA
+// managed by the operator
+type CARotationStatus struct {
+	// The current phase of the rotation
+	// +optional
+	Phase CARotationPhase `json:"phase,omitempty"`
+
+	// The reason why the rotation has been started: `Expiring`,
+	// `Requested` or `KeyAlgorithmChanged`
+	// +optional
+	Reason string `json:"reason,omitempty"`
+
+	// The time when the current phase has started
+	// +optional
+	PhaseStartedAt *metav1.Time `json:"phaseStartedAt,omitempty"`
+
+	// The value of the `cnpg.io/caRotationRequestedAt` annotation that
+	// has been handled last
+	// +optional
+	LastRequestedAt string `json:"lastRequestedAt,omitempty"`
+
+	// The time when the last rotation has been completed
+	// +optional
+	LastCompletionTime *metav1.Time `json:"lastCompletionTime,omitempty"`
 }
 
 // BootstrapInitDB is the configuration of the bootstrap process when
@@ -2059,6 +2568,42 @@ type StorageConfiguration struct {
 	// Template to be used to generate the Persistent Volume Claim
 	// +optional
 	PersistentVolumeClaimTemplate *corev1.PersistentVolumeClaimSpec `json:"pvcTemplate,omitempty"`
+
+	// AutoResize configures the automatic expansion of the PVCs when the
+	// used space in their volume crosses a threshold
+	// +optional
+	AutoResize *AutoResizeConfiguration `json:"autoResize,omitempty"`
+}
+
+// AutoResizeConfiguration configures the automatic expansion of the PVCs
+// of a storage type. The PVCs are expanded using the same path followed
+// when changing the size of the storage, and `resizeInUseVolumes` must be
+// enabled
+type AutoResizeConfiguration struct {
+	// Enables the automatic expansion of the PVCs
+	// +optional
+	Enabled bool `json:"enabled,omitempty"`
+
+	// The percentage of used space in the volume triggering the expansion
+	// of the PVC. Defaults to 80
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=99
+	// +optional
+	UsageThreshold *int32 `json:"usageThreshold,omitempty"`
+
+	// How much the PVC is expanded each time, either as a quantity, like
+	// `10Gi`, or as a percentage of the current size, like `20%`. Defaults
+	// to `20%`
+	// +optional
+	Increment string `json:"increment,omitempty"`
+
+	// The maximum size of the PVC. The PVC is never expanded over this size
+	MaxSize string `json:"maxSize"`
+
+	// The minimum time between two expansions of the same PVC. Defaults to
+	// `1h`. Some storage providers limit how often a volume can be expanded
+	// +optional
+	Cooldown *metav1.Duration `json:"cooldown,omitempty"`
 }
 
 // TablespaceConfiguration is the configuration of a tablespace, and includes
@@ -2184,7 +2729,8 @@ type BackupConfiguration struct {
 	// and WALs (i.e. '60d'). The retention policy is expressed in the form
 	// of `XXu` where `XX` is a positive integer and `u` is in `[dwm]` -
 	// days, weeks, months.
-	// It's currently only applicable when using the BarmanObjectStore method.
+	// It's currently only applicable when using the BarmanObjectStore method,
+	// volume snapshot backups use `volumeSnapshot.retentionPolicy` instead.
 	// +kubebuilder:validation:Pattern=^[1-9][0-9]*[dwm]$
 	// +optional
 	RetentionPolicy string `json:"retentionPolicy,omitempty"`
@@ -2251,6 +2797,82 @@ type MonitoringConfiguration struct {
 	// Setting this to zero disables the caching mechanism and can cause heavy load on the PostgreSQL server.
 	// +optional
 	MetricsQueriesTTL *metav1.Duration `json:"metricsQueriesTTL,omitempty"`
+
+	// Push the metrics of the instances to an OpenTelemetry collector.
+	// +optional
+	OTLP *OTLPMetricsConfiguration `json:"otlp,omitempty"`
+}
+
+// ClusterLoggingConfiguration configures where the PostgreSQL logs,
+// including the pgaudit ones, are sent
+type ClusterLoggingConfiguration struct {
+	// Export the PostgreSQL logs as OpenTelemetry log records.
+	// +optional
+	OTLP *OTLPLogsConfiguration `json:"otlp,omitempty"`
+
+	// How the text of the queries is reported in the structured records
+	// produced by `log_min_duration_statement` and `auto_explain`:
+	// `full` (default) reports it as logged by PostgreSQL, `normalized`
+	// replaces the constants with placeholders, and `redacted` removes
+	// the query text, the parameters and the plans.
+	// +kubebuilder:validation:Enum=full;normalized;redacted
+	// +optional
+	SlowQueryText SlowQueryTextMode `json:"slowQueryText,omitempty"`
+}
+
+// SlowQueryTextMode defines how the text of the slow queries is reported
+type SlowQueryTextMode string
+
+const (
+	// SlowQueryTextFull reports the query text as logged by PostgreSQL
+	SlowQueryTextFull = SlowQueryTextMode("full")
+
+	// SlowQueryTextNormalized replaces the constants with placeholders
+	SlowQueryTextNormalized = SlowQueryTextMode("normalized")
+
+	// SlowQueryTextRedacted removes the query text
+	SlowQueryTextRedacted = SlowQueryTextMode("redacted")
+)
+
+// OTLPProtocol is the transport used to send OTLP data
+// +kubebuilder:validation:Enum=grpc;http
+type OTLPProtocol string
+
+const (
+	// OTLPProtocolGRPC sends OTLP data using gRPC
+	OTLPProtocolGRPC = OTLPProtocol("grpc")
+
+	// OTLPProtocolHTTP sends OTLP data using protobuf over HTTP
+	OTLPProtocolHTTP = OTLPProtocol("http")
+)
+
+// OTLPLogsConfiguration configures the export of the PostgreSQL logs
+// to an OpenTelemetry collector
+type OTLPLogsConfiguration struct {
+	// The URL of the collector, for example
+	// `http://otel-collector.monitoring:4317`. The `https` scheme enables
+	// TLS using the system trust store. When using the `http` protocol
+	// and no path is specified, the standard `/v1/logs` one is used.
+	// +kubebuilder:validation:Pattern=`^https?://`
+	Endpoint string `json:"endpoint"`
+
+	// The protocol used to send the log records, `grpc` (default) or `http`
+	// +kubebuilder:default:=grpc
+	// +optional
+	Protocol OTLPProtocol `json:"protocol,omitempty"`
+
+	// When true, the PostgreSQL log records are only exported to the
+	// collector and are not written anymore to the standard output
+	// of the instance manager
+	// +optional
+	DisableStdout bool `json:"disableStdout,omitempty"`
+
+	// The maximum size of the on-disk buffer storing the log records
+	// while the collector is not reachable. When the buffer is full,
+	// the oldest records are discarded. The buffer is stored in the
+	// scratch volume of the Pod. Defaults to 64Mi.
+	// +optional
+	BufferSize *resource.Quantity `json:"bufferSize,omitempty"`
 }
 
 // ClusterMonitoringTLSConfiguration is the type containing the TLS configuration
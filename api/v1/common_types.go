@@ -19,6 +19,10 @@ SPDX-License-Identifier: Apache-2.0
 
 package v1
 
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
 // VolumeSnapshotKind this is a strongly typed reference to the kind used by the volumesnapshot package
 const VolumeSnapshotKind = "VolumeSnapshot"
 
@@ -45,3 +49,21 @@ type Metadata struct {
 	// +optional
 	Annotations map[string]string `json:"annotations,omitempty"`
 }
+
+// OTLPMetricsConfiguration configures the push of the metrics to an
+// OpenTelemetry collector using the OTLP protocol over HTTP.
+// The metrics are pushed in addition to being exposed
+// on the Prometheus endpoint
+type OTLPMetricsConfiguration struct {
+	// The URL of the OTLP/HTTP endpoint of the collector, for example
+	// `http://otel-collector.monitoring:4318`. When no path is specified,
+	// the standard `/v1/metrics` one is used. The `https` scheme enables
+	// TLS using the system trust store.
+	// +kubebuilder:validation:Pattern=`^https?://`
+	Endpoint string `json:"endpoint"`
+
+	// The interval between two pushes of the metrics.
+	// Defaults to 30 seconds.
+	// +optional
+	Interval *metav1.Duration `json:"interval,omitempty"`
+}
@@ -73,6 +73,26 @@ func (db *Database) SetStatusObservedGeneration(obsGeneration int64) {
 	db.Status.ObservedGeneration = obsGeneration
 }
 
+// HasPrivileges returns true if the database declares privileges to be
+// granted or revoked, either on the database itself or on its schemas
+func (db *Database) HasPrivileges() bool {
+	if len(db.Spec.Privileges) > 0 {
+		return true
+	}
+
+	for _, schema := range db.Spec.Schemas {
+		if len(schema.Privileges) > 0 ||
+			len(schema.TablePrivileges) > 0 ||
+			len(schema.SequencePrivileges) > 0 ||
+			len(schema.FunctionPrivileges) > 0 ||
+			len(schema.DefaultPrivileges) > 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
 // MustHaveManagedResourceExclusivity detects conflicting databases
 func (dbList *DatabaseList) MustHaveManagedResourceExclusivity(reference *Database) error {
 	pointers := toSliceWithPointers(dbList.Items)
@@ -52,6 +52,22 @@ const (
 	RevokeUsageSpecType UsageSpecType = "revoke"
 )
 
+// DefaultPrivilegeObjectType describes the kind of objects targeted by an
+// `ALTER DEFAULT PRIVILEGES` command
+// +enum
+type DefaultPrivilegeObjectType string
+
+const (
+	// DefaultPrivilegeTables targets the tables (and views) created in the future
+	DefaultPrivilegeTables DefaultPrivilegeObjectType = "tables"
+
+	// DefaultPrivilegeSequences targets the sequences created in the future
+	DefaultPrivilegeSequences DefaultPrivilegeObjectType = "sequences"
+
+	// DefaultPrivilegeFunctions targets the functions created in the future
+	DefaultPrivilegeFunctions DefaultPrivilegeObjectType = "functions"
+)
+
 // DatabaseSpec is the specification of a Postgresql Database, built around the
 // `CREATE DATABASE`, `ALTER DATABASE`, and `DROP DATABASE` SQL commands of
 // PostgreSQL.
@@ -195,6 +211,12 @@ type DatabaseSpec struct {
 	// The list of foreign servers to be managed in the database
 	// +optional
 	Servers []ServerSpec `json:"servers,omitempty"`
+
+	// The list of privileges on the database to be granted to or revoked
+	// from roles, such as `CONNECT`, `CREATE` and `TEMPORARY`. They are
+	// periodically checked against the database ACL and re-applied on drift.
+	// +optional
+	Privileges []PrivilegeSpec `json:"privileges,omitempty"`
 }
 
 // DatabaseObjectSpec contains the fields which are common to every
@@ -222,6 +244,69 @@ type SchemaSpec struct {
 	// It maps to the `AUTHORIZATION` parameter of `CREATE SCHEMA` and the
 	// `OWNER TO` command of `ALTER SCHEMA`.
 	Owner string `json:"owner,omitempty"`
+
+	// The list of privileges on the schema itself (`USAGE`, `CREATE`) to be
+	// granted to or revoked from roles
+	// +optional
+	Privileges []PrivilegeSpec `json:"privileges,omitempty"`
+
+	// The list of privileges to be granted to or revoked from roles on all
+	// the tables (and views) existing in the schema
+	// +optional
+	TablePrivileges []PrivilegeSpec `json:"tablePrivileges,omitempty"`
+
+	// The list of privileges to be granted to or revoked from roles on all
+	// the sequences existing in the schema
+	// +optional
+	SequencePrivileges []PrivilegeSpec `json:"sequencePrivileges,omitempty"`
+
+	// The list of privileges to be granted to or revoked from roles on all
+	// the functions existing in the schema
+	// +optional
+	FunctionPrivileges []PrivilegeSpec `json:"functionPrivileges,omitempty"`
+
+	// The list of privileges to be applied to the objects created in the
+	// schema in the future, via `ALTER DEFAULT PRIVILEGES`
+	// +optional
+	DefaultPrivileges []DefaultPrivilegeSpec `json:"defaultPrivileges,omitempty"`
+}
+
+// PrivilegeSpec configures a set of privileges to be granted to or revoked
+// from a role, built around the `GRANT` and `REVOKE` SQL commands
+type PrivilegeSpec struct {
+	// The name of the role receiving or losing the privileges.
+	// Use `PUBLIC` to target every role.
+	// +kubebuilder:validation:XValidation:rule="self != ''",message="role is required"
+	Role string `json:"role"`
+
+	// The list of privileges, e.g. `SELECT`, `USAGE` or `ALL`.
+	// Which privileges are valid depends on the kind of the target objects
+	// +kubebuilder:validation:MinItems=1
+	// +kubebuilder:validation:items:Enum=ALL;SELECT;INSERT;UPDATE;DELETE;TRUNCATE;REFERENCES;TRIGGER;USAGE;CREATE;CONNECT;TEMPORARY;EXECUTE
+	Privileges []string `json:"privileges"`
+
+	// Whether the privileges are granted or revoked
+	// +kubebuilder:default:="grant"
+	// +kubebuilder:validation:Enum=grant;revoke
+	// +optional
+	Type UsageSpecType `json:"type,omitempty"`
+}
+
+// DefaultPrivilegeSpec configures the privileges applied to the objects
+// that will be created in a schema, built around the `ALTER DEFAULT PRIVILEGES`
+// SQL command
+type DefaultPrivilegeSpec struct {
+	// Common fields
+	PrivilegeSpec `json:",inline"`
+
+	// The kind of objects the default privileges apply to
+	// +kubebuilder:validation:Enum=tables;sequences;functions
+	ObjectType DefaultPrivilegeObjectType `json:"objectType"`
+
+	// The role creating the objects, mapping to the `FOR ROLE` clause.
+	// Defaults to the owner of the database.
+	// +optional
+	ForRole string `json:"forRole,omitempty"`
 }
 
 // ExtensionSpec configures an extension in a database
@@ -353,6 +438,11 @@ type DatabaseStatus struct {
 	// Servers is the status of the managed servers
 	// +optional
 	Servers []DatabaseObjectStatus `json:"servers,omitempty"`
+
+	// Privileges is the status of the managed privileges, one entry
+	// per grant or revoke declared in the database and in its schemas
+	// +optional
+	Privileges []DatabaseObjectStatus `json:"privileges,omitempty"`
 }
 
 // DatabaseObjectStatus is the status of the managed database objects
@@ -48,6 +48,15 @@ const (
 
 	// DatabaseKind is the kind name of databases
 	DatabaseKind = "Database"
+
+	// RoleKind is the kind name of roles
+	RoleKind = "Role"
+
+	// ScheduledBackupKind is the kind name of scheduled backups
+	ScheduledBackupKind = "ScheduledBackup"
+
+	// ClientCertificateKind is the kind name of client certificates
+	ClientCertificateKind = "ClientCertificate"
 )
 
 var (
@@ -19,7 +19,17 @@ SPDX-License-Identifier: Apache-2.0
 
 package v1
 
-import corev1 "k8s.io/api/core/v1"
+import (
+	"slices"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/ptr"
+)
+
+// defaultPoolerScaleDownStabilizationWindow is the default amount of time
+// the autoscaler waits before scaling down a Pooler
+const defaultPoolerScaleDownStabilizationWindow = 5 * time.Minute
 
 // IsPaused returns whether all database should be paused or not.
 func (in PgBouncerSpec) IsPaused() bool {
@@ -79,7 +89,7 @@ func (in *Pooler) GetClientTLSSecretNameOrDefault(cluster *Cluster) string {
 // GetAuthQuery returns the specified AuthQuery name for PgBouncer
 // if provided or the default name otherwise.
 func (in *Pooler) GetAuthQuery() string {
-	if in.Spec.PgBouncer.AuthQuery != "" {
+	if in.Spec.PgBouncer != nil && in.Spec.PgBouncer.AuthQuery != "" {
 		return in.Spec.PgBouncer.AuthQuery
 	}
 
@@ -89,6 +99,11 @@ func (in *Pooler) GetAuthQuery() string {
 // IsAutomatedIntegration returns whether the Pooler integration with the
 // Cluster is automated or not.
 func (in *Pooler) IsAutomatedIntegration() bool {
+	// PgCat authenticates every user with its own credentials
+	if in.GetImplementation() == PoolerImplementationPgCat {
+		return false
+	}
+
 	if in.Spec.PgBouncer == nil {
 		return true
 	}
@@ -114,3 +129,78 @@ func (in *Pooler) GetResourcesRequirements() corev1.ResourceRequirements {
 
 	return *in.Spec.Template.Spec.Resources
 }
+
+// IsAutoscalingEnabled returns whether the horizontal autoscaling of the
+// Pooler is enabled or not
+func (in *Pooler) IsAutoscalingEnabled() bool {
+	return in.Spec.Autoscaling != nil
+}
+
+// GetDesiredInstances returns the number of instances the PgBouncer deployment
+// should have. When autoscaling is enabled this is the latest decision of the
+// autoscaler, falling back to `instances` clamped between the configured bounds.
+func (in *Pooler) GetDesiredInstances() *int32 {
+	if !in.IsAutoscalingEnabled() {
+		return in.Spec.Instances
+	}
+
+	if in.Status.Autoscaling != nil && in.Status.Autoscaling.DesiredInstances > 0 {
+		return ptr.To(in.Spec.Autoscaling.Clamp(in.Status.Autoscaling.DesiredInstances))
+	}
+
+	return ptr.To(in.Spec.Autoscaling.Clamp(ptr.Deref(in.Spec.Instances, 1)))
+}
+
+// Clamp limits the passed number of instances between the configured
+// minimum and maximum
+func (in *PoolerAutoscalingSpec) Clamp(instances int32) int32 {
+	minInstances := max(in.MinInstances, 1)
+	if instances < minInstances {
+		return minInstances
+	}
+	if in.MaxInstances > 0 && instances > in.MaxInstances {
+		return in.MaxInstances
+	}
+	return instances
+}
+
+// GetScaleDownStabilizationWindow returns the amount of time the recommended
+// number of instances must stay lower than the current one before scaling down
+func (in *PoolerAutoscalingSpec) GetScaleDownStabilizationWindow() time.Duration {
+	if in.ScaleDownStabilizationWindow == nil {
+		return defaultPoolerScaleDownStabilizationWindow
+	}
+	return in.ScaleDownStabilizationWindow.Duration
+}
+
+// GetImplementation returns the software used to pool the connections,
+// defaulting to PgBouncer
+func (in *Pooler) GetImplementation() PoolerImplementation {
+	if in.Spec.Implementation == "" {
+		return PoolerImplementationPgBouncer
+	}
+	return in.Spec.Implementation
+}
+
+// GetPgCatUserSecretNames returns the names of the secrets containing the
+// credentials of the PgCat users, without duplicates
+func (in *Pooler) GetPgCatUserSecretNames() []string {
+	if in.Spec.PgCat == nil {
+		return nil
+	}
+
+	var result []string
+	for _, database := range in.Spec.PgCat.Databases {
+		for _, user := range database.Users {
+			if !slices.Contains(result, user.Secret.Name) {
+				result = append(result, user.Secret.Name)
+			}
+		}
+	}
+	return result
+}
+
+// IsPrimaryReadsEnabled returns whether the primary can serve read queries
+func (in *PgCatSpec) IsPrimaryReadsEnabled() bool {
+	return in.PrimaryReadsEnabled == nil || *in.PrimaryReadsEnabled
+}
@@ -20,6 +20,11 @@ SPDX-License-Identifier: Apache-2.0
 package v1
 
 import (
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 )
@@ -37,4 +42,69 @@ var _ = Describe("Pooler type tests", func() {
 		}
 		Expect(pgbouncer.IsPaused()).To(BeTrue())
 	})
+
+	Context("autoscaling", func() {
+		It("uses the configured instances when autoscaling is disabled", func() {
+			pooler := Pooler{Spec: PoolerSpec{Instances: ptr.To(int32(3))}}
+			Expect(pooler.IsAutoscalingEnabled()).To(BeFalse())
+			Expect(pooler.GetDesiredInstances()).To(HaveValue(Equal(int32(3))))
+		})
+
+		It("clamps the configured instances before the first decision", func() {
+			pooler := Pooler{Spec: PoolerSpec{
+				Instances:   ptr.To(int32(1)),
+				Autoscaling: &PoolerAutoscalingSpec{MinInstances: 2, MaxInstances: 5},
+			}}
+			Expect(pooler.GetDesiredInstances()).To(HaveValue(Equal(int32(2))))
+		})
+
+		It("uses the autoscaler decision when available", func() {
+			pooler := Pooler{
+				Spec: PoolerSpec{
+					Instances:   ptr.To(int32(1)),
+					Autoscaling: &PoolerAutoscalingSpec{MinInstances: 1, MaxInstances: 5},
+				},
+				Status: PoolerStatus{Autoscaling: &PoolerAutoscalingStatus{DesiredInstances: 4}},
+			}
+			Expect(pooler.GetDesiredInstances()).To(HaveValue(Equal(int32(4))))
+
+			pooler.Spec.Autoscaling.MaxInstances = 3
+			Expect(pooler.GetDesiredInstances()).To(HaveValue(Equal(int32(3))))
+		})
+
+		It("defaults the scale down stabilization window", func() {
+			autoscaling := PoolerAutoscalingSpec{}
+			Expect(autoscaling.GetScaleDownStabilizationWindow()).To(Equal(5 * time.Minute))
+
+			autoscaling.ScaleDownStabilizationWindow = &metav1.Duration{Duration: time.Minute}
+			Expect(autoscaling.GetScaleDownStabilizationWindow()).To(Equal(time.Minute))
+		})
+	})
+
+	Context("implementation", func() {
+		It("defaults to PgBouncer", func() {
+			pooler := Pooler{}
+			Expect(pooler.GetImplementation()).To(Equal(PoolerImplementationPgBouncer))
+			Expect(pooler.IsAutomatedIntegration()).To(BeTrue())
+		})
+
+		It("never uses the automated integration with PgCat", func() {
+			pooler := Pooler{Spec: PoolerSpec{Implementation: PoolerImplementationPgCat}}
+			Expect(pooler.GetImplementation()).To(Equal(PoolerImplementationPgCat))
+			Expect(pooler.IsAutomatedIntegration()).To(BeFalse())
+		})
+
+		It("lists the PgCat user secrets without duplicates", func() {
+			pooler := Pooler{Spec: PoolerSpec{PgCat: &PgCatSpec{
+				Databases: []PgCatDatabase{
+					{Name: "app", Users: []PgCatUser{
+						{Secret: LocalObjectReference{Name: "app"}},
+						{Secret: LocalObjectReference{Name: "reporting"}},
+					}},
+					{Name: "other", Users: []PgCatUser{{Secret: LocalObjectReference{Name: "app"}}}},
+				},
+			}}}
+			Expect(pooler.GetPgCatUserSecretNames()).To(Equal([]string{"app", "reporting"}))
+		})
+	})
 })
@@ -60,7 +60,20 @@ const (
 	PgBouncerPoolModeTransaction = PgBouncerPoolMode("transaction")
 )
 
+// PoolerImplementation is the software used to pool the connections
+// +kubebuilder:validation:Enum=pgbouncer;pgcat
+type PoolerImplementation string
+
+const (
+	// PoolerImplementationPgBouncer uses PgBouncer
+	PoolerImplementationPgBouncer = PoolerImplementation("pgbouncer")
+
+	// PoolerImplementationPgCat uses PgCat
+	PoolerImplementationPgCat = PoolerImplementation("pgcat")
+)
+
 // PoolerSpec defines the desired state of Pooler
+// +kubebuilder:validation:XValidation:rule="has(self.implementation) && self.implementation == 'pgcat' ? has(self.pgcat) : has(self.pgbouncer)",message="the configuration section of the selected implementation is required"
 type PoolerSpec struct {
 	// This is the cluster reference on which the Pooler will work.
 	// Pooler name should never match with any cluster name within the same namespace.
@@ -80,8 +93,22 @@ type PoolerSpec struct {
 	// +optional
 	Template *PodTemplateSpec `json:"template,omitempty"`
 
-	// The PgBouncer configuration
-	PgBouncer *PgBouncerSpec `json:"pgbouncer"`
+	// The software used to pool the connections, `pgbouncer` (default)
+	// or `pgcat`. It cannot be changed once the Pooler is created.
+	// +kubebuilder:default:=pgbouncer
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="implementation is immutable"
+	// +optional
+	Implementation PoolerImplementation `json:"implementation,omitempty"`
+
+	// The PgBouncer configuration, required when the implementation
+	// is `pgbouncer`
+	// +optional
+	PgBouncer *PgBouncerSpec `json:"pgbouncer,omitempty"`
+
+	// The PgCat configuration, required when the implementation
+	// is `pgcat`
+	// +optional
+	PgCat *PgCatSpec `json:"pgcat,omitempty"`
 
 	// The deployment strategy to use for pgbouncer to replace existing pods with new ones
 	// +optional
@@ -97,6 +124,45 @@ type PoolerSpec struct {
 	// Template for the Service to be created
 	// +optional
 	ServiceTemplate *ServiceTemplateSpec `json:"serviceTemplate,omitempty"`
+
+	// The configuration of the horizontal autoscaling of the PgBouncer
+	// deployment, driven by the PgBouncer metrics. When set, `instances`
+	// is only used as the initial number of replicas.
+	// +optional
+	Autoscaling *PoolerAutoscalingSpec `json:"autoscaling,omitempty"`
+}
+
+// PoolerAutoscalingSpec contains the configuration of the horizontal
+// autoscaling of a Pooler
+// +kubebuilder:validation:XValidation:rule="self.minInstances <= self.maxInstances",message="minInstances cannot be greater than maxInstances"
+// +kubebuilder:validation:XValidation:rule="has(self.targetClientsWaiting) || has(self.targetMaxWait)",message="at least one of targetClientsWaiting and targetMaxWait is required"
+type PoolerAutoscalingSpec struct {
+	// The minimum number of PgBouncer instances. Default: 1.
+	// +kubebuilder:default:=1
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MinInstances int32 `json:"minInstances,omitempty"`
+
+	// The maximum number of PgBouncer instances
+	// +kubebuilder:validation:Minimum=1
+	MaxInstances int32 `json:"maxInstances"`
+
+	// The target average number of clients waiting for a server
+	// connection (`cl_waiting`) in each PgBouncer instance
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	TargetClientsWaiting *int32 `json:"targetClientsWaiting,omitempty"`
+
+	// The target maximum time the oldest client in the queue has been
+	// waiting for a server connection (`maxwait`), across all the
+	// PgBouncer instances
+	// +optional
+	TargetMaxWait *metav1.Duration `json:"targetMaxWait,omitempty"`
+
+	// The amount of time the recommended number of instances must stay
+	// below the current one before the Pooler is scaled down. Default: 5m.
+	// +optional
+	ScaleDownStabilizationWindow *metav1.Duration `json:"scaleDownStabilizationWindow,omitempty"`
 }
 
 // PoolerMonitoringConfiguration is the type containing all the monitoring
@@ -117,6 +183,11 @@ type PoolerMonitoringConfiguration struct {
 	// The list of relabelings for the `PodMonitor`. Applied to samples before scraping.
 	// +optional
 	PodMonitorRelabelConfigs []monitoringv1.RelabelConfig `json:"podMonitorRelabelings,omitempty"`
+
+	// Push the metrics of PgBouncer to an OpenTelemetry collector.
+	// Only supported by the PgBouncer pooler.
+	// +optional
+	OTLP *OTLPMetricsConfiguration `json:"otlp,omitempty"`
 }
 
 // PodTemplateSpec is a structure allowing the user to set
@@ -218,6 +289,191 @@ type PgBouncerSpec struct {
 	// +kubebuilder:default:=false
 	// +optional
 	Paused *bool `json:"paused,omitempty"`
+
+	// The list of databases with a specific configuration, written in the
+	// `[databases]` section of the PgBouncer configuration before the
+	// wildcard entry serving every other database
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	Databases []PgBouncerDatabase `json:"databases,omitempty"`
+
+	// The list of users with a specific configuration, written in the
+	// `[users]` section of the PgBouncer configuration
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	Users []PgBouncerUser `json:"users,omitempty"`
+}
+
+// PgBouncerDatabase is the PgBouncer configuration of a single database
+type PgBouncerDatabase struct {
+	// The name of the database as seen by the clients of PgBouncer
+	// +kubebuilder:validation:Pattern=`^[a-zA-Z0-9_][a-zA-Z0-9_.\-]*$`
+	// +kubebuilder:validation:MaxLength=63
+	Name string `json:"name"`
+
+	// The name of the database in PostgreSQL, when different from `name`
+	// +kubebuilder:validation:Pattern=`^[a-zA-Z0-9_][a-zA-Z0-9_.\-]*$`
+	// +kubebuilder:validation:MaxLength=63
+	// +optional
+	DBName string `json:"dbname,omitempty"`
+
+	// The pool mode of this database, overriding the one of the Pooler
+	// +optional
+	PoolMode PgBouncerPoolMode `json:"poolMode,omitempty"`
+
+	// The maximum number of server connections for each user/database pair
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	PoolSize *int32 `json:"poolSize,omitempty"`
+
+	// The minimum number of server connections kept in the pool
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MinPoolSize *int32 `json:"minPoolSize,omitempty"`
+
+	// The number of additional connections allowed to the pool
+	// when clients wait for too long
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	ReservePool *int32 `json:"reservePool,omitempty"`
+
+	// The maximum number of server connections to this database, across
+	// all the pools
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MaxDBConnections *int32 `json:"maxDBConnections,omitempty"`
+}
+
+// PgBouncerUser is the PgBouncer configuration of a single user
+type PgBouncerUser struct {
+	// The name of the user
+	// +kubebuilder:validation:Pattern=`^[a-zA-Z0-9_][a-zA-Z0-9_.\-]*$`
+	// +kubebuilder:validation:MaxLength=63
+	Name string `json:"name"`
+
+	// The pool mode used for the connections of this user, overriding
+	// the one of the Pooler and of the database
+	// +optional
+	PoolMode PgBouncerPoolMode `json:"poolMode,omitempty"`
+
+	// The maximum number of server connections of this user, across
+	// all the pools
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MaxUserConnections *int32 `json:"maxUserConnections,omitempty"`
+}
+
+// PgCatLoadBalancingMode is the algorithm PgCat uses to choose a server
+// +kubebuilder:validation:Enum=random;loc
+type PgCatLoadBalancingMode string
+
+const (
+	// PgCatLoadBalancingModeRandom chooses a random server
+	PgCatLoadBalancingModeRandom = PgCatLoadBalancingMode("random")
+
+	// PgCatLoadBalancingModeLeastOutstandingConnections chooses the server
+	// with the least outstanding connections
+	PgCatLoadBalancingModeLeastOutstandingConnections = PgCatLoadBalancingMode("loc")
+)
+
+// PgCatRole is the role of the server PgCat routes the queries to
+// +kubebuilder:validation:Enum=any;primary;replica
+type PgCatRole string
+
+const (
+	// PgCatRoleAny routes the queries to any server
+	PgCatRoleAny = PgCatRole("any")
+
+	// PgCatRolePrimary routes the queries to the primary
+	PgCatRolePrimary = PgCatRole("primary")
+
+	// PgCatRoleReplica routes the queries to the replicas
+	PgCatRoleReplica = PgCatRole("replica")
+)
+
+// PgCatSpec defines how to configure PgCat
+type PgCatSpec struct {
+	// The pool mode. Default: `transaction`.
+	// +kubebuilder:default:=transaction
+	// +optional
+	PoolMode PgBouncerPoolMode `json:"poolMode,omitempty"`
+
+	// The algorithm used to choose among the servers having the
+	// requested role. Default: `random`.
+	// +kubebuilder:default:=random
+	// +optional
+	LoadBalancingMode PgCatLoadBalancingMode `json:"loadBalancingMode,omitempty"`
+
+	// The role of the servers receiving the queries which are not routed
+	// by the query parser. Default: `any`.
+	// +kubebuilder:default:=any
+	// +optional
+	DefaultRole PgCatRole `json:"defaultRole,omitempty"`
+
+	// When set to `true`, PgCat parses the queries and sends the reads to
+	// the replicas and the writes to the primary. Only available with the
+	// `rw` Pooler type.
+	// +optional
+	ReadWriteSplitting bool `json:"readWriteSplitting,omitempty"`
+
+	// Whether the primary can serve read queries too. Default: `true`.
+	// +optional
+	PrimaryReadsEnabled *bool `json:"primaryReadsEnabled,omitempty"`
+
+	// Additional parameters to be written in the `general` section of the
+	// PgCat configuration - please check the CNPG documentation for a list
+	// of options you can configure
+	// +optional
+	Parameters map[string]string `json:"parameters,omitempty"`
+
+	// The list of databases served by PgCat
+	// +listType=map
+	// +listMapKey=name
+	// +kubebuilder:validation:MinItems=1
+	Databases []PgCatDatabase `json:"databases"`
+}
+
+// PgCatDatabase is a database served by PgCat
+type PgCatDatabase struct {
+	// The name of the database as seen by the clients of PgCat
+	// +kubebuilder:validation:Pattern=`^[a-zA-Z0-9_][a-zA-Z0-9_.\-]*$`
+	// +kubebuilder:validation:MaxLength=63
+	Name string `json:"name"`
+
+	// The name of the database in PostgreSQL, when different from `name`
+	// +kubebuilder:validation:Pattern=`^[a-zA-Z0-9_][a-zA-Z0-9_.\-]*$`
+	// +kubebuilder:validation:MaxLength=63
+	// +optional
+	DBName string `json:"dbname,omitempty"`
+
+	// The pool mode of this database, overriding the one of the Pooler
+	// +optional
+	PoolMode PgBouncerPoolMode `json:"poolMode,omitempty"`
+
+	// The users allowed to connect to this database
+	// +kubebuilder:validation:MinItems=1
+	Users []PgCatUser `json:"users"`
+}
+
+// PgCatUser is a user allowed to connect to a database through PgCat
+type PgCatUser struct {
+	// The secret of type `kubernetes.io/basic-auth` containing the
+	// credentials of the user, used both by the clients to connect to
+	// PgCat and by PgCat to connect to PostgreSQL
+	Secret LocalObjectReference `json:"secret"`
+
+	// The maximum number of server connections of this user. Default: 10.
+	// +kubebuilder:default:=10
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	PoolSize int32 `json:"poolSize,omitempty"`
+
+	// The minimum number of server connections kept in the pool
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MinPoolSize *int32 `json:"minPoolSize,omitempty"`
 }
 
 // PoolerStatus defines the observed state of Pooler
@@ -228,6 +484,53 @@ type PoolerStatus struct {
 	// The number of pods trying to be scheduled
 	// +optional
 	Instances int32 `json:"instances,omitempty"`
+
+	// The status of the horizontal autoscaling, if enabled
+	// +optional
+	Autoscaling *PoolerAutoscalingStatus `json:"autoscaling,omitempty"`
+
+	// The software used to pool the connections
+	// +optional
+	Implementation PoolerImplementation `json:"implementation,omitempty"`
+}
+
+// PoolerAutoscalingStatus contains the latest decision taken by the
+// Pooler autoscaler
+type PoolerAutoscalingStatus struct {
+	// The number of instances requested by the autoscaler
+	// +optional
+	DesiredInstances int32 `json:"desiredInstances,omitempty"`
+
+	// The average number of clients waiting for a server connection in
+	// each PgBouncer instance, as observed when the last decision was taken
+	// +optional
+	CurrentClientsWaiting *int32 `json:"currentClientsWaiting,omitempty"`
+
+	// The maximum waiting time of the oldest client across all the
+	// PgBouncer instances, as observed when the last decision was taken
+	// +optional
+	CurrentMaxWait *metav1.Duration `json:"currentMaxWait,omitempty"`
+
+	// The time since which the recommended number of instances is lower
+	// than the desired one
+	// +optional
+	ScaleDownRecommendedSince *metav1.Time `json:"scaleDownRecommendedSince,omitempty"`
+
+	// The last time the number of instances was changed by the autoscaler
+	// +optional
+	LastScaleTime *metav1.Time `json:"lastScaleTime,omitempty"`
+
+	// The time of the evaluation of the metrics leading to the last decision
+	// +optional
+	LastEvaluationTime *metav1.Time `json:"lastEvaluationTime,omitempty"`
+
+	// A machine-readable reason for the last decision
+	// +optional
+	Reason string `json:"reason,omitempty"`
+
+	// A human-readable message explaining the last decision
+	// +optional
+	Message string `json:"message,omitempty"`
 }
 
 // PoolerSecrets contains the versions of all the secrets used
@@ -278,6 +581,7 @@ type SecretVersion struct {
 // +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
 // +kubebuilder:printcolumn:name="Cluster",type="string",JSONPath=".spec.cluster.name"
 // +kubebuilder:printcolumn:name="Type",type="string",JSONPath=".spec.type"
+// +kubebuilder:printcolumn:name="Implementation",type="string",JSONPath=".spec.implementation",priority=1
 // +kubebuilder:subresource:scale:specpath=.spec.instances,statuspath=.status.instances
 
 // Pooler is the Schema for the poolers API
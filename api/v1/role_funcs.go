@@ -0,0 +1,101 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package v1
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/ptr"
+)
+
+// SetAsFailed sets the role as failed with the given error
+func (role *Role) SetAsFailed(err error) {
+	role.Status.Applied = ptr.To(false)
+	role.Status.Message = err.Error()
+}
+
+// SetAsUnknown sets the role as unknown with the given error
+func (role *Role) SetAsUnknown(err error) {
+	role.Status.Applied = nil
+	role.Status.Message = err.Error()
+}
+
+// SetAsReady sets the role as working correctly
+func (role *Role) SetAsReady() {
+	role.Status.Applied = ptr.To(true)
+	role.Status.Message = ""
+	role.Status.ObservedGeneration = role.Generation
+}
+
+// GetStatusMessage returns the status message of the role
+func (role *Role) GetStatusMessage() string {
+	return role.Status.Message
+}
+
+// GetClusterRef returns the cluster reference of the role
+func (role *Role) GetClusterRef() corev1.LocalObjectReference {
+	return role.Spec.ClusterRef
+}
+
+// GetManagedObjectName returns the name of the managed role object
+func (role *Role) GetManagedObjectName() string {
+	return role.Spec.Name
+}
+
+// GetName returns the role object name
+func (role *Role) GetName() string {
+	return role.Name
+}
+
+// HasReconciliations returns true if the role object has been reconciled at least once
+func (role *Role) HasReconciliations() bool {
+	return role.Status.ObservedGeneration > 0
+}
+
+// SetStatusObservedGeneration sets the observed generation of the role
+func (role *Role) SetStatusObservedGeneration(obsGeneration int64) {
+	role.Status.ObservedGeneration = obsGeneration
+}
+
+// MustNotConflictWithManagedRoles detects if the role is also declared
+// in the `spec.managed.roles` stanza of the passed cluster
+func (role *Role) MustNotConflictWithManagedRoles(cluster *Cluster) error {
+	if cluster.Spec.Managed == nil {
+		return nil
+	}
+
+	for _, managedRole := range cluster.Spec.Managed.Roles {
+		if managedRole.Name == role.Spec.Name {
+			return fmt.Errorf(
+				"%q is already managed by the spec.managed.roles stanza of cluster %q",
+				role.Spec.Name, cluster.Name,
+			)
+		}
+	}
+
+	return nil
+}
+
+// MustHaveManagedResourceExclusivity detects conflicting roles
+func (roleList *RoleList) MustHaveManagedResourceExclusivity(reference *Role) error {
+	pointers := toSliceWithPointers(roleList.Items)
+	return ensureManagedResourceExclusivity(reference, pointers)
+}
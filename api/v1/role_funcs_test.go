@@ -0,0 +1,92 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package v1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Role conflicts with managed roles", func() {
+	role := &Role{
+		ObjectMeta: metav1.ObjectMeta{Name: "app-role"},
+		Spec: RoleSpec{
+			ClusterRef:        corev1.LocalObjectReference{Name: "cluster-example"},
+			RoleConfiguration: RoleConfiguration{Name: "app"},
+		},
+	}
+
+	It("accepts a cluster without managed roles", func() {
+		cluster := &Cluster{ObjectMeta: metav1.ObjectMeta{Name: "cluster-example"}}
+		Expect(role.MustNotConflictWithManagedRoles(cluster)).To(Succeed())
+	})
+
+	It("accepts a cluster managing different roles", func() {
+		cluster := &Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example"},
+			Spec: ClusterSpec{
+				Managed: &ManagedConfiguration{
+					Roles: []RoleConfiguration{{Name: "dante"}},
+				},
+			},
+		}
+		Expect(role.MustNotConflictWithManagedRoles(cluster)).To(Succeed())
+	})
+
+	It("detects a role declared in the cluster too", func() {
+		cluster := &Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example"},
+			Spec: ClusterSpec{
+				Managed: &ManagedConfiguration{
+					Roles: []RoleConfiguration{{Name: "dante"}, {Name: "app"}},
+				},
+			},
+		}
+		Expect(role.MustNotConflictWithManagedRoles(cluster)).To(MatchError(ContainSubstring("app")))
+	})
+})
+
+var _ = Describe("Role exclusivity", func() {
+	newRole := func(name, roleName string, observedGeneration int64) Role {
+		return Role{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec: RoleSpec{
+				ClusterRef:        corev1.LocalObjectReference{Name: "cluster-example"},
+				RoleConfiguration: RoleConfiguration{Name: roleName},
+			},
+			Status: RoleResourceStatus{ObservedGeneration: observedGeneration},
+		}
+	}
+
+	It("detects two objects managing the same role", func() {
+		reference := newRole("role-two", "app", 0)
+		list := RoleList{Items: []Role{newRole("role-one", "app", 1), reference}}
+		Expect(list.MustHaveManagedResourceExclusivity(&reference)).ToNot(Succeed())
+	})
+
+	It("accepts objects managing different roles", func() {
+		reference := newRole("role-two", "app", 0)
+		list := RoleList{Items: []Role{newRole("role-one", "other", 1), reference}}
+		Expect(list.MustHaveManagedResourceExclusivity(&reference)).To(Succeed())
+	})
+})
@@ -20,6 +20,8 @@ SPDX-License-Identifier: Apache-2.0
 package v1
 
 import (
+	"time"
+
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/cloudnative-pg/cloudnative-pg/internal/configuration"
@@ -44,6 +46,25 @@ func (scheduledBackup ScheduledBackup) IsImmediate() bool {
 	return *scheduledBackup.Spec.Immediate
 }
 
+// GetConcurrencyPolicy gets the policy applied when a backup is due
+// while the previous one is still running
+func (scheduledBackup *ScheduledBackup) GetConcurrencyPolicy() ScheduledBackupConcurrencyPolicy {
+	if scheduledBackup.Spec.ConcurrencyPolicy == "" {
+		return ScheduledBackupConcurrencyPolicyWait
+	}
+
+	return scheduledBackup.Spec.ConcurrencyPolicy
+}
+
+// GetLocation gets the time zone used to evaluate the schedule
+func (scheduledBackup *ScheduledBackup) GetLocation() (*time.Location, error) {
+	if scheduledBackup.Spec.TimeZone == "" {
+		return time.Local, nil
+	}
+
+	return time.LoadLocation(scheduledBackup.Spec.TimeZone)
+}
+
 // GetName gets the scheduled backup name
 func (scheduledBackup *ScheduledBackup) GetName() string {
 	return scheduledBackup.Name
@@ -64,6 +85,36 @@ func (scheduledBackup *ScheduledBackup) GetStatus() *ScheduledBackupStatus {
 	return &scheduledBackup.Status
 }
 
+// GetRetentionPolicy gets the retention policy of the backups of a
+// tier, the empty name being the one of the main schedule. The second
+// value is false when the tier doesn't exist
+func (scheduledBackup *ScheduledBackup) GetRetentionPolicy(tier string) (*ScheduledBackupRetentionPolicy, bool) {
+	if tier == "" {
+		return scheduledBackup.Spec.RetentionPolicy, true
+	}
+
+	for _, t := range scheduledBackup.Spec.Tiers {
+		if t.Name == tier {
+			return t.RetentionPolicy, true
+		}
+	}
+
+	return nil, false
+}
+
+// GetTierStatus gets the status of the schedule of a tier that the caller
+// may update, adding it when missing
+func (status *ScheduledBackupStatus) GetTierStatus(name string) *ScheduledBackupTierStatus {
+	for i := range status.Tiers {
+		if status.Tiers[i].Name == name {
+			return &status.Tiers[i]
+		}
+	}
+
+	status.Tiers = append(status.Tiers, ScheduledBackupTierStatus{Name: name})
+	return &status.Tiers[len(status.Tiers)-1]
+}
+
 // CreateBackup creates a backup from this scheduled backup
 func (scheduledBackup *ScheduledBackup) CreateBackup(name string) *Backup {
 	backup := Backup{
@@ -38,6 +38,40 @@ type ScheduledBackupSpec struct {
 	// see https://pkg.go.dev/github.com/robfig/cron#hdr-CRON_Expression_Format
 	Schedule string `json:"schedule"`
 
+	// The IANA time zone used to evaluate the schedule, e.g. `Europe/Rome`.
+	// Defaults to the time zone of the operator, usually `UTC`
+	// +optional
+	TimeZone string `json:"timeZone,omitempty"`
+
+	// What to do when a backup is due while the previous one is still
+	// running. `Wait` (default) takes the backup once the previous one is
+	// done, `Forbid` skips it and waits for the next scheduled time
+	// +kubebuilder:validation:Enum=Wait;Forbid
+	// +kubebuilder:default:=Wait
+	// +optional
+	ConcurrencyPolicy ScheduledBackupConcurrencyPolicy `json:"concurrencyPolicy,omitempty"`
+
+	// The retention policy of the completed backups created by this
+	// ScheduledBackup. The backups which are not retained by any rule are
+	// deleted, while the most recent one is always retained
+	// +optional
+	RetentionPolicy *ScheduledBackupRetentionPolicy `json:"retentionPolicy,omitempty"`
+
+	// The number of failed backups created by this ScheduledBackup to be
+	// retained. When not set, the failed backups are never deleted
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	FailedBackupsHistoryLimit *int `json:"failedBackupsHistoryLimit,omitempty"`
+
+	// Additional schedules, each one with the retention policy of its own
+	// backups, e.g. to retain hourly, daily and weekly backups of the
+	// cluster. The schedules of the tiers are evaluated in the time zone
+	// and with the concurrency policy of the ScheduledBackup
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	Tiers []ScheduledBackupTier `json:"tiers,omitempty"`
+
 	// The cluster to backup
 	Cluster LocalObjectReference `json:"cluster"`
 
@@ -83,6 +117,53 @@ type ScheduledBackupSpec struct {
 	OnlineConfiguration *OnlineConfiguration `json:"onlineConfiguration,omitempty"`
 }
 
+// ScheduledBackupConcurrencyPolicy is the policy applied when a backup
+// is due while the previous one is still running
+type ScheduledBackupConcurrencyPolicy string
+
+const (
+	// ScheduledBackupConcurrencyPolicyWait means that the backup is taken
+	// as soon as the previous one is done
+	ScheduledBackupConcurrencyPolicyWait ScheduledBackupConcurrencyPolicy = "Wait"
+
+	// ScheduledBackupConcurrencyPolicyForbid means that the backup is skipped
+	ScheduledBackupConcurrencyPolicyForbid ScheduledBackupConcurrencyPolicy = "Forbid"
+)
+
+// ScheduledBackupRetentionPolicy defines which of the completed backups
+// created by a ScheduledBackup are retained
+// +kubebuilder:validation:XValidation:rule="has(self.keepLast) || has(self.maxAge)",message="at least one retention rule is required"
+type ScheduledBackupRetentionPolicy struct {
+	// Retain the given number of most recent backups
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	KeepLast *int `json:"keepLast,omitempty"`
+
+	// Retain the backups completed within the given period, expressed
+	// as `XXu` where `XX` is a positive integer and `u` is in `[dwm]`
+	// - days, weeks, months
+	// +kubebuilder:validation:Pattern=^[1-9][0-9]*[dwm]$
+	// +optional
+	MaxAge string `json:"maxAge,omitempty"`
+}
+
+// ScheduledBackupTier is an additional schedule of a ScheduledBackup
+type ScheduledBackupTier struct {
+	// The name of the tier, included in the names of its backups
+	// +kubebuilder:validation:Pattern=^[a-z0-9]([-a-z0-9]*[a-z0-9])?$
+	// +kubebuilder:validation:MaxLength=32
+	Name string `json:"name"`
+
+	// The schedule of the backups of the tier, in the same format
+	// of the schedule of the ScheduledBackup
+	Schedule string `json:"schedule"`
+
+	// The retention policy of the completed backups of the tier.
+	// When not set, they are never deleted
+	// +optional
+	RetentionPolicy *ScheduledBackupRetentionPolicy `json:"retentionPolicy,omitempty"`
+}
+
 // ScheduledBackupStatus defines the observed state of ScheduledBackup
 type ScheduledBackupStatus struct {
 	// The latest time the schedule
@@ -96,6 +177,28 @@ type ScheduledBackupStatus struct {
 	// Next time we will run a backup
 	// +optional
 	NextScheduleTime *metav1.Time `json:"nextScheduleTime,omitempty"`
+
+	// The status of the schedules of the tiers
+	// +optional
+	Tiers []ScheduledBackupTierStatus `json:"tiers,omitempty"`
+}
+
+// ScheduledBackupTierStatus is the status of the schedule of a tier
+type ScheduledBackupTierStatus struct {
+	// The name of the tier
+	Name string `json:"name"`
+
+	// The latest time the schedule of the tier was checked
+	// +optional
+	LastCheckTime *metav1.Time `json:"lastCheckTime,omitempty"`
+
+	// The last time a backup of the tier was scheduled
+	// +optional
+	LastScheduleTime *metav1.Time `json:"lastScheduleTime,omitempty"`
+
+	// The next time a backup of the tier will be taken
+	// +optional
+	NextScheduleTime *metav1.Time `json:"nextScheduleTime,omitempty"`
 }
 
 // +genclient
@@ -80,6 +80,31 @@ func (in *AffinityConfiguration) DeepCopy() *AffinityConfiguration {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AutoResizeConfiguration) DeepCopyInto(out *AutoResizeConfiguration) {
+	*out = *in
+	if in.UsageThreshold != nil {
+		in, out := &in.UsageThreshold, &out.UsageThreshold
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Cooldown != nil {
+		in, out := &in.Cooldown, &out.Cooldown
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AutoResizeConfiguration.
+func (in *AutoResizeConfiguration) DeepCopy() *AutoResizeConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(AutoResizeConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AvailableArchitecture) DeepCopyInto(out *AvailableArchitecture) {
 	*out = *in
@@ -345,6 +370,98 @@ func (in *BackupStatus) DeepCopy() *BackupStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BackupVerificationConfiguration) DeepCopyInto(out *BackupVerificationConfiguration) {
+	*out = *in
+	if in.Suspend != nil {
+		in, out := &in.Suspend, &out.Suspend
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Checks != nil {
+		in, out := &in.Checks, &out.Checks
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupVerificationConfiguration.
+func (in *BackupVerificationConfiguration) DeepCopy() *BackupVerificationConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(BackupVerificationConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BackupVerificationResult) DeepCopyInto(out *BackupVerificationResult) {
+	*out = *in
+	in.StartedAt.DeepCopyInto(&out.StartedAt)
+	in.CompletedAt.DeepCopyInto(&out.CompletedAt)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupVerificationResult.
+func (in *BackupVerificationResult) DeepCopy() *BackupVerificationResult {
+	if in == nil {
+		return nil
+	}
+	out := new(BackupVerificationResult)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BackupVerificationRun) DeepCopyInto(out *BackupVerificationRun) {
+	*out = *in
+	in.StartedAt.DeepCopyInto(&out.StartedAt)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupVerificationRun.
+func (in *BackupVerificationRun) DeepCopy() *BackupVerificationRun {
+	if in == nil {
+		return nil
+	}
+	out := new(BackupVerificationRun)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BackupVerificationStatus) DeepCopyInto(out *BackupVerificationStatus) {
+	*out = *in
+	if in.LastScheduleTime != nil {
+		in, out := &in.LastScheduleTime, &out.LastScheduleTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Running != nil {
+		in, out := &in.Running, &out.Running
+		*out = new(BackupVerificationRun)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.LastResult != nil {
+		in, out := &in.LastResult, &out.LastResult
+		*out = new(BackupVerificationResult)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupVerificationStatus.
+func (in *BackupVerificationStatus) DeepCopy() *BackupVerificationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(BackupVerificationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *BootstrapConfiguration) DeepCopyInto(out *BootstrapConfiguration) {
 	*out = *in
@@ -495,6 +612,29 @@ func (in *BootstrapRecovery) DeepCopy() *BootstrapRecovery {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CARotationStatus) DeepCopyInto(out *CARotationStatus) {
+	*out = *in
+	if in.PhaseStartedAt != nil {
+		in, out := &in.PhaseStartedAt, &out.PhaseStartedAt
+		*out = (*in).DeepCopy()
+	}
+	if in.LastCompletionTime != nil {
+		in, out := &in.LastCompletionTime, &out.LastCompletionTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CARotationStatus.
+func (in *CARotationStatus) DeepCopy() *CARotationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CARotationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CatalogImage) DeepCopyInto(out *CatalogImage) {
 	*out = *in
@@ -510,6 +650,21 @@ func (in *CatalogImage) DeepCopy() *CatalogImage {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CertManagerIssuerReference) DeepCopyInto(out *CertManagerIssuerReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertManagerIssuerReference.
+func (in *CertManagerIssuerReference) DeepCopy() *CertManagerIssuerReference {
+	if in == nil {
+		return nil
+	}
+	out := new(CertManagerIssuerReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CertificatesConfiguration) DeepCopyInto(out *CertificatesConfiguration) {
 	*out = *in
@@ -518,6 +673,16 @@ func (in *CertificatesConfiguration) DeepCopyInto(out *CertificatesConfiguration
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.CARotationPropagationDelay != nil {
+		in, out := &in.CARotationPropagationDelay, &out.CARotationPropagationDelay
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.IssuerRef != nil {
+		in, out := &in.IssuerRef, &out.IssuerRef
+		*out = new(CertManagerIssuerReference)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertificatesConfiguration.
@@ -541,6 +706,11 @@ func (in *CertificatesStatus) DeepCopyInto(out *CertificatesStatus) {
 			(*out)[key] = val
 		}
 	}
+	if in.CARotation != nil {
+		in, out := &in.CARotation, &out.CARotation
+		*out = new(CARotationStatus)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertificatesStatus.
@@ -553,6 +723,118 @@ func (in *CertificatesStatus) DeepCopy() *CertificatesStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClientCertificate) DeepCopyInto(out *ClientCertificate) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClientCertificate.
+func (in *ClientCertificate) DeepCopy() *ClientCertificate {
+	if in == nil {
+		return nil
+	}
+	out := new(ClientCertificate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClientCertificate) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClientCertificateList) DeepCopyInto(out *ClientCertificateList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ClientCertificate, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClientCertificateList.
+func (in *ClientCertificateList) DeepCopy() *ClientCertificateList {
+	if in == nil {
+		return nil
+	}
+	out := new(ClientCertificateList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClientCertificateList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClientCertificateSpec) DeepCopyInto(out *ClientCertificateSpec) {
+	*out = *in
+	out.ClusterRef = in.ClusterRef
+	if in.Duration != nil {
+		in, out := &in.Duration, &out.Duration
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.RenewBefore != nil {
+		in, out := &in.RenewBefore, &out.RenewBefore
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClientCertificateSpec.
+func (in *ClientCertificateSpec) DeepCopy() *ClientCertificateSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ClientCertificateSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClientCertificateStatus) DeepCopyInto(out *ClientCertificateStatus) {
+	*out = *in
+	if in.NotBefore != nil {
+		in, out := &in.NotBefore, &out.NotBefore
+		*out = (*in).DeepCopy()
+	}
+	if in.NotAfter != nil {
+		in, out := &in.NotAfter, &out.NotAfter
+		*out = (*in).DeepCopy()
+	}
+	if in.RenewalTime != nil {
+		in, out := &in.RenewalTime, &out.RenewalTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClientCertificateStatus.
+func (in *ClientCertificateStatus) DeepCopy() *ClientCertificateStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ClientCertificateStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Cluster) DeepCopyInto(out *Cluster) {
 	*out = *in
@@ -670,6 +952,26 @@ func (in *ClusterList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterLoggingConfiguration) DeepCopyInto(out *ClusterLoggingConfiguration) {
+	*out = *in
+	if in.OTLP != nil {
+		in, out := &in.OTLP, &out.OTLP
+		*out = new(OTLPLogsConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterLoggingConfiguration.
+func (in *ClusterLoggingConfiguration) DeepCopy() *ClusterLoggingConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterLoggingConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClusterMonitoringTLSConfiguration) DeepCopyInto(out *ClusterMonitoringTLSConfiguration) {
 	*out = *in
@@ -704,6 +1006,16 @@ func (in *ClusterSpec) DeepCopyInto(out *ClusterSpec) {
 		*out = new(ReplicationSlotsConfiguration)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.WraparoundProtection != nil {
+		in, out := &in.WraparoundProtection, &out.WraparoundProtection
+		*out = new(WraparoundProtectionConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DiskFullProtection != nil {
+		in, out := &in.DiskFullProtection, &out.DiskFullProtection
+		*out = new(DiskFullProtectionConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Bootstrap != nil {
 		in, out := &in.Bootstrap, &out.Bootstrap
 		*out = new(BootstrapConfiguration)
@@ -784,6 +1096,16 @@ func (in *ClusterSpec) DeepCopyInto(out *ClusterSpec) {
 		*out = new(NodeMaintenanceWindow)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.MaintenanceWindow != nil {
+		in, out := &in.MaintenanceWindow, &out.MaintenanceWindow
+		*out = new(MaintenanceWindowConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.BackupVerification != nil {
+		in, out := &in.BackupVerification, &out.BackupVerification
+		*out = new(BackupVerificationConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Monitoring != nil {
 		in, out := &in.Monitoring, &out.Monitoring
 		*out = new(MonitoringConfiguration)
@@ -796,6 +1118,11 @@ func (in *ClusterSpec) DeepCopyInto(out *ClusterSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Logging != nil {
+		in, out := &in.Logging, &out.Logging
+		*out = new(ClusterLoggingConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.ProjectedVolumeTemplate != nil {
 		in, out := &in.ProjectedVolumeTemplate, &out.ProjectedVolumeTemplate
 		*out = new(corev1.ProjectedVolumeSource)
@@ -894,7 +1221,7 @@ func (in *ClusterStatus) DeepCopyInto(out *ClusterStatus) {
 		in, out := &in.InstancesReportedState, &out.InstancesReportedState
 		*out = make(map[PodName]InstanceReportedState, len(*in))
 		for key, val := range *in {
-			(*out)[key] = val
+			(*out)[key] = *val.DeepCopy()
 		}
 	}
 	in.ManagedRolesStatus.DeepCopyInto(&out.ManagedRolesStatus)
@@ -981,6 +1308,16 @@ func (in *ClusterStatus) DeepCopyInto(out *ClusterStatus) {
 		}
 	}
 	out.SwitchReplicaClusterStatus = in.SwitchReplicaClusterStatus
+	if in.BackupVerification != nil {
+		in, out := &in.BackupVerification, &out.BackupVerification
+		*out = new(BackupVerificationStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.StorageMigration != nil {
+		in, out := &in.StorageMigration, &out.StorageMigration
+		*out = new(StorageMigrationStatus)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterStatus.
@@ -1070,6 +1407,21 @@ func (in *Database) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatabaseAge) DeepCopyInto(out *DatabaseAge) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatabaseAge.
+func (in *DatabaseAge) DeepCopy() *DatabaseAge {
+	if in == nil {
+		return nil
+	}
+	out := new(DatabaseAge)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DatabaseList) DeepCopyInto(out *DatabaseList) {
 	*out = *in
@@ -1169,7 +1521,9 @@ func (in *DatabaseSpec) DeepCopyInto(out *DatabaseSpec) {
 	if in.Schemas != nil {
 		in, out := &in.Schemas, &out.Schemas
 		*out = make([]SchemaSpec, len(*in))
-		copy(*out, *in)
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 	if in.Extensions != nil {
 		in, out := &in.Extensions, &out.Extensions
@@ -1190,6 +1544,13 @@ func (in *DatabaseSpec) DeepCopyInto(out *DatabaseSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Privileges != nil {
+		in, out := &in.Privileges, &out.Privileges
+		*out = make([]PrivilegeSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatabaseSpec.
@@ -1230,6 +1591,11 @@ func (in *DatabaseStatus) DeepCopyInto(out *DatabaseStatus) {
 		*out = make([]DatabaseObjectStatus, len(*in))
 		copy(*out, *in)
 	}
+	if in.Privileges != nil {
+		in, out := &in.Privileges, &out.Privileges
+		*out = make([]DatabaseObjectStatus, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatabaseStatus.
@@ -1243,16 +1609,62 @@ func (in *DatabaseStatus) DeepCopy() *DatabaseStatus {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *EmbeddedObjectMetadata) DeepCopyInto(out *EmbeddedObjectMetadata) {
+func (in *DefaultPrivilegeSpec) DeepCopyInto(out *DefaultPrivilegeSpec) {
 	*out = *in
-	if in.Labels != nil {
-		in, out := &in.Labels, &out.Labels
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
-	}
-	if in.Annotations != nil {
+	in.PrivilegeSpec.DeepCopyInto(&out.PrivilegeSpec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DefaultPrivilegeSpec.
+func (in *DefaultPrivilegeSpec) DeepCopy() *DefaultPrivilegeSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DefaultPrivilegeSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DiskFullProtectionConfiguration) DeepCopyInto(out *DiskFullProtectionConfiguration) {
+	*out = *in
+	if in.CriticalFreeSpacePercentage != nil {
+		in, out := &in.CriticalFreeSpacePercentage, &out.CriticalFreeSpacePercentage
+		*out = new(int32)
+		**out = **in
+	}
+	if in.RecoveryFreeSpacePercentage != nil {
+		in, out := &in.RecoveryFreeSpacePercentage, &out.RecoveryFreeSpacePercentage
+		*out = new(int32)
+		**out = **in
+	}
+	if in.CheckInterval != nil {
+		in, out := &in.CheckInterval, &out.CheckInterval
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DiskFullProtectionConfiguration.
+func (in *DiskFullProtectionConfiguration) DeepCopy() *DiskFullProtectionConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(DiskFullProtectionConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EmbeddedObjectMetadata) DeepCopyInto(out *EmbeddedObjectMetadata) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Annotations != nil {
 		in, out := &in.Annotations, &out.Annotations
 		*out = make(map[string]string, len(*in))
 		for key, val := range *in {
@@ -1697,6 +2109,16 @@ func (in *InstanceID) DeepCopy() *InstanceID {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *InstanceReportedState) DeepCopyInto(out *InstanceReportedState) {
 	*out = *in
+	if in.DatabaseAges != nil {
+		in, out := &in.DatabaseAges, &out.DatabaseAges
+		*out = make([]DatabaseAge, len(*in))
+		copy(*out, *in)
+	}
+	if in.VolumesUsage != nil {
+		in, out := &in.VolumesUsage, &out.VolumesUsage
+		*out = make([]VolumeUsage, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InstanceReportedState.
@@ -1810,6 +2232,42 @@ func (in *LivenessProbe) DeepCopy() *LivenessProbe {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MaintenanceWindow) DeepCopyInto(out *MaintenanceWindow) {
+	*out = *in
+	out.Duration = in.Duration
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaintenanceWindow.
+func (in *MaintenanceWindow) DeepCopy() *MaintenanceWindow {
+	if in == nil {
+		return nil
+	}
+	out := new(MaintenanceWindow)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MaintenanceWindowConfiguration) DeepCopyInto(out *MaintenanceWindowConfiguration) {
+	*out = *in
+	if in.Windows != nil {
+		in, out := &in.Windows, &out.Windows
+		*out = make([]MaintenanceWindow, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaintenanceWindowConfiguration.
+func (in *MaintenanceWindowConfiguration) DeepCopy() *MaintenanceWindowConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(MaintenanceWindowConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ManagedConfiguration) DeepCopyInto(out *ManagedConfiguration) {
 	*out = *in
@@ -2005,6 +2463,11 @@ func (in *MonitoringConfiguration) DeepCopyInto(out *MonitoringConfiguration) {
 		*out = new(metav1.Duration)
 		**out = **in
 	}
+	if in.OTLP != nil {
+		in, out := &in.OTLP, &out.OTLP
+		*out = new(OTLPMetricsConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MonitoringConfiguration.
@@ -2037,6 +2500,46 @@ func (in *NodeMaintenanceWindow) DeepCopy() *NodeMaintenanceWindow {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OTLPLogsConfiguration) DeepCopyInto(out *OTLPLogsConfiguration) {
+	*out = *in
+	if in.BufferSize != nil {
+		in, out := &in.BufferSize, &out.BufferSize
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OTLPLogsConfiguration.
+func (in *OTLPLogsConfiguration) DeepCopy() *OTLPLogsConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(OTLPLogsConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OTLPMetricsConfiguration) DeepCopyInto(out *OTLPMetricsConfiguration) {
+	*out = *in
+	if in.Interval != nil {
+		in, out := &in.Interval, &out.Interval
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OTLPMetricsConfiguration.
+func (in *OTLPMetricsConfiguration) DeepCopy() *OTLPMetricsConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(OTLPMetricsConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *OnlineConfiguration) DeepCopyInto(out *OnlineConfiguration) {
 	*out = *in
@@ -2092,6 +2595,41 @@ func (in *PasswordState) DeepCopy() *PasswordState {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PgBouncerDatabase) DeepCopyInto(out *PgBouncerDatabase) {
+	*out = *in
+	if in.PoolSize != nil {
+		in, out := &in.PoolSize, &out.PoolSize
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MinPoolSize != nil {
+		in, out := &in.MinPoolSize, &out.MinPoolSize
+		*out = new(int32)
+		**out = **in
+	}
+	if in.ReservePool != nil {
+		in, out := &in.ReservePool, &out.ReservePool
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MaxDBConnections != nil {
+		in, out := &in.MaxDBConnections, &out.MaxDBConnections
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PgBouncerDatabase.
+func (in *PgBouncerDatabase) DeepCopy() *PgBouncerDatabase {
+	if in == nil {
+		return nil
+	}
+	out := new(PgBouncerDatabase)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PgBouncerIntegrationStatus) DeepCopyInto(out *PgBouncerIntegrationStatus) {
 	*out = *in
@@ -2173,6 +2711,20 @@ func (in *PgBouncerSpec) DeepCopyInto(out *PgBouncerSpec) {
 		*out = new(bool)
 		**out = **in
 	}
+	if in.Databases != nil {
+		in, out := &in.Databases, &out.Databases
+		*out = make([]PgBouncerDatabase, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Users != nil {
+		in, out := &in.Users, &out.Users
+		*out = make([]PgBouncerUser, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PgBouncerSpec.
@@ -2185,6 +2737,103 @@ func (in *PgBouncerSpec) DeepCopy() *PgBouncerSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PgBouncerUser) DeepCopyInto(out *PgBouncerUser) {
+	*out = *in
+	if in.MaxUserConnections != nil {
+		in, out := &in.MaxUserConnections, &out.MaxUserConnections
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PgBouncerUser.
+func (in *PgBouncerUser) DeepCopy() *PgBouncerUser {
+	if in == nil {
+		return nil
+	}
+	out := new(PgBouncerUser)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PgCatDatabase) DeepCopyInto(out *PgCatDatabase) {
+	*out = *in
+	if in.Users != nil {
+		in, out := &in.Users, &out.Users
+		*out = make([]PgCatUser, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PgCatDatabase.
+func (in *PgCatDatabase) DeepCopy() *PgCatDatabase {
+	if in == nil {
+		return nil
+	}
+	out := new(PgCatDatabase)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PgCatSpec) DeepCopyInto(out *PgCatSpec) {
+	*out = *in
+	if in.PrimaryReadsEnabled != nil {
+		in, out := &in.PrimaryReadsEnabled, &out.PrimaryReadsEnabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Parameters != nil {
+		in, out := &in.Parameters, &out.Parameters
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Databases != nil {
+		in, out := &in.Databases, &out.Databases
+		*out = make([]PgCatDatabase, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PgCatSpec.
+func (in *PgCatSpec) DeepCopy() *PgCatSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PgCatSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PgCatUser) DeepCopyInto(out *PgCatUser) {
+	*out = *in
+	in.Secret.DeepCopyInto(&out.Secret)
+	if in.MinPoolSize != nil {
+		in, out := &in.MinPoolSize, &out.MinPoolSize
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PgCatUser.
+func (in *PgCatUser) DeepCopy() *PgCatUser {
+	if in == nil {
+		return nil
+	}
+	out := new(PgCatUser)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PluginConfiguration) DeepCopyInto(out *PluginConfiguration) {
 	*out = *in
@@ -2322,6 +2971,73 @@ func (in *Pooler) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PoolerAutoscalingSpec) DeepCopyInto(out *PoolerAutoscalingSpec) {
+	*out = *in
+	if in.TargetClientsWaiting != nil {
+		in, out := &in.TargetClientsWaiting, &out.TargetClientsWaiting
+		*out = new(int32)
+		**out = **in
+	}
+	if in.TargetMaxWait != nil {
+		in, out := &in.TargetMaxWait, &out.TargetMaxWait
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.ScaleDownStabilizationWindow != nil {
+		in, out := &in.ScaleDownStabilizationWindow, &out.ScaleDownStabilizationWindow
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PoolerAutoscalingSpec.
+func (in *PoolerAutoscalingSpec) DeepCopy() *PoolerAutoscalingSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PoolerAutoscalingSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PoolerAutoscalingStatus) DeepCopyInto(out *PoolerAutoscalingStatus) {
+	*out = *in
+	if in.CurrentClientsWaiting != nil {
+		in, out := &in.CurrentClientsWaiting, &out.CurrentClientsWaiting
+		*out = new(int32)
+		**out = **in
+	}
+	if in.CurrentMaxWait != nil {
+		in, out := &in.CurrentMaxWait, &out.CurrentMaxWait
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.ScaleDownRecommendedSince != nil {
+		in, out := &in.ScaleDownRecommendedSince, &out.ScaleDownRecommendedSince
+		*out = (*in).DeepCopy()
+	}
+	if in.LastScaleTime != nil {
+		in, out := &in.LastScaleTime, &out.LastScaleTime
+		*out = (*in).DeepCopy()
+	}
+	if in.LastEvaluationTime != nil {
+		in, out := &in.LastEvaluationTime, &out.LastEvaluationTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PoolerAutoscalingStatus.
+func (in *PoolerAutoscalingStatus) DeepCopy() *PoolerAutoscalingStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PoolerAutoscalingStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PoolerIntegrations) DeepCopyInto(out *PoolerIntegrations) {
 	*out = *in
@@ -2387,6 +3103,11 @@ func (in *PoolerMonitoringConfiguration) DeepCopyInto(out *PoolerMonitoringConfi
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.OTLP != nil {
+		in, out := &in.OTLP, &out.OTLP
+		*out = new(OTLPMetricsConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PoolerMonitoringConfiguration.
@@ -2442,6 +3163,11 @@ func (in *PoolerSpec) DeepCopyInto(out *PoolerSpec) {
 		*out = new(PgBouncerSpec)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.PgCat != nil {
+		in, out := &in.PgCat, &out.PgCat
+		*out = new(PgCatSpec)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.DeploymentStrategy != nil {
 		in, out := &in.DeploymentStrategy, &out.DeploymentStrategy
 		*out = new(appsv1.DeploymentStrategy)
@@ -2457,6 +3183,11 @@ func (in *PoolerSpec) DeepCopyInto(out *PoolerSpec) {
 		*out = new(ServiceTemplateSpec)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Autoscaling != nil {
+		in, out := &in.Autoscaling, &out.Autoscaling
+		*out = new(PoolerAutoscalingSpec)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PoolerSpec.
@@ -2477,6 +3208,11 @@ func (in *PoolerStatus) DeepCopyInto(out *PoolerStatus) {
 		*out = new(PoolerSecrets)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Autoscaling != nil {
+		in, out := &in.Autoscaling, &out.Autoscaling
+		*out = new(PoolerAutoscalingStatus)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PoolerStatus.
@@ -2545,11 +3281,31 @@ func (in *PostgresConfiguration) DeepCopy() *PostgresConfiguration {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Probe) DeepCopyInto(out *Probe) {
+func (in *PrivilegeSpec) DeepCopyInto(out *PrivilegeSpec) {
 	*out = *in
-	if in.TerminationGracePeriodSeconds != nil {
-		in, out := &in.TerminationGracePeriodSeconds, &out.TerminationGracePeriodSeconds
-		*out = new(int64)
+	if in.Privileges != nil {
+		in, out := &in.Privileges, &out.Privileges
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PrivilegeSpec.
+func (in *PrivilegeSpec) DeepCopy() *PrivilegeSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PrivilegeSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Probe) DeepCopyInto(out *Probe) {
+	*out = *in
+	if in.TerminationGracePeriodSeconds != nil {
+		in, out := &in.TerminationGracePeriodSeconds, &out.TerminationGracePeriodSeconds
+		*out = new(int64)
 		**out = **in
 	}
 }
@@ -2875,6 +3631,33 @@ func (in *ReplicationSlotsHAConfiguration) DeepCopy() *ReplicationSlotsHAConfigu
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Role) DeepCopyInto(out *Role) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Role.
+func (in *Role) DeepCopy() *Role {
+	if in == nil {
+		return nil
+	}
+	out := new(Role)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Role) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RoleConfiguration) DeepCopyInto(out *RoleConfiguration) {
 	*out = *in
@@ -2909,6 +3692,76 @@ func (in *RoleConfiguration) DeepCopy() *RoleConfiguration {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RoleList) DeepCopyInto(out *RoleList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Role, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RoleList.
+func (in *RoleList) DeepCopy() *RoleList {
+	if in == nil {
+		return nil
+	}
+	out := new(RoleList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *RoleList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RoleResourceStatus) DeepCopyInto(out *RoleResourceStatus) {
+	*out = *in
+	if in.Applied != nil {
+		in, out := &in.Applied, &out.Applied
+		*out = new(bool)
+		**out = **in
+	}
+	out.PasswordState = in.PasswordState
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RoleResourceStatus.
+func (in *RoleResourceStatus) DeepCopy() *RoleResourceStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RoleResourceStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RoleSpec) DeepCopyInto(out *RoleSpec) {
+	*out = *in
+	out.ClusterRef = in.ClusterRef
+	in.RoleConfiguration.DeepCopyInto(&out.RoleConfiguration)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RoleSpec.
+func (in *RoleSpec) DeepCopy() *RoleSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RoleSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SQLRefs) DeepCopyInto(out *SQLRefs) {
 	*out = *in
@@ -2993,6 +3846,26 @@ func (in *ScheduledBackupList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScheduledBackupRetentionPolicy) DeepCopyInto(out *ScheduledBackupRetentionPolicy) {
+	*out = *in
+	if in.KeepLast != nil {
+		in, out := &in.KeepLast, &out.KeepLast
+		*out = new(int)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScheduledBackupRetentionPolicy.
+func (in *ScheduledBackupRetentionPolicy) DeepCopy() *ScheduledBackupRetentionPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(ScheduledBackupRetentionPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ScheduledBackupSpec) DeepCopyInto(out *ScheduledBackupSpec) {
 	*out = *in
@@ -3006,6 +3879,23 @@ func (in *ScheduledBackupSpec) DeepCopyInto(out *ScheduledBackupSpec) {
 		*out = new(bool)
 		**out = **in
 	}
+	if in.RetentionPolicy != nil {
+		in, out := &in.RetentionPolicy, &out.RetentionPolicy
+		*out = new(ScheduledBackupRetentionPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.FailedBackupsHistoryLimit != nil {
+		in, out := &in.FailedBackupsHistoryLimit, &out.FailedBackupsHistoryLimit
+		*out = new(int)
+		**out = **in
+	}
+	if in.Tiers != nil {
+		in, out := &in.Tiers, &out.Tiers
+		*out = make([]ScheduledBackupTier, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	in.Cluster.DeepCopyInto(&out.Cluster)
 	if in.PluginConfiguration != nil {
 		in, out := &in.PluginConfiguration, &out.PluginConfiguration
@@ -3049,6 +3939,13 @@ func (in *ScheduledBackupStatus) DeepCopyInto(out *ScheduledBackupStatus) {
 		in, out := &in.NextScheduleTime, &out.NextScheduleTime
 		*out = (*in).DeepCopy()
 	}
+	if in.Tiers != nil {
+		in, out := &in.Tiers, &out.Tiers
+		*out = make([]ScheduledBackupTierStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScheduledBackupStatus.
@@ -3061,10 +3958,92 @@ func (in *ScheduledBackupStatus) DeepCopy() *ScheduledBackupStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScheduledBackupTier) DeepCopyInto(out *ScheduledBackupTier) {
+	*out = *in
+	if in.RetentionPolicy != nil {
+		in, out := &in.RetentionPolicy, &out.RetentionPolicy
+		*out = new(ScheduledBackupRetentionPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScheduledBackupTier.
+func (in *ScheduledBackupTier) DeepCopy() *ScheduledBackupTier {
+	if in == nil {
+		return nil
+	}
+	out := new(ScheduledBackupTier)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScheduledBackupTierStatus) DeepCopyInto(out *ScheduledBackupTierStatus) {
+	*out = *in
+	if in.LastCheckTime != nil {
+		in, out := &in.LastCheckTime, &out.LastCheckTime
+		*out = (*in).DeepCopy()
+	}
+	if in.LastScheduleTime != nil {
+		in, out := &in.LastScheduleTime, &out.LastScheduleTime
+		*out = (*in).DeepCopy()
+	}
+	if in.NextScheduleTime != nil {
+		in, out := &in.NextScheduleTime, &out.NextScheduleTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScheduledBackupTierStatus.
+func (in *ScheduledBackupTierStatus) DeepCopy() *ScheduledBackupTierStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ScheduledBackupTierStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SchemaSpec) DeepCopyInto(out *SchemaSpec) {
 	*out = *in
 	out.DatabaseObjectSpec = in.DatabaseObjectSpec
+	if in.Privileges != nil {
+		in, out := &in.Privileges, &out.Privileges
+		*out = make([]PrivilegeSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.TablePrivileges != nil {
+		in, out := &in.TablePrivileges, &out.TablePrivileges
+		*out = make([]PrivilegeSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.SequencePrivileges != nil {
+		in, out := &in.SequencePrivileges, &out.SequencePrivileges
+		*out = make([]PrivilegeSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.FunctionPrivileges != nil {
+		in, out := &in.FunctionPrivileges, &out.FunctionPrivileges
+		*out = make([]PrivilegeSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.DefaultPrivileges != nil {
+		in, out := &in.DefaultPrivileges, &out.DefaultPrivileges
+		*out = make([]DefaultPrivilegeSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SchemaSpec.
@@ -3205,6 +4184,11 @@ func (in *StorageConfiguration) DeepCopyInto(out *StorageConfiguration) {
 		*out = new(corev1.PersistentVolumeClaimSpec)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.AutoResize != nil {
+		in, out := &in.AutoResize, &out.AutoResize
+		*out = new(AutoResizeConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StorageConfiguration.
@@ -3217,6 +4201,34 @@ func (in *StorageConfiguration) DeepCopy() *StorageConfiguration {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StorageMigrationStatus) DeepCopyInto(out *StorageMigrationStatus) {
+	*out = *in
+	if in.PendingInstances != nil {
+		in, out := &in.PendingInstances, &out.PendingInstances
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.StartedAt != nil {
+		in, out := &in.StartedAt, &out.StartedAt
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletedAt != nil {
+		in, out := &in.CompletedAt, &out.CompletedAt
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StorageMigrationStatus.
+func (in *StorageMigrationStatus) DeepCopy() *StorageMigrationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(StorageMigrationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Subscription) DeepCopyInto(out *Subscription) {
 	*out = *in
@@ -3519,6 +4531,11 @@ func (in *VolumeSnapshotConfiguration) DeepCopyInto(out *VolumeSnapshotConfigura
 		**out = **in
 	}
 	in.OnlineConfiguration.DeepCopyInto(&out.OnlineConfiguration)
+	if in.RetentionPolicy != nil {
+		in, out := &in.RetentionPolicy, &out.RetentionPolicy
+		*out = new(VolumeSnapshotRetentionPolicy)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VolumeSnapshotConfiguration.
@@ -3530,3 +4547,113 @@ func (in *VolumeSnapshotConfiguration) DeepCopy() *VolumeSnapshotConfiguration {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VolumeSnapshotRetentionPolicy) DeepCopyInto(out *VolumeSnapshotRetentionPolicy) {
+	*out = *in
+	if in.KeepLast != nil {
+		in, out := &in.KeepLast, &out.KeepLast
+		*out = new(int)
+		**out = **in
+	}
+	if in.KeepDaily != nil {
+		in, out := &in.KeepDaily, &out.KeepDaily
+		*out = new(int)
+		**out = **in
+	}
+	if in.KeepWeekly != nil {
+		in, out := &in.KeepWeekly, &out.KeepWeekly
+		*out = new(int)
+		**out = **in
+	}
+	if in.KeepMonthly != nil {
+		in, out := &in.KeepMonthly, &out.KeepMonthly
+		*out = new(int)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VolumeSnapshotRetentionPolicy.
+func (in *VolumeSnapshotRetentionPolicy) DeepCopy() *VolumeSnapshotRetentionPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(VolumeSnapshotRetentionPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VolumeUsage) DeepCopyInto(out *VolumeUsage) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VolumeUsage.
+func (in *VolumeUsage) DeepCopy() *VolumeUsage {
+	if in == nil {
+		return nil
+	}
+	out := new(VolumeUsage)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WraparoundFreezeConfiguration) DeepCopyInto(out *WraparoundFreezeConfiguration) {
+	*out = *in
+	if in.MaxRelations != nil {
+		in, out := &in.MaxRelations, &out.MaxRelations
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WraparoundFreezeConfiguration.
+func (in *WraparoundFreezeConfiguration) DeepCopy() *WraparoundFreezeConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(WraparoundFreezeConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WraparoundProtectionConfiguration) DeepCopyInto(out *WraparoundProtectionConfiguration) {
+	*out = *in
+	if in.CheckInterval != nil {
+		in, out := &in.CheckInterval, &out.CheckInterval
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.XIDAgeThreshold != nil {
+		in, out := &in.XIDAgeThreshold, &out.XIDAgeThreshold
+		*out = new(int64)
+		**out = **in
+	}
+	if in.MultiXactIDAgeThreshold != nil {
+		in, out := &in.MultiXactIDAgeThreshold, &out.MultiXactIDAgeThreshold
+		*out = new(int64)
+		**out = **in
+	}
+	if in.Freeze != nil {
+		in, out := &in.Freeze, &out.Freeze
+		*out = new(WraparoundFreezeConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WraparoundProtectionConfiguration.
+func (in *WraparoundProtectionConfiguration) DeepCopy() *WraparoundProtectionConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(WraparoundProtectionConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
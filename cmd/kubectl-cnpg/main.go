@@ -32,6 +32,7 @@ import (
 	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin/backup"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin/certificate"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin/clone"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin/destroy"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin/fence"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin/fio"
@@ -39,6 +40,7 @@ import (
 	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin/install"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin/logical/publication"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin/logical/subscription"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin/locks"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin/logs"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin/maintenance"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin/pgadmin"
@@ -50,6 +52,7 @@ import (
 	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin/restart"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin/snapshot"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin/status"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin/top"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/versions"
 
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
@@ -115,11 +118,13 @@ func main() {
 	subcommands := []*cobra.Command{
 		backup.NewCmd(),
 		certificate.NewCmd(),
+		clone.NewCmd(),
 		destroy.NewCmd(),
 		fence.NewCmd(),
 		fio.NewCmd(),
 		hibernate.NewCmd(),
 		install.NewCmd(),
+		locks.NewCmd(),
 		logs.NewCmd(),
 		maintenance.NewCmd(),
 		pgadmin.NewCmd(),
@@ -133,6 +138,7 @@ func main() {
 		snapshot.NewCmd(),
 		status.NewCmd(),
 		subscription.NewCmd(),
+		top.NewCmd(),
 		versions.NewCmd(),
 	}
 
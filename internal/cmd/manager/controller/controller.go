@@ -254,6 +254,30 @@ func RunController(
 		return err
 	}
 
+	if err = (&controller.BackupVerificationReconciler{
+		Client:   mgr.GetClient(),
+		Recorder: mgr.GetEventRecorderFor("cloudnative-pg-backup-verification"),
+	}).SetupWithManager(mgr, maxConcurrentReconciles); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "BackupVerification")
+		return err
+	}
+
+	if err = (&controller.SnapshotRetentionReconciler{
+		Client: mgr.GetClient(),
+	}).SetupWithManager(mgr, maxConcurrentReconciles); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "SnapshotRetention")
+		return err
+	}
+
+	if err = (&controller.ClientCertificateReconciler{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Recorder: mgr.GetEventRecorderFor("cloudnative-pg-clientcertificate"),
+	}).SetupWithManager(mgr, maxConcurrentReconciles); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "ClientCertificate")
+		return err
+	}
+
 	if err = (&controller.PoolerReconciler{
 		Client:          mgr.GetClient(),
 		DiscoveryClient: discoveryClient,
@@ -289,6 +313,11 @@ func RunController(
 		return err
 	}
 
+	if err = webhookv1.SetupClientCertificateWebhookWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create webhook", "webhook", "ClientCertificate", "version", "v1")
+		return err
+	}
+
 	// Setup the handler used by the readiness and liveliness probe.
 	//
 	// Unfortunately the readiness of the probe is not sufficient for the operator to be
@@ -29,6 +29,7 @@ import (
 
 	"github.com/cloudnative-pg/machinery/pkg/log"
 	"github.com/spf13/cobra"
+	"go.opentelemetry.io/otel/attribute"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -47,14 +48,17 @@ import (
 	"github.com/cloudnative-pg/cloudnative-pg/internal/cnpi/plugin/repository"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/configuration"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/management/controller"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/management/controller/diskfull"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/management/controller/externalservers"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/management/controller/roles"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/management/controller/slots/runner"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/management/controller/tablespaces"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/management/controller/wraparound"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/management/istio"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/management/linkerd"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/concurrency"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/otlp"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/logpipe"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/metrics"
@@ -165,6 +169,21 @@ func NewCmd() *cobra.Command {
 	return cmd
 }
 
+// getOTLPWriterOptions returns the options of the export of the PostgreSQL
+// logs not depending on the Cluster configuration
+func getOTLPWriterOptions(instance *postgres.Instance) logpipe.OTLPWriterOptions {
+	return logpipe.OTLPWriterOptions{
+		BufferDirectory: pg.LogBufferDirectory,
+		Attributes: []attribute.KeyValue{
+			otlp.ServiceNameAttribute.String("cloudnative-pg"),
+			otlp.NamespaceAttribute.String(instance.GetNamespaceName()),
+			otlp.PodAttribute.String(instance.GetPodName()),
+			otlp.ClusterAttribute.String(instance.GetClusterName()),
+			otlp.InstanceAttribute.String(instance.GetPodName()),
+		},
+	}
+}
+
 func runSubCommand( //nolint:gocognit,gocyclo
 	ctx context.Context,
 	instance *postgres.Instance,
@@ -213,6 +232,11 @@ func runSubCommand( //nolint:gocognit,gocyclo
 						instance.GetNamespaceName(): {},
 					},
 				},
+				&apiv1.Role{}: {
+					Namespaces: map[string]cache.Config{
+						instance.GetNamespaceName(): {},
+					},
+				},
 			},
 		},
 		// We don't need a cache for secrets and configmap, as all reloads
@@ -293,8 +317,24 @@ func runSubCommand( //nolint:gocognit,gocyclo
 		return err
 	}
 
+	// declarative role reconciler
+	roleReconciler := controller.NewRoleReconciler(mgr, instance)
+	if err := roleReconciler.SetupWithManager(mgr); err != nil {
+		contextLogger.Error(err, "unable to create role controller")
+		return err
+	}
+
 	// postgres CSV logs handler (PGAudit too)
-	postgresLogPipe := logpipe.NewLogPipe()
+	postgresLogWriter := logpipe.NewConfigurableRecordWriter(instance.OTLPLogsChan(), getOTLPWriterOptions(instance))
+	if err := mgr.Add(postgresLogWriter); err != nil {
+		contextLogger.Error(err, "unable to add the OTLP logs writer")
+		return err
+	}
+	postgresLogPipe := logpipe.NewLogPipe().
+		WithSlowQueries(func() logpipe.QueryTextMode {
+			return logpipe.QueryTextMode(instance.GetSlowQueryText())
+		}, metricsExporter).
+		WithRecordWriter(postgresLogWriter)
 	if err := mgr.Add(postgresLogPipe); err != nil {
 		contextLogger.Error(err, "unable to add CSV logs handler")
 		return err
@@ -343,6 +383,16 @@ func runSubCommand( //nolint:gocognit,gocyclo
 		return err
 	}
 
+	if err = mgr.Add(diskfull.NewMonitor(instance)); err != nil {
+		contextLogger.Error(err, "unable to create disk-full monitor")
+		return err
+	}
+
+	if err = mgr.Add(wraparound.NewMonitor(instance)); err != nil {
+		contextLogger.Error(err, "unable to create wraparound monitor")
+		return err
+	}
+
 	roleSynchronizer := roles.NewRoleSynchronizer(instance, reconciler.GetClient())
 	if err = mgr.Add(roleSynchronizer); err != nil {
 		contextLogger.Error(err, "unable to create role synchronizer")
@@ -391,6 +441,11 @@ func runSubCommand( //nolint:gocognit,gocyclo
 		return err
 	}
 
+	if err = mgr.Add(metricsServer.NewOTLPPusher()); err != nil {
+		contextLogger.Error(err, "unable to add the OTLP metrics pusher runnable")
+		return err
+	}
+
 	contextLogger.Info("starting tablespace manager")
 	if err := tablespaces.NewTablespaceReconciler(instance, mgr.GetClient()).
 		SetupWithManager(mgr); err != nil {
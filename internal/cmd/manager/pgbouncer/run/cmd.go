@@ -35,6 +35,7 @@ import (
 	"github.com/spf13/cobra"
 	"k8s.io/apimachinery/pkg/types"
 
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/pgbouncer/management/controller"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/pgbouncer/config"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/pgbouncer/metricsserver"
@@ -120,6 +121,12 @@ func runSubCommand(ctx context.Context, poolerNamespacedName types.NamespacedNam
 		return fmt.Errorf("while initializing reconciler: %w", err)
 	}
 
+	otlpCtx, cancelOTLP := context.WithCancel(ctx)
+	defer cancelOTLP()
+	if err = startOTLPPusher(otlpCtx, reconciler, poolerNamespacedName); err != nil {
+		return fmt.Errorf("while starting the OTLP metrics pusher: %w", err)
+	}
+
 	// Start PgBouncer with the generated configuration
 	const pgBouncerCommandName = "/usr/bin/pgbouncer"
 	pgBouncerIni := filepath.Join(config.ConfigsDir, config.PgBouncerIniFileName)
@@ -201,6 +208,35 @@ func startWebServer(ctx context.Context) error {
 	return nil
 }
 
+// startOTLPPusher starts pushing the PgBouncer metrics to an
+// OpenTelemetry collector, when the Pooler requires it. Changing the
+// configuration rolls out the Pooler Pods, so it is read only once
+func startOTLPPusher(
+	ctx context.Context,
+	reconciler *controller.PgBouncerReconciler,
+	poolerNamespacedName types.NamespacedName,
+) error {
+	var pooler apiv1.Pooler
+	if err := reconciler.GetClient().Get(ctx, poolerNamespacedName, &pooler); err != nil {
+		return err
+	}
+
+	if pooler.Spec.Monitoring == nil || pooler.Spec.Monitoring.OTLP == nil {
+		return nil
+	}
+
+	pusher, err := metricsserver.NewOTLPPusher(ctx, &pooler)
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		_ = pusher.Start(ctx)
+	}()
+
+	return nil
+}
+
 // startReconciler start the reconciliation loop
 func startReconciler(ctx context.Context, reconciler *controller.PgBouncerReconciler) {
 	go reconciler.Run(ctx)
@@ -54,6 +54,11 @@ func Generate(ctx context.Context, params Params, dryRun bool, format plugin.Out
 		return err
 	}
 
+	if cluster.UsesCertManager() {
+		return fmt.Errorf("the certificates of cluster %s are issued by cert-manager, "+
+			"request the client certificate to its issuer", cluster.Name)
+	}
+
 	err = plugin.Client.Get(
 		ctx,
 		client.ObjectKey{Namespace: params.Namespace, Name: cluster.GetClientCASecretName()},
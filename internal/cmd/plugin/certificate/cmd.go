@@ -57,6 +57,8 @@ connect to the PostgreSQL cluster.`,
 		},
 	}
 
+	certificateCmd.AddCommand(newRotateCACmd())
+
 	certificateCmd.Flags().String(
 		"cnpg-user", "", "The name of the PostgreSQL user")
 	_ = certificateCmd.MarkFlagRequired("cnpg-user")
@@ -70,3 +72,23 @@ connect to the PostgreSQL cluster.`,
 
 	return certificateCmd
 }
+
+func newRotateCACmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "rotate-ca CLUSTER",
+		Short: "Rotate the CA managed by the operator for a cluster",
+		Long: `Request the rotation of the CA generated by the operator for the cluster.
+The new CA is trusted together with the old one, then every certificate generated by the
+operator is signed again, and finally the old CA is retired. The progress is reported in
+the status of the cluster.`,
+		Args: plugin.RequiresArguments(1),
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return plugin.CompleteClusters(cmd.Context(), args, toComplete), cobra.ShellCompDirectiveNoFileComp
+		},
+		RunE: func(_ *cobra.Command, args []string) error {
+			ctx := context.Background()
+			clusterName := args[0]
+			return RotateCA(ctx, clusterName)
+		},
+	}
+}
@@ -0,0 +1,68 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package certificate
+
+import (
+	"context"
+	"fmt"
+
+	pgTime "github.com/cloudnative-pg/machinery/pkg/postgres/time"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+// RotateCA requests the rotation of the CA managed by the operator for
+// the passed cluster
+func RotateCA(ctx context.Context, clusterName string) error {
+	var cluster apiv1.Cluster
+
+	err := plugin.Client.Get(ctx, client.ObjectKey{Namespace: plugin.Namespace, Name: clusterName}, &cluster)
+	if err != nil {
+		return err
+	}
+
+	secretName, ok := cluster.GetManagedCASecretName()
+	if !ok {
+		return fmt.Errorf("cluster %s is using user-provided CAs, which are not rotated by the operator",
+			clusterName)
+	}
+
+	if cluster.Status.Certificates.CARotation.IsInProgress() {
+		return fmt.Errorf("a rotation of the CA is already in progress for cluster %s (phase: %s)",
+			clusterName, cluster.Status.Certificates.CARotation.Phase)
+	}
+
+	origCluster := cluster.DeepCopy()
+	if cluster.Annotations == nil {
+		cluster.Annotations = make(map[string]string)
+	}
+	cluster.Annotations[utils.CARotationRequestedAnnotationName] = pgTime.GetCurrentTimestamp()
+	cluster.ManagedFields = nil
+
+	if err := plugin.Client.Patch(ctx, &cluster, client.MergeFrom(origCluster)); err != nil {
+		return err
+	}
+
+	fmt.Printf("rotation of the CA in secret %s requested for cluster %s\n", secretName, clusterName)
+	return nil
+}
@@ -0,0 +1,322 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package clone
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/cloudnative-pg/machinery/pkg/types"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
+)
+
+// cloneOptions are the options of the clone command
+type cloneOptions struct {
+	sourceName string
+	targetName string
+	backupName string
+	targetTime string
+	targetLSN  string
+}
+
+// targetTimeLayouts are the accepted formats for the recovery target time
+var targetTimeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02 15:04:05.999999999Z07:00",
+	"2006-01-02 15:04:05.999999999Z07",
+}
+
+// errNoSuitableBackup is raised when no backup of the source cluster
+// can be used to reach the requested recovery target
+var errNoSuitableBackup = errors.New("no suitable backup found")
+
+// parseTargetTime parses the recovery target time
+func parseTargetTime(value string) (time.Time, error) {
+	for _, layout := range targetTimeLayouts {
+		if result, err := time.Parse(layout, value); err == nil {
+			return result, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid target time %q, expected an RFC 3339 timestamp", value)
+}
+
+// clone creates the new cluster, or prints its definition when in dry-run mode
+func clone(ctx context.Context, options cloneOptions, dryRun bool) (*apiv1.Cluster, error) {
+	var source apiv1.Cluster
+	if err := plugin.Client.Get(
+		ctx,
+		client.ObjectKey{Namespace: plugin.Namespace, Name: options.sourceName},
+		&source,
+	); err != nil {
+		return nil, fmt.Errorf("while getting cluster %s: %w", options.sourceName, err)
+	}
+
+	var backupList apiv1.BackupList
+	if err := plugin.Client.List(ctx, &backupList, client.InNamespace(plugin.Namespace)); err != nil {
+		return nil, fmt.Errorf("while listing backups: %w", err)
+	}
+
+	backup, err := selectBackup(&source, backupList.Items, options)
+	if err != nil {
+		return nil, err
+	}
+
+	cluster, err := buildCluster(&source, backup, options)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := plugin.CreateAndGenerateObjects(ctx, []client.Object{cluster}, dryRun); err != nil {
+		return nil, err
+	}
+
+	return cluster, nil
+}
+
+// selectBackup chooses the backup of the source cluster to be recovered.
+// Unless a backup has been explicitly requested, the most recent one that can
+// reach the recovery target is used, preferring volume snapshots which are
+// faster to restore
+func selectBackup(source *apiv1.Cluster, backups []apiv1.Backup, options cloneOptions) (*apiv1.Backup, error) {
+	var targetTime time.Time
+	if options.targetTime != "" {
+		var err error
+		if targetTime, err = parseTargetTime(options.targetTime); err != nil {
+			return nil, err
+		}
+		if err := validateTargetTime(source, targetTime); err != nil {
+			return nil, err
+		}
+	}
+
+	if options.backupName != "" {
+		idx := slices.IndexFunc(backups, func(backup apiv1.Backup) bool {
+			return backup.Name == options.backupName
+		})
+		if idx < 0 {
+			return nil, fmt.Errorf("backup %s not found", options.backupName)
+		}
+
+		backup := &backups[idx]
+		if err := checkBackup(source, backup, options, targetTime); err != nil {
+			return nil, fmt.Errorf("backup %s cannot be used: %w", backup.Name, err)
+		}
+		return backup, nil
+	}
+
+	var result *apiv1.Backup
+	for i := range backups {
+		backup := &backups[i]
+		if checkBackup(source, backup, options, targetTime) != nil {
+			continue
+		}
+
+		if options.targetLSN != "" && backup.Status.EndLSN == "" {
+			// We cannot be sure that this backup precedes the target LSN
+			continue
+		}
+
+		if result == nil || isPreferredBackup(backup, result) {
+			result = backup
+		}
+	}
+
+	if result == nil {
+		return nil, fmt.Errorf("%w for cluster %s", errNoSuitableBackup, source.Name)
+	}
+
+	return result, nil
+}
+
+// isPreferredBackup checks if the candidate backup is better than the current one
+func isPreferredBackup(candidate, current *apiv1.Backup) bool {
+	candidateTime := candidate.Status.StoppedAt.Time
+	currentTime := current.Status.StoppedAt.Time
+	if candidateTime.Equal(currentTime) {
+		return candidate.Status.Method == apiv1.BackupMethodVolumeSnapshot
+	}
+
+	return candidateTime.After(currentTime)
+}
+
+// validateTargetTime checks that the target time does not precede the first
+// recoverability point of the source cluster
+func validateTargetTime(source *apiv1.Cluster, targetTime time.Time) error {
+	if len(source.Status.FirstRecoverabilityPointByMethod) == 0 {
+		return nil
+	}
+
+	var firstRecoverabilityPoint time.Time
+	for method, point := range source.Status.FirstRecoverabilityPointByMethod {
+		if !isSupportedMethod(method) {
+			continue
+		}
+		if firstRecoverabilityPoint.IsZero() || point.Time.Before(firstRecoverabilityPoint) {
+			firstRecoverabilityPoint = point.Time
+		}
+	}
+
+	if !firstRecoverabilityPoint.IsZero() && targetTime.Before(firstRecoverabilityPoint) {
+		return fmt.Errorf("target time %s precedes the first recoverability point of cluster %s (%s)",
+			targetTime.Format(time.RFC3339), source.Name, firstRecoverabilityPoint.Format(time.RFC3339))
+	}
+
+	return nil
+}
+
+// isSupportedMethod checks if the clone command can recover a backup taken
+// with the passed method
+func isSupportedMethod(method apiv1.BackupMethod) bool {
+	return method == apiv1.BackupMethodBarmanObjectStore || method == apiv1.BackupMethodVolumeSnapshot
+}
+
+// checkBackup checks if the backup can be used to reach the recovery target
+func checkBackup(
+	source *apiv1.Cluster,
+	backup *apiv1.Backup,
+	options cloneOptions,
+	targetTime time.Time,
+) error {
+	switch {
+	case backup.Spec.Cluster.Name != source.Name:
+		return fmt.Errorf("it belongs to cluster %s", backup.Spec.Cluster.Name)
+	case backup.Status.Phase != apiv1.BackupPhaseCompleted || backup.Status.StoppedAt == nil:
+		return errors.New("it is not completed")
+	case !isSupportedMethod(backup.Status.Method):
+		return fmt.Errorf("method %s is not supported", backup.Status.Method)
+	}
+
+	if backup.Status.MajorVersion != 0 {
+		if majorVersion, err := source.GetPostgresqlMajorVersion(); err == nil && majorVersion != backup.Status.MajorVersion {
+			return fmt.Errorf("it was taken with PostgreSQL %d while the cluster runs PostgreSQL %d",
+				backup.Status.MajorVersion, majorVersion)
+		}
+	}
+
+	isPointInTime := options.targetTime != "" || options.targetLSN != ""
+	if isPointInTime && backup.Status.Method == apiv1.BackupMethodVolumeSnapshot && !hasObjectStoreArchive(source) {
+		return errors.New("point-in-time recovery from volume snapshots needs the WAL archive " +
+			"in the object store of the cluster")
+	}
+
+	if !targetTime.IsZero() && backup.Status.StoppedAt.After(targetTime) {
+		return fmt.Errorf("it was completed after the target time")
+	}
+
+	if options.targetLSN != "" && backup.Status.EndLSN != "" &&
+		types.LSN(options.targetLSN).Less(types.LSN(backup.Status.EndLSN)) {
+		return fmt.Errorf("it ends after the target LSN")
+	}
+
+	return nil
+}
+
+// hasObjectStoreArchive checks if the source cluster archives its WALs
+// in an object store
+func hasObjectStoreArchive(source *apiv1.Cluster) bool {
+	return source.Spec.Backup != nil && source.Spec.Backup.BarmanObjectStore != nil
+}
+
+// originExternalClusterName is the name of the external cluster pointing
+// to the WAL archive of the source cluster
+func originExternalClusterName(source *apiv1.Cluster) string {
+	return fmt.Sprintf("%s-origin", source.Name)
+}
+
+// buildCluster derives the definition of the new cluster from the
+// one of the source cluster and the backup to be recovered
+func buildCluster(source *apiv1.Cluster, backup *apiv1.Backup, options cloneOptions) (*apiv1.Cluster, error) {
+	spec := source.Spec.DeepCopy()
+
+	// The new cluster must not write into the WAL archive of the source
+	// cluster, nor replicate from it
+	spec.Backup = nil
+	spec.ReplicaCluster = nil
+	spec.Plugins = slices.DeleteFunc(spec.Plugins, func(plugin apiv1.PluginConfiguration) bool {
+		return ptr.Deref(plugin.IsWALArchiver, false)
+	})
+	if spec.Managed != nil && spec.Managed.Services != nil {
+		// The names of the additional services would clash with
+		// the ones of the source cluster
+		spec.Managed.Services.Additional = nil
+	}
+
+	recovery := &apiv1.BootstrapRecovery{}
+	if source.Spec.Bootstrap != nil && source.Spec.Bootstrap.InitDB != nil {
+		recovery.Database = source.Spec.Bootstrap.InitDB.Database
+		recovery.Owner = source.Spec.Bootstrap.InitDB.Owner
+		recovery.Secret = source.Spec.Bootstrap.InitDB.Secret
+	}
+
+	if options.targetTime != "" || options.targetLSN != "" {
+		recovery.RecoveryTarget = &apiv1.RecoveryTarget{
+			TargetTime: options.targetTime,
+			TargetLSN:  options.targetLSN,
+		}
+	}
+
+	switch backup.Status.Method {
+	case apiv1.BackupMethodBarmanObjectStore:
+		recovery.Backup = &apiv1.BackupSource{
+			LocalObjectReference: apiv1.LocalObjectReference{Name: backup.Name},
+		}
+
+	case apiv1.BackupMethodVolumeSnapshot:
+		recovery.VolumeSnapshots = specs.BuildVolumeSnapshotDataSource(backup)
+		if recovery.RecoveryTarget != nil {
+			barmanObjectStore := source.Spec.Backup.BarmanObjectStore.DeepCopy()
+			if barmanObjectStore.ServerName == "" {
+				barmanObjectStore.ServerName = source.Name
+			}
+			spec.ExternalClusters = append(spec.ExternalClusters, apiv1.ExternalCluster{
+				Name:              originExternalClusterName(source),
+				BarmanObjectStore: barmanObjectStore,
+			})
+			recovery.Source = originExternalClusterName(source)
+		}
+
+	default:
+		return nil, fmt.Errorf("backup method %s is not supported", backup.Status.Method)
+	}
+
+	spec.Bootstrap = &apiv1.BootstrapConfiguration{Recovery: recovery}
+
+	cluster := &apiv1.Cluster{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: apiv1.SchemeGroupVersion.String(),
+			Kind:       apiv1.ClusterKind,
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      options.targetName,
+			Namespace: source.Namespace,
+		},
+		Spec: *spec,
+	}
+	return cluster, nil
+}
@@ -0,0 +1,212 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package clone
+
+import (
+	"time"
+
+	barmanApi "github.com/cloudnative-pg/barman-cloud/pkg/api"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("clone", func() {
+	now := time.Date(2025, time.March, 15, 12, 0, 0, 0, time.UTC)
+
+	var source *apiv1.Cluster
+
+	newBackup := func(name string, method apiv1.BackupMethod, stoppedAt time.Time) apiv1.Backup {
+		return apiv1.Backup{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+			Spec: apiv1.BackupSpec{
+				Cluster: apiv1.LocalObjectReference{Name: "cluster-example"},
+				Method:  method,
+			},
+			Status: apiv1.BackupStatus{
+				Method:    method,
+				Phase:     apiv1.BackupPhaseCompleted,
+				StoppedAt: ptr.To(metav1.NewTime(stoppedAt)),
+				EndLSN:    "0/5000000",
+				BackupSnapshotStatus: apiv1.BackupSnapshotStatus{
+					Elements: []apiv1.BackupSnapshotElementStatus{
+						{Name: name + "-1", Type: string(utils.PVCRolePgData)},
+						{Name: name + "-1-wal", Type: string(utils.PVCRolePgWal)},
+					},
+				},
+			},
+		}
+	}
+
+	BeforeEach(func() {
+		source = &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example", Namespace: "default"},
+			Spec: apiv1.ClusterSpec{
+				Instances: 3,
+				ImageName: "ghcr.io/cloudnative-pg/postgresql:17.4",
+				Bootstrap: &apiv1.BootstrapConfiguration{
+					InitDB: &apiv1.BootstrapInitDB{Database: "app", Owner: "app"},
+				},
+				Backup: &apiv1.BackupConfiguration{
+					BarmanObjectStore: &apiv1.BarmanObjectStoreConfiguration{
+						DestinationPath: "s3://backups/",
+					},
+				},
+				Plugins: []apiv1.PluginConfiguration{
+					{Name: "archiver", IsWALArchiver: ptr.To(true)},
+					{Name: "other"},
+				},
+			},
+			Status: apiv1.ClusterStatus{
+				FirstRecoverabilityPointByMethod: map[apiv1.BackupMethod]metav1.Time{
+					apiv1.BackupMethodBarmanObjectStore: metav1.NewTime(now.AddDate(0, 0, -7)),
+					apiv1.BackupMethodVolumeSnapshot:    metav1.NewTime(now.AddDate(0, 0, -2)),
+				},
+			},
+		}
+	})
+
+	Context("selectBackup", func() {
+		backups := []apiv1.Backup{
+			newBackup("object-store-old", apiv1.BackupMethodBarmanObjectStore, now.AddDate(0, 0, -7)),
+			newBackup("snapshot", apiv1.BackupMethodVolumeSnapshot, now.AddDate(0, 0, -1)),
+			newBackup("object-store", apiv1.BackupMethodBarmanObjectStore, now.AddDate(0, 0, -1)),
+			newBackup("object-store-recent", apiv1.BackupMethodBarmanObjectStore, now.Add(-time.Hour)),
+		}
+
+		It("chooses the most recent backup", func() {
+			backup, err := selectBackup(source, backups, cloneOptions{})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(backup.Name).To(Equal("object-store-recent"))
+		})
+
+		It("chooses the most recent backup preceding the target time, preferring snapshots", func() {
+			backup, err := selectBackup(source, backups, cloneOptions{
+				targetTime: now.Add(-2 * time.Hour).Format(time.RFC3339),
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(backup.Name).To(Equal("snapshot"))
+		})
+
+		It("accepts the target time in the PostgreSQL format", func() {
+			backup, err := selectBackup(source, backups, cloneOptions{
+				targetTime: "2025-03-12 10:00:00+00",
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(backup.Name).To(Equal("object-store-old"))
+		})
+
+		It("rejects a target time preceding the first recoverability point", func() {
+			_, err := selectBackup(source, backups, cloneOptions{
+				targetTime: now.AddDate(0, 0, -10).Format(time.RFC3339),
+			})
+			Expect(err).To(MatchError(ContainSubstring("first recoverability point")))
+		})
+
+		It("skips snapshots for point-in-time recovery without a WAL archive", func() {
+			source.Spec.Backup.BarmanObjectStore = nil
+			backup, err := selectBackup(source, backups, cloneOptions{
+				targetTime: now.Add(-2 * time.Hour).Format(time.RFC3339),
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(backup.Name).To(Equal("object-store"))
+		})
+
+		It("checks the requested backup", func() {
+			backup, err := selectBackup(source, backups, cloneOptions{backupName: "snapshot"})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(backup.Name).To(Equal("snapshot"))
+
+			_, err = selectBackup(source, backups, cloneOptions{backupName: "missing"})
+			Expect(err).To(MatchError(ContainSubstring("not found")))
+
+			_, err = selectBackup(source, backups, cloneOptions{backupName: "snapshot", targetLSN: "0/4000000"})
+			Expect(err).To(MatchError(ContainSubstring("after the target LSN")))
+		})
+
+		It("fails when no backup is available", func() {
+			_, err := selectBackup(source, nil, cloneOptions{})
+			Expect(err).To(MatchError(errNoSuitableBackup))
+		})
+	})
+
+	Context("buildCluster", func() {
+		It("recovers an object store backup", func() {
+			backup := newBackup("object-store", apiv1.BackupMethodBarmanObjectStore, now)
+			cluster, err := buildCluster(source, &backup, cloneOptions{
+				targetName: "cluster-copy",
+				targetLSN:  "0/6000000",
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(cluster.Name).To(Equal("cluster-copy"))
+			Expect(cluster.Spec.Instances).To(Equal(3))
+			Expect(cluster.Spec.Backup).To(BeNil())
+			Expect(cluster.Spec.Plugins).To(HaveLen(1))
+			Expect(cluster.Spec.Plugins[0].Name).To(Equal("other"))
+
+			recovery := cluster.Spec.Bootstrap.Recovery
+			Expect(recovery.Backup.Name).To(Equal("object-store"))
+			Expect(recovery.VolumeSnapshots).To(BeNil())
+			Expect(recovery.RecoveryTarget.TargetLSN).To(Equal("0/6000000"))
+			Expect(recovery.Database).To(Equal("app"))
+			Expect(recovery.Owner).To(Equal("app"))
+
+			// The source cluster is not changed
+			Expect(source.Spec.Backup).ToNot(BeNil())
+			Expect(source.Spec.Plugins).To(HaveLen(2))
+		})
+
+		It("recovers a volume snapshot backup replaying the WAL archive", func() {
+			backup := newBackup("snapshot", apiv1.BackupMethodVolumeSnapshot, now)
+			cluster, err := buildCluster(source, &backup, cloneOptions{
+				targetName: "cluster-copy",
+				targetTime: now.Format(time.RFC3339),
+			})
+			Expect(err).ToNot(HaveOccurred())
+
+			recovery := cluster.Spec.Bootstrap.Recovery
+			Expect(recovery.Backup).To(BeNil())
+			Expect(recovery.VolumeSnapshots.Storage.Name).To(Equal("snapshot-1"))
+			Expect(recovery.VolumeSnapshots.WalStorage.Name).To(Equal("snapshot-1-wal"))
+			Expect(recovery.Source).To(Equal("cluster-example-origin"))
+			Expect(cluster.Spec.ExternalClusters).To(ConsistOf(apiv1.ExternalCluster{
+				Name: "cluster-example-origin",
+				BarmanObjectStore: &barmanApi.BarmanObjectStoreConfiguration{
+					DestinationPath: "s3://backups/",
+					ServerName:      "cluster-example",
+				},
+			}))
+		})
+
+		It("does not need the WAL archive to recover a volume snapshot backup", func() {
+			backup := newBackup("snapshot", apiv1.BackupMethodVolumeSnapshot, now)
+			cluster, err := buildCluster(source, &backup, cloneOptions{targetName: "cluster-copy"})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(cluster.Spec.Bootstrap.Recovery.Source).To(BeEmpty())
+			Expect(cluster.Spec.Bootstrap.Recovery.RecoveryTarget).To(BeNil())
+			Expect(cluster.Spec.ExternalClusters).To(BeEmpty())
+		})
+	})
+})
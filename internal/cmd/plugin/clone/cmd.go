@@ -0,0 +1,164 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package clone
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin"
+)
+
+var cloneExample = `
+  # Create the cluster "cluster-copy" from the latest backup of "cluster-example"
+  kubectl-cnpg clone cluster-example cluster-copy
+
+  # Recover "cluster-example" up to a point in time, waiting for the new cluster to be ready
+  kubectl-cnpg clone cluster-example cluster-copy --target-time "2025-03-15T12:00:00Z" --wait
+
+  # Print the definition of the cluster that would be created from a given backup
+  kubectl-cnpg clone cluster-example cluster-copy --backup cluster-example-20250315120000 --dry-run
+`
+
+// NewCmd creates the new "clone" subcommand
+func NewCmd() *cobra.Command {
+	var (
+		options cloneOptions
+		dryRun  bool
+		wait    bool
+		timeout time.Duration
+	)
+
+	cloneSubcommand := &cobra.Command{
+		Use:     "clone CLUSTER NEW_CLUSTER",
+		Short:   "Create a new cluster recovering the backups of an existing one",
+		Example: cloneExample,
+		GroupID: plugin.GroupIDCluster,
+		Args:    plugin.RequiresArguments(2),
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			if len(args) > 0 {
+				return nil, cobra.ShellCompDirectiveNoFileComp
+			}
+			return plugin.CompleteClusters(cmd.Context(), args, toComplete), cobra.ShellCompDirectiveNoFileComp
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			options.sourceName = args[0]
+			options.targetName = args[1]
+
+			if options.targetTime != "" && options.targetLSN != "" {
+				return errors.New("target-time and target-lsn cannot be used together")
+			}
+			if dryRun && wait {
+				return errors.New("wait cannot be used together with dry-run")
+			}
+
+			cluster, err := clone(cmd.Context(), options, dryRun)
+			if err != nil {
+				return err
+			}
+
+			if !wait {
+				return nil
+			}
+
+			ctx, cancel := context.WithTimeout(cmd.Context(), timeout)
+			defer cancel()
+			return waitForHealthyCluster(ctx, client.ObjectKeyFromObject(cluster))
+		},
+	}
+
+	cloneSubcommand.Flags().StringVar(
+		&options.backupName,
+		"backup",
+		"",
+		"The name of the Backup to recover. Defaults to the most recent backup "+
+			"of the cluster that can reach the recovery target",
+	)
+	cloneSubcommand.Flags().StringVar(
+		&options.targetTime,
+		"target-time",
+		"",
+		"The point in time to recover, as an RFC 3339 timestamp",
+	)
+	cloneSubcommand.Flags().StringVar(
+		&options.targetLSN,
+		"target-lsn",
+		"",
+		"The LSN to recover",
+	)
+	cloneSubcommand.Flags().BoolVar(
+		&dryRun,
+		"dry-run",
+		false,
+		"When true prints the definition of the new cluster without creating it",
+	)
+	cloneSubcommand.Flags().BoolVar(
+		&wait,
+		"wait",
+		false,
+		"When true waits for the new cluster to become healthy",
+	)
+	cloneSubcommand.Flags().DurationVar(
+		&timeout,
+		"timeout",
+		30*time.Minute,
+		"The maximum time to wait for the new cluster to become healthy",
+	)
+
+	return cloneSubcommand
+}
+
+// waitForHealthyCluster waits for every instance of the cluster to be ready
+func waitForHealthyCluster(ctx context.Context, key client.ObjectKey) error {
+	const pollInterval = 5 * time.Second
+
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	lastPhase := ""
+	for {
+		var cluster apiv1.Cluster
+		if err := plugin.Client.Get(ctx, key, &cluster); err != nil {
+			return fmt.Errorf("while getting cluster %s: %w", key.Name, err)
+		}
+
+		if cluster.Status.Phase != lastPhase && cluster.Status.Phase != "" {
+			fmt.Printf("cluster/%s: %s\n", key.Name, cluster.Status.Phase)
+			lastPhase = cluster.Status.Phase
+		}
+
+		if cluster.Status.Phase == apiv1.PhaseHealthy &&
+			cluster.Status.ReadyInstances == cluster.Spec.Instances {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("while waiting for cluster %s to become healthy: %w", key.Name, ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,91 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package locks
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin"
+)
+
+// NewCmd creates the new "locks" command
+func NewCmd() *cobra.Command {
+	var (
+		cancelPID, terminatePID int
+		skipConfirmation, force bool
+		dbname, output          string
+		timeout                 time.Duration
+	)
+
+	cmd := &cobra.Command{
+		Use:   "locks CLUSTER",
+		Short: "Display the sessions blocked by locks in the primary",
+		Long: "Display the tree of the sessions holding locks in the primary instance and of the sessions " +
+			"waiting for them, optionally cancelling or terminating one of them",
+		Args:    plugin.RequiresArguments(1),
+		GroupID: plugin.GroupIDTroubleshooting,
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return plugin.CompleteClusters(cmd.Context(), args, toComplete), cobra.ShellCompDirectiveNoFileComp
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			clusterName := args[0]
+
+			if cmd.Flags().Changed("cancel") && cmd.Flags().Changed("terminate") {
+				return fmt.Errorf("--cancel and --terminate cannot be used together")
+			}
+
+			options := SignalOptions{SkipConfirmation: skipConfirmation, Force: force}
+			switch {
+			case cmd.Flags().Changed("cancel"):
+				options.Action = ActionCancel
+				options.PID = cancelPID
+			case cmd.Flags().Changed("terminate"):
+				options.Action = ActionTerminate
+				options.PID = terminatePID
+			}
+			if options.Action != "" && options.PID <= 0 {
+				return fmt.Errorf("invalid PID: %d", options.PID)
+			}
+
+			return Locks(ctx, clusterName, dbname, plugin.OutputFormat(output), timeout, options)
+		},
+	}
+
+	cmd.Flags().IntVar(&cancelPID, "cancel", 0,
+		"Cancel the current query of the session with this PID")
+	cmd.Flags().IntVar(&terminatePID, "terminate", 0,
+		"Terminate the session with this PID")
+	cmd.Flags().BoolVarP(&skipConfirmation, "yes", "y", false,
+		"Cancel or terminate the session without asking for confirmation")
+	cmd.Flags().BoolVar(&force, "force", false,
+		"Cancel or terminate the session even if it is not part of the blocking tree")
+	cmd.Flags().StringVarP(&dbname, "dbname", "d", "postgres",
+		"The database to connect to")
+	cmd.Flags().StringVarP(&output, "output", "o", "text",
+		"Output format. One of text|json|yaml")
+	cmd.Flags().DurationVarP(&timeout, "timeout", "t", 10*time.Second,
+		"Timeout for the queries run in the primary instance")
+
+	return cmd
+}
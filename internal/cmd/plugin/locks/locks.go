@@ -0,0 +1,406 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package locks
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/cheynewallace/tabby"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin"
+)
+
+// sessionsQuery extracts, as a JSON array, the sessions matching the
+// condition passed as the only formatting argument. The lock a session is
+// waiting for is reported with the relation name only when it belongs to
+// the database we are connected to, as the OIDs of the other databases
+// can't be resolved.
+const sessionsQuery = `WITH activity AS (
+  SELECT a.*, pg_catalog.pg_blocking_pids(a.pid) AS blocked_by
+  FROM pg_catalog.pg_stat_activity a
+)
+SELECT COALESCE(json_agg(row_to_json(s) ORDER BY s.pid), '[]')
+FROM (
+  SELECT a.pid, a.blocked_by, a.datname, a.usename, a.application_name,
+    a.client_addr::text AS client_addr, a.backend_type, a.state,
+    a.wait_event_type, a.wait_event, a.query,
+    EXTRACT(EPOCH FROM pg_catalog.now() - a.query_start)::float8 AS query_duration_seconds,
+    EXTRACT(EPOCH FROM pg_catalog.now() - a.xact_start)::float8 AS transaction_duration_seconds,
+    (
+      SELECT l.mode || ' on ' || l.locktype || COALESCE(' ' || CASE
+          WHEN l.database = (
+            SELECT oid FROM pg_catalog.pg_database WHERE datname = pg_catalog.current_database()
+          ) THEN l.relation::regclass::text
+          ELSE l.relation::text
+        END, '')
+      FROM pg_catalog.pg_locks l
+      WHERE l.pid = a.pid AND NOT l.granted
+      LIMIT 1
+    ) AS waiting_for
+  FROM activity a
+  WHERE %s
+) s`
+
+// blockingCondition selects the sessions that are waiting for a lock or
+// that are holding a lock other sessions are waiting for
+const blockingCondition = "pg_catalog.cardinality(a.blocked_by) > 0 " +
+	"OR a.pid IN (SELECT pg_catalog.unnest(blocked_by) FROM activity)"
+
+// maxQueryTextLength is the length of the query text in the text output
+const maxQueryTextLength = 60
+
+// Session is a PostgreSQL backend involved in a lock conflict
+type Session struct {
+	PID                 int       `json:"pid"`
+	BlockedBy           []int     `json:"blocked_by,omitempty"`
+	Database            string    `json:"datname,omitempty"`
+	User                string    `json:"usename,omitempty"`
+	ApplicationName     string    `json:"application_name,omitempty"`
+	ClientAddress       string    `json:"client_addr,omitempty"`
+	BackendType         string    `json:"backend_type,omitempty"`
+	State               string    `json:"state,omitempty"`
+	WaitEventType       string    `json:"wait_event_type,omitempty"`
+	WaitEvent           string    `json:"wait_event,omitempty"`
+	WaitingFor          string    `json:"waiting_for,omitempty"`
+	Query               string    `json:"query,omitempty"`
+	QueryDuration       float64   `json:"query_duration_seconds,omitempty"`
+	TransactionDuration float64   `json:"transaction_duration_seconds,omitempty"`
+	Waiters             []Session `json:"waiters,omitempty"`
+}
+
+// LocksReport is the result of the "locks" command
+type LocksReport struct {
+	Cluster  string    `json:"cluster"`
+	Instance string    `json:"instance"`
+	Sessions []Session `json:"sessions"`
+}
+
+// SignalAction is the function used to act on a session
+type SignalAction string
+
+const (
+	// ActionCancel cancels the current query of the session
+	ActionCancel SignalAction = "pg_cancel_backend"
+
+	// ActionTerminate terminates the session
+	ActionTerminate SignalAction = "pg_terminate_backend"
+)
+
+// SignalOptions are the options to cancel or terminate a session
+type SignalOptions struct {
+	// Action is the function used to act on the session. No session
+	// is signalled when empty
+	Action SignalAction
+
+	// PID is the PID of the session to be signalled
+	PID int
+
+	// SkipConfirmation signals the session without asking for confirmation
+	SkipConfirmation bool
+
+	// Force allows signalling a session which is not part of the
+	// blocking tree
+	Force bool
+}
+
+// Locks displays the blocking tree of the primary instance of a cluster and,
+// when requested, cancels or terminates a session
+func Locks(
+	ctx context.Context,
+	clusterName string,
+	dbname string,
+	format plugin.OutputFormat,
+	timeout time.Duration,
+	options SignalOptions,
+) error {
+	var cluster apiv1.Cluster
+	if err := plugin.Client.Get(
+		ctx,
+		client.ObjectKey{Namespace: plugin.Namespace, Name: clusterName},
+		&cluster,
+	); err != nil {
+		return fmt.Errorf("while getting the cluster %s: %w", clusterName, err)
+	}
+	if cluster.Status.CurrentPrimary == "" {
+		return fmt.Errorf("the cluster %s has no primary instance", clusterName)
+	}
+
+	var pod corev1.Pod
+	if err := plugin.Client.Get(
+		ctx,
+		client.ObjectKey{Namespace: plugin.Namespace, Name: cluster.Status.CurrentPrimary},
+		&pod,
+	); err != nil {
+		return fmt.Errorf("while getting the primary instance %s: %w", cluster.Status.CurrentPrimary, err)
+	}
+
+	sessions, err := getSessions(ctx, pod, dbname, blockingCondition, timeout)
+	if err != nil {
+		return err
+	}
+
+	report := LocksReport{
+		Cluster:  clusterName,
+		Instance: pod.Name,
+		Sessions: buildBlockingTree(sessions),
+	}
+
+	// When the report is machine-readable, the other messages are
+	// written to the standard error
+	messages := io.Writer(os.Stdout)
+	if format != plugin.OutputFormatText {
+		messages = os.Stderr
+		if err := plugin.Print(report, format, os.Stdout); err != nil {
+			return err
+		}
+	} else {
+		printLocksReport(os.Stdout, &report)
+	}
+
+	if options.Action == "" {
+		return nil
+	}
+
+	if !options.Force && !isInBlockingTree(sessions, options.PID) {
+		return fmt.Errorf("the session %d is not part of the blocking tree of %s, "+
+			"use --force to signal it anyway", options.PID, pod.Name)
+	}
+
+	return signalSession(ctx, pod, dbname, timeout, options, messages)
+}
+
+// isInBlockingTree checks whether the session with the passed PID
+// is one of the collected sessions
+func isInBlockingTree(sessions []Session, pid int) bool {
+	return slices.ContainsFunc(sessions, func(session Session) bool {
+		return session.PID == pid
+	})
+}
+
+// signalSession cancels or terminates the session with the requested PID,
+// after asking for confirmation
+func signalSession(
+	ctx context.Context,
+	pod corev1.Pod,
+	dbname string,
+	timeout time.Duration,
+	options SignalOptions,
+	messages io.Writer,
+) error {
+	targets, err := getSessions(ctx, pod, dbname, fmt.Sprintf("a.pid = %d", options.PID), timeout)
+	if err != nil {
+		return err
+	}
+	if len(targets) == 0 {
+		return fmt.Errorf("no session with PID %d found in %s", options.PID, pod.Name)
+	}
+	target := &targets[0]
+
+	verb := "cancel the current query of"
+	if options.Action == ActionTerminate {
+		verb = "terminate"
+	}
+
+	if !options.SkipConfirmation {
+		_, _ = fmt.Fprintf(messages, "\nAbout to %s the session %d (application %q, user %q, database %q):\n%s\n",
+			verb, target.PID, target.ApplicationName, target.User, target.Database, target.Query)
+		if !askToProceed(messages, os.Stdin) {
+			return nil
+		}
+	}
+
+	stdout, err := plugin.ExecPsql(ctx, pod, dbname,
+		fmt.Sprintf("SELECT pg_catalog.%s(%d)", options.Action, options.PID), timeout)
+	if err != nil {
+		return err
+	}
+	if strings.TrimSpace(stdout) != "t" {
+		return fmt.Errorf("unable to %s the session %d", verb, options.PID)
+	}
+
+	if options.Action == ActionTerminate {
+		_, _ = fmt.Fprintf(messages, "Session %d terminated\n", options.PID)
+	} else {
+		_, _ = fmt.Fprintf(messages, "Query of the session %d cancelled\n", options.PID)
+	}
+	return nil
+}
+
+func askToProceed(writer io.Writer, reader io.Reader) bool {
+	_, _ = fmt.Fprintf(writer, "Do you want to proceed? [y/n]: ")
+	answer, err := bufio.NewReader(reader).ReadString('\n')
+	if err != nil {
+		return false
+	}
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes"
+}
+
+func getSessions(
+	ctx context.Context,
+	pod corev1.Pod,
+	dbname string,
+	condition string,
+	timeout time.Duration,
+) ([]Session, error) {
+	stdout, err := plugin.ExecPsql(ctx, pod, dbname, fmt.Sprintf(sessionsQuery, condition), timeout)
+	if err != nil {
+		return nil, err
+	}
+
+	var sessions []Session
+	if err := json.Unmarshal([]byte(stdout), &sessions); err != nil {
+		return nil, fmt.Errorf("while decoding the sessions: %w", err)
+	}
+	return sessions, nil
+}
+
+// buildBlockingTree arranges the sessions so that every session holding a
+// lock contains the sessions waiting for it. The roots are the sessions
+// that are not waiting for any other session in the list. Sessions waiting
+// for more than one session are reported under the first one, and sessions
+// in a lock cycle are reported as roots.
+func buildBlockingTree(sessions []Session) []Session {
+	slices.SortFunc(sessions, func(a, b Session) int {
+		return a.PID - b.PID
+	})
+
+	index := make(map[int]int, len(sessions))
+	for i := range sessions {
+		index[sessions[i].PID] = i
+	}
+
+	waiters := make(map[int][]int)
+	isRoot := make([]bool, len(sessions))
+	for i := range sessions {
+		isRoot[i] = true
+		for _, blocker := range sessions[i].BlockedBy {
+			if _, ok := index[blocker]; ok && blocker != sessions[i].PID {
+				waiters[blocker] = append(waiters[blocker], i)
+				isRoot[i] = false
+			}
+		}
+	}
+
+	visited := make([]bool, len(sessions))
+	var build func(i int) Session
+	build = func(i int) Session {
+		visited[i] = true
+		session := sessions[i]
+		session.Waiters = nil
+		for _, waiter := range waiters[session.PID] {
+			if !visited[waiter] {
+				session.Waiters = append(session.Waiters, build(waiter))
+			}
+		}
+		return session
+	}
+
+	result := make([]Session, 0)
+	for i := range sessions {
+		if isRoot[i] {
+			result = append(result, build(i))
+		}
+	}
+	for i := range sessions {
+		if !visited[i] {
+			result = append(result, build(i))
+		}
+	}
+	return result
+}
+
+func printLocksReport(writer io.Writer, report *LocksReport) {
+	if len(report.Sessions) == 0 {
+		_, _ = fmt.Fprintf(writer, "No blocked sessions found in %s\n", report.Instance)
+		return
+	}
+
+	_, _ = fmt.Fprintf(writer, "Blocking sessions in %s\n\n", report.Instance)
+
+	table := tabby.NewCustom(tabwriter.NewWriter(writer, 0, 0, 2, ' ', 0))
+	table.AddHeader("PID", "Blocked By", "Application", "User", "Database", "State",
+		"Wait Event", "Waiting For", "Xact Duration", "Query Duration", "Query")
+
+	var addLines func(sessions []Session, depth int)
+	addLines = func(sessions []Session, depth int) {
+		for i := range sessions {
+			session := &sessions[i]
+			pid := strconv.Itoa(session.PID)
+			if depth > 0 {
+				pid = strings.Repeat("   ", depth-1) + "└─ " + pid
+			}
+
+			blockedBy := make([]string, len(session.BlockedBy))
+			for j, blocker := range session.BlockedBy {
+				blockedBy[j] = strconv.Itoa(blocker)
+			}
+
+			waitEvent := session.WaitEvent
+			if session.WaitEventType != "" {
+				waitEvent = session.WaitEventType + ":" + session.WaitEvent
+			}
+
+			table.AddLine(
+				pid,
+				strings.Join(blockedBy, ","),
+				session.ApplicationName,
+				session.User,
+				session.Database,
+				session.State,
+				waitEvent,
+				session.WaitingFor,
+				formatSeconds(session.TransactionDuration),
+				formatSeconds(session.QueryDuration),
+				plugin.ShortenQueryText(session.Query, maxQueryTextLength),
+			)
+			addLines(session.Waiters, depth+1)
+		}
+	}
+	addLines(report.Sessions, 0)
+
+	table.Print()
+}
+
+// formatSeconds displays an amount of seconds as a duration
+func formatSeconds(value float64) string {
+	if value <= 0 {
+		return ""
+	}
+
+	duration := time.Duration(value * float64(time.Second))
+	if duration >= time.Minute {
+		return duration.Round(time.Second).String()
+	}
+	return duration.Round(time.Millisecond).String()
+}
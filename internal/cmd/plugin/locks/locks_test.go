@@ -0,0 +1,172 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package locks
+
+import (
+	"bytes"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("buildBlockingTree", func() {
+	pids := func(sessions []Session) []int {
+		result := make([]int, len(sessions))
+		for i := range sessions {
+			result[i] = sessions[i].PID
+		}
+		return result
+	}
+
+	It("returns an empty tree when there are no sessions", func() {
+		Expect(buildBlockingTree(nil)).To(BeEmpty())
+	})
+
+	It("nests the waiters under the sessions blocking them", func() {
+		tree := buildBlockingTree([]Session{
+			{PID: 300, BlockedBy: []int{200}},
+			{PID: 100},
+			{PID: 200, BlockedBy: []int{100}},
+			{PID: 150, BlockedBy: []int{100}},
+		})
+
+		Expect(pids(tree)).To(Equal([]int{100}))
+		Expect(pids(tree[0].Waiters)).To(Equal([]int{150, 200}))
+		Expect(pids(tree[0].Waiters[1].Waiters)).To(Equal([]int{300}))
+	})
+
+	It("reports a session waiting for more sessions only once", func() {
+		tree := buildBlockingTree([]Session{
+			{PID: 100},
+			{PID: 101},
+			{PID: 200, BlockedBy: []int{100, 101}},
+		})
+
+		Expect(pids(tree)).To(Equal([]int{100, 101}))
+		Expect(pids(tree[0].Waiters)).To(Equal([]int{200}))
+		Expect(tree[1].Waiters).To(BeEmpty())
+	})
+
+	It("reports the sessions waiting for an unknown session as roots", func() {
+		tree := buildBlockingTree([]Session{
+			{PID: 200, BlockedBy: []int{0}},
+		})
+		Expect(pids(tree)).To(Equal([]int{200}))
+	})
+
+	It("reports the sessions in a lock cycle", func() {
+		tree := buildBlockingTree([]Session{
+			{PID: 100, BlockedBy: []int{200}},
+			{PID: 200, BlockedBy: []int{100}},
+		})
+
+		Expect(pids(tree)).To(Equal([]int{100}))
+		Expect(pids(tree[0].Waiters)).To(Equal([]int{200}))
+		Expect(tree[0].Waiters[0].Waiters).To(BeEmpty())
+	})
+})
+
+var _ = Describe("locks text output", func() {
+	It("reports when there are no blocked sessions", func() {
+		var buffer bytes.Buffer
+		printLocksReport(&buffer, &LocksReport{Instance: "cluster-example-1"})
+		Expect(buffer.String()).To(Equal("No blocked sessions found in cluster-example-1\n"))
+	})
+
+	It("prints the blocking tree", func() {
+		var buffer bytes.Buffer
+		printLocksReport(&buffer, &LocksReport{
+			Instance: "cluster-example-1",
+			Sessions: []Session{
+				{
+					PID:                 100,
+					ApplicationName:     "psql",
+					State:               "idle in transaction",
+					WaitEventType:       "Client",
+					WaitEvent:           "ClientRead",
+					Query:               "LOCK TABLE orders",
+					TransactionDuration: 125.2,
+					Waiters: []Session{
+						{
+							PID:           200,
+							BlockedBy:     []int{100},
+							State:         "active",
+							WaitEventType: "Lock",
+							WaitEvent:     "relation",
+							WaitingFor:    "AccessShareLock on relation orders",
+							Query:         "SELECT * FROM orders",
+							QueryDuration: 1.5,
+						},
+					},
+				},
+			},
+		})
+
+		lines := strings.Split(buffer.String(), "\n")
+		Expect(lines[0]).To(Equal("Blocking sessions in cluster-example-1"))
+		Expect(lines[4]).To(HavePrefix("100 "))
+		Expect(lines[4]).To(ContainSubstring("Client:ClientRead"))
+		Expect(lines[4]).To(ContainSubstring("2m5s"))
+		Expect(lines[5]).To(HavePrefix("└─ 200 "))
+		Expect(lines[5]).To(ContainSubstring("AccessShareLock on relation orders"))
+		Expect(lines[5]).To(ContainSubstring("1.5s"))
+	})
+
+	DescribeTable("formatSeconds",
+		func(value float64, expected string) {
+			Expect(formatSeconds(value)).To(Equal(expected))
+		},
+		Entry("unknown", 0.0, ""),
+		Entry("seconds", 1.23456, "1.235s"),
+		Entry("minutes", 3725.4, "1h2m5s"),
+	)
+})
+
+var _ = Describe("askToProceed", func() {
+	DescribeTable("parses the answer",
+		func(answer string, expected bool) {
+			var output bytes.Buffer
+			Expect(askToProceed(&output, strings.NewReader(answer))).To(Equal(expected))
+			Expect(output.String()).To(ContainSubstring("[y/n]"))
+		},
+		Entry("yes", "yes\n", true),
+		Entry("y", "Y\n", true),
+		Entry("no", "n\n", false),
+		Entry("no answer", "", false),
+	)
+})
+
+var _ = Describe("isInBlockingTree", func() {
+	sessions := []Session{
+		{PID: 10},
+		{PID: 20, BlockedBy: []int{10}},
+	}
+
+	It("accepts the sessions of the blocking tree", func() {
+		Expect(isInBlockingTree(sessions, 10)).To(BeTrue())
+		Expect(isInBlockingTree(sessions, 20)).To(BeTrue())
+	})
+
+	It("rejects the other sessions", func() {
+		Expect(isInBlockingTree(sessions, 30)).To(BeFalse())
+		Expect(isInBlockingTree(nil, 10)).To(BeFalse())
+	})
+})
@@ -171,6 +171,52 @@ func GetPGControlData(
 	return stdout, nil
 }
 
+// ExecPsql runs a query with psql inside the passed instance Pod, connecting
+// to the given database as the postgres user, and returns the output
+// in unaligned, tuples-only format.
+// This approach should be used only in the plugin commands.
+func ExecPsql(
+	ctx context.Context,
+	pod corev1.Pod,
+	dbname string,
+	query string,
+	timeout time.Duration,
+) (string, error) {
+	stdout, stderr, err := utils.ExecCommand(
+		ctx,
+		ClientInterface,
+		Config,
+		pod,
+		specs.PostgresContainerName,
+		&timeout,
+		"psql",
+		"-U", "postgres",
+		"-d", dbname,
+		"-v", "ON_ERROR_STOP=1",
+		"-X", "-A", "-t", "-q",
+		"-c", query,
+	)
+	if err != nil {
+		if stderr = strings.TrimSpace(stderr); stderr != "" {
+			return "", fmt.Errorf("%w: %s", err, stderr)
+		}
+		return "", err
+	}
+
+	return stdout, nil
+}
+
+// ShortenQueryText collapses the whitespace in the text of a query and
+// truncates it to the passed number of characters, to be printed in the
+// output of the plugin commands
+func ShortenQueryText(query string, length int) string {
+	query = strings.Join(strings.Fields(query), " ")
+	if runes := []rune(query); len(runes) > length {
+		return string(runes[:length-3]) + "..."
+	}
+	return query
+}
+
 // completeClusters is mainly used inside the unit tests
 func completeClusters(
 	ctx context.Context,
@@ -94,3 +94,10 @@ var _ = Describe("CompleteClusters testing", func() {
 		Expect(result).To(BeEmpty())
 	})
 })
+
+var _ = Describe("ShortenQueryText", func() {
+	It("collapses and truncates the query text", func() {
+		Expect(ShortenQueryText("SELECT *\n  FROM   orders", 80)).To(Equal("SELECT * FROM orders"))
+		Expect(ShortenQueryText("SELECT * FROM orders WHERE id = $1", 15)).To(Equal("SELECT * FRO..."))
+	})
+})
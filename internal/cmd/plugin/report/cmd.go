@@ -28,13 +28,14 @@ import (
 // NewCmd creates the new "report" command
 func NewCmd() *cobra.Command {
 	reportCmd := &cobra.Command{
-		Use:     "report operator/cluster",
+		Use:     "report operator/cluster/vacuum",
 		Short:   "Report on the operator or a cluster for troubleshooting",
 		GroupID: plugin.GroupIDTroubleshooting,
 	}
 
 	reportCmd.AddCommand(operatorCmd())
 	reportCmd.AddCommand(clusterCmd())
+	reportCmd.AddCommand(vacuumCmd())
 
 	return reportCmd
 }
@@ -0,0 +1,69 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package report
+
+import (
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin"
+)
+
+func vacuumCmd() *cobra.Command {
+	var (
+		file, output string
+		limit        int
+		timeout      time.Duration
+	)
+
+	const filePlaceholder = "report_vacuum_<name>_<timestamp>.zip"
+	cmd := &cobra.Command{
+		Use:   "vacuum CLUSTER",
+		Short: "Report table bloat, vacuum activity and transaction ID wraparound risks",
+		Long: "Collects the vacuum statistics of the tables, the transaction ID age of the databases " +
+			"and the sessions holding back the freeze horizon from the primary instance in a Zip file, " +
+			"and prints a summary",
+		Args: plugin.RequiresArguments(1),
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return plugin.CompleteClusters(cmd.Context(), args, toComplete), cobra.ShellCompDirectiveNoFileComp
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clusterName := args[0]
+			now := time.Now().UTC()
+			if file == filePlaceholder {
+				file = reportName("vacuum", now, clusterName) + ".zip"
+			}
+			return vacuum(cmd.Context(), clusterName, plugin.Namespace,
+				plugin.OutputFormat(output), file, limit, timeout, now)
+		},
+	}
+
+	cmd.Flags().StringVarP(&file, "file", "f", filePlaceholder,
+		"Output file")
+	cmd.Flags().StringVarP(&output, "output", "o", "json",
+		"Output format for the collected data (json or yaml)")
+	cmd.Flags().IntVar(&limit, "limit", 10,
+		"Maximum number of tables to display in the summary")
+	cmd.Flags().DurationVarP(&timeout, "timeout", "t", 30*time.Second,
+		"Timeout for the queries run in the primary instance")
+
+	return cmd
+}
@@ -0,0 +1,442 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package report
+
+import (
+	"archive/zip"
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/cheynewallace/tabby"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin"
+)
+
+// xidWraparoundLimit is the transaction ID age at which PostgreSQL stops
+// assigning new transaction IDs to prevent a wraparound
+const xidWraparoundLimit = 1 << 31
+
+// vacuumDatabasesQuery extracts the transaction ID age of the databases
+const vacuumDatabasesQuery = `SELECT COALESCE(json_agg(row_to_json(d) ORDER BY d.xid_age DESC), '[]')
+FROM (
+  SELECT datname, pg_catalog.age(datfrozenxid) AS xid_age,
+    pg_catalog.mxid_age(datminmxid) AS mxid_age,
+    datallowconn AND NOT datistemplate AS collected
+  FROM pg_catalog.pg_database
+) d`
+
+// vacuumSettingsQuery extracts the settings driving the anti-wraparound
+// vacuum
+const vacuumSettingsQuery = `SELECT pg_catalog.json_build_object(
+  'autovacuum', pg_catalog.current_setting('autovacuum')::bool,
+  'autovacuum_freeze_max_age', pg_catalog.current_setting('autovacuum_freeze_max_age')::bigint,
+  'autovacuum_multixact_freeze_max_age', pg_catalog.current_setting('autovacuum_multixact_freeze_max_age')::bigint
+)`
+
+// freezeHorizonBlockersQuery extracts whatever is preventing VACUUM from
+// freezing or removing the old row versions: replication slots, prepared
+// transactions, the oldest sessions, and the standbys using
+// hot_standby_feedback
+const freezeHorizonBlockersQuery = `SELECT COALESCE(json_agg(row_to_json(b) ORDER BY b.xid_age DESC), '[]')
+FROM (
+  SELECT 'replication_slot' AS kind, slot_name::text AS name, database::text AS datname,
+    GREATEST(pg_catalog.age(xmin), pg_catalog.age(catalog_xmin)) AS xid_age,
+    NULL::timestamptz AS since,
+    CASE WHEN active THEN 'active ' ELSE 'inactive ' END || slot_type || ' slot' AS details
+  FROM pg_catalog.pg_replication_slots
+  WHERE xmin IS NOT NULL OR catalog_xmin IS NOT NULL
+  UNION ALL
+  SELECT 'prepared_transaction', gid, database::text, pg_catalog.age(transaction), prepared,
+    'owned by ' || owner::text
+  FROM pg_catalog.pg_prepared_xacts
+  UNION ALL
+  SELECT * FROM (
+    SELECT 'session', pid::text, datname::text, pg_catalog.age(backend_xmin), xact_start,
+      COALESCE(state, '') || ', user ' || COALESCE(usename::text, '') ||
+      ', application ' || COALESCE(application_name, '')
+    FROM pg_catalog.pg_stat_activity
+    WHERE backend_xmin IS NOT NULL AND backend_type = 'client backend'
+      AND pid <> pg_catalog.pg_backend_pid()
+    ORDER BY pg_catalog.age(backend_xmin) DESC
+    LIMIT 10
+  ) s
+  UNION ALL
+  SELECT 'hot_standby_feedback', application_name, NULL, pg_catalog.age(backend_xmin), NULL,
+    'standby ' || COALESCE(client_addr::text, 'local') || ', ' || state
+  FROM pg_catalog.pg_stat_replication
+  WHERE backend_xmin IS NOT NULL
+) b`
+
+// vacuumTablesQuery extracts the vacuum statistics of the tables in the
+// current database. The bloat is estimated by comparing the number of pages
+// of the table with the ones needed to store its live tuples, using the
+// average tuple width computed by ANALYZE, a 24 bytes tuple header and a
+// 4 bytes line pointer, and ignoring the alignment padding.
+const vacuumTablesQuery = `SELECT COALESCE(json_agg(row_to_json(t)), '[]')
+FROM (
+  SELECT pg_catalog.current_database() AS datname, s.schemaname, s.relname,
+    s.n_live_tup, s.n_dead_tup,
+    CASE WHEN s.n_live_tup + s.n_dead_tup > 0
+      THEN s.n_dead_tup::float8 / (s.n_live_tup + s.n_dead_tup)
+      ELSE 0
+    END AS dead_tuple_ratio,
+    s.last_vacuum, s.last_autovacuum, s.last_analyze, s.last_autoanalyze,
+    s.vacuum_count, s.autovacuum_count, s.analyze_count, s.autoanalyze_count,
+    pg_catalog.age(c.relfrozenxid) AS xid_age,
+    pg_catalog.pg_table_size(c.oid) AS table_size,
+    CASE WHEN w.tuple_width IS NOT NULL THEN
+      GREATEST(0, c.relpages - CEIL(
+        GREATEST(c.reltuples, 0) * (w.tuple_width + 28) /
+        ((pg_catalog.current_setting('block_size')::int - 24) * COALESCE(f.fillfactor, 100) / 100.0)
+      ))::bigint * pg_catalog.current_setting('block_size')::bigint
+    END AS estimated_bloat
+  FROM pg_catalog.pg_stat_user_tables s
+  JOIN pg_catalog.pg_class c ON c.oid = s.relid
+  LEFT JOIN LATERAL (
+    SELECT option_value::int AS fillfactor
+    FROM pg_catalog.pg_options_to_table(c.reloptions)
+    WHERE option_name = 'fillfactor'
+  ) f ON true
+  LEFT JOIN LATERAL (
+    SELECT SUM((1 - st.null_frac) * st.avg_width) AS tuple_width
+    FROM pg_catalog.pg_stats st
+    WHERE st.schemaname = s.schemaname AND st.tablename = s.relname AND NOT st.inherited
+  ) w ON true
+) t`
+
+// vacuumSettings are the settings driving the anti-wraparound vacuum
+type vacuumSettings struct {
+	Autovacuum                      bool  `json:"autovacuum"`
+	AutovacuumFreezeMaxAge          int64 `json:"autovacuum_freeze_max_age"`
+	AutovacuumMultixactFreezeMaxAge int64 `json:"autovacuum_multixact_freeze_max_age"`
+}
+
+// databaseVacuumStatus is the transaction ID age of a database
+type databaseVacuumStatus struct {
+	Name      string `json:"datname"`
+	XIDAge    int64  `json:"xid_age"`
+	MXIDAge   int64  `json:"mxid_age"`
+	Collected bool   `json:"collected"`
+}
+
+// freezeHorizonBlocker is something preventing VACUUM from removing the
+// dead tuples and freezing the old ones
+type freezeHorizonBlocker struct {
+	Kind     string     `json:"kind"`
+	Name     string     `json:"name"`
+	Database string     `json:"datname,omitempty"`
+	XIDAge   int64      `json:"xid_age"`
+	Since    *time.Time `json:"since,omitempty"`
+	Details  string     `json:"details,omitempty"`
+}
+
+// tableVacuumStatus contains the vacuum statistics of a table
+type tableVacuumStatus struct {
+	Database         string     `json:"datname"`
+	Schema           string     `json:"schemaname"`
+	Name             string     `json:"relname"`
+	LiveTuples       int64      `json:"n_live_tup"`
+	DeadTuples       int64      `json:"n_dead_tup"`
+	DeadTupleRatio   float64    `json:"dead_tuple_ratio"`
+	LastVacuum       *time.Time `json:"last_vacuum,omitempty"`
+	LastAutovacuum   *time.Time `json:"last_autovacuum,omitempty"`
+	LastAnalyze      *time.Time `json:"last_analyze,omitempty"`
+	LastAutoanalyze  *time.Time `json:"last_autoanalyze,omitempty"`
+	VacuumCount      int64      `json:"vacuum_count"`
+	AutovacuumCount  int64      `json:"autovacuum_count"`
+	AnalyzeCount     int64      `json:"analyze_count"`
+	AutoanalyzeCount int64      `json:"autoanalyze_count"`
+	XIDAge           int64      `json:"xid_age"`
+	TableSize        int64      `json:"table_size"`
+	EstimatedBloat   *int64     `json:"estimated_bloat,omitempty"`
+}
+
+// lastVacuum is the time of the latest manual or automatic vacuum
+func (t *tableVacuumStatus) lastVacuum() *time.Time {
+	switch {
+	case t.LastVacuum == nil:
+		return t.LastAutovacuum
+	case t.LastAutovacuum == nil || t.LastVacuum.After(*t.LastAutovacuum):
+		return t.LastVacuum
+	default:
+		return t.LastAutovacuum
+	}
+}
+
+// vacuumReport contains the data collected by the `report vacuum` plugin
+type vacuumReport struct {
+	instance              string
+	settings              vacuumSettings
+	databases             []databaseVacuumStatus
+	freezeHorizonBlockers []freezeHorizonBlocker
+	tables                []tableVacuumStatus
+	errors                []string
+}
+
+// writeToZip makes a new section in the ZIP file with the collected data
+func (vr vacuumReport) writeToZip(zipper *zip.Writer, format plugin.OutputFormat, folder string) error {
+	objects := []struct {
+		content interface{}
+		name    string
+	}{
+		{content: vr.settings, name: "settings"},
+		{content: vr.databases, name: "databases"},
+		{content: vr.freezeHorizonBlockers, name: "freeze-horizon-blockers"},
+		{content: vr.tables, name: "tables"},
+	}
+	if len(vr.errors) > 0 {
+		objects = append(objects, struct {
+			content interface{}
+			name    string
+		}{content: vr.errors, name: "errors"})
+	}
+
+	newFolder := filepath.Join(folder, "vacuum")
+	_, err := zipper.Create(newFolder + "/")
+	if err != nil {
+		return err
+	}
+
+	for _, object := range objects {
+		err := addContentToZip(object.content, object.name, newFolder, format, zipper)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// vacuum implements the "report vacuum" subcommand
+// Produces a zip file containing, from the primary instance
+//   - the transaction ID age of each database
+//   - the replication slots, prepared transactions, sessions and standbys
+//     holding back the freeze horizon
+//   - the vacuum statistics and estimated bloat of the tables in each database
+//
+// and prints a summary of them
+func vacuum(ctx context.Context, clusterName, namespace string, format plugin.OutputFormat,
+	file string, limit int, timeout time.Duration, timestamp time.Time,
+) error {
+	var cluster apiv1.Cluster
+	err := plugin.Client.Get(ctx,
+		types.NamespacedName{Namespace: namespace, Name: clusterName},
+		&cluster)
+	if err != nil {
+		return fmt.Errorf("could not get cluster: %w", err)
+	}
+	if cluster.Status.CurrentPrimary == "" {
+		return fmt.Errorf("the cluster has no primary instance")
+	}
+
+	var pod corev1.Pod
+	err = plugin.Client.Get(ctx,
+		types.NamespacedName{Namespace: namespace, Name: cluster.Status.CurrentPrimary},
+		&pod)
+	if err != nil {
+		return fmt.Errorf("could not get the primary pod: %w", err)
+	}
+
+	rep, err := collectVacuumReport(ctx, pod, timeout)
+	if err != nil {
+		return err
+	}
+
+	reportZipper := func(zipper *zip.Writer, dirname string) error {
+		return rep.writeToZip(zipper, format, dirname)
+	}
+
+	err = writeZippedReport([]zipFileWriter{reportZipper}, file, reportName("vacuum", timestamp, clusterName))
+	if err != nil {
+		return fmt.Errorf("could not write report: %w", err)
+	}
+
+	rep.printSummary(os.Stdout, limit, timestamp)
+	fmt.Printf("Successfully written report to \"%s\" (format: \"%s\")\n", file, format)
+
+	return nil
+}
+
+// collectVacuumReport runs the vacuum queries in the passed instance. The
+// tables of the databases that cannot be queried are reported as errors.
+func collectVacuumReport(ctx context.Context, pod corev1.Pod, timeout time.Duration) (*vacuumReport, error) {
+	rep := &vacuumReport{instance: pod.Name}
+
+	if err := queryJSON(ctx, pod, "postgres", vacuumSettingsQuery, timeout, &rep.settings); err != nil {
+		return nil, fmt.Errorf("could not get the vacuum settings: %w", err)
+	}
+	if err := queryJSON(ctx, pod, "postgres", vacuumDatabasesQuery, timeout, &rep.databases); err != nil {
+		return nil, fmt.Errorf("could not get the databases: %w", err)
+	}
+	if err := queryJSON(ctx, pod, "postgres", freezeHorizonBlockersQuery, timeout,
+		&rep.freezeHorizonBlockers); err != nil {
+		return nil, fmt.Errorf("could not get the freeze horizon blockers: %w", err)
+	}
+
+	for _, database := range rep.databases {
+		if !database.Collected {
+			continue
+		}
+
+		var tables []tableVacuumStatus
+		if err := queryJSON(ctx, pod, database.Name, vacuumTablesQuery, timeout, &tables); err != nil {
+			logWarning(fmt.Sprintf("could not get the tables of database %s", database.Name), err,
+				"Continuing with the other databases.")
+			rep.errors = append(rep.errors, fmt.Sprintf("database %s: %v", database.Name, err))
+			continue
+		}
+		rep.tables = append(rep.tables, tables...)
+	}
+
+	slices.SortStableFunc(rep.tables, func(a, b tableVacuumStatus) int {
+		return cmp.Compare(b.DeadTuples, a.DeadTuples)
+	})
+
+	return rep, nil
+}
+
+// queryJSON runs a query returning a JSON document and decodes it
+func queryJSON(
+	ctx context.Context,
+	pod corev1.Pod,
+	dbname string,
+	query string,
+	timeout time.Duration,
+	target interface{},
+) error {
+	stdout, err := plugin.ExecPsql(ctx, pod, dbname, query, timeout)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal([]byte(stdout), target)
+}
+
+// printSummary prints the databases closer to the transaction ID
+// wraparound, the freeze horizon blockers and the tables with more dead
+// tuples
+func (vr vacuumReport) printSummary(writer io.Writer, limit int, now time.Time) {
+	newTable := func() *tabby.Tabby {
+		return tabby.NewCustom(tabwriter.NewWriter(writer, 0, 0, 2, ' ', 0))
+	}
+
+	_, _ = fmt.Fprintf(writer, "Transaction ID age of the databases in %s (autovacuum_freeze_max_age: %d)\n",
+		vr.instance, vr.settings.AutovacuumFreezeMaxAge)
+	if !vr.settings.Autovacuum {
+		_, _ = fmt.Fprintln(writer, "WARNING: autovacuum is disabled")
+	}
+	databases := newTable()
+	databases.AddHeader("Database", "XID Age", "Wraparound", "MXID Age")
+	for _, database := range vr.databases {
+		databases.AddLine(database.Name, database.XIDAge,
+			formatRatio(float64(database.XIDAge)/xidWraparoundLimit), database.MXIDAge)
+	}
+	databases.Print()
+	_, _ = fmt.Fprintln(writer)
+
+	if len(vr.freezeHorizonBlockers) > 0 {
+		_, _ = fmt.Fprintln(writer, "Freeze horizon blockers")
+		blockers := newTable()
+		blockers.AddHeader("Kind", "Name", "Database", "XID Age", "Since", "Details")
+		for _, blocker := range vr.freezeHorizonBlockers {
+			since := "-"
+			if blocker.Since != nil {
+				since = formatElapsedTime(blocker.Since, now)
+			}
+			blockers.AddLine(blocker.Kind, blocker.Name, blocker.Database, blocker.XIDAge, since, blocker.Details)
+		}
+		blockers.Print()
+		_, _ = fmt.Fprintln(writer)
+	}
+
+	shown := vr.tables
+	if limit > 0 && len(shown) > limit {
+		shown = shown[:limit]
+	}
+	_, _ = fmt.Fprintf(writer, "Tables with the most dead tuples (%d of %d)\n", len(shown), len(vr.tables))
+	tables := newTable()
+	tables.AddHeader("Database", "Table", "Dead Tuples", "Dead Ratio", "Size", "Est. Bloat",
+		"Last Vacuum", "Last Analyze", "XID Age")
+	for i := range shown {
+		table := &shown[i]
+		lastAnalyze := table.LastAnalyze
+		if lastAnalyze == nil || (table.LastAutoanalyze != nil && table.LastAutoanalyze.After(*lastAnalyze)) {
+			lastAnalyze = table.LastAutoanalyze
+		}
+		tables.AddLine(
+			table.Database,
+			table.Schema+"."+table.Name,
+			table.DeadTuples,
+			formatRatio(table.DeadTupleRatio),
+			formatBytes(&table.TableSize),
+			formatBytes(table.EstimatedBloat),
+			formatElapsedTime(table.lastVacuum(), now),
+			formatElapsedTime(lastAnalyze, now),
+			table.XIDAge,
+		)
+	}
+	tables.Print()
+	_, _ = fmt.Fprintln(writer)
+
+	for _, err := range vr.errors {
+		_, _ = fmt.Fprintf(writer, "ERROR: %s\n", err)
+	}
+}
+
+func formatRatio(value float64) string {
+	return strconv.FormatFloat(value*100, 'f', 1, 64) + "%"
+}
+
+func formatBytes(value *int64) string {
+	if value == nil {
+		return "-"
+	}
+
+	const unit = 1024
+	if *value < unit {
+		return strconv.FormatInt(*value, 10) + " B"
+	}
+	size := float64(*value)
+	exponent := 0
+	for size >= unit && exponent < 5 {
+		size /= unit
+		exponent++
+	}
+	return strconv.FormatFloat(size, 'f', 1, 64) + " " + string("KMGTP"[exponent-1]) + "iB"
+}
+
+func formatElapsedTime(value *time.Time, now time.Time) string {
+	if value == nil {
+		return "never"
+	}
+	return now.Sub(*value).Round(time.Second).String() + " ago"
+}
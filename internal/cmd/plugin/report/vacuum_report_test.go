@@ -0,0 +1,140 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package report
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"time"
+
+	"k8s.io/utils/ptr"
+
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("vacuum report", func() {
+	now := time.Date(2025, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	It("decodes the tables statistics produced by PostgreSQL", func() {
+		var tables []tableVacuumStatus
+		err := json.Unmarshal([]byte(`[{"datname":"app","schemaname":"public","relname":"orders",`+
+			`"n_live_tup":1000,"n_dead_tup":250,"dead_tuple_ratio":0.2,`+
+			`"last_vacuum":null,"last_autovacuum":"2025-05-01T11:30:00.123456+00:00",`+
+			`"last_analyze":null,"last_autoanalyze":null,"vacuum_count":0,"autovacuum_count":3,`+
+			`"analyze_count":0,"autoanalyze_count":1,"xid_age":1200,"table_size":8192,"estimated_bloat":null}]`),
+			&tables)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(tables).To(HaveLen(1))
+		Expect(tables[0].DeadTuples).To(BeEquivalentTo(250))
+		Expect(tables[0].EstimatedBloat).To(BeNil())
+		Expect(tables[0].lastVacuum()).ToNot(BeNil())
+		Expect(formatElapsedTime(tables[0].lastVacuum(), now)).To(Equal("30m0s ago"))
+	})
+
+	DescribeTable("lastVacuum",
+		func(manual, automatic *time.Time, expected *time.Time) {
+			table := tableVacuumStatus{LastVacuum: manual, LastAutovacuum: automatic}
+			Expect(table.lastVacuum()).To(Equal(expected))
+		},
+		Entry("never vacuumed", nil, nil, nil),
+		Entry("manual vacuum only", &now, nil, &now),
+		Entry("autovacuum only", nil, &now, &now),
+		Entry("latest is the autovacuum", ptr.To(now.Add(-time.Hour)), &now, &now),
+		Entry("latest is the manual vacuum", &now, ptr.To(now.Add(-time.Hour)), &now),
+	)
+
+	DescribeTable("formatBytes",
+		func(value *int64, expected string) {
+			Expect(formatBytes(value)).To(Equal(expected))
+		},
+		Entry("unknown", nil, "-"),
+		Entry("bytes", ptr.To(int64(512)), "512 B"),
+		Entry("kibibytes", ptr.To(int64(8192)), "8.0 KiB"),
+		Entry("gibibytes", ptr.To(int64(3<<30+1<<29)), "3.5 GiB"),
+	)
+
+	It("formats the ratios as percentages", func() {
+		Expect(formatRatio(0.1234)).To(Equal("12.3%"))
+	})
+
+	Context("with collected data", func() {
+		var rep vacuumReport
+
+		BeforeEach(func() {
+			rep = vacuumReport{
+				instance: "cluster-example-1",
+				settings: vacuumSettings{Autovacuum: true, AutovacuumFreezeMaxAge: 200000000},
+				databases: []databaseVacuumStatus{
+					{Name: "app", XIDAge: 214748365, MXIDAge: 10, Collected: true},
+				},
+				freezeHorizonBlockers: []freezeHorizonBlocker{
+					{Kind: "replication_slot", Name: "old_slot", XIDAge: 1000000, Details: "inactive physical slot"},
+				},
+				tables: []tableVacuumStatus{
+					{Database: "app", Schema: "public", Name: "orders", DeadTuples: 250, DeadTupleRatio: 0.2},
+					{Database: "app", Schema: "public", Name: "customers", DeadTuples: 10, DeadTupleRatio: 0.01},
+				},
+			}
+		})
+
+		It("prints the summary", func() {
+			var buffer bytes.Buffer
+			rep.printSummary(&buffer, 1, now)
+
+			output := buffer.String()
+			Expect(output).To(ContainSubstring("autovacuum_freeze_max_age: 200000000"))
+			Expect(output).To(ContainSubstring("10.0%"))
+			Expect(output).To(ContainSubstring("old_slot"))
+			Expect(output).To(ContainSubstring("Tables with the most dead tuples (1 of 2)"))
+			Expect(output).To(ContainSubstring("public.orders"))
+			Expect(output).ToNot(ContainSubstring("public.customers"))
+			Expect(output).ToNot(ContainSubstring("autovacuum is disabled"))
+		})
+
+		It("writes the collected data in the zip file", func() {
+			rep.errors = []string{"database other: connection refused"}
+
+			var buffer bytes.Buffer
+			zipper := zip.NewWriter(&buffer)
+			Expect(rep.writeToZip(zipper, plugin.OutputFormatJSON, "report")).To(Succeed())
+			Expect(zipper.Close()).To(Succeed())
+
+			reader, err := zip.NewReader(bytes.NewReader(buffer.Bytes()), int64(buffer.Len()))
+			Expect(err).ToNot(HaveOccurred())
+
+			names := make([]string, 0, len(reader.File))
+			for _, file := range reader.File {
+				names = append(names, file.Name)
+			}
+			Expect(names).To(ConsistOf(
+				"report/vacuum/",
+				"report/vacuum/settings.json",
+				"report/vacuum/databases.json",
+				"report/vacuum/freeze-horizon-blockers.json",
+				"report/vacuum/tables.json",
+				"report/vacuum/errors.json",
+			))
+		})
+	})
+})
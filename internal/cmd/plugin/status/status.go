@@ -930,6 +930,9 @@ func (fullStatus *PostgresqlStatus) printCertificatesStatus() {
 
 	fmt.Println(color("Certificates Status"))
 	status.Print()
+	if rotation := fullStatus.Cluster.Status.Certificates.CARotation; rotation.IsInProgress() {
+		fmt.Printf("CA rotation in progress, phase: %s, reason: %s\n", rotation.Phase, rotation.Reason)
+	}
 	fmt.Println()
 }
 
@@ -0,0 +1,279 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package top
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/cheynewallace/tabby"
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+// statementsQuery extracts the content of pg_stat_statements as a JSON array
+const statementsQuery = `SELECT COALESCE(json_agg(row_to_json(s)), '[]')
+FROM (
+  SELECT d.datname, r.rolname AS usename, s.queryid::text AS queryid, s.query,
+    s.calls, s.total_exec_time, s.rows,
+    s.shared_blks_hit, s.shared_blks_read, s.shared_blks_written,
+    s.temp_blks_read, s.temp_blks_written
+  FROM pg_catalog.pg_stat_statements s
+  LEFT JOIN pg_catalog.pg_database d ON d.oid = s.dbid
+  LEFT JOIN pg_catalog.pg_roles r ON r.oid = s.userid
+) s`
+
+// maxQueryTextLength is the length of the query text in the text output
+const maxQueryTextLength = 80
+
+// QueriesReport is the result of the "top queries" command
+type QueriesReport struct {
+	Cluster   string                `json:"cluster"`
+	SortBy    SortOrder             `json:"sortBy"`
+	Interval  string                `json:"interval,omitempty"`
+	Instances []string              `json:"instances"`
+	Queries   []StatementStatistics `json:"queries"`
+}
+
+type queriesOptions struct {
+	sortBy   string
+	limit    int
+	interval time.Duration
+	dbname   string
+	output   string
+	timeout  time.Duration
+}
+
+func queriesCmd() *cobra.Command {
+	var options queriesOptions
+
+	cmd := &cobra.Command{
+		Use:   "queries CLUSTER",
+		Short: "Display the statements with the highest resource usage",
+		Long: "Display the statements with the highest resource usage, as reported by pg_stat_statements, " +
+			"aggregated across the ready instances of the cluster",
+		Args: plugin.RequiresArguments(1),
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return plugin.CompleteClusters(cmd.Context(), args, toComplete), cobra.ShellCompDirectiveNoFileComp
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return queries(cmd.Context(), args[0], options)
+		},
+	}
+
+	cmd.Flags().StringVar(&options.sortBy, "sort-by", string(SortByTotalTime),
+		"Sort the statements by one of: total|mean|calls|io")
+	cmd.Flags().IntVar(&options.limit, "limit", 10,
+		"Maximum number of statements to display, 0 for all of them")
+	cmd.Flags().DurationVar(&options.interval, "interval", 0,
+		"When set, display the activity between two snapshots taken at this interval, "+
+			"instead of the activity since the last statistics reset")
+	cmd.Flags().StringVarP(&options.dbname, "dbname", "d", "postgres",
+		"The database where the pg_stat_statements extension is installed")
+	cmd.Flags().StringVarP(&options.output, "output", "o", "text",
+		"Output format. One of text|json|yaml")
+	cmd.Flags().DurationVarP(&options.timeout, "timeout", "t", 10*time.Second,
+		"Timeout for the queries run in each instance")
+
+	return cmd
+}
+
+func queries(ctx context.Context, clusterName string, options queriesOptions) error {
+	sortBy, err := parseSortOrder(options.sortBy)
+	if err != nil {
+		return err
+	}
+	if options.interval < 0 {
+		return fmt.Errorf("the interval cannot be negative")
+	}
+
+	var cluster apiv1.Cluster
+	if err := plugin.Client.Get(
+		ctx,
+		client.ObjectKey{Namespace: plugin.Namespace, Name: clusterName},
+		&cluster,
+	); err != nil {
+		return fmt.Errorf("while getting the cluster %s: %w", clusterName, err)
+	}
+
+	pods, err := getReadyInstancePods(ctx, clusterName)
+	if err != nil {
+		return err
+	}
+	if len(pods) == 0 {
+		return fmt.Errorf("no ready instances found in the cluster %s", clusterName)
+	}
+
+	data, err := takeSnapshot(ctx, pods, options)
+	if err != nil {
+		return err
+	}
+
+	if options.interval > 0 {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(options.interval):
+		}
+
+		after, err := takeSnapshot(ctx, pods, options)
+		if err != nil {
+			return err
+		}
+		data = diffSnapshots(data, after)
+	}
+
+	report := QueriesReport{
+		Cluster:   clusterName,
+		SortBy:    sortBy,
+		Instances: make([]string, 0, len(data)),
+		Queries:   sortStatements(aggregate(data), sortBy, options.limit),
+	}
+	for _, pod := range pods {
+		if _, ok := data[pod.Name]; ok {
+			report.Instances = append(report.Instances, pod.Name)
+		}
+	}
+	if options.interval > 0 {
+		report.Interval = options.interval.String()
+	}
+
+	if format := plugin.OutputFormat(options.output); format != plugin.OutputFormatText {
+		return plugin.Print(report, format, os.Stdout)
+	}
+
+	printQueriesReport(os.Stdout, &report)
+	return nil
+}
+
+// getReadyInstancePods lists the instance Pods of the cluster that are
+// ready to accept connections
+func getReadyInstancePods(ctx context.Context, clusterName string) ([]corev1.Pod, error) {
+	var podList corev1.PodList
+	if err := plugin.Client.List(
+		ctx,
+		&podList,
+		client.InNamespace(plugin.Namespace),
+		client.MatchingLabels{
+			utils.ClusterLabelName: clusterName,
+			utils.PodRoleLabelName: string(utils.PodRoleInstance),
+		},
+	); err != nil {
+		return nil, fmt.Errorf("while listing the instances of the cluster %s: %w", clusterName, err)
+	}
+
+	pods := make([]corev1.Pod, 0, len(podList.Items))
+	for _, pod := range podList.Items {
+		if utils.IsPodReady(pod) {
+			pods = append(pods, pod)
+		}
+	}
+	return pods, nil
+}
+
+// takeSnapshot reads pg_stat_statements from every instance. The
+// instances that cannot be queried are reported and skipped, unless
+// every one of them fails.
+func takeSnapshot(ctx context.Context, pods []corev1.Pod, options queriesOptions) (snapshot, error) {
+	result := make(snapshot, len(pods))
+	var errs []error
+	for _, pod := range pods {
+		statements, err := getStatements(ctx, pod, options)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("instance %s: %w", pod.Name, err))
+			continue
+		}
+		result[pod.Name] = statements
+	}
+
+	if len(result) == 0 {
+		return nil, errors.Join(errs...)
+	}
+	for _, err := range errs {
+		fmt.Fprintf(os.Stderr, "Warning: skipping %v\n", err)
+	}
+	return result, nil
+}
+
+func getStatements(ctx context.Context, pod corev1.Pod, options queriesOptions) ([]StatementStatistics, error) {
+	stdout, err := plugin.ExecPsql(ctx, pod, options.dbname, statementsQuery, options.timeout)
+	if err != nil {
+		return nil, err
+	}
+
+	var statements []StatementStatistics
+	if err := json.Unmarshal([]byte(stdout), &statements); err != nil {
+		return nil, fmt.Errorf("while decoding pg_stat_statements: %w", err)
+	}
+	return statements, nil
+}
+
+func printQueriesReport(writer io.Writer, report *QueriesReport) {
+	if report.Interval != "" {
+		_, _ = fmt.Fprintf(writer, "Activity in the last %s on %s\n\n",
+			report.Interval, strings.Join(report.Instances, ", "))
+	} else {
+		_, _ = fmt.Fprintf(writer, "Activity since the last statistics reset on %s\n\n",
+			strings.Join(report.Instances, ", "))
+	}
+
+	table := tabby.NewCustom(tabwriter.NewWriter(writer, 0, 0, 2, ' ', 0))
+	table.AddHeader("Total Time", "Mean Time", "Calls", "Rows", "IO Blocks", "Database", "User", "Query")
+	for i := range report.Queries {
+		statement := &report.Queries[i]
+		table.AddLine(
+			formatMilliseconds(statement.TotalTime),
+			formatMilliseconds(statement.MeanTime),
+			statement.Calls,
+			statement.Rows,
+			statement.IOBlocks(),
+			statement.Database,
+			statement.User,
+			plugin.ShortenQueryText(statement.Query, maxQueryTextLength),
+		)
+	}
+	table.Print()
+}
+
+// formatMilliseconds displays an amount of milliseconds as a duration
+func formatMilliseconds(value float64) string {
+	duration := time.Duration(value * float64(time.Millisecond))
+	switch {
+	case duration >= time.Second:
+		return duration.Round(time.Millisecond).String()
+	case duration >= time.Millisecond:
+		return duration.Round(time.Microsecond).String()
+	default:
+		return strconv.FormatFloat(value, 'f', 3, 64) + "ms"
+	}
+}
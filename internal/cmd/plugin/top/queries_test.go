@@ -0,0 +1,65 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package top
+
+import (
+	"bytes"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("queries text output", func() {
+	DescribeTable("formatMilliseconds",
+		func(value float64, expected string) {
+			Expect(formatMilliseconds(value)).To(Equal(expected))
+		},
+		Entry("sub-millisecond", 0.0421, "0.042ms"),
+		Entry("milliseconds", 12.3456789, "12.346ms"),
+		Entry("seconds", 65432.1, "1m5.432s"),
+	)
+
+	It("prints a line for each statement", func() {
+		var buffer bytes.Buffer
+		printQueriesReport(&buffer, &QueriesReport{
+			Cluster:   "cluster-example",
+			SortBy:    SortByTotalTime,
+			Interval:  "10s",
+			Instances: []string{"cluster-example-1", "cluster-example-2"},
+			Queries: []StatementStatistics{
+				{
+					Database:  "app",
+					User:      "app",
+					QueryID:   "42",
+					Query:     "SELECT * FROM orders WHERE customer_id = $1",
+					Calls:     4,
+					TotalTime: 10,
+					MeanTime:  2.5,
+				},
+			},
+		})
+
+		output := buffer.String()
+		Expect(output).To(ContainSubstring("Activity in the last 10s on cluster-example-1, cluster-example-2"))
+		Expect(output).To(ContainSubstring("Total Time"))
+		Expect(output).To(ContainSubstring("SELECT * FROM orders WHERE customer_id = $1"))
+		Expect(output).To(ContainSubstring("2.5ms"))
+	})
+})
@@ -0,0 +1,220 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package top
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// SortOrder is the criteria used to rank the statements
+type SortOrder string
+
+const (
+	// SortByTotalTime ranks the statements by the total execution time
+	SortByTotalTime SortOrder = "total"
+
+	// SortByMeanTime ranks the statements by the mean execution time
+	SortByMeanTime SortOrder = "mean"
+
+	// SortByCalls ranks the statements by the number of executions
+	SortByCalls SortOrder = "calls"
+
+	// SortByIO ranks the statements by the number of blocks read and written
+	SortByIO SortOrder = "io"
+)
+
+// parseSortOrder validates the sort criteria passed by the user
+func parseSortOrder(value string) (SortOrder, error) {
+	switch order := SortOrder(value); order {
+	case SortByTotalTime, SortByMeanTime, SortByCalls, SortByIO:
+		return order, nil
+	default:
+		return "", fmt.Errorf("unknown sort order %q, expected one of: %s, %s, %s, %s",
+			value, SortByTotalTime, SortByMeanTime, SortByCalls, SortByIO)
+	}
+}
+
+// statementKey identifies a statement in pg_stat_statements
+type statementKey struct {
+	database string
+	user     string
+	queryID  string
+}
+
+// StatementStatistics is the activity of a statement, as reported by
+// pg_stat_statements. Times are expressed in milliseconds.
+type StatementStatistics struct {
+	Database            string   `json:"datname"`
+	User                string   `json:"usename"`
+	QueryID             string   `json:"queryid"`
+	Query               string   `json:"query"`
+	Calls               int64    `json:"calls"`
+	TotalTime           float64  `json:"total_exec_time"`
+	MeanTime            float64  `json:"mean_exec_time"`
+	Rows                int64    `json:"rows"`
+	SharedBlocksHit     int64    `json:"shared_blks_hit"`
+	SharedBlocksRead    int64    `json:"shared_blks_read"`
+	SharedBlocksWritten int64    `json:"shared_blks_written"`
+	TempBlocksRead      int64    `json:"temp_blks_read"`
+	TempBlocksWritten   int64    `json:"temp_blks_written"`
+	Instances           []string `json:"instances,omitempty"`
+}
+
+func (s *StatementStatistics) key() statementKey {
+	return statementKey{database: s.Database, user: s.User, queryID: s.QueryID}
+}
+
+// IOBlocks is the number of blocks read and written by the statement,
+// excluding the ones found in the shared buffers
+func (s *StatementStatistics) IOBlocks() int64 {
+	return s.SharedBlocksRead + s.SharedBlocksWritten + s.TempBlocksRead + s.TempBlocksWritten
+}
+
+func (s *StatementStatistics) add(other *StatementStatistics) {
+	s.Calls += other.Calls
+	s.TotalTime += other.TotalTime
+	s.Rows += other.Rows
+	s.SharedBlocksHit += other.SharedBlocksHit
+	s.SharedBlocksRead += other.SharedBlocksRead
+	s.SharedBlocksWritten += other.SharedBlocksWritten
+	s.TempBlocksRead += other.TempBlocksRead
+	s.TempBlocksWritten += other.TempBlocksWritten
+	s.updateMeanTime()
+}
+
+func (s *StatementStatistics) subtract(other *StatementStatistics) {
+	s.Calls -= other.Calls
+	s.TotalTime -= other.TotalTime
+	s.Rows -= other.Rows
+	s.SharedBlocksHit -= other.SharedBlocksHit
+	s.SharedBlocksRead -= other.SharedBlocksRead
+	s.SharedBlocksWritten -= other.SharedBlocksWritten
+	s.TempBlocksRead -= other.TempBlocksRead
+	s.TempBlocksWritten -= other.TempBlocksWritten
+	s.updateMeanTime()
+}
+
+func (s *StatementStatistics) updateMeanTime() {
+	s.MeanTime = 0
+	if s.Calls > 0 {
+		s.MeanTime = s.TotalTime / float64(s.Calls)
+	}
+}
+
+// snapshot is the content of pg_stat_statements in every instance,
+// indexed by the instance name
+type snapshot map[string][]StatementStatistics
+
+// diffSnapshots computes the activity of the statements between two
+// snapshots of the same instances. Statements whose counters have been
+// reset or evicted from pg_stat_statements in the meantime are reported
+// with the counters of the latest snapshot.
+func diffSnapshots(before, after snapshot) snapshot {
+	result := make(snapshot, len(after))
+	for instance, statements := range after {
+		previous := make(map[statementKey]*StatementStatistics, len(before[instance]))
+		for i := range before[instance] {
+			previous[before[instance][i].key()] = &before[instance][i]
+		}
+
+		delta := make([]StatementStatistics, 0, len(statements))
+		for _, statement := range statements {
+			if old, ok := previous[statement.key()]; ok && old.Calls <= statement.Calls {
+				statement.subtract(old)
+			}
+			if statement.Calls == 0 {
+				continue
+			}
+			delta = append(delta, statement)
+		}
+		result[instance] = delta
+	}
+
+	return result
+}
+
+// aggregate sums the activity of the same statement across the instances,
+// returning the statements in no particular order
+func aggregate(data snapshot) []StatementStatistics {
+	instances := make([]string, 0, len(data))
+	for instance := range data {
+		instances = append(instances, instance)
+	}
+	slices.Sort(instances)
+
+	index := make(map[statementKey]int)
+	var result []StatementStatistics
+	for _, instance := range instances {
+		for _, statement := range data[instance] {
+			position, ok := index[statement.key()]
+			if !ok {
+				statement.updateMeanTime()
+				statement.Instances = []string{instance}
+				index[statement.key()] = len(result)
+				result = append(result, statement)
+				continue
+			}
+
+			result[position].add(&statement)
+			if !slices.Contains(result[position].Instances, instance) {
+				result[position].Instances = append(result[position].Instances, instance)
+			}
+		}
+	}
+
+	return result
+}
+
+// sortStatements ranks the statements in descending order, using the query
+// identifier to break the ties, and returns the first limit ones. A limit
+// that is not positive returns every statement.
+func sortStatements(statements []StatementStatistics, order SortOrder, limit int) []StatementStatistics {
+	value := func(s *StatementStatistics) float64 {
+		switch order {
+		case SortByMeanTime:
+			return s.MeanTime
+		case SortByCalls:
+			return float64(s.Calls)
+		case SortByIO:
+			return float64(s.IOBlocks())
+		default:
+			return s.TotalTime
+		}
+	}
+
+	slices.SortStableFunc(statements, func(a, b StatementStatistics) int {
+		va, vb := value(&a), value(&b)
+		switch {
+		case va > vb:
+			return -1
+		case va < vb:
+			return 1
+		default:
+			return strings.Compare(a.QueryID, b.QueryID)
+		}
+	})
+
+	if limit > 0 && len(statements) > limit {
+		statements = statements[:limit]
+	}
+	return statements
+}
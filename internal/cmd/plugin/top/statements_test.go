@@ -0,0 +1,155 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package top
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("parseSortOrder", func() {
+	DescribeTable("accepts the known sort orders",
+		func(value string, expected SortOrder) {
+			Expect(parseSortOrder(value)).To(Equal(expected))
+		},
+		Entry("total", "total", SortByTotalTime),
+		Entry("mean", "mean", SortByMeanTime),
+		Entry("calls", "calls", SortByCalls),
+		Entry("io", "io", SortByIO),
+	)
+
+	It("rejects an unknown sort order", func() {
+		_, err := parseSortOrder("rows")
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("statements statistics", func() {
+	statement := func(queryID string, calls int64, totalTime float64, read int64) StatementStatistics {
+		return StatementStatistics{
+			Database:         "app",
+			User:             "app",
+			QueryID:          queryID,
+			Query:            "SELECT " + queryID,
+			Calls:            calls,
+			TotalTime:        totalTime,
+			SharedBlocksRead: read,
+		}
+	}
+
+	Describe("diffSnapshots", func() {
+		It("computes the activity between two snapshots", func() {
+			before := snapshot{
+				"cluster-example-1": {statement("1", 10, 100, 5), statement("2", 4, 40, 0)},
+			}
+			after := snapshot{
+				"cluster-example-1": {
+					statement("1", 15, 200, 7),
+					statement("2", 4, 40, 0),
+					statement("3", 2, 10, 1),
+				},
+			}
+
+			result := diffSnapshots(before, after)
+			Expect(result).To(HaveKey("cluster-example-1"))
+			Expect(result["cluster-example-1"]).To(HaveLen(2))
+
+			first := result["cluster-example-1"][0]
+			Expect(first.QueryID).To(Equal("1"))
+			Expect(first.Calls).To(BeEquivalentTo(5))
+			Expect(first.TotalTime).To(BeNumerically("==", 100))
+			Expect(first.MeanTime).To(BeNumerically("==", 20))
+			Expect(first.SharedBlocksRead).To(BeEquivalentTo(2))
+
+			Expect(result["cluster-example-1"][1]).To(Equal(statement("3", 2, 10, 1)))
+		})
+
+		It("uses the latest counters when the statistics have been reset", func() {
+			before := snapshot{"cluster-example-1": {statement("1", 10, 100, 5)}}
+			after := snapshot{"cluster-example-1": {statement("1", 3, 30, 1)}}
+
+			result := diffSnapshots(before, after)
+			Expect(result["cluster-example-1"]).To(HaveLen(1))
+			Expect(result["cluster-example-1"][0].Calls).To(BeEquivalentTo(3))
+			Expect(result["cluster-example-1"][0].TotalTime).To(BeNumerically("==", 30))
+		})
+	})
+
+	Describe("aggregate", func() {
+		It("sums the same statement across the instances", func() {
+			data := snapshot{
+				"cluster-example-2": {statement("1", 30, 60, 3)},
+				"cluster-example-1": {statement("1", 10, 100, 5), statement("2", 1, 1, 0)},
+			}
+
+			result := aggregate(data)
+			Expect(result).To(HaveLen(2))
+			Expect(result[0].QueryID).To(Equal("1"))
+			Expect(result[0].Calls).To(BeEquivalentTo(40))
+			Expect(result[0].TotalTime).To(BeNumerically("==", 160))
+			Expect(result[0].MeanTime).To(BeNumerically("==", 4))
+			Expect(result[0].IOBlocks()).To(BeEquivalentTo(8))
+			Expect(result[0].Instances).To(Equal([]string{"cluster-example-1", "cluster-example-2"}))
+			Expect(result[1].Instances).To(Equal([]string{"cluster-example-1"}))
+		})
+
+		It("keeps the statements of different databases apart", func() {
+			other := statement("1", 1, 1, 1)
+			other.Database = "postgres"
+			data := snapshot{"cluster-example-1": {statement("1", 1, 1, 1), other}}
+
+			Expect(aggregate(data)).To(HaveLen(2))
+		})
+	})
+
+	Describe("sortStatements", func() {
+		var statements []StatementStatistics
+
+		BeforeEach(func() {
+			statements = aggregate(snapshot{
+				"cluster-example-1": {
+					statement("1", 100, 100, 1),
+					statement("2", 1, 50, 30),
+					statement("3", 10, 200, 2),
+				},
+			})
+		})
+
+		DescribeTable("ranks the statements",
+			func(order SortOrder, expected []string) {
+				var result []string
+				for _, s := range sortStatements(statements, order, 0) {
+					result = append(result, s.QueryID)
+				}
+				Expect(result).To(Equal(expected))
+			},
+			Entry("by total time", SortByTotalTime, []string{"3", "1", "2"}),
+			Entry("by mean time", SortByMeanTime, []string{"2", "3", "1"}),
+			Entry("by calls", SortByCalls, []string{"1", "3", "2"}),
+			Entry("by IO", SortByIO, []string{"2", "3", "1"}),
+		)
+
+		It("returns only the requested number of statements", func() {
+			result := sortStatements(statements, SortByTotalTime, 2)
+			Expect(result).To(HaveLen(2))
+			Expect(result[0].QueryID).To(Equal("3"))
+		})
+	})
+})
@@ -112,6 +112,10 @@ type Data struct {
 	// used by default for new poolers
 	PgbouncerImageName string `json:"pgbouncerImageName" env:"PGBOUNCER_IMAGE_NAME"`
 
+	// PgCatImageName is the name of the image of PgCat that is
+	// used by default for new poolers
+	PgCatImageName string `json:"pgcatImageName" env:"PGCAT_IMAGE_NAME"`
+
 	// InheritedAnnotations is a list of annotations that every resource could inherit from
 	// the owning Cluster
 	InheritedAnnotations []string `json:"inheritedAnnotations" env:"INHERITED_ANNOTATIONS"`
@@ -187,6 +191,7 @@ func newDefaultConfig() *Data {
 		OperatorImageName:       versions.DefaultOperatorImageName,
 		PostgresImageName:       versions.DefaultImageName,
 		PgbouncerImageName:      versions.DefaultPgbouncerImage,
+		PgCatImageName:          versions.DefaultPgCatImage,
 		PluginSocketDir:         DefaultPluginSocketDir,
 		CreateAnyService:        false,
 		CertificateDuration:     CertificateDuration,
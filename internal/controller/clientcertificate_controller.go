@@ -0,0 +1,403 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package controller
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/cloudnative-pg/machinery/pkg/log"
+	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/certs"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+// clientCertificateRetryDelay is the delay after which a ClientCertificate
+// that couldn't be issued is reconciled again
+const clientCertificateRetryDelay = 30 * time.Second
+
+// ClientCertificateReconciler reconciles a ClientCertificate object
+type ClientCertificateReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+}
+
+// +kubebuilder:rbac:groups=postgresql.cnpg.io,resources=clientcertificates,verbs=get;list;watch;update;patch
+// +kubebuilder:rbac:groups=postgresql.cnpg.io,resources=clientcertificates/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=postgresql.cnpg.io,resources=clusters,verbs=get;list;watch
+// +kubebuilder:rbac:groups=postgresql.cnpg.io,resources=roles,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+
+// Reconcile issues the client certificate and renews it before its expiration
+func (r *ClientCertificateReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	contextLogger, ctx := log.SetupLogger(ctx)
+
+	var clientCertificate apiv1.ClientCertificate
+	if err := r.Get(ctx, req.NamespacedName, &clientCertificate); err != nil {
+		if apierrs.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	if clientCertificate.Spec.Revoked {
+		return ctrl.Result{}, r.revoke(ctx, &clientCertificate)
+	}
+
+	result, err := r.reconcileCertificate(ctx, &clientCertificate)
+	if err != nil {
+		contextLogger.Error(err, "while issuing the client certificate")
+		if statusErr := r.patchStatus(ctx, &clientCertificate, func(clientCertificate *apiv1.ClientCertificate) {
+			clientCertificate.SetAsFailed(err)
+		}); statusErr != nil {
+			return ctrl.Result{}, statusErr
+		}
+		return ctrl.Result{RequeueAfter: clientCertificateRetryDelay}, nil
+	}
+
+	return result, nil
+}
+
+func (r *ClientCertificateReconciler) reconcileCertificate(
+	ctx context.Context,
+	clientCertificate *apiv1.ClientCertificate,
+) (ctrl.Result, error) {
+	var cluster apiv1.Cluster
+	if err := r.Get(ctx, client.ObjectKey{
+		Namespace: clientCertificate.Namespace,
+		Name:      clientCertificate.Spec.ClusterRef.Name,
+	}, &cluster); err != nil {
+		return ctrl.Result{}, fmt.Errorf("while getting cluster %q: %w", clientCertificate.Spec.ClusterRef.Name, err)
+	}
+	if cluster.UsesCertManager() {
+		return ctrl.Result{}, fmt.Errorf("the certificates of cluster %q are issued by cert-manager", cluster.Name)
+	}
+	if err := r.validateRole(ctx, &cluster, clientCertificate.Spec.Role); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	var clientCASecret corev1.Secret
+	if err := r.Get(ctx, client.ObjectKey{
+		Namespace: cluster.Namespace,
+		Name:      cluster.GetClientCASecretName(),
+	}, &clientCASecret); err != nil {
+		return ctrl.Result{}, fmt.Errorf("while getting the client CA secret: %w", err)
+	}
+	caPair, err := certs.ParseCASecret(&clientCASecret)
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("while parsing the client CA secret: %w", err)
+	}
+
+	var serverCASecret corev1.Secret
+	if err := r.Get(ctx, client.ObjectKey{
+		Namespace: cluster.Namespace,
+		Name:      cluster.GetServerCASecretName(),
+	}, &serverCASecret); err != nil {
+		return ctrl.Result{}, fmt.Errorf("while getting the server CA secret: %w", err)
+	}
+	serverCACertificate := serverCASecret.Data[certs.CACertKey]
+
+	var secret corev1.Secret
+	err = r.Get(ctx, client.ObjectKey{
+		Namespace: clientCertificate.Namespace,
+		Name:      clientCertificate.GetSecretName(),
+	}, &secret)
+	switch {
+	case apierrs.IsNotFound(err):
+		return r.issue(ctx, clientCertificate, &cluster, caPair, serverCACertificate, nil)
+	case err != nil:
+		return ctrl.Result{}, err
+	}
+
+	if !isOwnedByClientCertificate(&secret, clientCertificate) {
+		return ctrl.Result{}, fmt.Errorf(
+			"secret %q already exists and is not owned by this ClientCertificate", secret.Name)
+	}
+
+	renew, err := clientCertificateNeedsRenewal(clientCertificate, &secret, caPair)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if renew {
+		return r.issue(ctx, clientCertificate, &cluster, caPair, serverCACertificate, &secret)
+	}
+
+	if !bytes.Equal(secret.Data[certs.CACertKey], serverCACertificate) {
+		oldSecret := secret.DeepCopy()
+		secret.Data[certs.CACertKey] = serverCACertificate
+		if err := r.Patch(ctx, &secret, client.MergeFrom(oldSecret)); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	certificate, err := (&certs.KeyPair{Certificate: secret.Data[certs.TLSCertKey]}).ParseCertificate()
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	return r.setAsReady(ctx, clientCertificate, certificate.SerialNumber.Text(16),
+		certificate.NotBefore, certificate.NotAfter)
+}
+
+// issue creates a new certificate and stores it in the passed secret,
+// creating it when nil
+func (r *ClientCertificateReconciler) issue(
+	ctx context.Context,
+	clientCertificate *apiv1.ClientCertificate,
+	cluster *apiv1.Cluster,
+	caPair *certs.KeyPair,
+	serverCACertificate []byte,
+	secret *corev1.Secret,
+) (ctrl.Result, error) {
+	pair, err := caPair.CreateAndSignPairWithDuration(
+		clientCertificate.Spec.Role, certs.CertTypeClient, nil, clientCertificate.GetDuration())
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("while issuing the certificate: %w", err)
+	}
+	certificate, err := pair.ParseCertificate()
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	generatedSecret := pair.GenerateCertificateSecret(clientCertificate.Namespace, clientCertificate.GetSecretName())
+	generatedSecret.Data[certs.CACertKey] = serverCACertificate
+
+	if secret == nil {
+		generatedSecret.Labels[utils.ClusterLabelName] = cluster.Name
+		utils.SetAsOwnedBy(&generatedSecret.ObjectMeta, clientCertificate.ObjectMeta, metav1.TypeMeta{
+			Kind:       apiv1.ClientCertificateKind,
+			APIVersion: apiv1.SchemeGroupVersion.String(),
+		})
+		if err := r.Create(ctx, generatedSecret); err != nil {
+			return ctrl.Result{}, err
+		}
+	} else {
+		oldSecret := secret.DeepCopy()
+		secret.Data = generatedSecret.Data
+		if err := r.Patch(ctx, secret, client.MergeFrom(oldSecret)); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	serialNumber := certificate.SerialNumber.Text(16)
+	r.Recorder.Eventf(clientCertificate, corev1.EventTypeNormal, "Issued",
+		"Issued certificate %s for role %q, expiring at %s",
+		serialNumber, clientCertificate.Spec.Role, certificate.NotAfter.Format(time.RFC3339))
+
+	return r.setAsReady(ctx, clientCertificate, serialNumber, certificate.NotBefore, certificate.NotAfter)
+}
+
+// setAsReady records the current certificate in the status and
+// schedules its renewal
+func (r *ClientCertificateReconciler) setAsReady(
+	ctx context.Context,
+	clientCertificate *apiv1.ClientCertificate,
+	serialNumber string,
+	notBefore, notAfter time.Time,
+) (ctrl.Result, error) {
+	if err := r.patchStatus(ctx, clientCertificate, func(clientCertificate *apiv1.ClientCertificate) {
+		clientCertificate.SetAsReady(serialNumber, notBefore, notAfter)
+	}); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	return ctrl.Result{
+		RequeueAfter: max(time.Until(clientCertificate.GetRenewalTime(notAfter)), time.Second),
+	}, nil
+}
+
+// revoke removes the secret storing the certificate, which won't be renewed anymore
+func (r *ClientCertificateReconciler) revoke(ctx context.Context, clientCertificate *apiv1.ClientCertificate) error {
+	if clientCertificate.Status.Phase == apiv1.ClientCertificatePhaseRevoked {
+		return nil
+	}
+
+	var secret corev1.Secret
+	err := r.Get(ctx, client.ObjectKey{
+		Namespace: clientCertificate.Namespace,
+		Name:      clientCertificate.GetSecretName(),
+	}, &secret)
+	switch {
+	case err == nil:
+		if isOwnedByClientCertificate(&secret, clientCertificate) {
+			if err := r.Delete(ctx, &secret); err != nil && !apierrs.IsNotFound(err) {
+				return err
+			}
+		}
+	case !apierrs.IsNotFound(err):
+		return err
+	}
+
+	r.Recorder.Eventf(clientCertificate, corev1.EventTypeNormal, "Revoked",
+		"Certificate for role %q revoked, it won't be renewed anymore", clientCertificate.Spec.Role)
+
+	return r.patchStatus(ctx, clientCertificate, func(clientCertificate *apiv1.ClientCertificate) {
+		clientCertificate.SetAsRevoked()
+	})
+}
+
+func (r *ClientCertificateReconciler) patchStatus(
+	ctx context.Context,
+	clientCertificate *apiv1.ClientCertificate,
+	tx func(clientCertificate *apiv1.ClientCertificate),
+) error {
+	oldClientCertificate := clientCertificate.DeepCopy()
+	tx(clientCertificate)
+	return r.Status().Patch(ctx, clientCertificate, client.MergeFrom(oldClientCertificate))
+}
+
+// clientCertificateNeedsRenewal checks if the certificate stored in the secret
+// needs to be issued again, because it's expiring, it doesn't match the
+// specification or it isn't signed by the current client CA
+func clientCertificateNeedsRenewal(
+	clientCertificate *apiv1.ClientCertificate,
+	secret *corev1.Secret,
+	caPair *certs.KeyPair,
+) (bool, error) {
+	if clientCertificate.Status.ObservedGeneration != clientCertificate.Generation {
+		return true, nil
+	}
+
+	// A secret that can't be parsed is replaced with a new certificate
+	pair, err := certs.ParseServerSecret(secret)
+	if err != nil {
+		return true, nil
+	}
+	certificate, err := pair.ParseCertificate()
+	if err != nil {
+		return true, nil
+	}
+	sameKeyType, err := pair.HasSameKeyTypeOf(caPair)
+	if err != nil {
+		return true, nil
+	}
+
+	if certificate.Subject.CommonName != clientCertificate.Spec.Role || !sameKeyType {
+		return true, nil
+	}
+	if time.Now().After(clientCertificate.GetRenewalTime(certificate.NotAfter)) {
+		return true, nil
+	}
+
+	issued, err := pair.IsIssuedBy(caPair)
+	if err != nil {
+		return false, err
+	}
+	return !issued, nil
+}
+
+// validateRole refuses to issue certificates for the roles reserved to
+// PostgreSQL and to the operator, and for the roles declared with the
+// superuser or the replication privilege, either in the managed roles of
+// the cluster or with a Role resource
+func (r *ClientCertificateReconciler) validateRole(ctx context.Context, cluster *apiv1.Cluster, role string) error {
+	if postgres.IsRoleReserved(role) {
+		return fmt.Errorf("certificates can't be issued for the reserved role %q", role)
+	}
+
+	isPrivileged := func(configuration apiv1.RoleConfiguration) bool {
+		return configuration.Name == role && (configuration.Superuser || configuration.Replication)
+	}
+	privilegedRoleError := fmt.Errorf(
+		"certificates can't be issued for role %q having the superuser or replication privilege", role)
+
+	if cluster.Spec.Managed != nil && slices.ContainsFunc(cluster.Spec.Managed.Roles, isPrivileged) {
+		return privilegedRoleError
+	}
+
+	var roles apiv1.RoleList
+	if err := r.List(ctx, &roles, client.InNamespace(cluster.Namespace)); err != nil {
+		return fmt.Errorf("while listing the roles: %w", err)
+	}
+	for idx := range roles.Items {
+		roleSpec := &roles.Items[idx].Spec
+		if roleSpec.ClusterRef.Name == cluster.Name && isPrivileged(roleSpec.RoleConfiguration) {
+			return privilegedRoleError
+		}
+	}
+
+	return nil
+}
+
+func isOwnedByClientCertificate(secret *corev1.Secret, clientCertificate *apiv1.ClientCertificate) bool {
+	owner := metav1.GetControllerOf(secret)
+	return owner != nil &&
+		owner.Kind == apiv1.ClientCertificateKind &&
+		owner.UID == clientCertificate.UID
+}
+
+// SetupWithManager sets up the controller with the Manager
+func (r *ClientCertificateReconciler) SetupWithManager(mgr ctrl.Manager, maxConcurrentReconciles int) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		WithOptions(controller.Options{MaxConcurrentReconciles: maxConcurrentReconciles}).
+		For(&apiv1.ClientCertificate{}).
+		Named("client-certificate").
+		Owns(&corev1.Secret{}).
+		Watches(
+			&apiv1.Cluster{},
+			handler.EnqueueRequestsFromMapFunc(r.mapClusterToClientCertificates),
+		).
+		Complete(r)
+}
+
+// mapClusterToClientCertificates enqueues the ClientCertificates of a
+// cluster, so that the certificates are issued again as soon as the
+// client CA changes
+func (r *ClientCertificateReconciler) mapClusterToClientCertificates(
+	ctx context.Context,
+	obj client.Object,
+) []reconcile.Request {
+	var clientCertificates apiv1.ClientCertificateList
+	if err := r.List(ctx, &clientCertificates, client.InNamespace(obj.GetNamespace())); err != nil {
+		log.FromContext(ctx).Error(err, "while listing the ClientCertificates")
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, clientCertificate := range clientCertificates.Items {
+		if clientCertificate.Spec.ClusterRef.Name != obj.GetName() {
+			continue
+		}
+		requests = append(requests, reconcile.Request{
+			NamespacedName: types.NamespacedName{
+				Namespace: clientCertificate.Namespace,
+				Name:      clientCertificate.Name,
+			},
+		})
+	}
+	return requests
+}
@@ -0,0 +1,199 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package controller
+
+import (
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/certs"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ClientCertificate reconciler", func() {
+	var (
+		env               *testingEnvironment
+		cluster           *apiv1.Cluster
+		reconciler        *ClientCertificateReconciler
+		clientCertificate *apiv1.ClientCertificate
+	)
+
+	BeforeEach(func(ctx SpecContext) {
+		env = buildTestEnvironment()
+		namespace := newFakeNamespace(env.client)
+		cluster = newFakeCNPGCluster(env.client, namespace, func(cluster *apiv1.Cluster) {
+			cluster.Spec.Certificates = nil
+		})
+		Expect(env.clusterReconciler.setupPostgresPKI(ctx, cluster)).To(Succeed())
+
+		reconciler = &ClientCertificateReconciler{
+			Client:   env.client,
+			Scheme:   env.scheme,
+			Recorder: record.NewFakeRecorder(120),
+		}
+
+		clientCertificate = &apiv1.ClientCertificate{
+			ObjectMeta: metav1.ObjectMeta{Name: "alice", Namespace: namespace},
+			Spec: apiv1.ClientCertificateSpec{
+				ClusterRef: corev1.LocalObjectReference{Name: cluster.Name},
+				Role:       "alice",
+				Duration:   &metav1.Duration{Duration: 3 * time.Hour},
+			},
+		}
+		Expect(env.client.Create(ctx, clientCertificate)).To(Succeed())
+	})
+
+	reconcile := func(ctx SpecContext) ctrl.Result {
+		result, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(clientCertificate)})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(env.client.Get(ctx, client.ObjectKeyFromObject(clientCertificate), clientCertificate)).To(Succeed())
+		return result
+	}
+
+	getSecret := func(ctx SpecContext, name string) *corev1.Secret {
+		var secret corev1.Secret
+		Expect(env.client.Get(ctx, client.ObjectKey{Namespace: cluster.Namespace, Name: name}, &secret)).
+			To(Succeed())
+		return &secret
+	}
+
+	It("issues a certificate signed by the client CA", func(ctx SpecContext) {
+		result := reconcile(ctx)
+		Expect(clientCertificate.Status.Phase).To(Equal(apiv1.ClientCertificatePhaseReady))
+		Expect(clientCertificate.Status.SecretName).To(Equal("alice"))
+		Expect(clientCertificate.Status.NotAfter.Time).To(BeTemporally("~", time.Now().Add(3*time.Hour), time.Minute))
+		Expect(result.RequeueAfter).To(BeNumerically("~", 2*time.Hour, time.Minute))
+
+		secret := getSecret(ctx, "alice")
+		Expect(secret.Type).To(Equal(corev1.SecretTypeTLS))
+		Expect(secret.Data[certs.CACertKey]).To(Equal(getSecret(ctx, cluster.GetServerCASecretName()).Data[certs.CACertKey]))
+
+		caPair, err := certs.ParseCASecret(getSecret(ctx, cluster.GetClientCASecretName()))
+		Expect(err).ToNot(HaveOccurred())
+		pair, err := certs.ParseServerSecret(secret)
+		Expect(err).ToNot(HaveOccurred())
+		issued, err := pair.IsIssuedBy(caPair)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(issued).To(BeTrue())
+		certificate, err := pair.ParseCertificate()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(certificate.Subject.CommonName).To(Equal("alice"))
+		Expect(certificate.SerialNumber.Text(16)).To(Equal(clientCertificate.Status.SerialNumber))
+	})
+
+	It("keeps a valid certificate and renews an expiring one", func(ctx SpecContext) {
+		reconcile(ctx)
+		serialNumber := clientCertificate.Status.SerialNumber
+
+		reconcile(ctx)
+		Expect(clientCertificate.Status.SerialNumber).To(Equal(serialNumber))
+
+		By("storing a certificate which is about to expire")
+		caPair, err := certs.ParseCASecret(getSecret(ctx, cluster.GetClientCASecretName()))
+		Expect(err).ToNot(HaveOccurred())
+		expiringPair, err := caPair.CreateAndSignPairWithDuration("alice", certs.CertTypeClient, nil, time.Minute)
+		Expect(err).ToNot(HaveOccurred())
+		secret := getSecret(ctx, "alice")
+		secret.Data[certs.TLSCertKey] = expiringPair.Certificate
+		secret.Data[certs.TLSPrivateKeyKey] = expiringPair.Private
+		Expect(env.client.Update(ctx, secret)).To(Succeed())
+
+		reconcile(ctx)
+		Expect(clientCertificate.Status.SerialNumber).ToNot(Equal(serialNumber))
+		Expect(clientCertificate.Status.NotAfter.Time).To(BeTemporally("~", time.Now().Add(3*time.Hour), time.Minute))
+	})
+
+	It("refuses to overwrite a secret it doesn't own", func(ctx SpecContext) {
+		Expect(env.client.Create(ctx, &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "alice", Namespace: cluster.Namespace},
+		})).To(Succeed())
+
+		result := reconcile(ctx)
+		Expect(result.RequeueAfter).To(Equal(clientCertificateRetryDelay))
+		Expect(clientCertificate.Status.Phase).To(Equal(apiv1.ClientCertificatePhaseFailed))
+		Expect(clientCertificate.Status.Message).To(ContainSubstring("not owned"))
+	})
+
+	It("refuses to issue a certificate for a reserved role", func(ctx SpecContext) {
+		clientCertificate.Spec.Role = apiv1.StreamingReplicationUser
+		Expect(env.client.Update(ctx, clientCertificate)).To(Succeed())
+
+		result := reconcile(ctx)
+		Expect(result.RequeueAfter).To(Equal(clientCertificateRetryDelay))
+		Expect(clientCertificate.Status.Phase).To(Equal(apiv1.ClientCertificatePhaseFailed))
+		Expect(clientCertificate.Status.Message).To(ContainSubstring("reserved role"))
+
+		var secret corev1.Secret
+		err := env.client.Get(ctx, client.ObjectKey{Namespace: cluster.Namespace, Name: "alice"}, &secret)
+		Expect(apierrs.IsNotFound(err)).To(BeTrue())
+	})
+
+	It("refuses to issue a certificate for a managed superuser role", func(ctx SpecContext) {
+		cluster.Spec.Managed = &apiv1.ManagedConfiguration{
+			Roles: []apiv1.RoleConfiguration{{Name: "alice", Superuser: true}},
+		}
+		Expect(env.client.Update(ctx, cluster)).To(Succeed())
+
+		result := reconcile(ctx)
+		Expect(result.RequeueAfter).To(Equal(clientCertificateRetryDelay))
+		Expect(clientCertificate.Status.Phase).To(Equal(apiv1.ClientCertificatePhaseFailed))
+		Expect(clientCertificate.Status.Message).To(ContainSubstring("superuser or replication privilege"))
+	})
+
+	It("refuses to issue a certificate for a replication role declared with a Role resource", func(ctx SpecContext) {
+		Expect(env.client.Create(ctx, &apiv1.Role{
+			ObjectMeta: metav1.ObjectMeta{Name: "alice", Namespace: cluster.Namespace},
+			Spec: apiv1.RoleSpec{
+				ClusterRef:        corev1.LocalObjectReference{Name: cluster.Name},
+				RoleConfiguration: apiv1.RoleConfiguration{Name: "alice", Replication: true},
+			},
+		})).To(Succeed())
+
+		result := reconcile(ctx)
+		Expect(result.RequeueAfter).To(Equal(clientCertificateRetryDelay))
+		Expect(clientCertificate.Status.Phase).To(Equal(apiv1.ClientCertificatePhaseFailed))
+		Expect(clientCertificate.Status.Message).To(ContainSubstring("superuser or replication privilege"))
+	})
+
+	It("removes the secret when revoked", func(ctx SpecContext) {
+		reconcile(ctx)
+		notAfter := clientCertificate.Status.NotAfter
+
+		clientCertificate.Spec.Revoked = true
+		Expect(env.client.Update(ctx, clientCertificate)).To(Succeed())
+		result := reconcile(ctx)
+		Expect(result).To(Equal(ctrl.Result{}))
+		Expect(clientCertificate.Status.Phase).To(Equal(apiv1.ClientCertificatePhaseRevoked))
+		Expect(clientCertificate.Status.NotAfter.Time).To(BeTemporally("==", notAfter.Time))
+
+		var secret corev1.Secret
+		err := env.client.Get(ctx, client.ObjectKey{Namespace: cluster.Namespace, Name: "alice"}, &secret)
+		Expect(err).To(HaveOccurred())
+	})
+})
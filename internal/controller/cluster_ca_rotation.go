@@ -0,0 +1,384 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package controller
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/cloudnative-pg/machinery/pkg/log"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/certs"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/resources/status"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+const (
+	// caRotationRequeueDelay is the minimum delay between two checks
+	// of a CA rotation in progress
+	caRotationRequeueDelay = 10 * time.Second
+
+	// caRotationReasonExpiring is used when the rotation has been started
+	// because the CA is expiring
+	caRotationReasonExpiring = "Expiring"
+
+	// caRotationReasonRequested is used when the rotation has been
+	// requested with the cnpg.io/caRotationRequestedAt annotation
+	caRotationReasonRequested = "Requested"
+
+	// caRotationReasonKeyAlgorithmChanged is used when the rotation has
+	// been started because the requested key algorithm has changed
+	caRotationReasonKeyAlgorithmChanged = "KeyAlgorithmChanged"
+)
+
+// reconcileCARotation drives the rotation of the CA managed by the operator.
+// The rotation is staged so that every client and replica keeps working:
+//
+//  1. Trusting: a new CA is issued and appended to `ca.crt`, while the
+//     current one keeps signing the certificates
+//  2. Resigning: the new CA becomes the first one in `ca.crt` and starts
+//     signing the certificates, which are all renewed. The old CA is
+//     still trusted
+//  3. Completed: the old CA is removed from `ca.crt`
+//
+// Every phase lasts at least the propagation delay configured in the cluster
+func (r *ClusterReconciler) reconcileCARotation(ctx context.Context, cluster *apiv1.Cluster) error {
+	secretName, ok := cluster.GetManagedCASecretName()
+	if !ok {
+		return nil
+	}
+
+	var secret corev1.Secret
+	if err := r.Get(ctx, client.ObjectKey{Namespace: cluster.Namespace, Name: secretName}, &secret); err != nil {
+		if apierrors.IsNotFound(err) {
+			// The CA has not been generated yet
+			return nil
+		}
+		return err
+	}
+
+	rotation := cluster.Status.Certificates.CARotation
+	if !rotation.IsInProgress() {
+		return r.startCARotation(ctx, cluster, &secret)
+	}
+
+	if rotation.PhaseStartedAt != nil &&
+		time.Since(rotation.PhaseStartedAt.Time) < cluster.GetCARotationPropagationDelay() {
+		return nil
+	}
+
+	switch rotation.Phase {
+	case apiv1.CARotationPhaseTrusting:
+		return r.promoteNextCA(ctx, cluster, &secret)
+	case apiv1.CARotationPhaseResigning:
+		return r.retirePreviousCA(ctx, cluster, &secret)
+	}
+
+	return nil
+}
+
+// getCARotationReason checks if the CA managed by the operator needs to be
+// rotated, returning the reason or an empty string
+func getCARotationReason(cluster *apiv1.Cluster, secret *corev1.Secret) (string, error) {
+	if requestedAt := cluster.Annotations[utils.CARotationRequestedAnnotationName]; requestedAt != "" {
+		rotation := cluster.Status.Certificates.CARotation
+		if rotation == nil || rotation.LastRequestedAt != requestedAt {
+			return caRotationReasonRequested, nil
+		}
+	}
+
+	caPair := &certs.KeyPair{
+		Certificate: secret.Data[certs.CACertKey],
+		Private:     secret.Data[certs.CAPrivateKeyKey],
+	}
+	expiring, _, err := caPair.IsExpiring()
+	if err != nil {
+		return "", err
+	}
+	if expiring {
+		return caRotationReasonExpiring, nil
+	}
+
+	keyAlgorithm, err := caPair.GetKeyAlgorithm()
+	if err != nil {
+		return "", err
+	}
+	if keyAlgorithm != certs.KeyAlgorithm(cluster.GetCertificatesKeyAlgorithm()) {
+		return caRotationReasonKeyAlgorithmChanged, nil
+	}
+
+	return "", nil
+}
+
+// startCARotation issues a new CA and publishes it together with the
+// current one, if the CA needs to be rotated
+func (r *ClusterReconciler) startCARotation(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	secret *corev1.Secret,
+) error {
+	reason, err := getCARotationReason(cluster, secret)
+	if err != nil || reason == "" {
+		return err
+	}
+
+	contextLogger := log.FromContext(ctx)
+
+	// The new CA may have already been issued in a previous reconciliation
+	// loop that failed to update the status
+	if _, ok := secret.Data[certs.CANextPrivateKeyKey]; !ok {
+		nextCAPair, err := certs.CreateRootCAWithKeyAlgorithm(
+			cluster.Name,
+			cluster.Namespace,
+			certs.KeyAlgorithm(cluster.GetCertificatesKeyAlgorithm()),
+		)
+		if err != nil {
+			return err
+		}
+
+		origSecret := secret.DeepCopy()
+		secret.Data[certs.CACertKey] = certs.AppendCertificates(secret.Data[certs.CACertKey], nextCAPair.Certificate)
+		secret.Data[certs.CANextCertKey] = nextCAPair.Certificate
+		secret.Data[certs.CANextPrivateKeyKey] = nextCAPair.Private
+		if err := r.Patch(ctx, secret, client.MergeFrom(origSecret)); err != nil {
+			return err
+		}
+	}
+
+	contextLogger.Info("Starting the rotation of the CA", "secret", secret.Name, "reason", reason)
+	r.Recorder.Eventf(cluster, "Normal", "CARotation",
+		"Started the rotation of the CA in secret %s, reason: %s", secret.Name, reason)
+
+	now := metav1.Now()
+	requestedAt := cluster.Annotations[utils.CARotationRequestedAnnotationName]
+	return status.PatchWithOptimisticLock(ctx, r.Client, cluster, func(cluster *apiv1.Cluster) {
+		rotation := &apiv1.CARotationStatus{}
+		if cluster.Status.Certificates.CARotation != nil {
+			rotation = cluster.Status.Certificates.CARotation.DeepCopy()
+		}
+		rotation.Phase = apiv1.CARotationPhaseTrusting
+		rotation.Reason = reason
+		rotation.PhaseStartedAt = &now
+		rotation.LastRequestedAt = requestedAt
+		cluster.Status.Certificates.CARotation = rotation
+	})
+}
+
+// promoteNextCA makes the new CA the one signing the certificates. The
+// certificates signed by the previous CA will be renewed by the
+// PKI reconciliation
+func (r *ClusterReconciler) promoteNextCA(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	secret *corev1.Secret,
+) error {
+	// The new CA may have already been promoted in a previous reconciliation
+	// loop that failed to update the status
+	if nextPrivateKey, ok := secret.Data[certs.CANextPrivateKeyKey]; ok {
+		origSecret := secret.DeepCopy()
+		secret.Data[certs.CACertKey] = certs.AppendCertificates(
+			secret.Data[certs.CANextCertKey],
+			secret.Data[certs.CACertKey],
+		)
+		secret.Data[certs.CAPrivateKeyKey] = nextPrivateKey
+		delete(secret.Data, certs.CANextCertKey)
+		delete(secret.Data, certs.CANextPrivateKeyKey)
+
+		if _, err := certs.ParseCASecret(secret); err != nil {
+			return err
+		}
+
+		if err := r.Patch(ctx, secret, client.MergeFrom(origSecret)); err != nil {
+			return err
+		}
+	}
+
+	log.FromContext(ctx).Info("The new CA is now signing the certificates", "secret", secret.Name)
+	r.Recorder.Eventf(cluster, "Normal", "CARotation",
+		"The new CA in secret %s is now signing the certificates", secret.Name)
+
+	now := metav1.Now()
+	return status.PatchWithOptimisticLock(ctx, r.Client, cluster, func(cluster *apiv1.Cluster) {
+		if cluster.Status.Certificates.CARotation == nil {
+			return
+		}
+		cluster.Status.Certificates.CARotation.Phase = apiv1.CARotationPhaseResigning
+		cluster.Status.Certificates.CARotation.PhaseStartedAt = &now
+	})
+}
+
+// retirePreviousCA removes the previous CA from the trusted ones, once
+// every certificate has been signed by the new CA
+func (r *ClusterReconciler) retirePreviousCA(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	secret *corev1.Secret,
+) error {
+	contextLogger := log.FromContext(ctx)
+
+	caPair, err := certs.ParseCASecret(secret)
+	if err != nil {
+		return err
+	}
+
+	pendingSecretName, err := r.getCertificateNotIssuedBy(ctx, cluster, caPair)
+	if err != nil {
+		return err
+	}
+	if pendingSecretName != "" {
+		contextLogger.Info("Waiting for the certificate to be signed by the new CA before retiring the old one",
+			"secret", pendingSecretName)
+		return nil
+	}
+
+	currentCA, err := certs.FirstCertificate(secret.Data[certs.CACertKey])
+	if err != nil {
+		return err
+	}
+
+	if !bytes.Equal(currentCA, secret.Data[certs.CACertKey]) {
+		origSecret := secret.DeepCopy()
+		secret.Data[certs.CACertKey] = currentCA
+		if err := r.Patch(ctx, secret, client.MergeFrom(origSecret)); err != nil {
+			return err
+		}
+	}
+
+	contextLogger.Info("The rotation of the CA has been completed", "secret", secret.Name)
+	r.Recorder.Eventf(cluster, "Normal", "CARotation",
+		"Completed the rotation of the CA in secret %s", secret.Name)
+
+	now := metav1.Now()
+	return status.PatchWithOptimisticLock(ctx, r.Client, cluster, func(cluster *apiv1.Cluster) {
+		if cluster.Status.Certificates.CARotation == nil {
+			return
+		}
+		cluster.Status.Certificates.CARotation.Phase = apiv1.CARotationPhaseCompleted
+		cluster.Status.Certificates.CARotation.PhaseStartedAt = &now
+		cluster.Status.Certificates.CARotation.LastCompletionTime = &now
+	})
+}
+
+// getCertificateNotIssuedBy gets the name of the first secret containing a
+// certificate generated by the operator which has not been signed by the
+// passed CA, or an empty string if every certificate has been signed by it
+func (r *ClusterReconciler) getCertificateNotIssuedBy(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	caPair *certs.KeyPair,
+) (string, error) {
+	secretNames, err := r.getManagedCACertificateSecretNames(ctx, cluster)
+	if err != nil {
+		return "", err
+	}
+
+	for _, secretName := range secretNames {
+		var secret corev1.Secret
+		err := r.Get(ctx, client.ObjectKey{Namespace: cluster.Namespace, Name: secretName}, &secret)
+		if apierrors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return "", err
+		}
+
+		pair, err := certs.ParseServerSecret(&secret)
+		if err != nil {
+			return "", err
+		}
+
+		issued, err := pair.IsIssuedBy(caPair)
+		if err != nil {
+			return "", err
+		}
+		if !issued {
+			return secretName, nil
+		}
+	}
+
+	return "", nil
+}
+
+// getManagedCACertificateSecretNames gets the names of the secrets
+// containing the certificates signed by the CA managed by the operator,
+// including the ones issued for the ClientCertificate resources. The
+// ClientCertificates that are revoked or failed are skipped, as they
+// won't be issued again
+func (r *ClusterReconciler) getManagedCACertificateSecretNames(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+) ([]string, error) {
+	certificates := cluster.Spec.Certificates
+
+	var result []string
+	if certificates == nil || certificates.ServerCASecret == "" {
+		result = append(result, cluster.GetServerTLSSecretName())
+	}
+
+	if certificates == nil || certificates.ClientCASecret == "" {
+		result = append(result, cluster.GetReplicationSecretName())
+		if cluster.Status.PoolerIntegrations != nil {
+			result = append(result, cluster.Status.PoolerIntegrations.PgBouncerIntegration.Secrets...)
+		}
+
+		var clientCertificates apiv1.ClientCertificateList
+		if err := r.List(ctx, &clientCertificates, client.InNamespace(cluster.Namespace)); err != nil {
+			return nil, fmt.Errorf("while listing the ClientCertificates: %w", err)
+		}
+		for idx := range clientCertificates.Items {
+			clientCertificate := &clientCertificates.Items[idx]
+			if clientCertificate.Spec.ClusterRef.Name != cluster.Name ||
+				clientCertificate.Spec.Revoked ||
+				clientCertificate.Status.Phase == apiv1.ClientCertificatePhaseFailed {
+				continue
+			}
+			result = append(result, clientCertificate.GetSecretName())
+		}
+	}
+
+	return result, nil
+}
+
+// requeueDuringCARotation ensures the cluster is reconciled again when
+// the current phase of a CA rotation is expected to end
+func requeueDuringCARotation(cluster *apiv1.Cluster, result ctrl.Result) ctrl.Result {
+	rotation := cluster.Status.Certificates.CARotation
+	if !rotation.IsInProgress() || rotation.PhaseStartedAt == nil {
+		return result
+	}
+
+	delay := max(
+		time.Until(rotation.PhaseStartedAt.Add(cluster.GetCARotationPropagationDelay())),
+		caRotationRequeueDelay,
+	)
+	if result.RequeueAfter == 0 || result.RequeueAfter > delay {
+		result.RequeueAfter = delay
+	}
+
+	return result
+}
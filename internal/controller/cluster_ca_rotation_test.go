@@ -0,0 +1,255 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package controller
+
+import (
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/certs"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("CA rotation", func() {
+	var (
+		env     *testingEnvironment
+		cluster *apiv1.Cluster
+	)
+
+	BeforeEach(func(ctx SpecContext) {
+		env = buildTestEnvironment()
+		namespace := newFakeNamespace(env.client)
+		cluster = newFakeCNPGCluster(env.client, namespace, func(cluster *apiv1.Cluster) {
+			cluster.Spec.Certificates = nil
+		})
+		Expect(env.clusterReconciler.setupPostgresPKI(ctx, cluster)).To(Succeed())
+	})
+
+	getSecret := func(ctx SpecContext, name string) *corev1.Secret {
+		var secret corev1.Secret
+		Expect(env.client.Get(ctx, client.ObjectKey{Namespace: cluster.Namespace, Name: name}, &secret)).
+			To(Succeed())
+		return &secret
+	}
+
+	isIssuedByCA := func(ctx SpecContext, name string, caSecret *corev1.Secret) bool {
+		caPair, err := certs.ParseCASecret(caSecret)
+		Expect(err).ToNot(HaveOccurred())
+		pair, err := certs.ParseServerSecret(getSecret(ctx, name))
+		Expect(err).ToNot(HaveOccurred())
+		issued, err := pair.IsIssuedBy(caPair)
+		Expect(err).ToNot(HaveOccurred())
+		return issued
+	}
+
+	// endCurrentPhase moves the start of the current phase in the past
+	// to simulate the expiration of the propagation delay
+	endCurrentPhase := func(ctx SpecContext) {
+		Expect(env.client.Get(ctx, client.ObjectKeyFromObject(cluster), cluster)).To(Succeed())
+		past := metav1.NewTime(time.Now().Add(-2 * cluster.GetCARotationPropagationDelay()))
+		cluster.Status.Certificates.CARotation.PhaseStartedAt = &past
+		Expect(env.client.Status().Update(ctx, cluster)).To(Succeed())
+	}
+
+	requestRotation := func(ctx SpecContext, requestedAt string) {
+		Expect(env.client.Get(ctx, client.ObjectKeyFromObject(cluster), cluster)).To(Succeed())
+		cluster.SetAnnotations(map[string]string{utils.CARotationRequestedAnnotationName: requestedAt})
+		Expect(env.client.Update(ctx, cluster)).To(Succeed())
+	}
+
+	It("doesn't rotate a valid CA unless requested", func(ctx SpecContext) {
+		caName, _ := cluster.GetManagedCASecretName()
+		before := getSecret(ctx, caName)
+
+		Expect(env.clusterReconciler.setupPostgresPKI(ctx, cluster)).To(Succeed())
+		Expect(cluster.Status.Certificates.CARotation).To(BeNil())
+		Expect(getSecret(ctx, caName).Data).To(Equal(before.Data))
+	})
+
+	It("rotates the CA in stages when requested", func(ctx SpecContext) {
+		caName, ok := cluster.GetManagedCASecretName()
+		Expect(ok).To(BeTrue())
+		oldCASecret := getSecret(ctx, caName)
+
+		By("trusting the new CA")
+		requestRotation(ctx, "2026-10-16T10:00:00Z")
+		Expect(env.clusterReconciler.setupPostgresPKI(ctx, cluster)).To(Succeed())
+		rotation := cluster.Status.Certificates.CARotation
+		Expect(rotation).ToNot(BeNil())
+		Expect(rotation.Phase).To(Equal(apiv1.CARotationPhaseTrusting))
+		Expect(rotation.Reason).To(Equal(caRotationReasonRequested))
+		Expect(rotation.LastRequestedAt).To(Equal("2026-10-16T10:00:00Z"))
+
+		caSecret := getSecret(ctx, caName)
+		nextCA := caSecret.Data[certs.CANextCertKey]
+		Expect(nextCA).ToNot(BeEmpty())
+		Expect(caSecret.Data[certs.CANextPrivateKeyKey]).ToNot(BeEmpty())
+		Expect(caSecret.Data[certs.CAPrivateKeyKey]).To(Equal(oldCASecret.Data[certs.CAPrivateKeyKey]))
+		Expect(caSecret.Data[certs.CACertKey]).To(Equal(
+			certs.AppendCertificates(oldCASecret.Data[certs.CACertKey], nextCA)))
+		Expect(isIssuedByCA(ctx, cluster.GetServerTLSSecretName(), oldCASecret)).To(BeTrue())
+
+		By("waiting for the propagation delay")
+		Expect(env.clusterReconciler.setupPostgresPKI(ctx, cluster)).To(Succeed())
+		Expect(cluster.Status.Certificates.CARotation.Phase).To(Equal(apiv1.CARotationPhaseTrusting))
+
+		By("signing the certificates with the new CA")
+		endCurrentPhase(ctx)
+		Expect(env.clusterReconciler.setupPostgresPKI(ctx, cluster)).To(Succeed())
+		Expect(cluster.Status.Certificates.CARotation.Phase).To(Equal(apiv1.CARotationPhaseResigning))
+
+		caSecret = getSecret(ctx, caName)
+		Expect(caSecret.Data).ToNot(HaveKey(certs.CANextCertKey))
+		Expect(caSecret.Data).ToNot(HaveKey(certs.CANextPrivateKeyKey))
+		Expect(caSecret.Data[certs.CACertKey]).To(Equal(
+			certs.AppendCertificates(nextCA, oldCASecret.Data[certs.CACertKey])))
+		Expect(isIssuedByCA(ctx, cluster.GetServerTLSSecretName(), caSecret)).To(BeTrue())
+		Expect(isIssuedByCA(ctx, cluster.GetReplicationSecretName(), caSecret)).To(BeTrue())
+
+		By("retiring the old CA")
+		endCurrentPhase(ctx)
+		Expect(env.clusterReconciler.setupPostgresPKI(ctx, cluster)).To(Succeed())
+		rotation = cluster.Status.Certificates.CARotation
+		Expect(rotation.Phase).To(Equal(apiv1.CARotationPhaseCompleted))
+		Expect(rotation.LastCompletionTime).ToNot(BeNil())
+		Expect(getSecret(ctx, caName).Data[certs.CACertKey]).To(Equal(nextCA))
+
+		By("not starting a new rotation for the same request")
+		Expect(env.clusterReconciler.setupPostgresPKI(ctx, cluster)).To(Succeed())
+		Expect(cluster.Status.Certificates.CARotation.Phase).To(Equal(apiv1.CARotationPhaseCompleted))
+		Expect(getSecret(ctx, caName).Data[certs.CACertKey]).To(Equal(nextCA))
+
+		By("starting a new rotation for a new request")
+		requestRotation(ctx, "2026-10-16T11:00:00Z")
+		Expect(env.clusterReconciler.setupPostgresPKI(ctx, cluster)).To(Succeed())
+		Expect(cluster.Status.Certificates.CARotation.Phase).To(Equal(apiv1.CARotationPhaseTrusting))
+	})
+
+	It("waits for the ClientCertificates to be signed by the new CA", func(ctx SpecContext) {
+		clientCertificate := &apiv1.ClientCertificate{
+			ObjectMeta: metav1.ObjectMeta{Name: "alice", Namespace: cluster.Namespace},
+			Spec: apiv1.ClientCertificateSpec{
+				ClusterRef: corev1.LocalObjectReference{Name: cluster.Name},
+				Role:       "alice",
+			},
+		}
+		Expect(env.client.Create(ctx, clientCertificate)).To(Succeed())
+		clientCertificateReconciler := &ClientCertificateReconciler{
+			Client:   env.client,
+			Scheme:   env.scheme,
+			Recorder: record.NewFakeRecorder(120),
+		}
+		reconcileClientCertificate := func() {
+			_, err := clientCertificateReconciler.Reconcile(ctx, ctrl.Request{
+				NamespacedName: client.ObjectKeyFromObject(clientCertificate),
+			})
+			Expect(err).ToNot(HaveOccurred())
+		}
+		reconcileClientCertificate()
+
+		requestRotation(ctx, "2026-10-16T10:00:00Z")
+		Expect(env.clusterReconciler.setupPostgresPKI(ctx, cluster)).To(Succeed())
+		endCurrentPhase(ctx)
+		Expect(env.clusterReconciler.setupPostgresPKI(ctx, cluster)).To(Succeed())
+		Expect(cluster.Status.Certificates.CARotation.Phase).To(Equal(apiv1.CARotationPhaseResigning))
+
+		By("keeping the old CA while the client certificate is signed by it")
+		endCurrentPhase(ctx)
+		Expect(env.clusterReconciler.setupPostgresPKI(ctx, cluster)).To(Succeed())
+		Expect(cluster.Status.Certificates.CARotation.Phase).To(Equal(apiv1.CARotationPhaseResigning))
+
+		By("retiring the old CA once the client certificate has been issued again")
+		reconcileClientCertificate()
+		caName, _ := cluster.GetManagedCASecretName()
+		Expect(isIssuedByCA(ctx, clientCertificate.GetSecretName(), getSecret(ctx, caName))).To(BeTrue())
+		Expect(env.clusterReconciler.setupPostgresPKI(ctx, cluster)).To(Succeed())
+		Expect(cluster.Status.Certificates.CARotation.Phase).To(Equal(apiv1.CARotationPhaseCompleted))
+	})
+
+	It("doesn't rotate the CAs provided by the user", func(ctx SpecContext) {
+		cluster.Spec.Certificates = &apiv1.CertificatesConfiguration{
+			ServerCASecret: "server-ca",
+			ClientCASecret: "client-ca",
+		}
+		cluster.SetAnnotations(map[string]string{utils.CARotationRequestedAnnotationName: "2026-10-16T10:00:00Z"})
+		Expect(env.clusterReconciler.reconcileCARotation(ctx, cluster)).To(Succeed())
+		Expect(cluster.Status.Certificates.CARotation).To(BeNil())
+	})
+})
+
+var _ = Describe("requeueDuringCARotation", func() {
+	It("doesn't change the result when no rotation is in progress", func() {
+		cluster := &apiv1.Cluster{}
+		Expect(requeueDuringCARotation(cluster, ctrl.Result{})).To(Equal(ctrl.Result{}))
+
+		cluster.Status.Certificates.CARotation = &apiv1.CARotationStatus{Phase: apiv1.CARotationPhaseCompleted}
+		Expect(requeueDuringCARotation(cluster, ctrl.Result{})).To(Equal(ctrl.Result{}))
+	})
+
+	It("requeues when the current phase is expected to end", func() {
+		startedAt := metav1.Now()
+		cluster := &apiv1.Cluster{}
+		cluster.Status.Certificates.CARotation = &apiv1.CARotationStatus{
+			Phase:          apiv1.CARotationPhaseTrusting,
+			PhaseStartedAt: &startedAt,
+		}
+
+		result := requeueDuringCARotation(cluster, ctrl.Result{})
+		Expect(result.RequeueAfter).To(BeNumerically("~", apiv1.DefaultCARotationPropagationDelay, time.Second))
+
+		result = requeueDuringCARotation(cluster, ctrl.Result{RequeueAfter: time.Second})
+		Expect(result.RequeueAfter).To(Equal(time.Second))
+	})
+
+	It("uses the propagation delay configured in the cluster", func() {
+		startedAt := metav1.Now()
+		cluster := &apiv1.Cluster{}
+		cluster.Spec.Certificates = &apiv1.CertificatesConfiguration{
+			CARotationPropagationDelay: &metav1.Duration{Duration: 30 * time.Minute},
+		}
+		cluster.Status.Certificates.CARotation = &apiv1.CARotationStatus{
+			Phase:          apiv1.CARotationPhaseTrusting,
+			PhaseStartedAt: &startedAt,
+		}
+
+		result := requeueDuringCARotation(cluster, ctrl.Result{})
+		Expect(result.RequeueAfter).To(BeNumerically("~", 30*time.Minute, time.Second))
+	})
+
+	It("doesn't requeue too often once the phase is over", func() {
+		startedAt := metav1.NewTime(time.Now().Add(-2 * apiv1.DefaultCARotationPropagationDelay))
+		cluster := &apiv1.Cluster{}
+		cluster.Status.Certificates.CARotation = &apiv1.CARotationStatus{
+			Phase:          apiv1.CARotationPhaseResigning,
+			PhaseStartedAt: &startedAt,
+		}
+
+		Expect(requeueDuringCARotation(cluster, ctrl.Result{}).RequeueAfter).To(Equal(caRotationRequeueDelay))
+	})
+})
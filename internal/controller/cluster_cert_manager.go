@@ -0,0 +1,154 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/cloudnative-pg/machinery/pkg/log"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/certs"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
+)
+
+// errCertificatesNotIssued is raised when cert-manager has not yet
+// issued the certificates of the cluster
+var errCertificatesNotIssued = errors.New("waiting for cert-manager to issue the certificates")
+
+// setupCertManagerPKI requests the certificates of the cluster to the
+// cert-manager issuer. The instances are reloaded by the usual secret
+// reconciliation loop when cert-manager renews them
+func (r *ClusterReconciler) setupCertManagerPKI(ctx context.Context, cluster *apiv1.Cluster) error {
+	requests := []specs.CertManagerCertificateRequest{
+		{
+			SecretName: cluster.GetServerTLSSecretName(),
+			CommonName: cluster.GetServiceReadWriteName(),
+			Usage:      certs.CertTypeServer,
+			DNSNames:   cluster.GetClusterAltDNSNames(),
+		},
+		{
+			SecretName: cluster.GetReplicationSecretName(),
+			CommonName: apiv1.StreamingReplicationUser,
+			Usage:      certs.CertTypeClient,
+		},
+	}
+	if cluster.Status.PoolerIntegrations != nil {
+		for _, secretName := range cluster.Status.PoolerIntegrations.PgBouncerIntegration.Secrets {
+			requests = append(requests, specs.CertManagerCertificateRequest{
+				SecretName: secretName,
+				CommonName: apiv1.PGBouncerPoolerUserName,
+				Usage:      certs.CertTypeClient,
+			})
+		}
+	}
+
+	var notIssued []string
+	for _, request := range requests {
+		issued, err := r.ensureCertManagerCertificate(ctx, cluster, request)
+		if err != nil {
+			return fmt.Errorf("while requesting certificate %s to cert-manager: %w", request.SecretName, err)
+		}
+		if !issued {
+			notIssued = append(notIssued, request.SecretName)
+		}
+	}
+
+	if len(notIssued) > 0 {
+		return fmt.Errorf("%w: %s", errCertificatesNotIssued, strings.Join(notIssued, ", "))
+	}
+
+	return nil
+}
+
+// ensureCertManagerCertificate creates or updates the cert-manager Certificate
+// for the passed request, returning true when the secret storing the
+// certificate can be used by the cluster
+func (r *ClusterReconciler) ensureCertManagerCertificate(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	request specs.CertManagerCertificateRequest,
+) (bool, error) {
+	contextLogger := log.FromContext(ctx).WithValues("certificate", request.SecretName)
+
+	desired := specs.BuildCertManagerCertificate(cluster, request)
+
+	var current unstructured.Unstructured
+	current.SetGroupVersionKind(specs.CertManagerCertificateGVK)
+	err := r.Get(ctx, client.ObjectKeyFromObject(desired), &current)
+	switch {
+	case apierrors.IsNotFound(err):
+		contextLogger.Info("Requesting certificate to cert-manager")
+		if err := r.Create(ctx, desired); err != nil {
+			return false, err
+		}
+		r.Recorder.Eventf(cluster, corev1.EventTypeNormal, "RequestingCertificate",
+			"Requesting certificate %s to cert-manager", request.SecretName)
+		return false, nil
+
+	case err != nil:
+		return false, err
+	}
+
+	if !reflect.DeepEqual(current.Object["spec"], desired.Object["spec"]) {
+		contextLogger.Info("Updating the cert-manager certificate")
+		updated := current.DeepCopy()
+		updated.Object["spec"] = desired.Object["spec"]
+		if err := r.Patch(ctx, updated, client.MergeFrom(&current)); err != nil {
+			return false, err
+		}
+	}
+
+	var secret corev1.Secret
+	err = r.Get(ctx, client.ObjectKey{Namespace: cluster.Namespace, Name: request.SecretName}, &secret)
+	switch {
+	case apierrors.IsNotFound(err):
+		return false, nil
+	case err != nil:
+		return false, err
+	}
+
+	if _, err := certs.ParseServerSecret(&secret); err != nil {
+		if specs.IsCertManagerCertificateReady(&current) {
+			return false, err
+		}
+		// cert-manager is still working on it
+		return false, nil
+	}
+
+	// The CA is needed by the instances to verify the certificates,
+	// and not every issuer provides it
+	if len(secret.Data[certs.CACertKey]) == 0 {
+		return false, fmt.Errorf("missing %s in secret %s, the issuer must provide its CA",
+			certs.CACertKey, secret.Name)
+	}
+
+	// A certificate which has been issued can be used while cert-manager
+	// is renewing it
+	return true, nil
+}
@@ -0,0 +1,118 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package controller
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/certs"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("cert-manager PKI", func() {
+	var (
+		env     *testingEnvironment
+		cluster *apiv1.Cluster
+	)
+
+	BeforeEach(func() {
+		env = buildTestEnvironment()
+		namespace := newFakeNamespace(env.client)
+		cluster = newFakeCNPGCluster(env.client, namespace, func(cluster *apiv1.Cluster) {
+			cluster.Spec.Certificates = &apiv1.CertificatesConfiguration{
+				IssuerRef: &apiv1.CertManagerIssuerReference{Name: "corporate-pki"},
+			}
+		})
+	})
+
+	getCertificate := func(ctx SpecContext, name string) *unstructured.Unstructured {
+		var certificate unstructured.Unstructured
+		certificate.SetGroupVersionKind(specs.CertManagerCertificateGVK)
+		Expect(env.client.Get(ctx, client.ObjectKey{Namespace: cluster.Namespace, Name: name}, &certificate)).
+			To(Succeed())
+		return &certificate
+	}
+
+	// issue simulates cert-manager storing a certificate signed by
+	// the passed CA in the secret
+	issue := func(ctx SpecContext, caPair *certs.KeyPair, name, commonName string, usage certs.CertType) {
+		pair, err := caPair.CreateAndSignPair(commonName, usage, nil)
+		Expect(err).ToNot(HaveOccurred())
+		secret := pair.GenerateCertificateSecret(cluster.Namespace, name)
+		secret.Data[certs.CACertKey] = caPair.Certificate
+		Expect(env.client.Create(ctx, secret)).To(Succeed())
+	}
+
+	It("waits for cert-manager to issue the certificates", func(ctx SpecContext) {
+		err := env.clusterReconciler.setupPostgresPKI(ctx, cluster)
+		Expect(err).To(MatchError(errCertificatesNotIssued))
+
+		server := getCertificate(ctx, cluster.GetServerTLSSecretName())
+		Expect(server.Object["spec"]).To(HaveKeyWithValue("commonName", cluster.GetServiceReadWriteName()))
+		replication := getCertificate(ctx, cluster.GetReplicationSecretName())
+		Expect(replication.Object["spec"]).To(HaveKeyWithValue("commonName", apiv1.StreamingReplicationUser))
+
+		// No CA is generated by the operator
+		var caSecret corev1.Secret
+		err = env.client.Get(ctx, client.ObjectKey{Namespace: cluster.Namespace, Name: cluster.Name + "-ca"}, &caSecret)
+		Expect(err).To(HaveOccurred())
+
+		caPair, err := certs.CreateRootCA("corporate-pki", "pki")
+		Expect(err).ToNot(HaveOccurred())
+		issue(ctx, caPair, cluster.GetServerTLSSecretName(), cluster.GetServiceReadWriteName(), certs.CertTypeServer)
+		Expect(env.clusterReconciler.setupPostgresPKI(ctx, cluster)).To(MatchError(errCertificatesNotIssued))
+
+		issue(ctx, caPair, cluster.GetReplicationSecretName(), apiv1.StreamingReplicationUser, certs.CertTypeClient)
+		Expect(env.clusterReconciler.setupPostgresPKI(ctx, cluster)).To(Succeed())
+	})
+
+	It("requires the issuer to provide its CA", func(ctx SpecContext) {
+		Expect(env.clusterReconciler.setupPostgresPKI(ctx, cluster)).To(MatchError(errCertificatesNotIssued))
+
+		caPair, err := certs.CreateRootCA("corporate-pki", "pki")
+		Expect(err).ToNot(HaveOccurred())
+		pair, err := caPair.CreateAndSignPair(cluster.GetServiceReadWriteName(), certs.CertTypeServer, nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(env.client.Create(ctx, pair.GenerateCertificateSecret(cluster.Namespace, cluster.GetServerTLSSecretName()))).
+			To(Succeed())
+
+		err = env.clusterReconciler.setupPostgresPKI(ctx, cluster)
+		Expect(err).To(MatchError(ContainSubstring("the issuer must provide its CA")))
+	})
+
+	It("updates the certificates when the configuration changes", func(ctx SpecContext) {
+		Expect(env.clusterReconciler.setupPostgresPKI(ctx, cluster)).To(MatchError(errCertificatesNotIssued))
+
+		cluster.Spec.Certificates.KeyAlgorithm = apiv1.CertificateKeyAlgorithmEd25519
+		Expect(env.clusterReconciler.setupPostgresPKI(ctx, cluster)).To(MatchError(errCertificatesNotIssued))
+
+		privateKey, _, err := unstructured.NestedMap(
+			getCertificate(ctx, cluster.GetServerTLSSecretName()).Object, "spec", "privateKey")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(privateKey).To(HaveKeyWithValue("algorithm", "Ed25519"))
+		Expect(privateKey).ToNot(HaveKey("size"))
+	})
+})
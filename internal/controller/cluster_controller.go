@@ -33,6 +33,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	policyv1 "k8s.io/api/policy/v1"
 	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
@@ -61,6 +62,7 @@ import (
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/reconciler/majorupgrade"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/reconciler/persistentvolumeclaim"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/reconciler/replicaclusterswitch"
+	resourcestatus "github.com/cloudnative-pg/cloudnative-pg/pkg/resources/status"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
 )
@@ -124,6 +126,7 @@ var ErrNextLoop = utils.ErrNextLoop
 // +kubebuilder:rbac:groups=admissionregistration.k8s.io,resources=mutatingwebhookconfigurations,verbs=get;patch
 // +kubebuilder:rbac:groups=admissionregistration.k8s.io,resources=validatingwebhookconfigurations,verbs=get;patch
 // +kubebuilder:rbac:groups=batch,resources=jobs,verbs=get;list;delete;patch;create;watch
+// +kubebuilder:rbac:groups=cert-manager.io,resources=certificates,verbs=get;create;patch
 // +kubebuilder:rbac:groups=coordination.k8s.io,resources=leases,verbs=get;create;update
 // +kubebuilder:rbac:groups=monitoring.coreos.com,resources=podmonitors,verbs=get;create;list;watch;delete;patch
 // +kubebuilder:rbac:groups=policy,resources=poddisruptionbudgets,verbs=create;delete;get;list;watch;update;patch
@@ -139,14 +142,18 @@ var ErrNextLoop = utils.ErrNextLoop
 // +kubebuilder:rbac:groups="",resources=persistentvolumeclaims,verbs=get;list;create;watch;delete;patch
 // +kubebuilder:rbac:groups="",resources=pods,verbs=get;list;delete;patch;create;watch
 // +kubebuilder:rbac:groups="",resources=pods/status,verbs=get
+// +kubebuilder:rbac:groups="",resources=resourcequotas,verbs=get;list;watch
 // +kubebuilder:rbac:groups="",resources=secrets,verbs=create;list;get;watch;delete
 // +kubebuilder:rbac:groups="",resources=serviceaccounts,verbs=create;patch;update;list;watch;get
 // +kubebuilder:rbac:groups="",resources=services,verbs=get;create;delete;update;patch;list;watch
 // +kubebuilder:rbac:groups=snapshot.storage.k8s.io,resources=volumesnapshots,verbs=get;create;watch;list;patch
+// +kubebuilder:rbac:groups=storage.k8s.io,resources=storageclasses,verbs=get;list;watch
 // +kubebuilder:rbac:groups=postgresql.cnpg.io,resources=imagecatalogs,verbs=get;watch;list
 // +kubebuilder:rbac:groups=postgresql.cnpg.io,resources=clusterimagecatalogs,verbs=get;watch;list
 // +kubebuilder:rbac:groups=postgresql.cnpg.io,resources=failoverquorums,verbs=create;get;watch;delete;list
 // +kubebuilder:rbac:groups=postgresql.cnpg.io,resources=failoverquorums/status,verbs=get;patch;update;watch
+// +kubebuilder:rbac:groups=postgresql.cnpg.io,resources=roles,verbs=get;list;watch;update;patch
+// +kubebuilder:rbac:groups=postgresql.cnpg.io,resources=roles/status,verbs=get;update;patch
 
 // Reconcile is the operator reconcile loop
 func (r *ClusterReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
@@ -256,7 +263,7 @@ func (r *ClusterReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 	if err != nil {
 		return ctrl.Result{}, err
 	}
-	return result, nil
+	return requeueDuringCARotation(cluster, result), nil
 }
 
 // Inner reconcile loop. Anything inside can require the reconciliation loop to stop by returning ErrNextLoop
@@ -316,6 +323,13 @@ func (r *ClusterReconciler) reconcile(ctx context.Context, cluster *apiv1.Cluste
 		if errors.Is(err, ErrNextLoop) {
 			return ctrl.Result{}, err
 		}
+		if errors.Is(err, errCertificatesNotIssued) {
+			contextLogger.Info(err.Error())
+			if regErr := r.RegisterPhase(ctx, cluster, apiv1.PhaseWaitingForCertificates, err.Error()); regErr != nil {
+				return ctrl.Result{}, regErr
+			}
+			return ctrl.Result{RequeueAfter: 10 * time.Second}, ErrNextLoop
+		}
 		contextLogger.Error(err, "while reconciling postgres cluster objects")
 		if regErr := r.RegisterPhase(ctx, cluster, apiv1.PhaseCannotCreateClusterObjects, err.Error()); regErr != nil {
 			contextLogger.Error(regErr, "unable to register phase", "outerErr", err.Error())
@@ -800,6 +814,19 @@ func (r *ClusterReconciler) reconcileResources(
 		return res, err
 	}
 
+	if err := persistentvolumeclaim.ReconcileAutoResize(
+		ctx,
+		r.Client,
+		r.Recorder,
+		cluster,
+		resources.pvcs.Items,
+	); err != nil {
+		if apierrs.IsConflict(err) {
+			return ctrl.Result{Requeue: true}, nil
+		}
+		return ctrl.Result{}, fmt.Errorf("cannot automatically resize the PVCs: %w", err)
+	}
+
 	// In-place Postgres major version upgrades
 	if result, err := majorupgrade.Reconcile(
 		ctx,
@@ -1019,6 +1046,11 @@ func (r *ClusterReconciler) reconcilePods(
 		return res, err
 	}
 
+	// Are there instances to be migrated to a new storage class? Replace one of them
+	if res, err := r.reconcileStorageMigration(ctx, cluster, resources, instancesStatus); !res.IsZero() || err != nil {
+		return res, err
+	}
+
 	// Should we scale down the cluster?
 	if cluster.Status.Instances > cluster.Spec.Instances {
 		if err := r.scaleDownCluster(ctx, cluster, resources); err != nil {
@@ -1085,7 +1117,48 @@ func (r *ClusterReconciler) handleRollingUpdate(
 
 	// If we need to roll out a restart of any instance, this is the right moment
 	done, err := r.rolloutRequiredInstances(ctx, cluster, &instancesStatus)
+
+	var windowClosedErr *errMaintenanceWindowClosed
+	isWindowClosed := errors.As(err, &windowClosedErr)
+	if !isWindowClosed && meta.IsStatusConditionTrue(
+		cluster.Status.Conditions,
+		string(apiv1.ConditionMaintenancePending),
+	) {
+		if err := resourcestatus.PatchConditionsWithOptimisticLock(
+			ctx,
+			r.Client,
+			cluster,
+			rolloutManager.NoPendingMaintenanceCondition(),
+		); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
 	switch {
+	case isWindowClosed:
+		contextLogger.Info(
+			"A Pod needs to be rolled out, waiting for the maintenance window",
+			"nextOpening", windowClosedErr.nextOpening,
+		)
+		if err := resourcestatus.PatchConditionsWithOptimisticLock(
+			ctx,
+			r.Client,
+			cluster,
+			rolloutManager.MaintenancePendingCondition("A rolling update", windowClosedErr.nextOpening),
+		); err != nil {
+			return ctrl.Result{}, err
+		}
+		if err := r.RegisterPhase(
+			ctx,
+			cluster,
+			apiv1.PhaseUpgradeDelayed,
+			fmt.Sprintf("The cluster needs to be updated, waiting for the maintenance window opening at %s",
+				windowClosedErr.nextOpening.Format(time.RFC3339)),
+		); err != nil {
+			return ctrl.Result{}, err
+		}
+
+		return ctrl.Result{RequeueAfter: time.Until(windowClosedErr.nextOpening)}, nil
 	case errors.Is(err, errLogShippingReplicaElected):
 		contextLogger.Warning(
 			"The primary needs to be restarted, but the chosen new primary is still " +
@@ -1173,6 +1246,11 @@ func (r *ClusterReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manag
 			&apiv1.Pooler{},
 			handler.EnqueueRequestsFromMapFunc(r.mapPoolersToClusters()),
 		).
+		Watches(
+			&apiv1.Role{},
+			handler.EnqueueRequestsFromMapFunc(r.mapRolesToClusters()),
+			builder.WithPredicates(predicate.GenerationChangedPredicate{}),
+		).
 		Watches(
 			&corev1.Node{},
 			handler.EnqueueRequestsFromMapFunc(r.mapNodeToClusters()),
@@ -1404,6 +1482,21 @@ func (r *ClusterReconciler) mapPoolersToClusters() handler.MapFunc {
 	}
 }
 
+// mapRolesToClusters returns a function mapping Role events to the reconcile
+// requests of the cluster hosting them, as the instance manager needs access
+// to their password Secrets
+func (r *ClusterReconciler) mapRolesToClusters() handler.MapFunc {
+	return func(_ context.Context, obj client.Object) []reconcile.Request {
+		role, ok := obj.(*apiv1.Role)
+		if !ok || role.Spec.ClusterRef.Name == "" {
+			return nil
+		}
+		return []reconcile.Request{{
+			NamespacedName: types.NamespacedName{Namespace: role.Namespace, Name: role.Spec.ClusterRef.Name},
+		}}
+	}
+}
+
 // mapNodeToClusters returns a function mapping cluster events watched to cluster reconcile requests
 func (r *ClusterReconciler) mapConfigMapsToClusters() handler.MapFunc {
 	return func(ctx context.Context, obj client.Object) []reconcile.Request {
@@ -268,7 +268,9 @@ func createOrPatchClusterCredentialSecret(
 }
 
 func (r *ClusterReconciler) reconcilePoolerSecrets(ctx context.Context, cluster *apiv1.Cluster) error {
-	if cluster.Status.PoolerIntegrations == nil {
+	// The certificates issued by cert-manager are requested
+	// together with the other certificates of the cluster
+	if cluster.Status.PoolerIntegrations == nil || cluster.UsesCertManager() {
 		return nil
 	}
 
@@ -715,6 +717,11 @@ func (r *ClusterReconciler) createOrPatchRole(ctx context.Context, cluster *apiv
 		return err
 	}
 
+	var declarativeRoles apiv1.RoleList
+	if err := r.List(ctx, &declarativeRoles, client.InNamespace(cluster.Namespace)); err != nil {
+		return fmt.Errorf("while listing the role objects: %w", err)
+	}
+
 	var role rbacv1.Role
 	if err := r.Get(ctx, client.ObjectKey{Name: cluster.Name, Namespace: cluster.Namespace}, &role); err != nil {
 		if !apierrs.IsNotFound(err) {
@@ -722,10 +729,10 @@ func (r *ClusterReconciler) createOrPatchRole(ctx context.Context, cluster *apiv
 		}
 
 		r.Recorder.Event(cluster, "Normal", "CreatingRole", "Creating Cluster Role")
-		return r.createRole(ctx, cluster, originBackup)
+		return r.createRole(ctx, cluster, originBackup, declarativeRoles.Items)
 	}
 
-	generatedRole := specs.CreateRole(*cluster, originBackup)
+	generatedRole := specs.CreateRole(*cluster, originBackup, declarativeRoles.Items)
 	if equality.Semantic.DeepEqual(generatedRole.Rules, role.Rules) {
 		// Everything fine, the two rules have the same content
 		return nil
@@ -1017,8 +1024,13 @@ func createOrPatchPodMonitor(
 }
 
 // createRole creates the role
-func (r *ClusterReconciler) createRole(ctx context.Context, cluster *apiv1.Cluster, backupOrigin *apiv1.Backup) error {
-	role := specs.CreateRole(*cluster, backupOrigin)
+func (r *ClusterReconciler) createRole(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	backupOrigin *apiv1.Backup,
+	declarativeRoles []apiv1.Role,
+) error {
+	role := specs.CreateRole(*cluster, backupOrigin, declarativeRoles)
 	cluster.SetInheritedDataAndOwnership(&role.ObjectMeta)
 
 	err := r.Create(ctx, &role)
@@ -1250,10 +1262,23 @@ func (r *ClusterReconciler) joinReplicaInstance(
 		return ctrl.Result{}, err
 	}
 
-	job := specs.JoinReplicaInstance(*cluster, nodeSerial)
-
 	// If we can bootstrap this replica from a pre-existing source, we do it
 	storageSource := persistentvolumeclaim.GetCandidateStorageSourceForReplica(ctx, cluster, backupList)
+	return r.createReplicaInstance(ctx, nodeSerial, cluster, storageSource)
+}
+
+// createReplicaInstance creates the Job and the PVCs of a new replica,
+// restoring it from the passed storage source or, when nil, cloning the
+// primary with pg_basebackup
+func (r *ClusterReconciler) createReplicaInstance(
+	ctx context.Context,
+	nodeSerial int,
+	cluster *apiv1.Cluster,
+	storageSource *persistentvolumeclaim.StorageSource,
+) (ctrl.Result, error) {
+	contextLogger := log.FromContext(ctx)
+
+	job := specs.JoinReplicaInstance(*cluster, nodeSerial)
 	if storageSource != nil {
 		job = specs.RestoreReplicaInstance(*cluster, nodeSerial)
 	}
@@ -1442,7 +1467,20 @@ func findInstancePodToCreate(
 		if err != nil {
 			return nil, err
 		}
-		return specs.NewInstance(ctx, *cluster, serial, true)
+		instance, err := specs.NewInstance(ctx, *cluster, serial, true)
+		if err != nil {
+			return nil, err
+		}
+
+		// The WALs of the instances created before the WAL storage was
+		// removed are still in the WAL PVC, until they are replaced
+		walPVCName := persistentvolumeclaim.NewPgWalCalculator().GetName(instance.Name)
+		if !cluster.ShouldCreateWalArchiveVolume() && slices.ContainsFunc(pvcs,
+			func(claim corev1.PersistentVolumeClaim) bool { return claim.Name == walPVCName }) {
+			specs.AddPgWalVolume(instance)
+		}
+
+		return instance, nil
 	}
 
 	return nil, nil
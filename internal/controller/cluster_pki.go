@@ -37,6 +37,16 @@ import (
 // setupPostgresPKI create all the PKI infrastructure that PostgreSQL need to work
 // if using ssl=on
 func (r *ClusterReconciler) setupPostgresPKI(ctx context.Context, cluster *apiv1.Cluster) error {
+	if cluster.UsesCertManager() {
+		return r.setupCertManagerPKI(ctx, cluster)
+	}
+
+	// Rotate the CA managed by the operator if needed. The certificates
+	// are renewed below once the new CA starts signing them
+	if err := r.reconcileCARotation(ctx, cluster); err != nil {
+		return fmt.Errorf("rotating the CA: %w", err)
+	}
+
 	// This is the CA of cluster
 	serverCaSecret, err := r.ensureServerCASecret(ctx, cluster)
 	if err != nil {
@@ -173,18 +183,17 @@ func (r *ClusterReconciler) ensureCASecret(ctx context.Context, cluster *apiv1.C
 	var secret corev1.Secret
 	err := r.Get(ctx, client.ObjectKey{Namespace: cluster.GetNamespace(), Name: secretName}, &secret)
 	if err == nil {
-		// Verify the validity of this CA and renew it if needed
-		err = r.renewCASecret(ctx, &secret)
-		if err != nil {
-			return nil, err
-		}
-
+		// An expiring CA is replaced by reconcileCARotation
 		return &secret, nil
 	} else if !apierrors.IsNotFound(err) {
 		return nil, err
 	}
 
-	caPair, err := certs.CreateRootCA(cluster.Name, cluster.Namespace)
+	caPair, err := certs.CreateRootCAWithKeyAlgorithm(
+		cluster.Name,
+		cluster.Namespace,
+		certs.KeyAlgorithm(cluster.GetCertificatesKeyAlgorithm()),
+	)
 	if err != nil {
 		return nil, fmt.Errorf("while creating the CA of the cluster: %w", err)
 	}
@@ -196,35 +205,6 @@ func (r *ClusterReconciler) ensureCASecret(ctx context.Context, cluster *apiv1.C
 	return derivedCaSecret, err
 }
 
-// renewCASecret check if this CA secret is valid and renew it if needed
-func (r *ClusterReconciler) renewCASecret(ctx context.Context, secret *corev1.Secret) error {
-	pair, err := certs.ParseCASecret(secret)
-	if err != nil {
-		return err
-	}
-
-	expiring, _, err := pair.IsExpiring()
-	if err != nil {
-		return err
-	}
-	if !expiring {
-		return nil
-	}
-
-	privateKey, err := pair.ParseECPrivateKey()
-	if err != nil {
-		return err
-	}
-
-	err = pair.RenewCertificate(privateKey, nil, nil)
-	if err != nil {
-		return err
-	}
-
-	secret.Data[certs.CACertKey] = pair.Certificate
-	return r.Update(ctx, secret)
-}
-
 // ensureServerLeafCertificate checks if we have a certificate for PostgreSQL and generate/renew it
 func (r *ClusterReconciler) ensureServerLeafCertificate(
 	ctx context.Context,
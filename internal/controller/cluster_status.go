@@ -25,6 +25,7 @@ import (
 	"reflect"
 	"runtime"
 	"sort"
+	"strings"
 
 	"github.com/cloudnative-pg/machinery/pkg/log"
 	pgTime "github.com/cloudnative-pg/machinery/pkg/postgres/time"
@@ -280,6 +281,7 @@ func (r *ClusterReconciler) updateResourceStatus(
 	cluster.Status.Certificates.ClientCASecret = cluster.GetClientCASecretName()
 	cluster.Status.Certificates.ReplicationTLSSecret = cluster.GetReplicationSecretName()
 	cluster.Status.Certificates.ServerAltDNSNames = cluster.GetClusterAltDNSNames()
+	cluster.Status.Certificates.KeyAlgorithm = cluster.GetCertificatesKeyAlgorithm()
 
 	// Set the version of the operator inside the status. This will allow us
 	// to discover the exact version of the operator which worked the last time
@@ -724,9 +726,11 @@ func (r *ClusterReconciler) updateClusterStatusThatRequiresInstancesState(
 	// we extract the instances reported state
 	for _, item := range statuses.Items {
 		cluster.Status.InstancesReportedState[apiv1.PodName(item.Pod.Name)] = apiv1.InstanceReportedState{
-			IsPrimary:  item.IsPrimary,
-			TimeLineID: item.TimeLineID,
-			IP:         item.Pod.Status.PodIP,
+			IsPrimary:    item.IsPrimary,
+			TimeLineID:   item.TimeLineID,
+			IP:           item.Pod.Status.PodIP,
+			DatabaseAges: toAPIDatabaseAges(item.DatabaseAges),
+			VolumesUsage: toAPIVolumesUsage(item.VolumesUsage),
 		}
 	}
 
@@ -780,12 +784,165 @@ func (r *ClusterReconciler) updateClusterStatusThatRequiresInstancesState(
 		})
 	}
 
+	r.updateWraparoundRiskCondition(cluster, statuses)
+	r.updateDiskFullProtectionCondition(cluster, statuses)
+
 	if !reflect.DeepEqual(existingClusterStatus, cluster.Status) {
 		return r.Status().Update(ctx, cluster)
 	}
 	return nil
 }
 
+// toAPIDatabaseAges converts the database ages reported by an instance
+// into their API representation
+func toAPIDatabaseAges(ages []postgres.DatabaseAge) []apiv1.DatabaseAge {
+	if ages == nil {
+		return nil
+	}
+
+	result := make([]apiv1.DatabaseAge, len(ages))
+	for i, age := range ages {
+		result[i] = apiv1.DatabaseAge{
+			Name:           age.Name,
+			XIDAge:         age.XIDAge,
+			MultiXactIDAge: age.MultiXactIDAge,
+		}
+	}
+	return result
+}
+
+// toAPIVolumesUsage converts the usage of the volumes reported by an
+// instance into its API representation. The percentages are truncated, so
+// that the status of the cluster changes only once the usage changes by
+// at least one percent
+func toAPIVolumesUsage(volumes []postgres.VolumeUsage) []apiv1.VolumeUsage {
+	if volumes == nil {
+		return nil
+	}
+
+	result := make([]apiv1.VolumeUsage, len(volumes))
+	for i, volume := range volumes {
+		result[i] = apiv1.VolumeUsage{
+			Role:                 string(volume.Role),
+			Tablespace:           volume.Tablespace,
+			TotalBytes:           volume.TotalBytes,
+			UsedPercentage:       int32(volume.UsedPercentage()),       //nolint:gosec
+			InodesUsedPercentage: int32(volume.InodesUsedPercentage()), //nolint:gosec
+		}
+	}
+	return result
+}
+
+// updateWraparoundRiskCondition sets the WraparoundRisk condition using the
+// database ages reported by the primary instance. The condition is left
+// untouched when the primary didn't report them yet
+func (r *ClusterReconciler) updateWraparoundRiskCondition(
+	cluster *apiv1.Cluster,
+	statuses postgres.PostgresqlStatusList,
+) {
+	var primaryAges []postgres.DatabaseAge
+	for _, item := range statuses.Items {
+		if item.IsPrimary && item.DatabaseAges != nil {
+			primaryAges = item.DatabaseAges
+			break
+		}
+	}
+	if primaryAges == nil {
+		return
+	}
+
+	var atRisk []string
+	for _, age := range toAPIDatabaseAges(primaryAges) {
+		if cluster.Spec.WraparoundProtection.IsAboveThreshold(age) {
+			atRisk = append(atRisk, fmt.Sprintf("%s (xid age %d, multixact age %d)",
+				age.Name, age.XIDAge, age.MultiXactIDAge))
+		}
+	}
+
+	if len(atRisk) == 0 {
+		meta.SetStatusCondition(&cluster.Status.Conditions, metav1.Condition{
+			Type:    string(apiv1.ConditionWraparoundRisk),
+			Status:  metav1.ConditionFalse,
+			Reason:  string(apiv1.ConditionReasonAgeBelowThreshold),
+			Message: "The age of every database is below the wraparound protection thresholds",
+		})
+		return
+	}
+
+	message := fmt.Sprintf("Databases over the wraparound protection thresholds: %s",
+		strings.Join(atRisk, ", "))
+	if !meta.IsStatusConditionTrue(cluster.Status.Conditions, string(apiv1.ConditionWraparoundRisk)) {
+		r.Recorder.Event(cluster, "Warning", "WraparoundRisk", message)
+	}
+	meta.SetStatusCondition(&cluster.Status.Conditions, metav1.Condition{
+		Type:    string(apiv1.ConditionWraparoundRisk),
+		Status:  metav1.ConditionTrue,
+		Reason:  string(apiv1.ConditionReasonAgeThresholdExceeded),
+		Message: message,
+	})
+}
+
+// updateDiskFullProtectionCondition sets the DiskFullProtection condition
+// using the state reported by the primary instance. The condition is left
+// untouched when the primary didn't report its status
+func (r *ClusterReconciler) updateDiskFullProtectionCondition(
+	cluster *apiv1.Cluster,
+	statuses postgres.PostgresqlStatusList,
+) {
+	var primary *postgres.PostgresqlStatus
+	for idx := range statuses.Items {
+		if statuses.Items[idx].IsPrimary && statuses.Items[idx].Error == nil {
+			primary = &statuses.Items[idx]
+			break
+		}
+	}
+	if primary == nil {
+		return
+	}
+
+	wasActive := meta.IsStatusConditionTrue(cluster.Status.Conditions, string(apiv1.ConditionDiskFullProtection))
+	if !primary.IsDiskFullProtectionActive {
+		if !cluster.Spec.DiskFullProtection.IsEnabled() &&
+			meta.FindStatusCondition(cluster.Status.Conditions, string(apiv1.ConditionDiskFullProtection)) == nil {
+			return
+		}
+
+		message := "The volumes of the primary instance have enough free space"
+		if wasActive {
+			r.Recorder.Event(cluster, "Normal", "DiskFullProtectionLifted", message)
+		}
+		meta.SetStatusCondition(&cluster.Status.Conditions, metav1.Condition{
+			Type:    string(apiv1.ConditionDiskFullProtection),
+			Status:  metav1.ConditionFalse,
+			Reason:  string(apiv1.ConditionReasonDiskSpaceAvailable),
+			Message: message,
+		})
+		return
+	}
+
+	volumes := make([]string, 0, len(primary.VolumesUsage))
+	for _, usage := range primary.VolumesUsage {
+		if usage.Role != utils.PVCRolePgData && usage.Role != utils.PVCRolePgWal {
+			continue
+		}
+		volumes = append(volumes, fmt.Sprintf("%s (%d%% space used, %d%% inodes used)",
+			usage.Role, int32(usage.UsedPercentage()), int32(usage.InodesUsedPercentage())))
+	}
+
+	message := fmt.Sprintf("Primary instance %s is rejecting the write transactions "+
+		"as its volumes are running out of space: %s",
+		primary.Pod.Name, strings.Join(volumes, ", "))
+	if !wasActive {
+		r.Recorder.Event(cluster, "Warning", "DiskFullProtectionActivated", message)
+	}
+	meta.SetStatusCondition(&cluster.Status.Conditions, metav1.Condition{
+		Type:    string(apiv1.ConditionDiskFullProtection),
+		Status:  metav1.ConditionTrue,
+		Reason:  string(apiv1.ConditionReasonDiskSpaceCritical),
+		Message: message,
+	})
+}
+
 // getPodsTopology returns a map with all the information about the pods topology
 func getPodsTopology(
 	ctx context.Context,
@@ -28,11 +28,13 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/rand"
+	"k8s.io/utils/ptr"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/certs"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/reconciler/persistentvolumeclaim"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -387,4 +389,157 @@ var _ = Describe("updateClusterStatusThatRequiresInstancesState tests", func() {
 		Expect(state2.TimeLineID).To(Equal(123))
 		Expect(state2.IP).To(Equal("192.168.1.2"))
 	})
+
+	It("reports the usage of the volumes as percentages", func(ctx SpecContext) {
+		statuses := postgres.PostgresqlStatusList{
+			Items: []postgres.PostgresqlStatus{
+				{
+					Pod:       &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "pod-1"}},
+					IsPrimary: true,
+					VolumesUsage: []postgres.VolumeUsage{
+						{
+							Role:           utils.PVCRolePgWal,
+							TotalBytes:     1000,
+							UsedBytes:      859,
+							AvailableBytes: 141,
+							TotalInodes:    100,
+							FreeInodes:     75,
+						},
+					},
+				},
+			},
+		}
+
+		err := env.clusterReconciler.updateClusterStatusThatRequiresInstancesState(ctx, cluster, statuses)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(cluster.Status.InstancesReportedState["pod-1"].VolumesUsage).To(Equal([]apiv1.VolumeUsage{
+			{
+				Role:                 string(utils.PVCRolePgWal),
+				TotalBytes:           1000,
+				UsedPercentage:       85,
+				InodesUsedPercentage: 25,
+			},
+		}))
+	})
+
+	Context("wraparound risk", func() {
+		primaryWithAges := func(ages ...postgres.DatabaseAge) postgres.PostgresqlStatusList {
+			return postgres.PostgresqlStatusList{
+				Items: []postgres.PostgresqlStatus{
+					{
+						Pod:          &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "pod-1"}},
+						IsPrimary:    true,
+						SystemID:     "system123",
+						DatabaseAges: ages,
+					},
+					{
+						Pod:      &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "pod-2"}},
+						SystemID: "system123",
+						DatabaseAges: []postgres.DatabaseAge{
+							{Name: "app", XIDAge: 1500000000},
+						},
+					},
+				},
+			}
+		}
+
+		It("doesn't set the condition when the primary didn't report the ages", func(ctx SpecContext) {
+			err := env.clusterReconciler.updateClusterStatusThatRequiresInstancesState(ctx, cluster, primaryWithAges())
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(meta.FindStatusCondition(cluster.Status.Conditions, string(apiv1.ConditionWraparoundRisk))).To(BeNil())
+			Expect(cluster.Status.InstancesReportedState["pod-2"].DatabaseAges).To(HaveLen(1))
+		})
+
+		It("reports the databases over the thresholds", func(ctx SpecContext) {
+			cluster.Spec.WraparoundProtection = &apiv1.WraparoundProtectionConfiguration{
+				XIDAgeThreshold: ptr.To(int64(200000000)),
+			}
+			statuses := primaryWithAges(
+				postgres.DatabaseAge{Name: "app", XIDAge: 250000000, MultiXactIDAge: 10},
+				postgres.DatabaseAge{Name: "postgres", XIDAge: 1000, MultiXactIDAge: 10},
+			)
+
+			err := env.clusterReconciler.updateClusterStatusThatRequiresInstancesState(ctx, cluster, statuses)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(cluster.Status.InstancesReportedState["pod-1"].DatabaseAges).To(ConsistOf(
+				apiv1.DatabaseAge{Name: "app", XIDAge: 250000000, MultiXactIDAge: 10},
+				apiv1.DatabaseAge{Name: "postgres", XIDAge: 1000, MultiXactIDAge: 10},
+			))
+			condition := meta.FindStatusCondition(cluster.Status.Conditions, string(apiv1.ConditionWraparoundRisk))
+			Expect(condition).ToNot(BeNil())
+			Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+			Expect(condition.Reason).To(Equal(string(apiv1.ConditionReasonAgeThresholdExceeded)))
+			Expect(condition.Message).To(ContainSubstring("app (xid age 250000000"))
+			Expect(condition.Message).ToNot(ContainSubstring("postgres"))
+		})
+
+		It("clears the condition when the ages are below the thresholds", func(ctx SpecContext) {
+			statuses := primaryWithAges(postgres.DatabaseAge{Name: "app", XIDAge: 250000000})
+
+			err := env.clusterReconciler.updateClusterStatusThatRequiresInstancesState(ctx, cluster, statuses)
+			Expect(err).ToNot(HaveOccurred())
+
+			condition := meta.FindStatusCondition(cluster.Status.Conditions, string(apiv1.ConditionWraparoundRisk))
+			Expect(condition).ToNot(BeNil())
+			Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+			Expect(condition.Reason).To(Equal(string(apiv1.ConditionReasonAgeBelowThreshold)))
+		})
+	})
+
+	Context("disk-full protection", func() {
+		primaryWithProtection := func(active bool) postgres.PostgresqlStatusList {
+			return postgres.PostgresqlStatusList{
+				Items: []postgres.PostgresqlStatus{
+					{
+						Pod:                        &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "pod-1"}},
+						IsPrimary:                  true,
+						IsDiskFullProtectionActive: active,
+						VolumesUsage: []postgres.VolumeUsage{
+							{
+								Role:           utils.PVCRolePgData,
+								TotalBytes:     1000,
+								UsedBytes:      970,
+								AvailableBytes: 30,
+							},
+						},
+					},
+				},
+			}
+		}
+
+		It("doesn't set the condition when the protection is disabled", func(ctx SpecContext) {
+			err := env.clusterReconciler.updateClusterStatusThatRequiresInstancesState(
+				ctx, cluster, primaryWithProtection(false))
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(meta.FindStatusCondition(cluster.Status.Conditions, string(apiv1.ConditionDiskFullProtection))).
+				To(BeNil())
+		})
+
+		It("reports when the primary is rejecting the write transactions", func(ctx SpecContext) {
+			cluster.Spec.DiskFullProtection = &apiv1.DiskFullProtectionConfiguration{Enabled: true}
+
+			err := env.clusterReconciler.updateClusterStatusThatRequiresInstancesState(
+				ctx, cluster, primaryWithProtection(true))
+			Expect(err).ToNot(HaveOccurred())
+
+			condition := meta.FindStatusCondition(cluster.Status.Conditions, string(apiv1.ConditionDiskFullProtection))
+			Expect(condition).ToNot(BeNil())
+			Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+			Expect(condition.Reason).To(Equal(string(apiv1.ConditionReasonDiskSpaceCritical)))
+			Expect(condition.Message).To(ContainSubstring("PG_DATA (97% space used"))
+
+			err = env.clusterReconciler.updateClusterStatusThatRequiresInstancesState(
+				ctx, cluster, primaryWithProtection(false))
+			Expect(err).ToNot(HaveOccurred())
+
+			condition = meta.FindStatusCondition(cluster.Status.Conditions, string(apiv1.ConditionDiskFullProtection))
+			Expect(condition).ToNot(BeNil())
+			Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+			Expect(condition.Reason).To(Equal(string(apiv1.ConditionReasonDiskSpaceAvailable)))
+		})
+	})
 })
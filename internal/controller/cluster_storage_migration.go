@@ -0,0 +1,262 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/cloudnative-pg/machinery/pkg/log"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/reconciler/persistentvolumeclaim"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/resources/status"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+// reconcileStorageMigration replaces, one at a time, the instances having
+// PVCs of a storage class different from the configured one, or the WAL PVC
+// of a removed WAL storage. A new replica is cloned from the primary with the
+// current storage configuration, then one of the instances pending migration
+// is decommissioned, after a switchover when it's the primary
+func (r *ClusterReconciler) reconcileStorageMigration(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	resources *managedResources,
+	instancesStatus postgres.PostgresqlStatusList,
+) (ctrl.Result, error) {
+	contextLogger := log.FromContext(ctx).WithName("storage_migration")
+
+	pending := getInstancesPendingStorageMigration(cluster, resources)
+	if err := r.updateStorageMigrationStatus(ctx, cluster, pending); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if len(pending) == 0 || cluster.Status.Instances < cluster.Spec.Instances {
+		return ctrl.Result{}, nil
+	}
+
+	isClusterReady := cluster.Status.ReadyInstances == cluster.Status.Instances && instancesStatus.IsComplete()
+
+	// The instance replacing the first pending one still needs to be created.
+	// When the migration is paused, the instances already replaced are
+	// decommissioned anyway, but no new instance is created
+	if cluster.Status.Instances == cluster.Spec.Instances {
+		if utils.IsStorageMigrationDisabled(&cluster.ObjectMeta) || !isClusterReady {
+			return ctrl.Result{}, nil
+		}
+
+		newNodeSerial, err := r.generateNodeSerial(ctx, cluster)
+		if err != nil {
+			return ctrl.Result{}, fmt.Errorf("cannot generate node serial: %w", err)
+		}
+
+		r.Recorder.Eventf(cluster, "Normal", "StorageMigration",
+			"Creating instance %s to replace %s, having an outdated storage configuration",
+			specs.GetInstanceName(cluster.Name, newNodeSerial), pending[0])
+
+		// The PVCs can't be restored from volume snapshots taken on
+		// a different storage class, let's clone the primary instead
+		return r.createReplicaInstance(ctx, newNodeSerial, cluster, nil)
+	}
+
+	// The new instance needs to be streaming from the primary before
+	// decommissioning the one it replaces
+	if !isClusterReady || cluster.Status.CurrentPrimary != cluster.Status.TargetPrimary {
+		contextLogger.Debug("Waiting for the instances to be ready before decommissioning",
+			"pendingInstances", pending)
+		return ctrl.Result{RequeueAfter: time.Second}, ErrNextLoop
+	}
+
+	instanceName := pending[0]
+	if instanceName == cluster.Status.CurrentPrimary {
+		return r.switchoverForStorageMigration(ctx, cluster, instancesStatus, pending)
+	}
+
+	message := fmt.Sprintf("Removing instance %s, replaced by an instance using the new storage configuration",
+		instanceName)
+	contextLogger.Info(message)
+	r.Recorder.Event(cluster, "Normal", "StorageMigration", message)
+	if err := r.ensureInstanceIsDeleted(ctx, cluster, instanceName); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// We deleted the pod and the PVC group. Give time to the informer cache to notice that.
+	return ctrl.Result{RequeueAfter: time.Second}, nil
+}
+
+// switchoverForStorageMigration promotes the most advanced replica already
+// using the new storage configuration, so that the previous primary can be
+// decommissioned
+func (r *ClusterReconciler) switchoverForStorageMigration(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	instancesStatus postgres.PostgresqlStatusList,
+	pending []string,
+) (ctrl.Result, error) {
+	contextLogger := log.FromContext(ctx).WithName("storage_migration")
+
+	if cluster.GetPrimaryUpdateStrategy() == apiv1.PrimaryUpdateStrategySupervised {
+		contextLogger.Info("Waiting for the user to request a switchover to complete the storage migration")
+		if err := r.RegisterPhase(ctx, cluster, apiv1.PhaseWaitingForUser,
+			"User must issue a supervised switchover to complete the storage migration"); err != nil {
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{}, ErrNextLoop
+	}
+
+	// The list of the instances is sorted in the same order we use
+	// for switchover and failover
+	for _, item := range instancesStatus.Items {
+		if item.IsPrimary || item.Pod.Name == cluster.Status.CurrentPrimary ||
+			slices.Contains(pending, item.Pod.Name) {
+			continue
+		}
+
+		// Before promoting a replica, the instance manager will wait for the WAL receiver
+		// process to be down, to avoid losing data written on the primary. This protection
+		// can work only when the streaming connection is active
+		if !item.IsWalReceiverActive {
+			contextLogger.Info("The chosen new primary is still not connected via streaming replication, waiting",
+				"currentPrimary", cluster.Status.CurrentPrimary,
+				"targetPrimary", item.Pod.Name)
+			return ctrl.Result{RequeueAfter: 5 * time.Second}, nil
+		}
+
+		r.Recorder.Eventf(cluster, "Normal", "Switchover",
+			"Initiating switchover to %s to migrate the storage of %s",
+			item.Pod.Name, cluster.Status.CurrentPrimary)
+		return ctrl.Result{RequeueAfter: time.Second}, r.setPrimaryInstance(ctx, cluster, item.Pod.Name)
+	}
+
+	contextLogger.Info("No replica using the new storage configuration is available for the switchover")
+	return ctrl.Result{RequeueAfter: time.Second}, ErrNextLoop
+}
+
+// getInstancesPendingStorageMigration returns the instances having PVCs of
+// a previous storage class or the WAL PVC of a removed WAL storage, in the
+// order in which they are replaced: the replicas first, and then the primary
+func getInstancesPendingStorageMigration(cluster *apiv1.Cluster, resources *managedResources) []string {
+	pending := slices.Concat(
+		persistentvolumeclaim.GetInstancesWithOutdatedStorageClass(
+			cluster,
+			resources.instances.Items,
+			resources.pvcs.Items,
+		),
+		persistentvolumeclaim.GetInstancesWithUnexpectedWalPVC(
+			cluster,
+			resources.instances.Items,
+			resources.pvcs.Items,
+		),
+	)
+	slices.Sort(pending)
+	pending = slices.Compact(pending)
+
+	if idx := slices.Index(pending, cluster.Status.CurrentPrimary); idx != -1 {
+		pending = append(slices.Delete(pending, idx, idx+1), cluster.Status.CurrentPrimary)
+	}
+
+	return pending
+}
+
+// updateStorageMigrationStatus stores the progress of the storage migration
+// in the status of the cluster, emitting an event when its phase changes
+func (r *ClusterReconciler) updateStorageMigrationStatus(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	pending []string,
+) error {
+	currentStatus := cluster.Status.StorageMigration
+	newStatus := getStorageMigrationStatus(
+		currentStatus,
+		pending,
+		utils.IsStorageMigrationDisabled(&cluster.ObjectMeta),
+		metav1.Now(),
+	)
+	if equality.Semantic.DeepEqual(currentStatus, newStatus) {
+		return nil
+	}
+
+	var currentPhase apiv1.StorageMigrationPhase
+	if currentStatus != nil {
+		currentPhase = currentStatus.Phase
+	}
+	if newStatus != nil && newStatus.Phase != currentPhase {
+		switch newStatus.Phase {
+		case apiv1.StorageMigrationPhaseRunning:
+			r.Recorder.Eventf(cluster, "Normal", "StorageMigration",
+				"Migrating the instances to the new storage configuration: %v", pending)
+		case apiv1.StorageMigrationPhasePaused:
+			r.Recorder.Event(cluster, "Normal", "StorageMigration", "Storage migration paused")
+		case apiv1.StorageMigrationPhaseCompleted:
+			r.Recorder.Event(cluster, "Normal", "StorageMigration", "Storage migration completed")
+		}
+	}
+
+	return status.PatchWithOptimisticLock(ctx, r.Client, cluster, func(cluster *apiv1.Cluster) {
+		cluster.Status.StorageMigration = newStatus
+	})
+}
+
+// getStorageMigrationStatus computes the status of the storage migration
+// given the instances still pending migration
+func getStorageMigrationStatus(
+	currentStatus *apiv1.StorageMigrationStatus,
+	pending []string,
+	paused bool,
+	now metav1.Time,
+) *apiv1.StorageMigrationStatus {
+	if len(pending) == 0 {
+		if currentStatus == nil || currentStatus.Phase == apiv1.StorageMigrationPhaseCompleted {
+			return currentStatus
+		}
+
+		result := currentStatus.DeepCopy()
+		result.Phase = apiv1.StorageMigrationPhaseCompleted
+		result.PendingInstances = nil
+		result.CompletedAt = &now
+		return result
+	}
+
+	phase := apiv1.StorageMigrationPhaseRunning
+	if paused {
+		phase = apiv1.StorageMigrationPhasePaused
+	}
+
+	if currentStatus == nil || currentStatus.Phase == apiv1.StorageMigrationPhaseCompleted {
+		return &apiv1.StorageMigrationStatus{
+			Phase:            phase,
+			PendingInstances: pending,
+			StartedAt:        &now,
+		}
+	}
+
+	result := currentStatus.DeepCopy()
+	result.Phase = phase
+	result.PendingInstances = pending
+	return result
+}
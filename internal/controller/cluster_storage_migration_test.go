@@ -0,0 +1,298 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/reconciler/persistentvolumeclaim"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("storage migration status", func() {
+	now := metav1.NewTime(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+	later := metav1.NewTime(now.Add(time.Hour))
+
+	It("is not set when there's nothing to migrate", func() {
+		Expect(getStorageMigrationStatus(nil, nil, false, now)).To(BeNil())
+	})
+
+	It("starts when there are instances to migrate", func() {
+		status := getStorageMigrationStatus(nil, []string{"cluster-2", "cluster-1"}, false, now)
+		Expect(status).To(Equal(&apiv1.StorageMigrationStatus{
+			Phase:            apiv1.StorageMigrationPhaseRunning,
+			PendingInstances: []string{"cluster-2", "cluster-1"},
+			StartedAt:        &now,
+		}))
+	})
+
+	It("keeps the starting time while progressing and pausing", func() {
+		current := &apiv1.StorageMigrationStatus{
+			Phase:            apiv1.StorageMigrationPhaseRunning,
+			PendingInstances: []string{"cluster-2", "cluster-1"},
+			StartedAt:        &now,
+		}
+
+		status := getStorageMigrationStatus(current, []string{"cluster-1"}, true, later)
+		Expect(status.Phase).To(Equal(apiv1.StorageMigrationPhasePaused))
+		Expect(status.PendingInstances).To(Equal([]string{"cluster-1"}))
+		Expect(status.StartedAt).To(Equal(&now))
+		Expect(current.PendingInstances).To(HaveLen(2))
+	})
+
+	It("completes when every instance has been migrated", func() {
+		current := &apiv1.StorageMigrationStatus{
+			Phase:            apiv1.StorageMigrationPhaseRunning,
+			PendingInstances: []string{"cluster-1"},
+			StartedAt:        &now,
+		}
+
+		status := getStorageMigrationStatus(current, nil, false, later)
+		Expect(status).To(Equal(&apiv1.StorageMigrationStatus{
+			Phase:       apiv1.StorageMigrationPhaseCompleted,
+			StartedAt:   &now,
+			CompletedAt: &later,
+		}))
+		Expect(getStorageMigrationStatus(status, nil, false, metav1.NewTime(later.Add(time.Hour)))).To(BeIdenticalTo(status))
+	})
+
+	It("starts again after a completed migration", func() {
+		current := &apiv1.StorageMigrationStatus{
+			Phase:       apiv1.StorageMigrationPhaseCompleted,
+			StartedAt:   &now,
+			CompletedAt: &now,
+		}
+
+		status := getStorageMigrationStatus(current, []string{"cluster-3"}, false, later)
+		Expect(status.Phase).To(Equal(apiv1.StorageMigrationPhaseRunning))
+		Expect(status.StartedAt).To(Equal(&later))
+		Expect(status.CompletedAt).To(BeNil())
+	})
+})
+
+var _ = Describe("storage migration", func() {
+	var (
+		env       *testingEnvironment
+		cluster   *apiv1.Cluster
+		resources *managedResources
+	)
+
+	// createInstance creates the Pod and the PVCs of an instance,
+	// using the storage class currently set in the cluster
+	createInstance := func(ctx context.Context, serial int) {
+		pod, err := specs.NewInstance(ctx, *cluster, serial, true)
+		Expect(err).ToNot(HaveOccurred())
+		cluster.SetInheritedDataAndOwnership(&pod.ObjectMeta)
+		Expect(env.client.Create(ctx, pod)).To(Succeed())
+
+		resources.instances.Items = append(resources.instances.Items, *pod)
+		resources.pvcs.Items = append(resources.pvcs.Items,
+			newFakePVC(env.client, cluster, serial, persistentvolumeclaim.StatusReady)...)
+	}
+
+	// getInstancesStatus returns a healthy status for every instance
+	getInstancesStatus := func() postgres.PostgresqlStatusList {
+		var result postgres.PostgresqlStatusList
+		for _, pod := range resources.instances.Items {
+			result.Items = append(result.Items, postgres.PostgresqlStatus{
+				Pod:                 &pod,
+				IsPrimary:           pod.Name == cluster.Status.CurrentPrimary,
+				IsWalReceiverActive: pod.Name != cluster.Status.CurrentPrimary,
+				IsPodReady:          true,
+			})
+		}
+		return result
+	}
+
+	setStatus := func(ctx context.Context, instances int) {
+		cluster.Status.Instances = instances
+		cluster.Status.ReadyInstances = instances
+		cluster.Status.CurrentPrimary = specs.GetInstanceName(cluster.Name, 1)
+		cluster.Status.TargetPrimary = cluster.Status.CurrentPrimary
+		Expect(env.client.Status().Update(ctx, cluster)).To(Succeed())
+	}
+
+	BeforeEach(func(ctx SpecContext) {
+		env = buildTestEnvironment()
+		namespace := newFakeNamespace(env.client)
+		cluster = newFakeCNPGCluster(env.client, namespace, func(cluster *apiv1.Cluster) {
+			cluster.Spec.StorageConfiguration.StorageClass = ptr.To("old")
+		})
+		resources = &managedResources{}
+		for serial := 1; serial <= cluster.Spec.Instances; serial++ {
+			createInstance(ctx, serial)
+		}
+		cluster.Spec.StorageConfiguration.StorageClass = ptr.To("new")
+		Expect(env.client.Update(ctx, cluster)).To(Succeed())
+	})
+
+	It("migrates the replicas before the primary", func() {
+		pending := getInstancesPendingStorageMigration(cluster, resources)
+		Expect(pending).To(Equal([]string{
+			specs.GetInstanceName(cluster.Name, 1),
+			specs.GetInstanceName(cluster.Name, 2),
+			specs.GetInstanceName(cluster.Name, 3),
+		}))
+
+		cluster.Status.CurrentPrimary = specs.GetInstanceName(cluster.Name, 1)
+		pending = getInstancesPendingStorageMigration(cluster, resources)
+		Expect(pending).To(Equal([]string{
+			specs.GetInstanceName(cluster.Name, 2),
+			specs.GetInstanceName(cluster.Name, 3),
+			specs.GetInstanceName(cluster.Name, 1),
+		}))
+	})
+
+	It("doesn't create new instances while paused", func(ctx SpecContext) {
+		cluster.Annotations = map[string]string{utils.StorageMigrationAnnotationName: "disabled"}
+		Expect(env.client.Update(ctx, cluster)).To(Succeed())
+		setStatus(ctx, cluster.Spec.Instances)
+
+		res, err := env.clusterReconciler.reconcileStorageMigration(ctx, cluster, resources, getInstancesStatus())
+		Expect(err).ToNot(HaveOccurred())
+		Expect(res.IsZero()).To(BeTrue())
+		Expect(cluster.Status.StorageMigration).ToNot(BeNil())
+		Expect(cluster.Status.StorageMigration.Phase).To(Equal(apiv1.StorageMigrationPhasePaused))
+		Expect(cluster.Status.StorageMigration.PendingInstances).To(HaveLen(3))
+	})
+
+	It("removes a replica once its replacement is ready", func(ctx SpecContext) {
+		createInstance(ctx, 4)
+		setStatus(ctx, cluster.Spec.Instances+1)
+
+		res, err := env.clusterReconciler.reconcileStorageMigration(ctx, cluster, resources, getInstancesStatus())
+		Expect(err).ToNot(HaveOccurred())
+		Expect(res.RequeueAfter).To(Equal(time.Second))
+		Expect(cluster.Status.StorageMigration.Phase).To(Equal(apiv1.StorageMigrationPhaseRunning))
+
+		removedInstance := types.NamespacedName{
+			Name:      specs.GetInstanceName(cluster.Name, 2),
+			Namespace: cluster.Namespace,
+		}
+		Expect(isResourceExisting(ctx, env.client, &corev1.Pod{}, removedInstance)).To(BeFalse())
+		Expect(isResourceExisting(ctx, env.client, &corev1.PersistentVolumeClaim{}, removedInstance)).To(BeFalse())
+	})
+
+	It("waits for the new instance to be ready", func(ctx SpecContext) {
+		createInstance(ctx, 4)
+		setStatus(ctx, cluster.Spec.Instances+1)
+		instancesStatus := getInstancesStatus()
+		instancesStatus.Items[3].Error = errors.New("connection refused")
+
+		res, err := env.clusterReconciler.reconcileStorageMigration(ctx, cluster, resources, instancesStatus)
+		Expect(err).To(MatchError(ErrNextLoop))
+		Expect(res.RequeueAfter).To(Equal(time.Second))
+	})
+
+	It("switches over before removing the primary", func(ctx SpecContext) {
+		resources.instances.Items = resources.instances.Items[:1]
+		resources.pvcs.Items = resources.pvcs.Items[:1]
+		createInstance(ctx, 4)
+		createInstance(ctx, 5)
+		createInstance(ctx, 6)
+		setStatus(ctx, cluster.Spec.Instances+1)
+
+		_, err := env.clusterReconciler.reconcileStorageMigration(ctx, cluster, resources, getInstancesStatus())
+		Expect(err).ToNot(HaveOccurred())
+		Expect(cluster.Status.TargetPrimary).To(Equal(specs.GetInstanceName(cluster.Name, 4)))
+	})
+})
+
+var _ = Describe("storage migration after removing the WAL storage", func() {
+	var (
+		env       *testingEnvironment
+		cluster   *apiv1.Cluster
+		resources *managedResources
+	)
+
+	BeforeEach(func(ctx SpecContext) {
+		env = buildTestEnvironment()
+		namespace := newFakeNamespace(env.client)
+		cluster = newFakeCNPGCluster(env.client, namespace, func(cluster *apiv1.Cluster) {
+			cluster.Spec.WalStorage = &apiv1.StorageConfiguration{Size: "1G"}
+		})
+		resources = &managedResources{
+			pvcs: corev1.PersistentVolumeClaimList{
+				Items: generateClusterPVC(env.client, cluster, persistentvolumeclaim.StatusReady),
+			},
+			instances: corev1.PodList{
+				Items: generateFakeClusterPods(env.client, cluster, true),
+			},
+		}
+
+		cluster.Spec.WalStorage = nil
+		Expect(env.client.Update(ctx, cluster)).To(Succeed())
+		cluster.Status.CurrentPrimary = specs.GetInstanceName(cluster.Name, 1)
+	})
+
+	It("replaces every instance still having a WAL PVC", func() {
+		Expect(getInstancesPendingStorageMigration(cluster, resources)).To(Equal([]string{
+			specs.GetInstanceName(cluster.Name, 2),
+			specs.GetInstanceName(cluster.Name, 3),
+			specs.GetInstanceName(cluster.Name, 1),
+		}))
+	})
+
+	It("doesn't restart the instances still using the WAL PVC", func(ctx SpecContext) {
+		pod := resources.instances.Items[1]
+		Expect(isPodNeedingRollout(ctx, &pod, cluster).required).To(BeFalse())
+	})
+
+	It("rolls out the instances still using the WAL PVC when the image changes", func(ctx SpecContext) {
+		pod := resources.instances.Items[1]
+		cluster.Spec.ImageName = "postgres:18.0"
+		cluster.Status.Image = "postgres:18.0"
+		podRollout := isPodNeedingRollout(ctx, &pod, cluster)
+		Expect(podRollout.required).To(BeTrue())
+		Expect(podRollout.reason).To(ContainSubstring("image"))
+	})
+
+	It("keeps the WAL PVC attached when recreating a Pod", func(ctx SpecContext) {
+		instanceName := specs.GetInstanceName(cluster.Name, 2)
+		cluster.Status.DanglingPVC = []string{
+			instanceName,
+			persistentvolumeclaim.NewPgWalCalculator().GetName(instanceName),
+		}
+		instancesStatus := postgres.PostgresqlStatusList{
+			Items: []postgres.PostgresqlStatus{
+				{Pod: &resources.instances.Items[0]},
+				{Pod: &resources.instances.Items[2]},
+			},
+		}
+
+		pod, err := findInstancePodToCreate(ctx, cluster, instancesStatus, resources.pvcs.Items)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(pod.Name).To(Equal(instanceName))
+		Expect(persistentvolumeclaim.InstanceHasUnexpectedWalMount(cluster, pod)).To(BeTrue())
+	})
+})
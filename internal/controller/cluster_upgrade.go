@@ -25,6 +25,7 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
+	"time"
 
 	"github.com/cloudnative-pg/machinery/pkg/log"
 	corev1 "k8s.io/api/core/v1"
@@ -33,6 +34,7 @@ import (
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/configuration"
+	rolloutManager "github.com/cloudnative-pg/cloudnative-pg/internal/controller/rollout"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/webserver/client/remote"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/reconciler/persistentvolumeclaim"
@@ -49,6 +51,30 @@ var errLogShippingReplicaElected = errors.New("log shipping replica elected as a
 // of the operator configuration
 var errRolloutDelayed = errors.New("pod rollout delayed")
 
+// errMaintenanceWindowClosed is raised when a pod rollout have been delayed
+// because the maintenance window of the cluster is closed
+type errMaintenanceWindowClosed struct {
+	nextOpening time.Time
+}
+
+func (e *errMaintenanceWindowClosed) Error() string {
+	return fmt.Sprintf("pod rollout delayed until the maintenance window opens at %s",
+		e.nextOpening.Format(time.RFC3339))
+}
+
+// checkMaintenanceWindow returns an errMaintenanceWindowClosed error when
+// the maintenance window of the cluster is closed
+func checkMaintenanceWindow(cluster *apiv1.Cluster, now time.Time) error {
+	result, err := rolloutManager.EvaluateMaintenanceWindow(cluster.Spec.MaintenanceWindow, now)
+	if err != nil {
+		return err
+	}
+	if !result.Open {
+		return &errMaintenanceWindowClosed{nextOpening: result.NextOpening}
+	}
+	return nil
+}
+
 type rolloutReason = string
 
 func (r *ClusterReconciler) rolloutRequiredInstances(
@@ -79,6 +105,10 @@ func (r *ClusterReconciler) rolloutRequiredInstances(
 			continue
 		}
 
+		if err := checkMaintenanceWindow(cluster, time.Now()); err != nil {
+			return false, err
+		}
+
 		managerResult := r.rolloutManager.CoordinateRollout(client.ObjectKeyFromObject(cluster), postgresqlStatus.Pod.Name)
 		if !managerResult.RolloutAllowed {
 			r.Recorder.Eventf(
@@ -125,6 +155,10 @@ func (r *ClusterReconciler) rolloutRequiredInstances(
 		return false, nil
 	}
 
+	if err := checkMaintenanceWindow(cluster, time.Now()); err != nil {
+		return false, err
+	}
+
 	managerResult := r.rolloutManager.CoordinateRollout(
 		client.ObjectKeyFromObject(cluster),
 		primaryPostgresqlStatus.Pod.Name)
@@ -337,6 +371,7 @@ func isPodNeedingRollout(
 	cluster *apiv1.Cluster,
 ) rollout {
 	contextLogger := log.FromContext(ctx)
+
 	applyCheckers := func(checkers map[string]rolloutChecker) rollout {
 		for message, check := range checkers {
 			podRollout, err := check(ctx, pod, cluster)
@@ -628,6 +663,13 @@ func checkPodSpecIsOutdated(
 		return rollout{}, fmt.Errorf("while creating a new pod to check podSpec: %w", err)
 	}
 
+	// The instances still using the WAL volume of a removed WAL storage
+	// are replaced by the storage migration. Restarting them would not
+	// detach the WAL volume, as it still contains their WALs
+	if persistentvolumeclaim.InstanceHasUnexpectedWalMount(cluster, pod) {
+		specs.AddPgWalVolume(targetPod)
+	}
+
 	// the bootstrap init-container could change image after an operator upgrade.
 	// If in-place upgrades of the instance manager are enabled, we don't need rollout.
 	opCurrentImageName, err := specs.GetBootstrapControllerImageName(*pod)
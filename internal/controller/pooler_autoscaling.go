@@ -0,0 +1,394 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/cloudnative-pg/machinery/pkg/log"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+	"github.com/prometheus/common/model"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/url"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+const (
+	// poolerAutoscalingSyncPeriod is the interval between two evaluations
+	// of the PgBouncer metrics
+	poolerAutoscalingSyncPeriod = 15 * time.Second
+
+	// poolerAutoscalingTolerance is the relative distance from the target
+	// within which the autoscaler doesn't change the number of instances
+	poolerAutoscalingTolerance = 0.1
+
+	// poolerMetricsClientsWaiting and poolerMetricsMaxWait are the names of
+	// the metrics exposed by the PgBouncer exporter driving the autoscaler
+	poolerMetricsClientsWaiting = "cnpg_pgbouncer_pools_cl_waiting"
+	poolerMetricsMaxWait        = "cnpg_pgbouncer_pools_maxwait"
+	poolerMetricsMaxWaitUs      = "cnpg_pgbouncer_pools_maxwait_us"
+)
+
+// The reasons reported in the autoscaling status of a Pooler
+const (
+	poolerAutoscalingReasonScaledUp             = "ScaledUp"
+	poolerAutoscalingReasonScaledDown           = "ScaledDown"
+	poolerAutoscalingReasonScaleDownStabilizing = "ScaleDownStabilizing"
+	poolerAutoscalingReasonWithinTarget         = "WithinTarget"
+	poolerAutoscalingReasonPaused               = "Paused"
+	poolerAutoscalingReasonMetricsUnavailable   = "MetricsUnavailable"
+)
+
+// poolerPodMetrics are the metrics of a PgBouncer instance used by the autoscaler
+type poolerPodMetrics struct {
+	// The number of clients waiting for a server connection, across all the pools
+	clientsWaiting float64
+
+	// The waiting time of the oldest client in the queue, across all the pools
+	maxWait time.Duration
+}
+
+// reconcileAutoscaling evaluates the PgBouncer metrics and stores the number
+// of instances the Pooler deployment should have in the Pooler status
+func (r *PoolerReconciler) reconcileAutoscaling(
+	ctx context.Context,
+	pooler *apiv1.Pooler,
+	resources *poolerManagedResources,
+) (ctrl.Result, error) {
+	contextLogger := log.FromContext(ctx)
+
+	if !pooler.IsAutoscalingEnabled() {
+		if pooler.Status.Autoscaling == nil {
+			return ctrl.Result{}, nil
+		}
+
+		origPooler := pooler.DeepCopy()
+		pooler.Status.Autoscaling = nil
+		return ctrl.Result{}, r.Status().Patch(ctx, pooler, client.MergeFrom(origPooler))
+	}
+
+	if resources.Deployment == nil {
+		return ctrl.Result{RequeueAfter: poolerAutoscalingSyncPeriod}, nil
+	}
+
+	paused := pooler.Spec.PgBouncer != nil && pooler.Spec.PgBouncer.IsPaused()
+
+	var metrics []poolerPodMetrics
+	if !paused {
+		var err error
+		if metrics, err = r.collectPoolerMetrics(ctx, pooler); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	current := ptr.Deref(resources.Deployment.Spec.Replicas, 1)
+	updatedStatus := evaluatePoolerAutoscaling(
+		pooler.Spec.Autoscaling,
+		pooler.Status.Autoscaling,
+		current,
+		paused,
+		metrics,
+		time.Now(),
+	)
+
+	if updatedStatus.DesiredInstances != current {
+		contextLogger.Info("Scaling pooler",
+			"from", current,
+			"to", updatedStatus.DesiredInstances,
+			"reason", updatedStatus.Reason)
+		r.Recorder.Event(pooler, "Normal", "PoolerScaled", updatedStatus.Message)
+	}
+
+	// The status is patched only when the decision changes: every patch
+	// triggers a new reconciliation, and the metrics are evaluated again
+	// anyway after the sync period
+	if isPoolerAutoscalingDecisionChanged(pooler.Status.Autoscaling, updatedStatus) {
+		origPooler := pooler.DeepCopy()
+		pooler.Status.Autoscaling = updatedStatus
+		if err := r.Status().Patch(ctx, pooler, client.MergeFrom(origPooler)); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	return ctrl.Result{RequeueAfter: poolerAutoscalingSyncPeriod}, nil
+}
+
+// isPoolerAutoscalingDecisionChanged checks if the autoscaler took a
+// different decision, ignoring the time of the evaluation and the
+// observed metrics which change at every evaluation
+func isPoolerAutoscalingDecisionChanged(previous, updated *apiv1.PoolerAutoscalingStatus) bool {
+	if previous == nil || updated == nil {
+		return previous != updated
+	}
+
+	withoutObservations := func(status *apiv1.PoolerAutoscalingStatus) *apiv1.PoolerAutoscalingStatus {
+		result := status.DeepCopy()
+		result.LastEvaluationTime = nil
+		result.CurrentClientsWaiting = nil
+		result.CurrentMaxWait = nil
+		return result
+	}
+
+	return !reflect.DeepEqual(withoutObservations(previous), withoutObservations(updated))
+}
+
+// collectPoolerMetrics scrapes the metrics of every ready PgBouncer instance
+// of the Pooler. Instances whose metrics cannot be retrieved are skipped.
+func (r *PoolerReconciler) collectPoolerMetrics(
+	ctx context.Context,
+	pooler *apiv1.Pooler,
+) ([]poolerPodMetrics, error) {
+	contextLogger := log.FromContext(ctx)
+
+	var pods corev1.PodList
+	if err := r.List(ctx, &pods,
+		client.InNamespace(pooler.Namespace),
+		client.MatchingLabels{utils.PgbouncerNameLabel: pooler.Name},
+	); err != nil {
+		return nil, fmt.Errorf("while listing pooler pods: %w", err)
+	}
+
+	result := make([]poolerPodMetrics, 0, len(pods.Items))
+	for idx := range pods.Items {
+		pod := &pods.Items[idx]
+		if !utils.IsPodActive(*pod) || !utils.IsPodReady(*pod) || pod.Status.PodIP == "" {
+			continue
+		}
+
+		podMetrics, err := fetchPoolerPodMetrics(ctx, pod)
+		if err != nil {
+			contextLogger.Warning("Cannot retrieve PgBouncer metrics, skipping instance",
+				"pod", pod.Name, "error", err.Error())
+			continue
+		}
+		result = append(result, *podMetrics)
+	}
+
+	return result, nil
+}
+
+// fetchPoolerPodMetrics retrieves the metrics of a PgBouncer instance from
+// its exporter
+func fetchPoolerPodMetrics(ctx context.Context, pod *corev1.Pod) (*poolerPodMetrics, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	metricsURL := url.Build("http", pod.Status.PodIP, url.PathMetrics, url.PgBouncerMetricsPort)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, metricsURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+
+	return parsePoolerPodMetrics(resp.Body)
+}
+
+// parsePoolerPodMetrics extracts the metrics used by the autoscaler from
+// the output of the PgBouncer exporter
+func parsePoolerPodMetrics(in io.Reader) (*poolerPodMetrics, error) {
+	parser := expfmt.NewTextParser(model.UTF8Validation)
+	families, err := parser.TextToMetricFamilies(in)
+	if err != nil {
+		return nil, fmt.Errorf("while parsing PgBouncer metrics: %w", err)
+	}
+
+	clientsWaiting, ok := families[poolerMetricsClientsWaiting]
+	if !ok {
+		return nil, fmt.Errorf("metric %s not found", poolerMetricsClientsWaiting)
+	}
+
+	result := &poolerPodMetrics{}
+	for _, metric := range clientsWaiting.GetMetric() {
+		result.clientsWaiting += metric.GetGauge().GetValue()
+	}
+
+	// The waiting time is exposed in two metrics, the seconds
+	// and the microseconds part, sharing the same labels
+	microseconds := make(map[string]float64)
+	for _, metric := range families[poolerMetricsMaxWaitUs].GetMetric() {
+		microseconds[poolerMetricLabelsKey(metric)] = metric.GetGauge().GetValue()
+	}
+	for _, metric := range families[poolerMetricsMaxWait].GetMetric() {
+		maxWait := time.Duration(metric.GetGauge().GetValue()*float64(time.Second)) +
+			time.Duration(microseconds[poolerMetricLabelsKey(metric)]*float64(time.Microsecond))
+		result.maxWait = max(result.maxWait, maxWait)
+	}
+
+	return result, nil
+}
+
+// poolerMetricLabelsKey identifies a sample of a metric by its labels
+func poolerMetricLabelsKey(metric *dto.Metric) string {
+	var key strings.Builder
+	for _, label := range metric.GetLabel() {
+		key.WriteString(label.GetName())
+		key.WriteRune('=')
+		key.WriteString(label.GetValue())
+		key.WriteRune(',')
+	}
+	return key.String()
+}
+
+// evaluatePoolerAutoscaling computes the number of instances a Pooler should
+// have given the metrics of its PgBouncer instances, following the same
+// algorithm of the Kubernetes HorizontalPodAutoscaler. Scaling up happens
+// immediately, while scaling down happens only after the recommendation has
+// stayed lower than the current number of instances for the whole
+// stabilization window.
+func evaluatePoolerAutoscaling(
+	spec *apiv1.PoolerAutoscalingSpec,
+	previous *apiv1.PoolerAutoscalingStatus,
+	current int32,
+	paused bool,
+	metrics []poolerPodMetrics,
+	now time.Time,
+) *apiv1.PoolerAutoscalingStatus {
+	status := &apiv1.PoolerAutoscalingStatus{}
+	if previous != nil {
+		status = previous.DeepCopy()
+	}
+	status.LastEvaluationTime = ptr.To(metav1.NewTime(now))
+	status.CurrentClientsWaiting = nil
+	status.CurrentMaxWait = nil
+
+	// Whatever the metrics say, the number of instances
+	// must respect the configured bounds
+	bounded := spec.Clamp(current)
+	target := bounded
+
+	switch {
+	case paused:
+		status.ScaleDownRecommendedSince = nil
+		status.Reason = poolerAutoscalingReasonPaused
+		status.Message = "The pooler is paused, autoscaling is suspended"
+
+	case len(metrics) == 0:
+		status.ScaleDownRecommendedSince = nil
+		status.Reason = poolerAutoscalingReasonMetricsUnavailable
+		status.Message = "No PgBouncer metrics available, autoscaling is suspended"
+
+	default:
+		recommended := recommendPoolerInstances(spec, status, bounded, metrics)
+
+		switch {
+		case recommended > bounded:
+			status.ScaleDownRecommendedSince = nil
+			target = recommended
+
+		case recommended < bounded:
+			if status.ScaleDownRecommendedSince == nil {
+				status.ScaleDownRecommendedSince = ptr.To(metav1.NewTime(now))
+			}
+			if now.Sub(status.ScaleDownRecommendedSince.Time) >= spec.GetScaleDownStabilizationWindow() {
+				status.ScaleDownRecommendedSince = nil
+				target = recommended
+			} else {
+				status.Reason = poolerAutoscalingReasonScaleDownStabilizing
+				status.Message = fmt.Sprintf(
+					"Recommended %d instances, waiting for the scale down stabilization window", recommended)
+			}
+
+		default:
+			status.ScaleDownRecommendedSince = nil
+			status.Reason = poolerAutoscalingReasonWithinTarget
+			status.Message = "The metrics are within the configured targets"
+		}
+	}
+
+	status.DesiredInstances = target
+	switch {
+	case target > current:
+		status.LastScaleTime = ptr.To(metav1.NewTime(now))
+		status.Reason = poolerAutoscalingReasonScaledUp
+		status.Message = fmt.Sprintf("Scaled up from %d to %d instances", current, target)
+	case target < current:
+		status.LastScaleTime = ptr.To(metav1.NewTime(now))
+		status.Reason = poolerAutoscalingReasonScaledDown
+		status.Message = fmt.Sprintf("Scaled down from %d to %d instances", current, target)
+	}
+
+	return status
+}
+
+// recommendPoolerInstances computes the number of instances needed to meet
+// every configured target, recording the observed values in the status
+func recommendPoolerInstances(
+	spec *apiv1.PoolerAutoscalingSpec,
+	status *apiv1.PoolerAutoscalingStatus,
+	current int32,
+	metrics []poolerPodMetrics,
+) int32 {
+	var totalClientsWaiting float64
+	var maxWait time.Duration
+	for _, podMetrics := range metrics {
+		totalClientsWaiting += podMetrics.clientsWaiting
+		maxWait = max(maxWait, podMetrics.maxWait)
+	}
+	averageClientsWaiting := totalClientsWaiting / float64(len(metrics))
+
+	status.CurrentClientsWaiting = ptr.To(int32(math.Round(averageClientsWaiting)))
+	status.CurrentMaxWait = &metav1.Duration{Duration: maxWait}
+
+	// The desired number of instances is computed on the instances
+	// we collected metrics from, like the HorizontalPodAutoscaler does
+	// with the ready pods
+	readyInstances := int32(len(metrics))
+	scaleTo := func(ratio float64) int32 {
+		if math.Abs(ratio-1) <= poolerAutoscalingTolerance {
+			return current
+		}
+		return int32(math.Ceil(float64(readyInstances) * ratio))
+	}
+
+	var recommended int32
+	if spec.TargetClientsWaiting != nil {
+		recommended = max(recommended, scaleTo(averageClientsWaiting/float64(*spec.TargetClientsWaiting)))
+	}
+	if spec.TargetMaxWait != nil && spec.TargetMaxWait.Duration > 0 {
+		recommended = max(recommended, scaleTo(maxWait.Seconds()/spec.TargetMaxWait.Seconds()))
+	}
+
+	return spec.Clamp(recommended)
+}
@@ -0,0 +1,245 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package controller
+
+import (
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/configuration"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("parsePoolerPodMetrics", func() {
+	It("sums the waiting clients and finds the longest wait across pools", func() {
+		const exposition = `# HELP cnpg_pgbouncer_pools_cl_waiting Client connections waiting.
+# TYPE cnpg_pgbouncer_pools_cl_waiting gauge
+cnpg_pgbouncer_pools_cl_waiting{database="app",user="app"} 3
+cnpg_pgbouncer_pools_cl_waiting{database="pgbouncer",user="pgbouncer"} 2
+# HELP cnpg_pgbouncer_pools_maxwait How long the first client in the queue has waited, in seconds.
+# TYPE cnpg_pgbouncer_pools_maxwait gauge
+cnpg_pgbouncer_pools_maxwait{database="app",user="app"} 1
+cnpg_pgbouncer_pools_maxwait{database="pgbouncer",user="pgbouncer"} 0
+# HELP cnpg_pgbouncer_pools_maxwait_us Microsecond part of the maximum waiting time.
+# TYPE cnpg_pgbouncer_pools_maxwait_us gauge
+cnpg_pgbouncer_pools_maxwait_us{database="app",user="app"} 500000
+cnpg_pgbouncer_pools_maxwait_us{database="pgbouncer",user="pgbouncer"} 900000
+`
+		metrics, err := parsePoolerPodMetrics(strings.NewReader(exposition))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(metrics.clientsWaiting).To(BeEquivalentTo(5))
+		Expect(metrics.maxWait).To(Equal(1500 * time.Millisecond))
+	})
+
+	It("fails when the PgBouncer metrics are missing", func() {
+		_, err := parsePoolerPodMetrics(strings.NewReader("# TYPE other gauge\nother 1\n"))
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("evaluatePoolerAutoscaling", func() {
+	var spec *apiv1.PoolerAutoscalingSpec
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	BeforeEach(func() {
+		spec = &apiv1.PoolerAutoscalingSpec{
+			MinInstances:         1,
+			MaxInstances:         6,
+			TargetClientsWaiting: ptr.To(int32(10)),
+		}
+	})
+
+	podsWaiting := func(clientsWaiting ...float64) []poolerPodMetrics {
+		result := make([]poolerPodMetrics, len(clientsWaiting))
+		for idx := range clientsWaiting {
+			result[idx].clientsWaiting = clientsWaiting[idx]
+		}
+		return result
+	}
+
+	It("scales up immediately when the target is exceeded", func() {
+		status := evaluatePoolerAutoscaling(spec, nil, 2, false, podsWaiting(20, 20), now)
+		Expect(status.DesiredInstances).To(BeEquivalentTo(4))
+		Expect(status.Reason).To(Equal(poolerAutoscalingReasonScaledUp))
+		Expect(status.LastScaleTime.Time).To(Equal(now))
+		Expect(status.CurrentClientsWaiting).To(HaveValue(BeEquivalentTo(20)))
+	})
+
+	It("never exceeds the maximum number of instances", func() {
+		status := evaluatePoolerAutoscaling(spec, nil, 2, false, podsWaiting(100, 100), now)
+		Expect(status.DesiredInstances).To(BeEquivalentTo(6))
+	})
+
+	It("keeps the current instances within the tolerance", func() {
+		status := evaluatePoolerAutoscaling(spec, nil, 2, false, podsWaiting(10, 11), now)
+		Expect(status.DesiredInstances).To(BeEquivalentTo(2))
+		Expect(status.Reason).To(Equal(poolerAutoscalingReasonWithinTarget))
+		Expect(status.LastScaleTime).To(BeNil())
+	})
+
+	It("scales up using the maximum wait time target", func() {
+		spec.TargetClientsWaiting = nil
+		spec.TargetMaxWait = &metav1.Duration{Duration: time.Second}
+		metrics := []poolerPodMetrics{{maxWait: 3 * time.Second}, {}}
+		status := evaluatePoolerAutoscaling(spec, nil, 2, false, metrics, now)
+		Expect(status.DesiredInstances).To(BeEquivalentTo(6))
+		Expect(status.CurrentMaxWait.Duration).To(Equal(3 * time.Second))
+	})
+
+	It("scales down only after the stabilization window", func() {
+		status := evaluatePoolerAutoscaling(spec, nil, 4, false, podsWaiting(0, 0, 0, 0), now)
+		Expect(status.DesiredInstances).To(BeEquivalentTo(4))
+		Expect(status.Reason).To(Equal(poolerAutoscalingReasonScaleDownStabilizing))
+		Expect(status.ScaleDownRecommendedSince.Time).To(Equal(now))
+
+		status = evaluatePoolerAutoscaling(spec, status, 4, false, podsWaiting(0, 0, 0, 0), now.Add(time.Minute))
+		Expect(status.DesiredInstances).To(BeEquivalentTo(4))
+		Expect(status.ScaleDownRecommendedSince.Time).To(Equal(now))
+
+		status = evaluatePoolerAutoscaling(spec, status, 4, false, podsWaiting(0, 0, 0, 0), now.Add(5*time.Minute))
+		Expect(status.DesiredInstances).To(BeEquivalentTo(1))
+		Expect(status.Reason).To(Equal(poolerAutoscalingReasonScaledDown))
+		Expect(status.ScaleDownRecommendedSince).To(BeNil())
+	})
+
+	It("restarts the stabilization window when the load comes back", func() {
+		status := evaluatePoolerAutoscaling(spec, nil, 4, false, podsWaiting(0, 0, 0, 0), now)
+		Expect(status.ScaleDownRecommendedSince).ToNot(BeNil())
+
+		status = evaluatePoolerAutoscaling(spec, status, 4, false, podsWaiting(10, 10, 10, 10), now.Add(time.Minute))
+		Expect(status.DesiredInstances).To(BeEquivalentTo(4))
+		Expect(status.ScaleDownRecommendedSince).To(BeNil())
+	})
+
+	It("doesn't take decisions while the pooler is paused", func() {
+		status := evaluatePoolerAutoscaling(spec, nil, 3, true, nil, now)
+		Expect(status.DesiredInstances).To(BeEquivalentTo(3))
+		Expect(status.Reason).To(Equal(poolerAutoscalingReasonPaused))
+	})
+
+	It("doesn't take decisions without metrics", func() {
+		status := evaluatePoolerAutoscaling(spec, nil, 3, false, nil, now)
+		Expect(status.DesiredInstances).To(BeEquivalentTo(3))
+		Expect(status.Reason).To(Equal(poolerAutoscalingReasonMetricsUnavailable))
+	})
+
+	It("enforces the bounds even without metrics", func() {
+		spec.MinInstances = 2
+		status := evaluatePoolerAutoscaling(spec, nil, 1, true, nil, now)
+		Expect(status.DesiredInstances).To(BeEquivalentTo(2))
+		Expect(status.Reason).To(Equal(poolerAutoscalingReasonScaledUp))
+	})
+})
+
+var _ = Describe("pooler autoscaling reconciliation", func() {
+	var env *testingEnvironment
+
+	BeforeEach(func() {
+		env = buildTestEnvironment()
+		configuration.Current = configuration.NewConfiguration()
+	})
+
+	AfterEach(func() {
+		configuration.Current = configuration.NewConfiguration()
+	})
+
+	It("reports the decision in the status and scales the deployment", func(ctx SpecContext) {
+		namespace := newFakeNamespace(env.client)
+		cluster := newFakeCNPGCluster(env.client, namespace)
+		pooler := newFakePooler(env.client, cluster)
+		res := &poolerManagedResources{Deployment: nil, Cluster: cluster}
+
+		Expect(env.poolerReconciler.updateDeployment(ctx, pooler, res)).To(Succeed())
+		Expect(res.Deployment.Spec.Replicas).To(HaveValue(BeEquivalentTo(1)))
+
+		pooler.Spec.Autoscaling = &apiv1.PoolerAutoscalingSpec{
+			MinInstances:         2,
+			MaxInstances:         4,
+			TargetClientsWaiting: ptr.To(int32(10)),
+		}
+		pooler.Spec.PgBouncer.Paused = ptr.To(true)
+		Expect(env.client.Update(ctx, pooler)).To(Succeed())
+
+		result, err := env.poolerReconciler.reconcileAutoscaling(ctx, pooler, res)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result.RequeueAfter).To(Equal(poolerAutoscalingSyncPeriod))
+		Expect(pooler.Status.Autoscaling).ToNot(BeNil())
+		Expect(pooler.Status.Autoscaling.DesiredInstances).To(BeEquivalentTo(2))
+
+		Expect(env.poolerReconciler.updateDeployment(ctx, pooler, res)).To(Succeed())
+		Expect(res.Deployment.Spec.Replicas).To(HaveValue(BeEquivalentTo(2)))
+
+		// A new decision of the autoscaler doesn't change the Pooler
+		// specification, but the deployment must be scaled anyway
+		hash := res.Deployment.Annotations[utils.PoolerSpecHashAnnotationName]
+		pooler.Status.Autoscaling.DesiredInstances = 3
+		Expect(env.poolerReconciler.updateDeployment(ctx, pooler, res)).To(Succeed())
+
+		deployment := getPoolerDeployment(ctx, env.client, pooler)
+		Expect(deployment.Annotations[utils.PoolerSpecHashAnnotationName]).To(Equal(hash))
+		Expect(deployment.Spec.Replicas).To(HaveValue(BeEquivalentTo(3)))
+	})
+
+	It("patches the status only when the decision changes", func(ctx SpecContext) {
+		namespace := newFakeNamespace(env.client)
+		cluster := newFakeCNPGCluster(env.client, namespace)
+		pooler := newFakePooler(env.client, cluster)
+		res := &poolerManagedResources{Deployment: nil, Cluster: cluster}
+		Expect(env.poolerReconciler.updateDeployment(ctx, pooler, res)).To(Succeed())
+
+		pooler.Spec.Autoscaling = &apiv1.PoolerAutoscalingSpec{
+			MinInstances:         1,
+			MaxInstances:         4,
+			TargetClientsWaiting: ptr.To(int32(10)),
+		}
+		pooler.Spec.PgBouncer.Paused = ptr.To(true)
+		Expect(env.client.Update(ctx, pooler)).To(Succeed())
+
+		_, err := env.poolerReconciler.reconcileAutoscaling(ctx, pooler, res)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(pooler.Status.Autoscaling.Reason).To(Equal(poolerAutoscalingReasonPaused))
+		resourceVersion := pooler.ResourceVersion
+
+		_, err = env.poolerReconciler.reconcileAutoscaling(ctx, pooler, res)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(pooler.ResourceVersion).To(Equal(resourceVersion))
+	})
+
+	It("clears the status when autoscaling is disabled", func(ctx SpecContext) {
+		namespace := newFakeNamespace(env.client)
+		cluster := newFakeCNPGCluster(env.client, namespace)
+		pooler := newFakePooler(env.client, cluster)
+
+		pooler.Status.Autoscaling = &apiv1.PoolerAutoscalingStatus{DesiredInstances: 3}
+		Expect(env.client.Status().Update(ctx, pooler)).To(Succeed())
+
+		result, err := env.poolerReconciler.reconcileAutoscaling(ctx, pooler, &poolerManagedResources{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result.IsZero()).To(BeTrue())
+		Expect(pooler.Status.Autoscaling).To(BeNil())
+	})
+})
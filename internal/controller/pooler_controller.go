@@ -23,6 +23,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
 	"time"
 
 	"github.com/cloudnative-pg/machinery/pkg/log"
@@ -60,6 +61,7 @@ type PoolerReconciler struct {
 // +kubebuilder:rbac:groups="",resources=secrets/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups="",resources=services,verbs=get;create;delete;update;patch;list;watch
 // +kubebuilder:rbac:groups="apps",resources=deployments,verbs=get;create;delete;update;patch;list;watch
+// +kubebuilder:rbac:groups="",resources=pods,verbs=get;list;watch
 
 // Reconcile implements the main reconciliation loop for pooler objects
 func (r *PoolerReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
@@ -120,8 +122,18 @@ func (r *PoolerReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctr
 		}
 	}
 
+	// Decide the number of instances when autoscaling is enabled
+	result, err := r.reconcileAutoscaling(ctx, &pooler, resources)
+	if err != nil {
+		if apierrs.IsConflict(err) {
+			contextLogger.Debug("Conflict while reconciling pooler autoscaling", "error", err)
+			return ctrl.Result{RequeueAfter: time.Second}, nil
+		}
+		return ctrl.Result{}, fmt.Errorf("while reconciling pooler autoscaling: %w", err)
+	}
+
 	// Take the required actions to align the spec with the collected status
-	return ctrl.Result{}, r.updateOwnedObjects(ctx, &pooler, resources)
+	return result, r.updateOwnedObjects(ctx, &pooler, resources)
 }
 
 // SetupWithManager setup this controller inside the controller manager
@@ -262,6 +274,15 @@ func (r *PoolerReconciler) waitForPrerequisites(
 		return waitResult
 	}
 
+	// PgCat needs the credentials of every configured user
+	for secretName, secret := range resources.PgCatUserSecrets {
+		if secret == nil {
+			contextLogger.Info("PgCat user secret not found, waiting 30 seconds",
+				"secret", secretName)
+			return waitResult
+		}
+	}
+
 	return nil
 }
 
@@ -327,6 +348,16 @@ func getPoolersUsingSecret(poolers apiv1.PoolerList, secret *corev1.Secret) (req
 			)
 			continue
 		}
+
+		if slices.Contains(pooler.GetPgCatUserSecretNames(), secret.Name) {
+			requests = append(requests,
+				types.NamespacedName{
+					Name:      pooler.Name,
+					Namespace: pooler.Namespace,
+				},
+			)
+			continue
+		}
 	}
 	return requests
 }
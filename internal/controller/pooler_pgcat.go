@@ -0,0 +1,117 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package controller
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	"github.com/cloudnative-pg/machinery/pkg/log"
+	"github.com/sethvargo/go-password/password"
+	appsv1 "k8s.io/api/apps/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs/pgbouncer"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs/pgcat"
+)
+
+// buildPoolerDeployment generates the deployment of the Pooler
+// using the builder of the selected implementation
+func buildPoolerDeployment(pooler *apiv1.Pooler, cluster *apiv1.Cluster) (*appsv1.Deployment, error) {
+	switch pooler.GetImplementation() {
+	case apiv1.PoolerImplementationPgCat:
+		return pgcat.Deployment(pooler, cluster)
+	default:
+		return pgbouncer.Deployment(pooler, cluster)
+	}
+}
+
+// reconcilePgCatConfiguration generates the PgCat configuration and stores
+// it in the secret mounted by the PgCat pods, which reload it automatically
+func (r *PoolerReconciler) reconcilePgCatConfiguration(
+	ctx context.Context,
+	pooler *apiv1.Pooler,
+	resources *poolerManagedResources,
+) error {
+	contextLogger := log.FromContext(ctx)
+
+	users := make(map[string]pgcat.User, len(resources.PgCatUserSecrets))
+	for secretName, secret := range resources.PgCatUserSecrets {
+		if secret == nil {
+			return fmt.Errorf("missing PgCat user secret %s", secretName)
+		}
+
+		user, err := pgcat.UserFromSecret(secret)
+		if err != nil {
+			return err
+		}
+		users[secretName] = user
+	}
+
+	// The password of the administrator is generated once and then
+	// preserved across the reconciliation loops
+	var adminPassword string
+	if resources.PgCatConfigSecret != nil {
+		adminPassword = string(resources.PgCatConfigSecret.Data[pgcat.AdminPasswordKey])
+	}
+	if adminPassword == "" {
+		var err error
+		if adminPassword, err = password.Generate(64, 10, 0, false, true); err != nil {
+			return err
+		}
+	}
+
+	config, err := pgcat.BuildConfiguration(pooler, resources.Cluster, users, adminPassword)
+	if err != nil {
+		return fmt.Errorf("while generating the PgCat configuration: %w", err)
+	}
+
+	expectedSecret := pgcat.ConfigSecret(pooler, resources.Cluster, config, adminPassword)
+	if err := ctrl.SetControllerReference(pooler, expectedSecret, r.Scheme); err != nil {
+		return err
+	}
+
+	if resources.PgCatConfigSecret == nil {
+		contextLogger.Info("Creating PgCat configuration secret", "secret", expectedSecret.Name)
+		if err := r.Create(ctx, expectedSecret); err != nil && !apierrs.IsAlreadyExists(err) {
+			return err
+		}
+		resources.PgCatConfigSecret = expectedSecret
+		return nil
+	}
+
+	if bytes.Equal(resources.PgCatConfigSecret.Data[pgcat.ConfigFileName], config) {
+		return nil
+	}
+
+	updatedSecret := resources.PgCatConfigSecret.DeepCopy()
+	updatedSecret.Data = expectedSecret.Data
+	contextLogger.Info("Updating PgCat configuration secret", "secret", updatedSecret.Name)
+	if err := r.Patch(ctx, updatedSecret, client.MergeFrom(resources.PgCatConfigSecret)); err != nil {
+		return err
+	}
+	resources.PgCatConfigSecret = updatedSecret
+
+	return nil
+}
@@ -0,0 +1,123 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package controller
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/configuration"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs/pgcat"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("pgcat poolers", func() {
+	var env *testingEnvironment
+
+	BeforeEach(func() {
+		env = buildTestEnvironment()
+		configuration.Current = configuration.NewConfiguration()
+	})
+
+	newPgCatPooler := func(ctx SpecContext) (*apiv1.Pooler, *apiv1.Cluster) {
+		namespace := newFakeNamespace(env.client)
+		cluster := newFakeCNPGCluster(env.client, namespace)
+		pooler := newFakePooler(env.client, cluster)
+
+		pooler.Spec.Implementation = apiv1.PoolerImplementationPgCat
+		pooler.Spec.PgBouncer = nil
+		pooler.Spec.PgCat = &apiv1.PgCatSpec{
+			Databases: []apiv1.PgCatDatabase{
+				{
+					Name:  "app",
+					Users: []apiv1.PgCatUser{{Secret: apiv1.LocalObjectReference{Name: "app-user"}}},
+				},
+			},
+		}
+		Expect(env.client.Update(ctx, pooler)).To(Succeed())
+
+		return pooler, cluster
+	}
+
+	It("uses the PgCat deployment builder", func(ctx SpecContext) {
+		pooler, cluster := newPgCatPooler(ctx)
+		deployment, err := buildPoolerDeployment(pooler, cluster)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(deployment.Spec.Template.Spec.Containers[0].Name).To(Equal(pgcat.ContainerName))
+	})
+
+	It("waits for the user secrets and generates the configuration", func(ctx SpecContext) {
+		pooler, cluster := newPgCatPooler(ctx)
+
+		resources, err := env.poolerReconciler.getManagedResources(ctx, pooler)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(resources.PgCatUserSecrets).To(HaveKeyWithValue("app-user", BeNil()))
+		Expect(resources.PgCatConfigSecret).To(BeNil())
+
+		userSecret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "app-user", Namespace: pooler.Namespace},
+			Type:       corev1.SecretTypeBasicAuth,
+			Data: map[string][]byte{
+				corev1.BasicAuthUsernameKey: []byte("app"),
+				corev1.BasicAuthPasswordKey: []byte("first"),
+			},
+		}
+		Expect(env.client.Create(ctx, userSecret)).To(Succeed())
+
+		resources, err = env.poolerReconciler.getManagedResources(ctx, pooler)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(resources.Cluster.Name).To(Equal(cluster.Name))
+		Expect(env.poolerReconciler.reconcilePgCatConfiguration(ctx, pooler, resources)).To(Succeed())
+
+		var configSecret corev1.Secret
+		configKey := types.NamespacedName{Name: pgcat.ConfigSecretName(pooler), Namespace: pooler.Namespace}
+		Expect(env.client.Get(ctx, configKey, &configSecret)).To(Succeed())
+		Expect(isOwnedByPooler(pooler.Name, &configSecret)).To(BeTrue())
+		Expect(string(configSecret.Data[pgcat.ConfigFileName])).To(ContainSubstring(`password = "first"`))
+		adminPassword := configSecret.Data[pgcat.AdminPasswordKey]
+		Expect(adminPassword).ToNot(BeEmpty())
+
+		By("updating the configuration when the credentials change", func() {
+			userSecret.Data[corev1.BasicAuthPasswordKey] = []byte("second")
+			Expect(env.client.Update(ctx, userSecret)).To(Succeed())
+
+			resources, err = env.poolerReconciler.getManagedResources(ctx, pooler)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(env.poolerReconciler.reconcilePgCatConfiguration(ctx, pooler, resources)).To(Succeed())
+
+			Expect(env.client.Get(ctx, configKey, &configSecret)).To(Succeed())
+			Expect(string(configSecret.Data[pgcat.ConfigFileName])).To(ContainSubstring(`password = "second"`))
+			Expect(configSecret.Data[pgcat.AdminPasswordKey]).To(Equal(adminPassword))
+		})
+	})
+
+	It("maps the user secrets to the pooler", func(ctx SpecContext) {
+		pooler, _ := newPgCatPooler(ctx)
+		secret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "app-user", Namespace: pooler.Namespace},
+		}
+		requests := getPoolersUsingSecret(apiv1.PoolerList{Items: []apiv1.Pooler{*pooler}}, secret)
+		Expect(requests).To(ConsistOf(types.NamespacedName{Name: pooler.Name, Namespace: pooler.Namespace}))
+	})
+})
@@ -29,6 +29,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs/pgcat"
 )
 
 // poolerManagedResources contains all the resources that are going to be
@@ -66,6 +67,13 @@ type poolerManagedResources struct {
 	ServiceAccount *corev1.ServiceAccount
 	RoleBinding    *rbacv1.RoleBinding
 	Role           *rbacv1.Role
+
+	// The secrets containing the credentials of the PgCat users,
+	// indexed by name. Missing secrets have a nil value.
+	PgCatUserSecrets map[string]*corev1.Secret
+
+	// The secret containing the generated PgCat configuration
+	PgCatConfigSecret *corev1.Secret
 }
 
 // getManagedResources detects the list of the resources created and manager
@@ -171,6 +179,23 @@ func (r *PoolerReconciler) getManagedResources(
 		return nil, err
 	}
 
+	if pooler.GetImplementation() == apiv1.PoolerImplementationPgCat {
+		result.PgCatUserSecrets = make(map[string]*corev1.Secret)
+		for _, secretName := range pooler.GetPgCatUserSecretNames() {
+			result.PgCatUserSecrets[secretName], err = getSecretOrNil(
+				ctx, r.Client, client.ObjectKey{Name: secretName, Namespace: pooler.Namespace})
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		result.PgCatConfigSecret, err = getSecretOrNil(
+			ctx, r.Client, client.ObjectKey{Name: pgcat.ConfigSecretName(pooler), Namespace: pooler.Namespace})
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	return result, nil
 }
 
@@ -92,6 +92,8 @@ func (r *PoolerReconciler) updatePoolerStatus(
 		updatedStatus.Instances = resources.Deployment.Status.Replicas
 	}
 
+	updatedStatus.Implementation = pooler.GetImplementation()
+
 	// then update the status if anything changed
 	if !reflect.DeepEqual(pooler.Status, updatedStatus) {
 		pooler.Status = *updatedStatus
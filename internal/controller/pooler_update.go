@@ -28,6 +28,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	apierrs "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
@@ -51,6 +52,12 @@ func (r *PoolerReconciler) updateOwnedObjects(
 		return err
 	}
 
+	if pooler.GetImplementation() == apiv1.PoolerImplementationPgCat {
+		if err := r.reconcilePgCatConfiguration(ctx, pooler, resources); err != nil {
+			return err
+		}
+	}
+
 	if err := r.updateDeployment(ctx, pooler, resources); err != nil {
 		return err
 	}
@@ -72,7 +79,7 @@ func (r *PoolerReconciler) updateDeployment(
 ) error {
 	contextLog := log.FromContext(ctx)
 
-	generatedDeployment, err := pgbouncer.Deployment(pooler, resources.Cluster)
+	generatedDeployment, err := buildPoolerDeployment(pooler, resources.Cluster)
 	if err != nil {
 		return err
 	}
@@ -97,7 +104,22 @@ func (r *PoolerReconciler) updateDeployment(
 		updatedVersion := generatedDeployment.Annotations[utils.PoolerSpecHashAnnotationName]
 		if currentVersion == updatedVersion {
 			// Everything fine, the two deployments are using the
-			// same specifications
+			// same specifications. The autoscaler may still have
+			// changed the number of replicas
+			if !pooler.IsAutoscalingEnabled() ||
+				ptr.Equal(resources.Deployment.Spec.Replicas, generatedDeployment.Spec.Replicas) {
+				return nil
+			}
+
+			deployment := resources.Deployment.DeepCopy()
+			deployment.Spec.Replicas = generatedDeployment.Spec.Replicas
+
+			contextLog.Info("Scaling deployment", "replicas", *deployment.Spec.Replicas)
+			if err := r.Patch(ctx, deployment, client.MergeFrom(resources.Deployment)); err != nil {
+				return err
+			}
+
+			resources.Deployment = deployment
 			return nil
 		}
 
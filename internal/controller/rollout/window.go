@@ -0,0 +1,107 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package rollout
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/robfig/cron"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+)
+
+// WindowResult is the outcome of the evaluation of the
+// maintenance windows of a cluster
+type WindowResult struct {
+	// This is true when disruptive operations are allowed
+	Open bool
+
+	// When the window is closed, this is the moment the
+	// next window opens
+	NextOpening time.Time
+}
+
+// EvaluateMaintenanceWindow checks whether the passed moment in time
+// is inside one of the maintenance windows. A nil configuration means
+// that disruptive operations are always allowed
+func EvaluateMaintenanceWindow(
+	configuration *apiv1.MaintenanceWindowConfiguration,
+	now time.Time,
+) (WindowResult, error) {
+	if configuration == nil || len(configuration.Windows) == 0 {
+		return WindowResult{Open: true}, nil
+	}
+
+	location := time.UTC
+	if configuration.TimeZone != "" {
+		var err error
+		if location, err = time.LoadLocation(configuration.TimeZone); err != nil {
+			return WindowResult{}, fmt.Errorf("invalid maintenance window time zone: %w", err)
+		}
+	}
+	localNow := now.In(location)
+
+	var result WindowResult
+	for _, window := range configuration.Windows {
+		schedule, err := cron.Parse(window.Schedule)
+		if err != nil {
+			return WindowResult{}, fmt.Errorf("invalid maintenance window schedule %q: %w", window.Schedule, err)
+		}
+
+		// The first opening after the beginning of the interval having
+		// the length of the window and ending now. If it is not in the future,
+		// the window is currently open
+		opening := schedule.Next(localNow.Add(-window.Duration.Duration))
+		if !opening.After(localNow) {
+			return WindowResult{Open: true}, nil
+		}
+
+		if result.NextOpening.IsZero() || opening.Before(result.NextOpening) {
+			result.NextOpening = opening
+		}
+	}
+
+	return result, nil
+}
+
+// MaintenancePendingCondition builds the condition signaling that a disruptive
+// operation is waiting for the passed opening of the maintenance window
+func MaintenancePendingCondition(operation string, nextOpening time.Time) metav1.Condition {
+	return metav1.Condition{
+		Type:   string(apiv1.ConditionMaintenancePending),
+		Status: metav1.ConditionTrue,
+		Reason: string(apiv1.ConditionReasonWaitingForMaintenanceWindow),
+		Message: fmt.Sprintf("%s is pending, the next maintenance window opens at %s",
+			operation, nextOpening.Format(time.RFC3339)),
+	}
+}
+
+// NoPendingMaintenanceCondition builds the condition signaling that no
+// disruptive operation is waiting for the maintenance window
+func NoPendingMaintenanceCondition() metav1.Condition {
+	return metav1.Condition{
+		Type:    string(apiv1.ConditionMaintenancePending),
+		Status:  metav1.ConditionFalse,
+		Reason:  string(apiv1.ConditionReasonNoPendingMaintenance),
+		Message: "No operation is waiting for the maintenance window",
+	}
+}
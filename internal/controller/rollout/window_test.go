@@ -0,0 +1,96 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package rollout
+
+import (
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Maintenance window", func() {
+	// Saturday 2 AM, for two hours
+	saturdayNight := &apiv1.MaintenanceWindowConfiguration{
+		Windows: []apiv1.MaintenanceWindow{
+			{Schedule: "0 0 2 * * 6", Duration: metav1.Duration{Duration: 2 * time.Hour}},
+		},
+	}
+
+	It("is always open when not configured", func() {
+		result, err := EvaluateMaintenanceWindow(nil, time.Now())
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result.Open).To(BeTrue())
+	})
+
+	It("is open during the window", func() {
+		// Saturday
+		now := time.Date(2025, time.March, 15, 3, 30, 0, 0, time.UTC)
+		result, err := EvaluateMaintenanceWindow(saturdayNight, now)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result.Open).To(BeTrue())
+	})
+
+	It("is closed after the window, and reports the next opening", func() {
+		now := time.Date(2025, time.March, 15, 4, 0, 0, 0, time.UTC)
+		result, err := EvaluateMaintenanceWindow(saturdayNight, now)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result.Open).To(BeFalse())
+		Expect(result.NextOpening.Equal(time.Date(2025, time.March, 22, 2, 0, 0, 0, time.UTC))).To(BeTrue())
+	})
+
+	It("honors the time zone", func() {
+		configuration := saturdayNight.DeepCopy()
+		configuration.TimeZone = "America/New_York"
+
+		// 3:30 AM UTC is still Friday evening in New York
+		now := time.Date(2025, time.March, 15, 3, 30, 0, 0, time.UTC)
+		result, err := EvaluateMaintenanceWindow(configuration, now)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result.Open).To(BeFalse())
+		Expect(result.NextOpening.UTC()).To(Equal(time.Date(2025, time.March, 15, 6, 0, 0, 0, time.UTC)))
+	})
+
+	It("reports the earliest opening among the windows", func() {
+		configuration := saturdayNight.DeepCopy()
+		configuration.Windows = append(configuration.Windows, apiv1.MaintenanceWindow{
+			Schedule: "0 30 22 * * *",
+			Duration: metav1.Duration{Duration: time.Hour},
+		})
+
+		now := time.Date(2025, time.March, 17, 12, 0, 0, 0, time.UTC)
+		result, err := EvaluateMaintenanceWindow(configuration, now)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result.Open).To(BeFalse())
+		Expect(result.NextOpening.Equal(time.Date(2025, time.March, 17, 22, 30, 0, 0, time.UTC))).To(BeTrue())
+	})
+
+	It("fails with invalid schedules", func() {
+		configuration := &apiv1.MaintenanceWindowConfiguration{
+			Windows: []apiv1.MaintenanceWindow{{Schedule: "sometimes"}},
+		}
+		_, err := EvaluateMaintenanceWindow(configuration, time.Now())
+		Expect(err).To(HaveOccurred())
+	})
+})
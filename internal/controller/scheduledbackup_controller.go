@@ -23,6 +23,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
 	"strconv"
 	"time"
 
@@ -37,6 +38,8 @@ import (
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
@@ -63,7 +66,7 @@ type ScheduledBackupReconciler struct {
 
 // +kubebuilder:rbac:groups=postgresql.cnpg.io,resources=scheduledbackups,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=postgresql.cnpg.io,resources=scheduledbackups/status,verbs=get;update;patch
-// +kubebuilder:rbac:groups=postgresql.cnpg.io,resources=backups,verbs=get;list;create
+// +kubebuilder:rbac:groups=postgresql.cnpg.io,resources=backups,verbs=get;list;watch;create;delete
 // +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
 
 // Reconcile is the main reconciler logic
@@ -110,11 +113,22 @@ func (r *ScheduledBackupReconciler) Reconcile(ctx context.Context, req ctrl.Requ
 		return ctrl.Result{}, err
 	}
 
+	if err := enforceScheduledBackupRetention(
+		ctx, r.Recorder, r.Client, &scheduledBackup, childBackups, time.Now(),
+	); err != nil {
+		contextLogger.Error(err, "Cannot enforce the retention policy")
+		return ctrl.Result{}, err
+	}
+
 	// We are supposed to start a new backup. Let's extract
 	// the list of backups we have already taken to see if anything
 	// is running now
 	for _, backup := range childBackups {
 		if !backup.Status.IsDone() {
+			if scheduledBackup.GetConcurrencyPolicy() == apiv1.ScheduledBackupConcurrencyPolicyForbid {
+				return skipScheduledBackup(ctx, r.Recorder, r.Client, &scheduledBackup, &backup)
+			}
+
 			contextLogger.Info(
 				"The system is already taking a scheduledBackup, retrying in 60 seconds",
 				"backupName", backup.GetName(),
@@ -123,7 +137,116 @@ func (r *ScheduledBackupReconciler) Reconcile(ctx context.Context, req ctrl.Requ
 		}
 	}
 
-	return ReconcileScheduledBackup(ctx, r.Recorder, r.Client, &scheduledBackup)
+	lastScheduleTime := scheduledBackup.Status.LastScheduleTime
+	result, err := ReconcileScheduledBackup(ctx, r.Recorder, r.Client, &scheduledBackup)
+	if err != nil || scheduledBackup.Status.LastScheduleTime != lastScheduleTime {
+		// The backups of the tiers will be taken once the one
+		// we just created is done
+		return result, err
+	}
+
+	tiersResult, err := reconcileScheduledBackupTiers(ctx, r.Recorder, r.Client, &scheduledBackup)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	return ctrl.Result{RequeueAfter: getEarliestRequeue(result.RequeueAfter, tiersResult.RequeueAfter)}, nil
+}
+
+// skipScheduledBackup skips the backups that are due while another
+// backup is running, according to the `Forbid` concurrency policy.
+// This applies to the main schedule and to the ones of the tiers
+func skipScheduledBackup(
+	ctx context.Context,
+	event record.EventRecorder,
+	cli client.Client,
+	scheduledBackup *apiv1.ScheduledBackup,
+	runningBackup *apiv1.Backup,
+) (ctrl.Result, error) {
+	contextLogger := log.FromContext(ctx)
+
+	location, err := scheduledBackup.GetLocation()
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	now := time.Now().In(location)
+	origScheduled := scheduledBackup.DeepCopy()
+
+	var requeueAfter time.Duration
+	nextBackupTime, err := getNextSkippedBackupTime(
+		scheduledBackup.GetSchedule(), scheduledBackup.Status.LastCheckTime, now)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if !nextBackupTime.IsZero() {
+		scheduledBackup.Status.LastCheckTime = &metav1.Time{Time: now}
+		scheduledBackup.Status.NextScheduleTime = &metav1.Time{Time: nextBackupTime}
+		requeueAfter = nextBackupTime.Sub(now)
+	}
+
+	for _, tier := range scheduledBackup.Spec.Tiers {
+		status := scheduledBackup.Status.GetTierStatus(tier.Name)
+		nextBackupTime, err := getNextSkippedBackupTime(tier.Schedule, status.LastCheckTime, now)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if !nextBackupTime.IsZero() {
+			status.LastCheckTime = &metav1.Time{Time: now}
+			status.NextScheduleTime = &metav1.Time{Time: nextBackupTime}
+			requeueAfter = getEarliestRequeue(requeueAfter, nextBackupTime.Sub(now))
+		}
+	}
+
+	if requeueAfter == 0 {
+		// No backup is due, we just wait for the running one to be done
+		return ctrl.Result{RequeueAfter: time.Minute}, nil
+	}
+
+	contextLogger.Info("Skipping the scheduled backup as the previous one is still running",
+		"backupName", runningBackup.GetName(),
+		"backupPhase", runningBackup.Status.Phase)
+	event.Eventf(scheduledBackup, "Normal", "BackupSkipped",
+		"Skipped scheduled backup as backup %s is still running", runningBackup.GetName())
+
+	if err := cli.Status().Patch(ctx, scheduledBackup, client.MergeFrom(origScheduled)); err != nil {
+		if apierrs.IsConflict(err) {
+			// Retry later, the cache is stale
+			contextLogger.Debug("Conflict while updating scheduled backup", "error", err)
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	return ctrl.Result{RequeueAfter: requeueAfter}, nil
+}
+
+// getNextSkippedBackupTime returns the next time of the schedule when a
+// backup is due since the last check, and the zero time otherwise
+func getNextSkippedBackupTime(schedule string, lastCheckTime *metav1.Time, now time.Time) (time.Time, error) {
+	cronSchedule, err := cron.Parse(schedule)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	if lastCheckTime == nil || now.Before(cronSchedule.Next(lastCheckTime.In(now.Location()))) {
+		return time.Time{}, nil
+	}
+
+	return cronSchedule.Next(now), nil
+}
+
+// getEarliestRequeue returns the earliest of the passed requeue
+// delays, ignoring the zero ones
+func getEarliestRequeue(delays ...time.Duration) time.Duration {
+	var result time.Duration
+	for _, delay := range delays {
+		if delay > 0 && (result == 0 || delay < result) {
+			result = delay
+		}
+	}
+
+	return result
 }
 
 // ReconcileScheduledBackup is the main reconciliation logic for a scheduled backup
@@ -176,7 +299,16 @@ func ReconcileScheduledBackup(
 		}
 	}
 
-	now := time.Now()
+	location, err := scheduledBackup.GetLocation()
+	if err != nil {
+		contextLogger.Info("Detected an invalid time zone",
+			"timeZone", scheduledBackup.Spec.TimeZone)
+		return ctrl.Result{}, err
+	}
+
+	// The schedule is evaluated in the time zone of the times
+	// passed to it
+	now := time.Now().In(location)
 	if schedule.Next(now).IsZero() {
 		// No time satisfying the schedule have been found.
 		// We cannot proceed reconciling it.
@@ -214,7 +346,7 @@ func ReconcileScheduledBackup(
 	}
 
 	// Let's check if we are supposed to start a new backup.
-	nextTime := schedule.Next(scheduledBackup.GetStatus().LastCheckTime.Time)
+	nextTime := schedule.Next(scheduledBackup.GetStatus().LastCheckTime.In(location))
 	contextLogger.Info("Next backup schedule", "next", nextTime)
 
 	if now.Before(nextTime) {
@@ -244,6 +376,50 @@ func createBackup(
 	// Let's have deterministic names to avoid creating the job two
 	// times
 	name := fmt.Sprintf("%s-%s", scheduledBackup.GetName(), pgTime.ToCompactISO8601(backupTime))
+	if created, err := createChildBackup(ctx, event, cli, scheduledBackup, name, "", immediate); !created {
+		return ctrl.Result{}, err
+	}
+
+	// Ok, now update the latest check to now
+	scheduledBackup.Status.LastCheckTime = &metav1.Time{
+		Time: now,
+	}
+	scheduledBackup.Status.LastScheduleTime = &metav1.Time{
+		Time: backupTime,
+	}
+	nextBackupTime := schedule.Next(now)
+	scheduledBackup.Status.NextScheduleTime = &metav1.Time{
+		Time: nextBackupTime,
+	}
+
+	if err := cli.Status().Patch(ctx, scheduledBackup, client.MergeFrom(origScheduled)); err != nil {
+		if apierrs.IsConflict(err) {
+			// Retry later, the cache is stale
+			contextLogger.Debug("Conflict while updating scheduled backup", "error", err)
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	contextLogger.Info("Next backup schedule", "next", backupTime)
+	event.Eventf(scheduledBackup, "Normal", "BackupSchedule", "Next backup scheduled by %v", nextBackupTime)
+	return ctrl.Result{RequeueAfter: nextBackupTime.Sub(now)}, nil
+}
+
+// createChildBackup creates a backup of the scheduled backup, labelled with
+// the tier it belongs to, if any. It returns false when the backup was not
+// created
+func createChildBackup(
+	ctx context.Context,
+	event record.EventRecorder,
+	cli client.Client,
+	scheduledBackup *apiv1.ScheduledBackup,
+	name string,
+	tier string,
+	immediate bool,
+) (bool, error) {
+	contextLogger := log.FromContext(ctx)
+
 	backup := scheduledBackup.CreateBackup(name)
 	metadata := &backup.ObjectMeta
 	if metadata.Labels == nil {
@@ -252,6 +428,9 @@ func createBackup(
 	metadata.Labels[utils.ClusterLabelName] = scheduledBackup.Spec.Cluster.Name
 	metadata.Labels[utils.ImmediateBackupLabelName] = strconv.FormatBool(immediate)
 	metadata.Labels[utils.ParentScheduledBackupLabelName] = scheduledBackup.GetName()
+	if tier != "" {
+		metadata.Labels[utils.ScheduledBackupTierLabelName] = tier
+	}
 
 	switch scheduledBackup.Spec.BackupOwnerReference {
 	case "cluster":
@@ -261,7 +440,7 @@ func createBackup(
 			types.NamespacedName{Name: scheduledBackup.Spec.Cluster.Name, Namespace: scheduledBackup.Namespace},
 			&cluster,
 		); err != nil {
-			return ctrl.Result{}, err
+			return false, err
 		}
 		cluster.SetInheritedDataAndOwnership(&backup.ObjectMeta)
 	case "self":
@@ -276,57 +455,73 @@ func createBackup(
 		if apierrs.IsConflict(err) {
 			// Retry later, the cache is stale
 			contextLogger.Debug("Conflict while creating backup", "error", err)
-			return ctrl.Result{}, nil
+			return false, nil
 		}
 
 		contextLogger.Error(
 			err, "Error while creating backup object",
 			"backupName", backup.GetName())
 		event.Event(scheduledBackup, "Warning", "BackupCreation", "Error while creating backup object")
-		return ctrl.Result{}, err
-	}
-
-	// Ok, now update the latest check to now
-	scheduledBackup.Status.LastCheckTime = &metav1.Time{
-		Time: now,
-	}
-	scheduledBackup.Status.LastScheduleTime = &metav1.Time{
-		Time: backupTime,
-	}
-	nextBackupTime := schedule.Next(now)
-	scheduledBackup.Status.NextScheduleTime = &metav1.Time{
-		Time: nextBackupTime,
-	}
-
-	if err := cli.Status().Patch(ctx, scheduledBackup, client.MergeFrom(origScheduled)); err != nil {
-		if apierrs.IsConflict(err) {
-			// Retry later, the cache is stale
-			contextLogger.Debug("Conflict while updating scheduled backup", "error", err)
-			return ctrl.Result{}, nil
-		}
-		return ctrl.Result{}, err
+		return false, err
 	}
 
-	contextLogger.Info("Next backup schedule", "next", backupTime)
-	event.Eventf(scheduledBackup, "Normal", "BackupSchedule", "Next backup scheduled by %v", nextBackupTime)
-	return ctrl.Result{RequeueAfter: nextBackupTime.Sub(now)}, nil
+	return true, nil
 }
 
-// GetChildBackups gets all the backups scheduled by a certain scheduler
+// GetChildBackups gets all the backups scheduled by a certain scheduler.
+// The backups are matched by the label of their parent scheduled backup,
+// and by their controller reference for the ones created without it
 func (r *ScheduledBackupReconciler) GetChildBackups(
 	ctx context.Context,
 	scheduledBackup apiv1.ScheduledBackup,
 ) ([]apiv1.Backup, error) {
-	var childBackups apiv1.BackupList
+	var labelledBackups, ownedBackups apiv1.BackupList
+
+	if err := r.List(ctx, &labelledBackups,
+		client.InNamespace(scheduledBackup.Namespace),
+		client.MatchingLabels{utils.ParentScheduledBackupLabelName: scheduledBackup.Name},
+	); err != nil {
+		return nil, fmt.Errorf("unable to list child backups resource: %w", err)
+	}
 
-	if err := r.List(ctx, &childBackups,
+	if err := r.List(ctx, &ownedBackups,
 		client.InNamespace(scheduledBackup.Namespace),
 		client.MatchingFields{backupOwnerKey: scheduledBackup.Name},
 	); err != nil {
-		return nil, fmt.Errorf("unable to list child pods resource: %w", err)
+		return nil, fmt.Errorf("unable to list owned backups resource: %w", err)
+	}
+
+	childBackups := labelledBackups.Items
+	for _, backup := range ownedBackups.Items {
+		if !slices.ContainsFunc(childBackups, func(child apiv1.Backup) bool {
+			return child.Name == backup.Name
+		}) {
+			childBackups = append(childBackups, backup)
+		}
 	}
 
-	return childBackups.Items, nil
+	return childBackups, nil
+}
+
+// backupOwnerIndexFunc maps a backup to the scheduled backup controlling
+// it, and is used as an index function to look up the backups created
+// by a scheduled backup
+func backupOwnerIndexFunc(rawObj client.Object) []string {
+	backup := rawObj.(*apiv1.Backup)
+	owner := metav1.GetControllerOf(backup)
+	if owner == nil {
+		return nil
+	}
+
+	if owner.Kind != apiv1.ScheduledBackupKind {
+		return nil
+	}
+
+	if owner.APIVersion != apiSGVString {
+		return nil
+	}
+
+	return []string{owner.Name}
 }
 
 // SetupWithManager install this controller in the controller manager
@@ -340,23 +535,7 @@ func (r *ScheduledBackupReconciler) SetupWithManager(
 	if err := mgr.GetFieldIndexer().IndexField(
 		ctx,
 		&apiv1.Backup{},
-		backupOwnerKey, func(rawObj client.Object) []string {
-			pod := rawObj.(*apiv1.Backup)
-			owner := metav1.GetControllerOf(pod)
-			if owner == nil {
-				return nil
-			}
-
-			if owner.Kind != apiv1.BackupKind {
-				return nil
-			}
-
-			if owner.APIVersion != apiSGVString {
-				return nil
-			}
-
-			return []string{owner.Name}
-		}); err != nil {
+		backupOwnerKey, backupOwnerIndexFunc); err != nil {
 		return err
 	}
 
@@ -364,5 +543,28 @@ func (r *ScheduledBackupReconciler) SetupWithManager(
 		WithOptions(controller.Options{MaxConcurrentReconciles: maxConcurrentReconciles}).
 		For(&apiv1.ScheduledBackup{}).
 		Named("scheduled-backup").
+		Watches(
+			&apiv1.Backup{},
+			handler.EnqueueRequestsFromMapFunc(mapBackupToScheduledBackup),
+		).
 		Complete(r)
 }
+
+// mapBackupToScheduledBackup enqueues the ScheduledBackup which created
+// the backup, so that the retention policy is enforced as soon as the
+// backup is done
+func mapBackupToScheduledBackup(_ context.Context, obj client.Object) []reconcile.Request {
+	parentName := obj.GetLabels()[utils.ParentScheduledBackupLabelName]
+	if parentName == "" {
+		return nil
+	}
+
+	return []reconcile.Request{
+		{
+			NamespacedName: types.NamespacedName{
+				Namespace: obj.GetNamespace(),
+				Name:      parentName,
+			},
+		},
+	}
+}
@@ -0,0 +1,82 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package controller
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("GetChildBackups", func() {
+	var env *testingEnvironment
+	BeforeEach(func() {
+		env = buildTestEnvironment()
+	})
+
+	It("finds the backups by label and by controller reference", func(ctx SpecContext) {
+		newBackup := func(name string, labels map[string]string, owners []metav1.OwnerReference) *apiv1.Backup {
+			return &apiv1.Backup{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:            name,
+					Namespace:       "default",
+					Labels:          labels,
+					OwnerReferences: owners,
+				},
+				Spec: apiv1.BackupSpec{
+					Cluster: apiv1.LocalObjectReference{Name: "cluster-example"},
+				},
+			}
+		}
+		owner := metav1.OwnerReference{
+			APIVersion: apiv1.SchemeGroupVersion.String(),
+			Kind:       apiv1.ScheduledBackupKind,
+			Name:       "daily",
+			Controller: ptr.To(true),
+		}
+		labels := map[string]string{utils.ParentScheduledBackupLabelName: "daily"}
+
+		for _, backup := range []*apiv1.Backup{
+			newBackup("labelled", labels, nil),
+			newBackup("owned", nil, []metav1.OwnerReference{owner}),
+			newBackup("labelled-and-owned", labels, []metav1.OwnerReference{owner}),
+			newBackup("other", map[string]string{utils.ParentScheduledBackupLabelName: "weekly"}, nil),
+		} {
+			Expect(env.client.Create(ctx, backup)).To(Succeed())
+		}
+
+		reconciler := &ScheduledBackupReconciler{Client: env.client}
+		childBackups, err := reconciler.GetChildBackups(ctx, apiv1.ScheduledBackup{
+			ObjectMeta: metav1.ObjectMeta{Name: "daily", Namespace: "default"},
+		})
+		Expect(err).ToNot(HaveOccurred())
+
+		names := make([]string, 0, len(childBackups))
+		for _, backup := range childBackups {
+			names = append(names, backup.Name)
+		}
+		Expect(names).To(ConsistOf("labelled", "owned", "labelled-and-owned"))
+	})
+})
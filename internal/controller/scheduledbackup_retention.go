@@ -0,0 +1,176 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/cloudnative-pg/machinery/pkg/log"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/reconciler/backup/volumesnapshot"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+// enforceScheduledBackupRetention deletes the backups created by the scheduled
+// backup that are not retained by its retention policy or by its history
+// limit for failed backups
+func enforceScheduledBackupRetention(
+	ctx context.Context,
+	event record.EventRecorder,
+	cli client.Client,
+	scheduledBackup *apiv1.ScheduledBackup,
+	childBackups []apiv1.Backup,
+	now time.Time,
+) error {
+	contextLogger := log.FromContext(ctx)
+
+	expired, err := getExpiredScheduledBackups(scheduledBackup, childBackups, now)
+	if err != nil {
+		return err
+	}
+
+	for i := range expired {
+		backup := &expired[i]
+		if err := deleteScheduledBackupChild(ctx, cli, backup); err != nil {
+			return err
+		}
+
+		contextLogger.Info("Deleted backup according to the retention policy",
+			"backupName", backup.Name, "backupPhase", backup.Status.Phase)
+		event.Eventf(scheduledBackup, "Normal", "BackupDeleted",
+			"Deleted backup %s according to the retention policy", backup.Name)
+	}
+
+	return nil
+}
+
+// getExpiredScheduledBackups returns the completed backups not retained by
+// the retention policy of their tier, and the failed backups exceeding the
+// history limit. The most recent completed backup of every tier is always
+// retained, as well as the backups of the tiers which have been removed,
+// and backups which are still running are never considered
+func getExpiredScheduledBackups(
+	scheduledBackup *apiv1.ScheduledBackup,
+	childBackups []apiv1.Backup,
+	now time.Time,
+) ([]apiv1.Backup, error) {
+	var failed []apiv1.Backup
+	completed := make(map[string][]apiv1.Backup)
+	for _, backup := range childBackups {
+		if !backup.DeletionTimestamp.IsZero() {
+			continue
+		}
+		switch backup.Status.Phase {
+		case apiv1.BackupPhaseCompleted:
+			tier := backup.Labels[utils.ScheduledBackupTierLabelName]
+			completed[tier] = append(completed[tier], backup)
+		case apiv1.BackupPhaseFailed:
+			failed = append(failed, backup)
+		}
+	}
+
+	// Sort the backups from the most recent to the oldest one
+	mostRecentFirst := func(a, b apiv1.Backup) int {
+		return getScheduledBackupChildTime(&b).Compare(getScheduledBackupChildTime(&a))
+	}
+	slices.SortStableFunc(failed, mostRecentFirst)
+
+	var result []apiv1.Backup
+
+	for _, tier := range slices.Sorted(maps.Keys(completed)) {
+		policy, _ := scheduledBackup.GetRetentionPolicy(tier)
+		if policy == nil {
+			continue
+		}
+
+		tierBackups := completed[tier]
+		slices.SortStableFunc(tierBackups, mostRecentFirst)
+		expired, err := getExpiredTierBackups(policy, tierBackups, now)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, expired...)
+	}
+
+	if limit := scheduledBackup.Spec.FailedBackupsHistoryLimit; limit != nil && len(failed) > *limit {
+		result = append(result, failed[*limit:]...)
+	}
+
+	return result, nil
+}
+
+// getExpiredTierBackups returns the completed backups of a tier, sorted from
+// the most recent one, which are not retained by its retention policy
+func getExpiredTierBackups(
+	policy *apiv1.ScheduledBackupRetentionPolicy,
+	completed []apiv1.Backup,
+	now time.Time,
+) ([]apiv1.Backup, error) {
+	var threshold time.Time
+	if policy.MaxAge != "" {
+		var err error
+		if threshold, err = utils.GetRetentionThreshold(policy.MaxAge, now); err != nil {
+			return nil, err
+		}
+	}
+
+	var result []apiv1.Backup
+	for i := range completed {
+		retained := i == 0 ||
+			(policy.KeepLast != nil && i < *policy.KeepLast) ||
+			(policy.MaxAge != "" && !getScheduledBackupChildTime(&completed[i]).Before(threshold))
+		if !retained {
+			result = append(result, completed[i])
+		}
+	}
+
+	return result, nil
+}
+
+// getScheduledBackupChildTime returns the time when the backup was completed
+func getScheduledBackupChildTime(backup *apiv1.Backup) time.Time {
+	if backup.Status.StoppedAt != nil {
+		return backup.Status.StoppedAt.Time
+	}
+	return backup.CreationTimestamp.Time
+}
+
+// deleteScheduledBackupChild deletes a backup. The VolumeSnapshots taken
+// by a completed volume snapshot backup are deleted too, while the content
+// of the object stores is managed by their own retention policies
+func deleteScheduledBackupChild(ctx context.Context, cli client.Client, backup *apiv1.Backup) error {
+	if backup.Status.Method == apiv1.BackupMethodVolumeSnapshot && utils.HaveVolumeSnapshot() {
+		return volumesnapshot.DeleteBackupWithSnapshots(ctx, cli, backup)
+	}
+
+	if err := cli.Delete(ctx, backup); err != nil && !apierrs.IsNotFound(err) {
+		return fmt.Errorf("while deleting backup %q: %w", backup.Name, err)
+	}
+
+	return nil
+}
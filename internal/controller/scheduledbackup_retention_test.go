@@ -0,0 +1,180 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package controller
+
+import (
+	"slices"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/scheme"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Scheduled backup retention", func() {
+	now := time.Date(2025, time.March, 15, 12, 0, 0, 0, time.UTC)
+
+	var scheduledBackup *apiv1.ScheduledBackup
+
+	newChildBackup := func(name string, phase apiv1.BackupPhase, stoppedAt time.Time) apiv1.Backup {
+		return apiv1.Backup{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+				Labels:    map[string]string{utils.ParentScheduledBackupLabelName: "daily"},
+			},
+			Spec: apiv1.BackupSpec{
+				Cluster: apiv1.LocalObjectReference{Name: "cluster-example"},
+			},
+			Status: apiv1.BackupStatus{
+				Method:    apiv1.BackupMethodBarmanObjectStore,
+				Phase:     phase,
+				StoppedAt: ptr.To(metav1.NewTime(stoppedAt)),
+			},
+		}
+	}
+
+	names := func(backups []apiv1.Backup) []string {
+		result := make([]string, len(backups))
+		for i := range backups {
+			result[i] = backups[i].Name
+		}
+		return result
+	}
+
+	childBackups := []apiv1.Backup{
+		newChildBackup("day-3", apiv1.BackupPhaseCompleted, now.AddDate(0, 0, -3)),
+		newChildBackup("day-1", apiv1.BackupPhaseCompleted, now.AddDate(0, 0, -1)),
+		newChildBackup("day-2", apiv1.BackupPhaseCompleted, now.AddDate(0, 0, -2)),
+		newChildBackup("day-10", apiv1.BackupPhaseCompleted, now.AddDate(0, 0, -10)),
+		newChildBackup("failed-1", apiv1.BackupPhaseFailed, now.AddDate(0, 0, -1)),
+		newChildBackup("failed-2", apiv1.BackupPhaseFailed, now.AddDate(0, 0, -2)),
+		newChildBackup("running", apiv1.BackupPhaseRunning, now.AddDate(0, 0, -20)),
+	}
+
+	BeforeEach(func() {
+		scheduledBackup = &apiv1.ScheduledBackup{
+			ObjectMeta: metav1.ObjectMeta{Name: "daily", Namespace: "default"},
+			Spec: apiv1.ScheduledBackupSpec{
+				Schedule: "0 0 0 * * *",
+				Cluster:  apiv1.LocalObjectReference{Name: "cluster-example"},
+			},
+		}
+	})
+
+	It("retains every backup without a retention policy", func() {
+		expired, err := getExpiredScheduledBackups(scheduledBackup, childBackups, now)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(expired).To(BeEmpty())
+	})
+
+	It("keeps the requested number of recent backups", func() {
+		scheduledBackup.Spec.RetentionPolicy = &apiv1.ScheduledBackupRetentionPolicy{KeepLast: ptr.To(2)}
+		expired, err := getExpiredScheduledBackups(scheduledBackup, childBackups, now)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(names(expired)).To(ConsistOf("day-3", "day-10"))
+	})
+
+	It("keeps the backups younger than the maximum age", func() {
+		scheduledBackup.Spec.RetentionPolicy = &apiv1.ScheduledBackupRetentionPolicy{MaxAge: "1w"}
+		expired, err := getExpiredScheduledBackups(scheduledBackup, childBackups, now)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(names(expired)).To(ConsistOf("day-10"))
+	})
+
+	It("never deletes the most recent completed backup", func() {
+		scheduledBackup.Spec.RetentionPolicy = &apiv1.ScheduledBackupRetentionPolicy{MaxAge: "1d"}
+		expired, err := getExpiredScheduledBackups(scheduledBackup, childBackups[3:4], now)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(expired).To(BeEmpty())
+	})
+
+	It("limits the history of failed backups", func() {
+		scheduledBackup.Spec.FailedBackupsHistoryLimit = ptr.To(1)
+		expired, err := getExpiredScheduledBackups(scheduledBackup, childBackups, now)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(names(expired)).To(ConsistOf("failed-2"))
+	})
+
+	It("applies the retention policy of their tier to the backups", func() {
+		weekly := func(name string, stoppedAt time.Time) apiv1.Backup {
+			backup := newChildBackup(name, apiv1.BackupPhaseCompleted, stoppedAt)
+			backup.Labels = map[string]string{
+				utils.ParentScheduledBackupLabelName: "daily",
+				utils.ScheduledBackupTierLabelName:   "weekly",
+			}
+			return backup
+		}
+		backups := append(slices.Clone(childBackups),
+			weekly("week-1", now.AddDate(0, 0, -7)),
+			weekly("week-2", now.AddDate(0, 0, -14)),
+			weekly("week-3", now.AddDate(0, 0, -21)),
+		)
+
+		scheduledBackup.Spec.RetentionPolicy = &apiv1.ScheduledBackupRetentionPolicy{KeepLast: ptr.To(1)}
+		scheduledBackup.Spec.Tiers = []apiv1.ScheduledBackupTier{
+			{
+				Name:            "weekly",
+				Schedule:        "0 0 0 * * 0",
+				RetentionPolicy: &apiv1.ScheduledBackupRetentionPolicy{MaxAge: "2w"},
+			},
+		}
+		expired, err := getExpiredScheduledBackups(scheduledBackup, backups, now)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(names(expired)).To(ConsistOf("day-2", "day-3", "day-10", "week-3"))
+
+		By("retaining the backups of a removed tier")
+		scheduledBackup.Spec.Tiers = nil
+		expired, err = getExpiredScheduledBackups(scheduledBackup, backups, now)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(names(expired)).To(ConsistOf("day-2", "day-3", "day-10"))
+	})
+
+	It("deletes the expired backups", func(ctx SpecContext) {
+		scheduledBackup.Spec.RetentionPolicy = &apiv1.ScheduledBackupRetentionPolicy{KeepLast: ptr.To(1)}
+		scheduledBackup.Spec.FailedBackupsHistoryLimit = ptr.To(0)
+
+		objects := []client.Object{scheduledBackup}
+		for i := range childBackups {
+			objects = append(objects, &childBackups[i])
+		}
+		cli := fake.NewClientBuilder().
+			WithScheme(scheme.BuildWithAllKnownScheme()).
+			WithObjects(objects...).
+			Build()
+
+		Expect(enforceScheduledBackupRetention(
+			ctx, record.NewFakeRecorder(10), cli, scheduledBackup, childBackups, now,
+		)).To(Succeed())
+
+		var backupList apiv1.BackupList
+		Expect(cli.List(ctx, &backupList)).To(Succeed())
+		Expect(names(backupList.Items)).To(ConsistOf("day-1", "running"))
+	})
+})
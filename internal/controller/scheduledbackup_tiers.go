@@ -0,0 +1,131 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"slices"
+	"time"
+
+	"github.com/cloudnative-pg/machinery/pkg/log"
+	pgTime "github.com/cloudnative-pg/machinery/pkg/postgres/time"
+	"github.com/robfig/cron"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+)
+
+// reconcileScheduledBackupTiers takes the backups of the tiers of the
+// scheduled backup which are due. A single backup is taken at every
+// reconciliation, as the following ones wait for it to be done according
+// to the concurrency policy
+func reconcileScheduledBackupTiers(
+	ctx context.Context,
+	event record.EventRecorder,
+	cli client.Client,
+	scheduledBackup *apiv1.ScheduledBackup,
+) (ctrl.Result, error) {
+	contextLogger := log.FromContext(ctx)
+
+	location, err := scheduledBackup.GetLocation()
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	now := time.Now().In(location)
+	origScheduled := scheduledBackup.DeepCopy()
+
+	// Forget the status of the tiers which have been removed
+	scheduledBackup.Status.Tiers = slices.DeleteFunc(scheduledBackup.Status.Tiers,
+		func(status apiv1.ScheduledBackupTierStatus) bool {
+			return !slices.ContainsFunc(scheduledBackup.Spec.Tiers, func(tier apiv1.ScheduledBackupTier) bool {
+				return tier.Name == status.Name
+			})
+		})
+
+	var requeueAfter time.Duration
+	var created bool
+	for _, tier := range scheduledBackup.Spec.Tiers {
+		schedule, err := cron.Parse(tier.Schedule)
+		if err != nil {
+			contextLogger.Info("Detected an invalid cron schedule",
+				"tier", tier.Name, "schedule", tier.Schedule)
+			return ctrl.Result{}, err
+		}
+
+		status := scheduledBackup.Status.GetTierStatus(tier.Name)
+		if status.LastCheckTime == nil {
+			// This is the first time we check this schedule,
+			// let's wait until the first backup is due
+			nextTime := schedule.Next(now)
+			status.LastCheckTime = &metav1.Time{Time: now}
+			status.NextScheduleTime = &metav1.Time{Time: nextTime}
+			requeueAfter = getEarliestRequeue(requeueAfter, nextTime.Sub(now))
+			continue
+		}
+
+		backupTime := schedule.Next(status.LastCheckTime.In(location))
+		if now.Before(backupTime) {
+			requeueAfter = getEarliestRequeue(requeueAfter, backupTime.Sub(now))
+			continue
+		}
+
+		if created {
+			// This backup will be taken once the one of
+			// the previous tier is done
+			requeueAfter = getEarliestRequeue(requeueAfter, time.Minute)
+			continue
+		}
+
+		name := fmt.Sprintf("%s-%s-%s", scheduledBackup.GetName(), tier.Name, pgTime.ToCompactISO8601(backupTime))
+		if created, err = createChildBackup(ctx, event, cli, scheduledBackup, name, tier.Name, false); !created {
+			return ctrl.Result{}, err
+		}
+
+		nextTime := schedule.Next(now)
+		status.LastCheckTime = &metav1.Time{Time: now}
+		status.LastScheduleTime = &metav1.Time{Time: backupTime}
+		status.NextScheduleTime = &metav1.Time{Time: nextTime}
+		requeueAfter = getEarliestRequeue(requeueAfter, nextTime.Sub(now))
+		event.Eventf(scheduledBackup, "Normal", "BackupSchedule",
+			"Next backup of tier %s scheduled by %v", tier.Name, nextTime)
+	}
+
+	if reflect.DeepEqual(origScheduled.Status, scheduledBackup.Status) {
+		return ctrl.Result{RequeueAfter: requeueAfter}, nil
+	}
+
+	if err := cli.Status().Patch(ctx, scheduledBackup, client.MergeFrom(origScheduled)); err != nil {
+		if apierrs.IsConflict(err) {
+			// Retry later, the cache is stale
+			contextLogger.Debug("Conflict while updating scheduled backup", "error", err)
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	return ctrl.Result{RequeueAfter: requeueAfter}, nil
+}
@@ -0,0 +1,107 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package controller
+
+import (
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/scheme"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Scheduled backup tiers", func() {
+	var (
+		scheduledBackup *apiv1.ScheduledBackup
+		cli             client.Client
+	)
+
+	BeforeEach(func() {
+		scheduledBackup = &apiv1.ScheduledBackup{
+			ObjectMeta: metav1.ObjectMeta{Name: "hourly", Namespace: "default"},
+			Spec: apiv1.ScheduledBackupSpec{
+				Schedule: "0 0 * * * *",
+				Cluster:  apiv1.LocalObjectReference{Name: "cluster-example"},
+				Tiers: []apiv1.ScheduledBackupTier{
+					{Name: "daily", Schedule: "0 0 0 * * *"},
+					{Name: "weekly", Schedule: "0 0 0 * * 0"},
+				},
+			},
+		}
+		cli = fake.NewClientBuilder().
+			WithScheme(scheme.BuildWithAllKnownScheme()).
+			WithObjects(scheduledBackup).
+			WithStatusSubresource(&apiv1.ScheduledBackup{}).
+			Build()
+	})
+
+	It("waits for the first backup of every tier", func(ctx SpecContext) {
+		result, err := reconcileScheduledBackupTiers(ctx, record.NewFakeRecorder(10), cli, scheduledBackup)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result.RequeueAfter).To(BeNumerically(">", 0))
+		Expect(result.RequeueAfter).To(BeNumerically("<=", 24*time.Hour))
+
+		Expect(scheduledBackup.Status.Tiers).To(HaveLen(2))
+		for _, status := range scheduledBackup.Status.Tiers {
+			Expect(status.LastCheckTime).ToNot(BeNil())
+			Expect(status.NextScheduleTime).ToNot(BeNil())
+			Expect(status.LastScheduleTime).To(BeNil())
+		}
+
+		var backupList apiv1.BackupList
+		Expect(cli.List(ctx, &backupList)).To(Succeed())
+		Expect(backupList.Items).To(BeEmpty())
+	})
+
+	It("takes a single backup when several tiers are due", func(ctx SpecContext) {
+		lastCheckTime := metav1.NewTime(time.Now().AddDate(0, 0, -8).Truncate(time.Second))
+		scheduledBackup.Status.Tiers = []apiv1.ScheduledBackupTierStatus{
+			{Name: "daily", LastCheckTime: &lastCheckTime},
+			{Name: "weekly", LastCheckTime: &lastCheckTime},
+			{Name: "removed", LastCheckTime: &lastCheckTime},
+		}
+
+		result, err := reconcileScheduledBackupTiers(ctx, record.NewFakeRecorder(10), cli, scheduledBackup)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result.RequeueAfter).To(Equal(time.Minute))
+
+		var backupList apiv1.BackupList
+		Expect(cli.List(ctx, &backupList)).To(Succeed())
+		Expect(backupList.Items).To(HaveLen(1))
+		backup := backupList.Items[0]
+		Expect(backup.Name).To(HavePrefix("hourly-daily-"))
+		Expect(backup.Labels).To(HaveKeyWithValue(utils.ParentScheduledBackupLabelName, "hourly"))
+		Expect(backup.Labels).To(HaveKeyWithValue(utils.ScheduledBackupTierLabelName, "daily"))
+
+		Expect(scheduledBackup.Status.Tiers).To(HaveLen(2))
+		Expect(scheduledBackup.Status.GetTierStatus("daily").LastScheduleTime).ToNot(BeNil())
+		Expect(scheduledBackup.Status.GetTierStatus("weekly").LastScheduleTime).To(BeNil())
+		Expect(scheduledBackup.Status.GetTierStatus("weekly").LastCheckTime.Time).
+			To(BeTemporally("==", lastCheckTime.Time))
+	})
+})
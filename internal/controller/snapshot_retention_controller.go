@@ -0,0 +1,99 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/cloudnative-pg/machinery/pkg/log"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/reconciler/backup/volumesnapshot"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+// snapshotRetentionCheckInterval is how often the retention policy of the
+// volume snapshot backups is enforced, even when no new backup is taken
+const snapshotRetentionCheckInterval = 30 * time.Minute
+
+// SnapshotRetentionReconciler periodically enforces the retention policy
+// of the volume snapshot backups of the clusters
+type SnapshotRetentionReconciler struct {
+	client.Client
+}
+
+// +kubebuilder:rbac:groups=postgresql.cnpg.io,resources=clusters,verbs=get;list;watch
+// +kubebuilder:rbac:groups=postgresql.cnpg.io,resources=clusters/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=postgresql.cnpg.io,resources=backups,verbs=get;list;watch;delete
+// +kubebuilder:rbac:groups=snapshot.storage.k8s.io,resources=volumesnapshots,verbs=get;list;watch;delete;deletecollection
+
+// Reconcile is the main reconciler logic
+func (r *SnapshotRetentionReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	contextLogger, ctx := log.SetupLogger(ctx)
+
+	contextLogger.Debug(fmt.Sprintf("reconciling object %#q", req.NamespacedName))
+
+	defer func() {
+		contextLogger.Debug(fmt.Sprintf("object %#q has been reconciled", req.NamespacedName))
+	}()
+
+	var cluster apiv1.Cluster
+	if err := r.Get(ctx, req.NamespacedName, &cluster); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	if !cluster.DeletionTimestamp.IsZero() || !utils.HaveVolumeSnapshot() ||
+		cluster.Spec.Backup == nil || cluster.Spec.Backup.VolumeSnapshot == nil ||
+		cluster.Spec.Backup.VolumeSnapshot.RetentionPolicy == nil {
+		return ctrl.Result{}, nil
+	}
+
+	deleted, err := volumesnapshot.EnforceRetentionPolicy(ctx, r.Client, &cluster, time.Now())
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if len(deleted) > 0 {
+		if err := updateClusterWithSnapshotsBackupTimes(ctx, r.Client, cluster.Namespace, cluster.Name); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	return ctrl.Result{RequeueAfter: snapshotRetentionCheckInterval}, nil
+}
+
+// SetupWithManager install this controller in the controller manager
+func (r *SnapshotRetentionReconciler) SetupWithManager(
+	mgr ctrl.Manager,
+	maxConcurrentReconciles int,
+) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		WithOptions(controller.Options{MaxConcurrentReconciles: maxConcurrentReconciles}).
+		For(&apiv1.Cluster{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Named("snapshot-retention").
+		Complete(r)
+}
@@ -0,0 +1,105 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package controller
+
+import (
+	"time"
+
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("SnapshotRetentionReconciler", func() {
+	var (
+		env        *testingEnvironment
+		cluster    *apiv1.Cluster
+		reconciler *SnapshotRetentionReconciler
+	)
+
+	createBackup := func(ctx SpecContext, name string, stoppedAt time.Time) {
+		backup := &apiv1.Backup{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: cluster.Namespace},
+			Spec: apiv1.BackupSpec{
+				Cluster: apiv1.LocalObjectReference{Name: cluster.Name},
+				Method:  apiv1.BackupMethodVolumeSnapshot,
+			},
+		}
+		Expect(env.client.Create(ctx, backup)).To(Succeed())
+		backup.Status = apiv1.BackupStatus{
+			Method:    apiv1.BackupMethodVolumeSnapshot,
+			Phase:     apiv1.BackupPhaseCompleted,
+			StoppedAt: ptr.To(metav1.NewTime(stoppedAt)),
+		}
+		Expect(env.client.Status().Update(ctx, backup)).To(Succeed())
+	}
+
+	BeforeEach(func() {
+		utils.SetVolumeSnapshot(true)
+		env = buildTestEnvironment()
+		namespace := newFakeNamespace(env.client)
+		cluster = newFakeCNPGCluster(env.client, namespace, func(cluster *apiv1.Cluster) {
+			cluster.Spec.Backup = &apiv1.BackupConfiguration{
+				VolumeSnapshot: &apiv1.VolumeSnapshotConfiguration{
+					RetentionPolicy: &apiv1.VolumeSnapshotRetentionPolicy{MaxAge: "7d"},
+				},
+			}
+		})
+		reconciler = &SnapshotRetentionReconciler{Client: env.client}
+	})
+
+	AfterEach(func() {
+		utils.SetVolumeSnapshot(false)
+	})
+
+	It("expires the old backups even when no backup is taken", func(ctx SpecContext) {
+		createBackup(ctx, "old", time.Now().AddDate(0, 0, -10))
+		createBackup(ctx, "recent", time.Now().Add(-time.Hour))
+
+		result, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(cluster)})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result.RequeueAfter).To(Equal(snapshotRetentionCheckInterval))
+
+		err = env.client.Get(ctx, client.ObjectKey{Namespace: cluster.Namespace, Name: "old"}, &apiv1.Backup{})
+		Expect(apierrs.IsNotFound(err)).To(BeTrue())
+		Expect(env.client.Get(ctx, client.ObjectKey{Namespace: cluster.Namespace, Name: "recent"},
+			&apiv1.Backup{})).To(Succeed())
+	})
+
+	It("does nothing without a retention policy", func(ctx SpecContext) {
+		cluster.Spec.Backup = nil
+		Expect(env.client.Update(ctx, cluster)).To(Succeed())
+		createBackup(ctx, "old", time.Now().AddDate(0, 0, -10))
+
+		result, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(cluster)})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result.IsZero()).To(BeTrue())
+		Expect(env.client.Get(ctx, client.ObjectKey{Namespace: cluster.Namespace, Name: "old"},
+			&apiv1.Backup{})).To(Succeed())
+	})
+})
@@ -76,8 +76,9 @@ func buildTestEnvironment() *testingEnvironment {
 	scheme := schemeBuilder.BuildWithAllKnownScheme()
 	k8sClient := fake.NewClientBuilder().WithScheme(scheme).
 		WithStatusSubresource(&apiv1.Cluster{}, &apiv1.Backup{}, &apiv1.Pooler{}, &corev1.Service{},
-			&corev1.ConfigMap{}, &corev1.Secret{}).
+			&corev1.ConfigMap{}, &corev1.Secret{}, &apiv1.ClientCertificate{}).
 		WithIndex(&batchv1.Job{}, jobOwnerKey, jobOwnerIndexFunc).
+		WithIndex(&apiv1.Backup{}, backupOwnerKey, backupOwnerIndexFunc).
 		WithIndex(&apiv1.Backup{}, ".spec.cluster.name", func(rawObj client.Object) []string {
 			return []string{rawObj.(*apiv1.Backup).Spec.Cluster.Name}
 		}).
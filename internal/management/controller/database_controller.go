@@ -115,8 +115,9 @@ func (r *DatabaseReconciler) Reconcile(ctx context.Context, req ctrl.Request) (c
 		return ctrl.Result{}, nil
 	}
 
-	// If everything is reconciled, we're done here
-	if database.Generation == database.Status.ObservedGeneration {
+	// If everything is reconciled, we're done here. Databases declaring
+	// privileges are periodically reconciled to correct drifts in the ACLs
+	if database.Generation == database.Status.ObservedGeneration && !database.HasPrivileges() {
 		return ctrl.Result{}, nil
 	}
 
@@ -269,6 +270,11 @@ func (r *DatabaseReconciler) reconcileDatabaseResource(ctx context.Context, obj
 			return ErrFailedDatabaseObjectReconciliation
 		}
 	}
+	for _, status := range obj.Status.Privileges {
+		if !status.Applied {
+			return ErrFailedDatabaseObjectReconciliation
+		}
+	}
 
 	return nil
 }
@@ -282,7 +288,7 @@ func (r *DatabaseReconciler) reconcileDatabaseObjects(
 	objectCount += len(obj.Spec.FDWs)
 	objectCount += len(obj.Spec.Servers)
 
-	if objectCount == 0 {
+	if objectCount == 0 && !obj.HasPrivileges() {
 		return nil
 	}
 
@@ -295,6 +301,7 @@ func (r *DatabaseReconciler) reconcileDatabaseObjects(
 	obj.Status.Extensions = extensionObjectManager.reconcileList(ctx, db, obj.Spec.Extensions)
 	obj.Status.FDWs = fdwObjectManager.reconcileList(ctx, db, obj.Spec.FDWs)
 	obj.Status.Servers = serverObjectManager.reconcileList(ctx, db, obj.Spec.Servers)
+	obj.Status.Privileges = reconcilePrivileges(ctx, db, obj)
 
 	return nil
 }
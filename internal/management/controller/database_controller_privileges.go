@@ -0,0 +1,329 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package controller
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/cloudnative-pg/machinery/pkg/log"
+	"github.com/jackc/pgx/v5"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+)
+
+// privilegesCountSQLTemplate is used to detect drifts between the declared
+// privileges and the ACLs stored in the catalog. The objects CTE is
+// injected by the caller and must return the `oid` and the `acl` of
+// every target object. The query returns the number of target objects and,
+// for every privilege held by the grantee ($1), the number of objects where
+// it has been granted
+const privilegesCountSQLTemplate = `
+WITH objects AS (%s),
+grantee AS (
+	SELECT CASE WHEN pg_catalog.upper($1::pg_catalog.text) = 'PUBLIC' THEN 0::pg_catalog.oid
+		ELSE (SELECT r.oid FROM pg_catalog.pg_roles r WHERE r.rolname = $1::pg_catalog.text) END AS oid
+)
+SELECT
+	(SELECT pg_catalog.count(*) FROM objects),
+	COALESCE((
+		SELECT pg_catalog.json_object_agg(s.privilege_type, s.objects)
+		FROM (
+			SELECT a.privilege_type, pg_catalog.count(DISTINCT o.oid) AS objects
+			FROM objects o, pg_catalog.aclexplode(o.acl) a, grantee g
+			WHERE a.grantee = g.oid
+			GROUP BY a.privilege_type
+		) s
+	), '{}')
+`
+
+const (
+	databaseACLSQL = `SELECT d.oid, COALESCE(d.datacl, pg_catalog.acldefault('d', d.datdba)) AS acl
+	FROM pg_catalog.pg_database d
+	WHERE d.datname = $2`
+
+	schemaACLSQL = `SELECT n.oid, COALESCE(n.nspacl, pg_catalog.acldefault('n', n.nspowner)) AS acl
+	FROM pg_catalog.pg_namespace n
+	WHERE n.nspname = $2`
+
+	tablesACLSQL = `SELECT c.oid, COALESCE(c.relacl, pg_catalog.acldefault('r', c.relowner)) AS acl
+	FROM pg_catalog.pg_class c
+	JOIN pg_catalog.pg_namespace n ON n.oid = c.relnamespace
+	WHERE n.nspname = $2 AND c.relkind IN ('r', 'p', 'v', 'm', 'f')`
+
+	sequencesACLSQL = `SELECT c.oid, COALESCE(c.relacl, pg_catalog.acldefault('s', c.relowner)) AS acl
+	FROM pg_catalog.pg_class c
+	JOIN pg_catalog.pg_namespace n ON n.oid = c.relnamespace
+	WHERE n.nspname = $2 AND c.relkind = 'S'`
+
+	functionsACLSQL = `SELECT p.oid, COALESCE(p.proacl, pg_catalog.acldefault('f', p.proowner)) AS acl
+	FROM pg_catalog.pg_proc p
+	JOIN pg_catalog.pg_namespace n ON n.oid = p.pronamespace
+	WHERE n.nspname = $2 AND p.prokind <> 'p'`
+
+	defaultACLSQL = `SELECT d.oid, d.defaclacl AS acl
+	FROM pg_catalog.pg_default_acl d
+	JOIN pg_catalog.pg_namespace n ON n.oid = d.defaclnamespace
+	WHERE n.nspname = $2 AND d.defaclobjtype = $3
+	AND d.defaclrole = (SELECT r.oid FROM pg_catalog.pg_roles r WHERE r.rolname = $4)`
+)
+
+var (
+	allDatabasePrivileges = []string{"CREATE", "TEMPORARY", "CONNECT"}
+	allSchemaPrivileges   = []string{"USAGE", "CREATE"}
+	allTablePrivileges    = []string{
+		"SELECT", "INSERT", "UPDATE", "DELETE", "TRUNCATE", "REFERENCES", "TRIGGER",
+	}
+	allSequencePrivileges = []string{"USAGE", "SELECT", "UPDATE"}
+	allFunctionPrivileges = []string{"EXECUTE"}
+)
+
+// privilegeTarget describes the objects a set of privileges is
+// granted on or revoked from
+type privilegeTarget struct {
+	// objectClause is the `ON` clause of the GRANT and REVOKE commands
+	objectClause string
+
+	// commandPrefix is prepended to the GRANT and REVOKE commands, and is
+	// used to build `ALTER DEFAULT PRIVILEGES` commands
+	commandPrefix string
+
+	// aclSQL is the objects CTE used in privilegesCountSQLTemplate
+	aclSQL string
+
+	// aclArgs are the arguments of aclSQL, starting from $2
+	aclArgs []any
+
+	// singleton is true when the privileges target exactly one object,
+	// which is supposed to exist
+	singleton bool
+
+	// allPrivileges is the list of privileges `ALL` expands to
+	allPrivileges []string
+}
+
+type privilegeCounts struct {
+	objects int
+	granted map[string]int
+}
+
+// reconcilePrivileges aligns the privileges declared in the database and in its
+// schemas to the ACLs stored in the catalog, returning one status per grant
+func reconcilePrivileges(ctx context.Context, db *sql.DB, obj *apiv1.Database) []apiv1.DatabaseObjectStatus {
+	var result []apiv1.DatabaseObjectStatus
+
+	apply := func(target privilegeTarget, specs []apiv1.PrivilegeSpec) {
+		for _, spec := range specs {
+			result = append(result, reconcilePrivilege(ctx, db, target, spec))
+		}
+	}
+
+	apply(privilegeTarget{
+		objectClause:  fmt.Sprintf("DATABASE %s", pgx.Identifier{obj.Spec.Name}.Sanitize()),
+		aclSQL:        databaseACLSQL,
+		aclArgs:       []any{obj.Spec.Name},
+		singleton:     true,
+		allPrivileges: allDatabasePrivileges,
+	}, obj.Spec.Privileges)
+
+	for _, schema := range obj.Spec.Schemas {
+		if schema.Ensure == apiv1.EnsureAbsent {
+			continue
+		}
+
+		sanitizedSchema := pgx.Identifier{schema.Name}.Sanitize()
+		apply(privilegeTarget{
+			objectClause:  fmt.Sprintf("SCHEMA %s", sanitizedSchema),
+			aclSQL:        schemaACLSQL,
+			aclArgs:       []any{schema.Name},
+			singleton:     true,
+			allPrivileges: allSchemaPrivileges,
+		}, schema.Privileges)
+		apply(privilegeTarget{
+			objectClause:  fmt.Sprintf("ALL TABLES IN SCHEMA %s", sanitizedSchema),
+			aclSQL:        tablesACLSQL,
+			aclArgs:       []any{schema.Name},
+			allPrivileges: allTablePrivileges,
+		}, schema.TablePrivileges)
+		apply(privilegeTarget{
+			objectClause:  fmt.Sprintf("ALL SEQUENCES IN SCHEMA %s", sanitizedSchema),
+			aclSQL:        sequencesACLSQL,
+			aclArgs:       []any{schema.Name},
+			allPrivileges: allSequencePrivileges,
+		}, schema.SequencePrivileges)
+		apply(privilegeTarget{
+			objectClause:  fmt.Sprintf("ALL FUNCTIONS IN SCHEMA %s", sanitizedSchema),
+			aclSQL:        functionsACLSQL,
+			aclArgs:       []any{schema.Name},
+			allPrivileges: allFunctionPrivileges,
+		}, schema.FunctionPrivileges)
+
+		for _, defaultPrivilege := range schema.DefaultPrivileges {
+			result = append(result, reconcilePrivilege(
+				ctx,
+				db,
+				newDefaultPrivilegeTarget(schema.Name, obj.Spec.Owner, defaultPrivilege),
+				defaultPrivilege.PrivilegeSpec,
+			))
+		}
+	}
+
+	return result
+}
+
+func newDefaultPrivilegeTarget(
+	schemaName string,
+	databaseOwner string,
+	spec apiv1.DefaultPrivilegeSpec,
+) privilegeTarget {
+	forRole := spec.ForRole
+	if forRole == "" {
+		forRole = databaseOwner
+	}
+
+	target := privilegeTarget{
+		commandPrefix: fmt.Sprintf(
+			"ALTER DEFAULT PRIVILEGES FOR ROLE %s IN SCHEMA %s ",
+			pgx.Identifier{forRole}.Sanitize(),
+			pgx.Identifier{schemaName}.Sanitize(),
+		),
+		aclSQL:    defaultACLSQL,
+		singleton: true,
+	}
+
+	// The object types are the ones used in pg_default_acl.defaclobjtype
+	switch spec.ObjectType {
+	case apiv1.DefaultPrivilegeTables:
+		target.objectClause = "TABLES"
+		target.aclArgs = []any{schemaName, "r", forRole}
+		target.allPrivileges = allTablePrivileges
+	case apiv1.DefaultPrivilegeSequences:
+		target.objectClause = "SEQUENCES"
+		target.aclArgs = []any{schemaName, "S", forRole}
+		target.allPrivileges = allSequencePrivileges
+	case apiv1.DefaultPrivilegeFunctions:
+		target.objectClause = "FUNCTIONS"
+		target.aclArgs = []any{schemaName, "f", forRole}
+		target.allPrivileges = allFunctionPrivileges
+	}
+
+	return target
+}
+
+// reconcilePrivilege grants or revokes a set of privileges, if the ACLs
+// stored in the catalog are not already aligned with the specification
+func reconcilePrivilege(
+	ctx context.Context,
+	db *sql.DB,
+	target privilegeTarget,
+	spec apiv1.PrivilegeSpec,
+) apiv1.DatabaseObjectStatus {
+	contextLogger := log.FromContext(ctx)
+	statement := target.buildStatement(spec)
+
+	counts, err := getPrivilegeCounts(ctx, db, target, spec.Role)
+	if err != nil {
+		return createFailedStatus(statement, fmt.Sprintf("while reading the privileges: %v", err))
+	}
+
+	if !target.needsChanges(spec, counts) {
+		return createSuccessStatus(statement)
+	}
+
+	if _, err := db.ExecContext(ctx, statement); err != nil {
+		contextLogger.Error(err, "while applying privileges", "query", statement)
+		return createFailedStatus(statement, err.Error())
+	}
+	contextLogger.Info("applied privileges", "query", statement)
+
+	return createSuccessStatus(statement)
+}
+
+func getPrivilegeCounts(
+	ctx context.Context,
+	db *sql.DB,
+	target privilegeTarget,
+	role string,
+) (*privilegeCounts, error) {
+	args := append([]any{role}, target.aclArgs...)
+	row := db.QueryRowContext(ctx, fmt.Sprintf(privilegesCountSQLTemplate, target.aclSQL), args...)
+
+	var grantedJSON []byte
+	result := privilegeCounts{}
+	if err := row.Scan(&result.objects, &grantedJSON); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(grantedJSON, &result.granted); err != nil {
+		return nil, fmt.Errorf("while decoding the privileges: %w", err)
+	}
+
+	if target.singleton {
+		result.objects = 1
+	}
+
+	return &result, nil
+}
+
+// needsChanges returns true when at least one of the objects misses one of the
+// privileges to be granted, or holds one of the privileges to be revoked
+func (target privilegeTarget) needsChanges(spec apiv1.PrivilegeSpec, counts *privilegeCounts) bool {
+	for _, privilege := range target.expandPrivileges(spec.Privileges) {
+		granted := counts.granted[privilege]
+		if spec.Type == apiv1.RevokeUsageSpecType && granted > 0 {
+			return true
+		}
+		if spec.Type != apiv1.RevokeUsageSpecType && granted < counts.objects {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (target privilegeTarget) expandPrivileges(privileges []string) []string {
+	if slices.Contains(privileges, "ALL") {
+		return target.allPrivileges
+	}
+	return privileges
+}
+
+func (target privilegeTarget) buildStatement(spec apiv1.PrivilegeSpec) string {
+	privileges := strings.Join(spec.Privileges, ", ")
+	if slices.Contains(spec.Privileges, "ALL") {
+		privileges = "ALL"
+	}
+
+	grantee := pgx.Identifier{spec.Role}.Sanitize()
+	if strings.EqualFold(spec.Role, "public") {
+		grantee = "PUBLIC"
+	}
+
+	if spec.Type == apiv1.RevokeUsageSpecType {
+		return fmt.Sprintf("%sREVOKE %s ON %s FROM %s",
+			target.commandPrefix, privileges, target.objectClause, grantee)
+	}
+
+	return fmt.Sprintf("%sGRANT %s ON %s TO %s",
+		target.commandPrefix, privileges, target.objectClause, grantee)
+}
@@ -0,0 +1,213 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package controller
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Managed Database privileges", func() {
+	var (
+		dbMock   sqlmock.Sqlmock
+		db       *sql.DB
+		database *apiv1.Database
+		err      error
+	)
+
+	privilegeColumns := []string{"objects", "privileges"}
+
+	BeforeEach(func() {
+		db, dbMock, err = sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		Expect(err).ToNot(HaveOccurred())
+
+		database = &apiv1.Database{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "db-one",
+			},
+			Spec: apiv1.DatabaseSpec{
+				ClusterRef: corev1.LocalObjectReference{
+					Name: "cluster-example",
+				},
+				Name:  "db-one",
+				Owner: "app",
+			},
+		}
+	})
+
+	AfterEach(func() {
+		Expect(dbMock.ExpectationsWereMet()).To(Succeed())
+	})
+
+	It("does nothing when no privilege is declared", func(ctx SpecContext) {
+		Expect(database.HasPrivileges()).To(BeFalse())
+		Expect(reconcilePrivileges(ctx, db, database)).To(BeEmpty())
+	})
+
+	It("grants the missing database privileges", func(ctx SpecContext) {
+		database.Spec.Privileges = []apiv1.PrivilegeSpec{
+			{Role: "reader", Privileges: []string{"CONNECT", "TEMPORARY"}, Type: apiv1.GrantUsageSpecType},
+		}
+
+		dbMock.ExpectQuery(fmt.Sprintf(privilegesCountSQLTemplate, databaseACLSQL)).
+			WithArgs("reader", "db-one").
+			WillReturnRows(sqlmock.NewRows(privilegeColumns).AddRow(1, []byte(`{"CONNECT": 1}`)))
+		dbMock.ExpectExec(`GRANT CONNECT, TEMPORARY ON DATABASE "db-one" TO "reader"`).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+
+		Expect(reconcilePrivileges(ctx, db, database)).To(ConsistOf(apiv1.DatabaseObjectStatus{
+			Name:    `GRANT CONNECT, TEMPORARY ON DATABASE "db-one" TO "reader"`,
+			Applied: true,
+		}))
+	})
+
+	It("does not grant privileges that are already in place", func(ctx SpecContext) {
+		database.Spec.Schemas = []apiv1.SchemaSpec{
+			{
+				DatabaseObjectSpec: apiv1.DatabaseObjectSpec{Name: "app", Ensure: apiv1.EnsurePresent},
+				TablePrivileges: []apiv1.PrivilegeSpec{
+					{Role: "reader", Privileges: []string{"SELECT"}, Type: apiv1.GrantUsageSpecType},
+				},
+			},
+		}
+		Expect(database.HasPrivileges()).To(BeTrue())
+
+		dbMock.ExpectQuery(fmt.Sprintf(privilegesCountSQLTemplate, tablesACLSQL)).
+			WithArgs("reader", "app").
+			WillReturnRows(sqlmock.NewRows(privilegeColumns).AddRow(3, []byte(`{"SELECT": 3}`)))
+
+		Expect(reconcilePrivileges(ctx, db, database)).To(ConsistOf(apiv1.DatabaseObjectStatus{
+			Name:    `GRANT SELECT ON ALL TABLES IN SCHEMA "app" TO "reader"`,
+			Applied: true,
+		}))
+	})
+
+	It("re-applies table privileges when a table misses them", func(ctx SpecContext) {
+		database.Spec.Schemas = []apiv1.SchemaSpec{
+			{
+				DatabaseObjectSpec: apiv1.DatabaseObjectSpec{Name: "app", Ensure: apiv1.EnsurePresent},
+				TablePrivileges: []apiv1.PrivilegeSpec{
+					{Role: "writer", Privileges: []string{"ALL"}, Type: apiv1.GrantUsageSpecType},
+				},
+			},
+		}
+
+		dbMock.ExpectQuery(fmt.Sprintf(privilegesCountSQLTemplate, tablesACLSQL)).
+			WithArgs("writer", "app").
+			WillReturnRows(sqlmock.NewRows(privilegeColumns).AddRow(
+				2, []byte(`{"SELECT": 2, "INSERT": 2, "UPDATE": 2, "DELETE": 1}`)))
+		dbMock.ExpectExec(`GRANT ALL ON ALL TABLES IN SCHEMA "app" TO "writer"`).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+
+		statuses := reconcilePrivileges(ctx, db, database)
+		Expect(statuses).To(HaveLen(1))
+		Expect(statuses[0].Applied).To(BeTrue())
+	})
+
+	It("revokes schema privileges from PUBLIC", func(ctx SpecContext) {
+		database.Spec.Schemas = []apiv1.SchemaSpec{
+			{
+				DatabaseObjectSpec: apiv1.DatabaseObjectSpec{Name: "public", Ensure: apiv1.EnsurePresent},
+				Privileges: []apiv1.PrivilegeSpec{
+					{Role: "public", Privileges: []string{"CREATE"}, Type: apiv1.RevokeUsageSpecType},
+				},
+			},
+		}
+
+		dbMock.ExpectQuery(fmt.Sprintf(privilegesCountSQLTemplate, schemaACLSQL)).
+			WithArgs("public", "public").
+			WillReturnRows(sqlmock.NewRows(privilegeColumns).AddRow(1, []byte(`{"USAGE": 1, "CREATE": 1}`)))
+		dbMock.ExpectExec(`REVOKE CREATE ON SCHEMA "public" FROM PUBLIC`).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+
+		statuses := reconcilePrivileges(ctx, db, database)
+		Expect(statuses).To(HaveLen(1))
+		Expect(statuses[0].Applied).To(BeTrue())
+	})
+
+	It("skips the privileges of the schemas to be dropped", func(ctx SpecContext) {
+		database.Spec.Schemas = []apiv1.SchemaSpec{
+			{
+				DatabaseObjectSpec: apiv1.DatabaseObjectSpec{Name: "old", Ensure: apiv1.EnsureAbsent},
+				Privileges: []apiv1.PrivilegeSpec{
+					{Role: "reader", Privileges: []string{"USAGE"}, Type: apiv1.GrantUsageSpecType},
+				},
+			},
+		}
+
+		Expect(reconcilePrivileges(ctx, db, database)).To(BeEmpty())
+	})
+
+	It("alters the default privileges of the database owner", func(ctx SpecContext) {
+		database.Spec.Schemas = []apiv1.SchemaSpec{
+			{
+				DatabaseObjectSpec: apiv1.DatabaseObjectSpec{Name: "app", Ensure: apiv1.EnsurePresent},
+				DefaultPrivileges: []apiv1.DefaultPrivilegeSpec{
+					{
+						PrivilegeSpec: apiv1.PrivilegeSpec{
+							Role:       "reader",
+							Privileges: []string{"USAGE", "SELECT"},
+							Type:       apiv1.GrantUsageSpecType,
+						},
+						ObjectType: apiv1.DefaultPrivilegeSequences,
+					},
+				},
+			},
+		}
+
+		dbMock.ExpectQuery(fmt.Sprintf(privilegesCountSQLTemplate, defaultACLSQL)).
+			WithArgs("reader", "app", "S", "app").
+			WillReturnRows(sqlmock.NewRows(privilegeColumns).AddRow(0, []byte(`{}`)))
+		expectedStatement := `ALTER DEFAULT PRIVILEGES FOR ROLE "app" IN SCHEMA "app" ` +
+			`GRANT USAGE, SELECT ON SEQUENCES TO "reader"`
+		dbMock.ExpectExec(expectedStatement).WillReturnResult(sqlmock.NewResult(0, 0))
+
+		Expect(reconcilePrivileges(ctx, db, database)).To(ConsistOf(apiv1.DatabaseObjectStatus{
+			Name:    expectedStatement,
+			Applied: true,
+		}))
+	})
+
+	It("reports the errors in the status of the grant", func(ctx SpecContext) {
+		database.Spec.Privileges = []apiv1.PrivilegeSpec{
+			{Role: "ghost", Privileges: []string{"CONNECT"}, Type: apiv1.GrantUsageSpecType},
+		}
+
+		dbMock.ExpectQuery(fmt.Sprintf(privilegesCountSQLTemplate, databaseACLSQL)).
+			WithArgs("ghost", "db-one").
+			WillReturnRows(sqlmock.NewRows(privilegeColumns).AddRow(1, []byte(`{}`)))
+		dbMock.ExpectExec(`GRANT CONNECT ON DATABASE "db-one" TO "ghost"`).
+			WillReturnError(fmt.Errorf(`role "ghost" does not exist`))
+
+		statuses := reconcilePrivileges(ctx, db, database)
+		Expect(statuses).To(HaveLen(1))
+		Expect(statuses[0].Applied).To(BeFalse())
+		Expect(statuses[0].Message).To(ContainSubstring("does not exist"))
+	})
+})
@@ -0,0 +1,207 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package diskfull
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"reflect"
+	"time"
+
+	"github.com/cloudnative-pg/machinery/pkg/fileutils"
+	"github.com/cloudnative-pg/machinery/pkg/log"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/configfile"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/constants"
+	pgpostgres "github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+// readOnlyParameter is the parameter used to reject the write
+// transactions while the protection is active
+const readOnlyParameter = "default_transaction_read_only"
+
+// Monitor periodically checks the free space in the PGDATA and WAL volumes
+// and, on the primary instance, rejects the write transactions when it
+// falls below the critical threshold
+type Monitor struct {
+	instance *postgres.Instance
+}
+
+// NewMonitor creates a new disk-full monitor
+func NewMonitor(instance *postgres.Instance) *Monitor {
+	return &Monitor{
+		instance: instance,
+	}
+}
+
+// Start starts running the disk-full monitor
+func (m *Monitor) Start(ctx context.Context) error {
+	contextLog := log.FromContext(ctx).WithName("DiskFullMonitor")
+	go func() {
+		var config *apiv1.DiskFullProtectionConfiguration
+		select {
+		case config = <-m.instance.DiskFullProtectionChan():
+		case <-ctx.Done():
+			return
+		}
+
+		checkInterval := config.GetCheckInterval()
+		ticker := time.NewTicker(checkInterval)
+
+		defer func() {
+			ticker.Stop()
+			contextLog.Info("Terminated disk-full monitor loop")
+		}()
+
+		for {
+			if err := m.check(ctx, config); err != nil {
+				contextLog.Warning("checking the free space of the volumes", "err", err)
+			}
+
+		wait:
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case newConfig := <-m.instance.DiskFullProtectionChan():
+					// The configuration is sent at every reconciliation of
+					// the instance, let's check again only when it changes
+					if reflect.DeepEqual(config, newConfig) {
+						continue
+					}
+					config = newConfig
+					break wait
+				case <-ticker.C:
+					break wait
+				}
+			}
+
+			// Update the ticker if the check interval has changed
+			if newCheckInterval := config.GetCheckInterval(); checkInterval != newCheckInterval {
+				ticker.Reset(newCheckInterval)
+				checkInterval = newCheckInterval
+			}
+		}
+	}()
+	<-ctx.Done()
+	return nil
+}
+
+func (m *Monitor) check(ctx context.Context, config *apiv1.DiskFullProtectionConfiguration) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("recovered from a panic: %s", r)
+		}
+	}()
+
+	contextLog := log.FromContext(ctx).WithName("DiskFullMonitor")
+
+	if m.instance.IsFenced() {
+		contextLog.Trace("Disk-full check skipped: instance is fenced.")
+		return nil
+	}
+
+	// The parameter is written in a file managed only by this monitor, as
+	// the other configuration files are concurrently updated by the
+	// instance reconciler
+	configFile := path.Join(m.instance.PgData, constants.PostgresqlDiskFullConfigurationFile)
+	active, err := isReadOnlyConfigured(configFile)
+	if err != nil {
+		return err
+	}
+
+	// Only the primary instance accepts write transactions, while
+	// the replicas must follow it even when the protection is active
+	shouldBeActive := false
+	if config.IsEnabled() {
+		isPrimary, err := m.instance.IsPrimary()
+		if err != nil {
+			return err
+		}
+		if isPrimary {
+			shouldBeActive = isProtectionNeeded(active, config, m.instance.GetVolumesUsage())
+		}
+	}
+
+	options := map[string]string{}
+	if shouldBeActive {
+		options[readOnlyParameter] = "on"
+	}
+	changed, err := configfile.UpdatePostgresConfigurationFile(configFile, options, readOnlyParameter)
+	if err != nil {
+		return err
+	}
+
+	if changed {
+		if shouldBeActive {
+			contextLog.Warning("The free space of the volumes is critical, rejecting the write transactions")
+		} else {
+			contextLog.Info("Lifting the disk-full protection, accepting the write transactions")
+		}
+		if err := m.instance.Reload(ctx); err != nil {
+			return err
+		}
+	}
+
+	m.instance.SetDiskFullProtectionActive(shouldBeActive)
+	return nil
+}
+
+// isReadOnlyConfigured checks whether the protection has been applied
+// to the passed configuration file
+func isReadOnlyConfigured(fileName string) (bool, error) {
+	lines, err := fileutils.ReadFileLines(fileName)
+	if err != nil {
+		return false, fmt.Errorf("error while reading content of %v: %w", fileName, err)
+	}
+
+	return len(configfile.ReadLinesFromConfigurationContents(lines, readOnlyParameter)) > 0, nil
+}
+
+// isProtectionNeeded decides whether the protection should be active given
+// the usage of the volumes. Once active, the protection is lifted only when
+// the free space grows above the recovery threshold, to avoid flapping
+func isProtectionNeeded(
+	active bool,
+	config *apiv1.DiskFullProtectionConfiguration,
+	volumesUsage []pgpostgres.VolumeUsage,
+) bool {
+	threshold := config.GetCriticalFreeSpacePercentage()
+	if active {
+		threshold = config.GetRecoveryFreeSpacePercentage()
+	}
+
+	for _, usage := range volumesUsage {
+		if usage.Role != utils.PVCRolePgData && usage.Role != utils.PVCRolePgWal {
+			continue
+		}
+
+		freePercentage := min(100-usage.UsedPercentage(), 100-usage.InodesUsedPercentage())
+		if freePercentage < float64(threshold) {
+			return true
+		}
+	}
+
+	return false
+}
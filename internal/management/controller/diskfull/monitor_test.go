@@ -0,0 +1,102 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package diskfull
+
+import (
+	"os"
+	"path/filepath"
+
+	"k8s.io/utils/ptr"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	pgpostgres "github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("isProtectionNeeded", func() {
+	config := &apiv1.DiskFullProtectionConfiguration{
+		Enabled:                     true,
+		CriticalFreeSpacePercentage: ptr.To(int32(5)),
+		RecoveryFreeSpacePercentage: ptr.To(int32(10)),
+	}
+
+	usage := func(role utils.PVCRole, freePercentage int64) pgpostgres.VolumeUsage {
+		return pgpostgres.VolumeUsage{
+			Role:           role,
+			TotalBytes:     100,
+			UsedBytes:      100 - freePercentage,
+			AvailableBytes: freePercentage,
+			TotalInodes:    100,
+			FreeInodes:     100,
+		}
+	}
+
+	It("activates the protection when a volume is below the critical threshold", func() {
+		Expect(isProtectionNeeded(false, config, []pgpostgres.VolumeUsage{
+			usage(utils.PVCRolePgData, 50),
+			usage(utils.PVCRolePgWal, 4),
+		})).To(BeTrue())
+	})
+
+	It("doesn't activate the protection while the free space is above the critical threshold", func() {
+		Expect(isProtectionNeeded(false, config, []pgpostgres.VolumeUsage{
+			usage(utils.PVCRolePgData, 7),
+		})).To(BeFalse())
+	})
+
+	It("keeps the protection active until the free space reaches the recovery threshold", func() {
+		Expect(isProtectionNeeded(true, config, []pgpostgres.VolumeUsage{
+			usage(utils.PVCRolePgData, 7),
+		})).To(BeTrue())
+		Expect(isProtectionNeeded(true, config, []pgpostgres.VolumeUsage{
+			usage(utils.PVCRolePgData, 10),
+		})).To(BeFalse())
+	})
+
+	It("considers the free inodes", func() {
+		volume := usage(utils.PVCRolePgData, 50)
+		volume.FreeInodes = 2
+		Expect(isProtectionNeeded(false, config, []pgpostgres.VolumeUsage{volume})).To(BeTrue())
+	})
+
+	It("ignores the tablespace volumes", func() {
+		Expect(isProtectionNeeded(false, config, []pgpostgres.VolumeUsage{
+			usage(utils.PVCRolePgData, 50),
+			usage(utils.PVCRolePgTablespace, 1),
+		})).To(BeFalse())
+	})
+})
+
+var _ = Describe("isReadOnlyConfigured", func() {
+	It("detects the read-only parameter in the configuration file", func() {
+		fileName := filepath.Join(GinkgoT().TempDir(), "diskfull.conf")
+
+		Expect(isReadOnlyConfigured(fileName)).To(BeFalse())
+
+		Expect(os.WriteFile(fileName, []byte("primary_conninfo = 'host=test'\n"), 0o600)).To(Succeed())
+		Expect(isReadOnlyConfigured(fileName)).To(BeFalse())
+
+		Expect(os.WriteFile(fileName, []byte("default_transaction_read_only = 'on'\n"), 0o600)).To(Succeed())
+		Expect(isReadOnlyConfigured(fileName)).To(BeTrue())
+	})
+})
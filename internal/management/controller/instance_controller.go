@@ -204,8 +204,32 @@ func (r *InstanceReconciler) Reconcile(
 	}
 	reloadNeeded = reloadNeeded || reloadClusterRoleConfig
 
+	// The slow query text mode is set before PostgreSQL is started, as
+	// the slow queries are redacted until then
+	var slowQueryText apiv1.SlowQueryTextMode
+	if cluster.Spec.Logging != nil {
+		slowQueryText = cluster.Spec.Logging.SlowQueryText
+	}
+	r.instance.SetSlowQueryText(slowQueryText)
+
 	r.systemInitialization.Broadcast()
 
+	// The disk-full protection is configured before checking whether
+	// PostgreSQL is up, as it needs to work while the volumes are full
+	r.instance.ConfigureDiskFullProtection(cluster.Spec.DiskFullProtection)
+
+	var otlpMetrics *apiv1.OTLPMetricsConfiguration
+	if cluster.Spec.Monitoring != nil {
+		otlpMetrics = cluster.Spec.Monitoring.OTLP
+	}
+	r.instance.ConfigureOTLPMetrics(otlpMetrics)
+
+	var otlpLogs *apiv1.OTLPLogsConfiguration
+	if cluster.Spec.Logging != nil {
+		otlpLogs = cluster.Spec.Logging.OTLP
+	}
+	r.instance.ConfigureOTLPLogs(otlpLogs)
+
 	if result := r.reconcileFencing(ctx, cluster); result != nil {
 		contextLogger.Info("Fencing status changed, will not proceed with the reconciliation loop")
 		return *result, nil
@@ -282,6 +306,7 @@ func (r *InstanceReconciler) Reconcile(
 	// needing the database to be up should be put below this line.
 
 	r.configureSlotReplicator(cluster)
+	r.instance.ConfigureWraparoundMonitor(cluster.Spec.WraparoundProtection)
 
 	postgresDB, err := r.instance.ConnectionPool().Connection("postgres")
 	if err != nil {
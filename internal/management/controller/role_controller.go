@@ -0,0 +1,225 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package controller
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/cloudnative-pg/machinery/pkg/log"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/management/controller/roles"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+// RoleReconciler reconciles a Role object
+type RoleReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+
+	instance            instanceInterface
+	finalizerReconciler *finalizerReconciler[*apiv1.Role]
+
+	getSuperUserDB func() (*sql.DB, error)
+}
+
+// roleReconciliationInterval is the time between the
+// role reconciliation loop failures
+const roleReconciliationInterval = 30 * time.Second
+
+// +kubebuilder:rbac:groups=postgresql.cnpg.io,resources=roles,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=postgresql.cnpg.io,resources=roles/status,verbs=get;update;patch
+
+// Reconcile is the role reconciliation loop
+func (r *RoleReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	contextLogger := log.FromContext(ctx).
+		WithName("role_reconciler").
+		WithValues("roleName", req.Name)
+
+	// Get the role object
+	var role apiv1.Role
+	if err := r.Get(ctx, client.ObjectKey{
+		Namespace: req.Namespace,
+		Name:      req.Name,
+	}, &role); err != nil {
+		contextLogger.Trace("Could not fetch Role", "error", err)
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	// This is not for me!
+	if role.Spec.ClusterRef.Name != r.instance.GetClusterName() {
+		contextLogger.Trace("Role is not for this cluster",
+			"cluster", role.Spec.ClusterRef.Name,
+			"expected", r.instance.GetClusterName(),
+		)
+		return ctrl.Result{}, nil
+	}
+
+	// If everything is reconciled, we're done here. Roles having a password
+	// Secret are periodically reconciled to follow the changes in the Secret
+	if role.Generation == role.Status.ObservedGeneration && role.Spec.PasswordSecret == nil {
+		return ctrl.Result{}, nil
+	}
+
+	// Fetch the Cluster from the cache
+	cluster, err := r.GetCluster(ctx)
+	if err != nil {
+		return ctrl.Result{}, markAsFailed(ctx, r.Client, &role, fmt.Errorf("while fetching the cluster: %w", err))
+	}
+
+	// Still not for me, we're waiting for a switchover
+	if cluster.Status.CurrentPrimary != cluster.Status.TargetPrimary {
+		return ctrl.Result{RequeueAfter: roleReconciliationInterval}, nil
+	}
+
+	// This is not for me, at least now
+	if cluster.Status.CurrentPrimary != r.instance.GetPodName() {
+		return ctrl.Result{RequeueAfter: roleReconciliationInterval}, nil
+	}
+
+	contextLogger.Debug("Reconciling role")
+	defer func() {
+		contextLogger.Debug("Reconciliation loop of role exited")
+	}()
+
+	// Cannot do anything on a replica cluster
+	if cluster.IsReplica() {
+		if err := markAsUnknown(ctx, r.Client, &role, errClusterIsReplica); err != nil {
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{RequeueAfter: roleReconciliationInterval}, nil
+	}
+
+	if err := r.finalizerReconciler.reconcile(ctx, &role); err != nil {
+		return ctrl.Result{}, fmt.Errorf("while reconciling the finalizer: %w", err)
+	}
+	if !role.GetDeletionTimestamp().IsZero() {
+		return ctrl.Result{}, nil
+	}
+
+	// The roles declared in the Cluster have precedence over the Role objects
+	if err := role.MustNotConflictWithManagedRoles(cluster); err != nil {
+		if markErr := markAsFailed(ctx, r.Client, &role, err); markErr != nil {
+			return ctrl.Result{}, markErr
+		}
+		return ctrl.Result{RequeueAfter: roleReconciliationInterval}, nil
+	}
+
+	if res, err := detectConflictingManagers(ctx, r.Client, &role, &apiv1.RoleList{}); err != nil ||
+		!res.IsZero() {
+		return res, err
+	}
+
+	if err := r.reconcileRoleResource(ctx, &role); err != nil {
+		if markErr := markAsFailed(ctx, r.Client, &role, err); markErr != nil {
+			contextLogger.Error(err, "while marking as failed the role resource",
+				"error", err,
+				"markError", markErr,
+			)
+			return ctrl.Result{}, fmt.Errorf(
+				"encountered an error while marking as failed the role resource: %w, original error: %w",
+				markErr,
+				err)
+		}
+		return ctrl.Result{RequeueAfter: roleReconciliationInterval}, nil
+	}
+
+	if err := markAsReady(ctx, r.Client, &role); err != nil {
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{RequeueAfter: roleReconciliationInterval}, nil
+}
+
+func (r *RoleReconciler) reconcileRoleResource(ctx context.Context, role *apiv1.Role) error {
+	db, err := r.getSuperUserDB()
+	if err != nil {
+		return fmt.Errorf("while getting DB connection: %w", err)
+	}
+
+	passwordState, err := roles.ApplyRole(
+		ctx,
+		r.Client,
+		role.Namespace,
+		db,
+		role.Spec.RoleConfiguration,
+		role.Status.PasswordState,
+	)
+	if err != nil {
+		return err
+	}
+
+	role.Status.PasswordState = passwordState
+	return nil
+}
+
+func (r *RoleReconciler) evaluateDropRole(ctx context.Context, role *apiv1.Role) error {
+	if role.Spec.ReclaimPolicy != apiv1.RoleReclaimDelete {
+		return nil
+	}
+	db, err := r.getSuperUserDB()
+	if err != nil {
+		return fmt.Errorf("while getting DB connection: %w", err)
+	}
+
+	return roles.DropRole(ctx, r.Client, role.Namespace, db, role.Spec.Name)
+}
+
+// NewRoleReconciler creates a new role reconciler
+func NewRoleReconciler(
+	mgr manager.Manager,
+	instance *postgres.Instance,
+) *RoleReconciler {
+	rr := &RoleReconciler{
+		Client:   mgr.GetClient(),
+		instance: instance,
+		getSuperUserDB: func() (*sql.DB, error) {
+			return instance.GetSuperUserDB()
+		},
+	}
+
+	rr.finalizerReconciler = newFinalizerReconciler(
+		mgr.GetClient(),
+		utils.RoleFinalizerName,
+		rr.evaluateDropRole,
+	)
+
+	return rr
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *RoleReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&apiv1.Role{}).
+		Named("instance-role").
+		Complete(r)
+}
+
+// GetCluster gets the managed cluster through the client
+func (r *RoleReconciler) GetCluster(ctx context.Context) (*apiv1.Cluster, error) {
+	return getClusterFromInstance(ctx, r.Client, r.instance)
+}
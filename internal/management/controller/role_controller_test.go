@@ -0,0 +1,304 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package controller
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	schemeBuilder "github.com/cloudnative-pg/cloudnative-pg/internal/scheme"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+const roleListQuery = `SELECT rolname, rolsuper, rolinherit, rolcreaterole, rolcreatedb,
+		rolcanlogin, rolreplication, rolconnlimit, rolpassword, rolvaliduntil, rolbypassrls,
+		pg_catalog.shobj_description(auth.oid, 'pg_authid') as comment, auth.xmin,
+		mem.inroles
+	FROM pg_catalog.pg_authid as auth
+	LEFT JOIN (
+		SELECT pg_catalog.array_agg(pg_catalog.pg_get_userbyid(roleid)) as inroles, member
+		FROM pg_catalog.pg_auth_members GROUP BY member
+	) mem ON member = oid
+	WHERE rolname not like 'pg\_%'`
+
+var roleListColumns = []string{
+	"rolname", "rolsuper", "rolinherit", "rolcreaterole", "rolcreatedb",
+	"rolcanlogin", "rolreplication", "rolconnlimit", "rolpassword", "rolvaliduntil", "rolbypassrls", "comment",
+	"xmin", "inroles",
+}
+
+const roleCreateStatement = `CREATE ROLE "dante" NOBYPASSRLS NOCREATEDB NOCREATEROLE INHERIT ` +
+	`LOGIN NOREPLICATION NOSUPERUSER CONNECTION LIMIT -1`
+
+var _ = Describe("Managed role controller tests", func() {
+	var (
+		dbMock     sqlmock.Sqlmock
+		db         *sql.DB
+		role       *apiv1.Role
+		cluster    *apiv1.Cluster
+		r          *RoleReconciler
+		fakeClient client.Client
+		err        error
+	)
+
+	BeforeEach(func() {
+		cluster = &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "cluster-example",
+				Namespace: "default",
+			},
+			Status: apiv1.ClusterStatus{
+				CurrentPrimary: "cluster-example-1",
+				TargetPrimary:  "cluster-example-1",
+			},
+		}
+		role = &apiv1.Role{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:       "role-dante",
+				Namespace:  "default",
+				Generation: 1,
+			},
+			Spec: apiv1.RoleSpec{
+				ClusterRef: corev1.LocalObjectReference{
+					Name: cluster.Name,
+				},
+				RoleConfiguration: apiv1.RoleConfiguration{
+					Name:            "dante",
+					Ensure:          apiv1.EnsurePresent,
+					Login:           true,
+					ConnectionLimit: -1,
+				},
+				ReclaimPolicy: apiv1.RoleReclaimDelete,
+			},
+		}
+		db, dbMock, err = sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		Expect(err).ToNot(HaveOccurred())
+
+		pgInstance := postgres.NewInstance().
+			WithNamespace("default").
+			WithPodName("cluster-example-1").
+			WithClusterName("cluster-example")
+
+		fakeClient = fake.NewClientBuilder().WithScheme(schemeBuilder.BuildWithAllKnownScheme()).
+			WithObjects(cluster, role).
+			WithStatusSubresource(&apiv1.Cluster{}, &apiv1.Role{}).
+			Build()
+
+		r = &RoleReconciler{
+			Client:   fakeClient,
+			Scheme:   schemeBuilder.BuildWithAllKnownScheme(),
+			instance: pgInstance,
+			getSuperUserDB: func() (*sql.DB, error) {
+				return db, nil
+			},
+		}
+		r.finalizerReconciler = newFinalizerReconciler(
+			fakeClient,
+			utils.RoleFinalizerName,
+			r.evaluateDropRole,
+		)
+	})
+
+	AfterEach(func() {
+		Expect(dbMock.ExpectationsWereMet()).To(Succeed())
+	})
+
+	expectRoleCreation := func() {
+		dbMock.ExpectQuery(roleListQuery).WillReturnRows(sqlmock.NewRows(roleListColumns))
+		dbMock.ExpectExec(roleCreateStatement).WillReturnResult(sqlmock.NewResult(0, 1))
+		dbMock.ExpectQuery("SELECT xmin FROM pg_catalog.pg_authid WHERE rolname = $1").
+			WithArgs("dante").
+			WillReturnRows(sqlmock.NewRows([]string{"xmin"}).AddRow("42"))
+	}
+
+	It("adds finalizer and sets status ready on success", func(ctx SpecContext) {
+		expectRoleCreation()
+
+		err := reconcileRole(ctx, fakeClient, r, role)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(role.Status.Applied).Should(HaveValue(BeTrue()))
+		Expect(role.GetStatusMessage()).Should(BeEmpty())
+		Expect(role.Status.PasswordState.TransactionID).To(BeEquivalentTo(42))
+		Expect(role.GetFinalizers()).NotTo(BeEmpty())
+	})
+
+	It("role object inherits error after patching", func(ctx SpecContext) {
+		expectedError := fmt.Errorf("no permission")
+		dbMock.ExpectQuery(roleListQuery).WillReturnRows(sqlmock.NewRows(roleListColumns))
+		dbMock.ExpectExec(roleCreateStatement).WillReturnError(expectedError)
+
+		err := reconcileRole(ctx, fakeClient, r, role)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(role.Status.Applied).Should(HaveValue(BeFalse()))
+		Expect(role.Status.Message).Should(ContainSubstring(expectedError.Error()))
+	})
+
+	When("reclaim policy is delete", func() {
+		It("on deletion it removes finalizers and drops the role", func(ctx SpecContext) {
+			expectRoleCreation()
+
+			err := reconcileRole(ctx, fakeClient, r, role)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(role.GetFinalizers()).NotTo(BeEmpty())
+			Expect(role.Status.Applied).Should(HaveValue(BeTrue()))
+
+			// Make sure the next reconciler call doesn't skip on account
+			// of Generation == ObservedGeneration.
+			role.SetGeneration(role.GetGeneration() + 1)
+			Expect(fakeClient.Update(ctx, role)).To(Succeed())
+
+			dbMock.ExpectQuery(roleListQuery).WillReturnRows(
+				sqlmock.NewRows(roleListColumns).
+					AddRow("dante", false, true, false, false, true, false, -1, []byte("12345"),
+						nil, false, nil, 42, []byte("{}")))
+			dbMock.ExpectExec(`DROP ROLE "dante"`).WillReturnResult(sqlmock.NewResult(0, 1))
+
+			Expect(fakeClient.Delete(ctx, role)).To(Succeed())
+
+			err = reconcileRole(ctx, fakeClient, r, role)
+			Expect(err).To(HaveOccurred())
+			Expect(apierrors.IsNotFound(err)).To(BeTrue())
+		})
+	})
+
+	When("reclaim policy is retain", func() {
+		It("on deletion it removes finalizers and does NOT drop the role", func(ctx SpecContext) {
+			role.Spec.ReclaimPolicy = apiv1.RoleReclaimRetain
+			Expect(fakeClient.Update(ctx, role)).To(Succeed())
+
+			expectRoleCreation()
+
+			err := reconcileRole(ctx, fakeClient, r, role)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(role.GetFinalizers()).NotTo(BeEmpty())
+
+			role.SetGeneration(role.GetGeneration() + 1)
+			Expect(fakeClient.Update(ctx, role)).To(Succeed())
+			Expect(fakeClient.Delete(ctx, role)).To(Succeed())
+
+			err = reconcileRole(ctx, fakeClient, r, role)
+			Expect(err).To(HaveOccurred())
+			Expect(apierrors.IsNotFound(err)).To(BeTrue())
+		})
+	})
+
+	It("marks as failed if the role is declared in the cluster too", func(ctx SpecContext) {
+		initialCluster := cluster.DeepCopy()
+		cluster.Spec.Managed = &apiv1.ManagedConfiguration{
+			Roles: []apiv1.RoleConfiguration{{Name: "dante"}},
+		}
+		Expect(fakeClient.Patch(ctx, cluster, client.MergeFrom(initialCluster))).To(Succeed())
+
+		err := reconcileRole(ctx, fakeClient, r, role)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(role.Status.Applied).Should(HaveValue(BeFalse()))
+		Expect(role.Status.Message).Should(ContainSubstring("spec.managed.roles"))
+	})
+
+	It("marks as failed if the target role is already being managed", func(ctx SpecContext) {
+		role.Status.ObservedGeneration = 2
+		Expect(fakeClient.Status().Update(ctx, role)).To(Succeed())
+
+		roleDuplicate := &apiv1.Role{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:       "role-duplicate",
+				Namespace:  "default",
+				Generation: 1,
+			},
+			Spec: apiv1.RoleSpec{
+				ClusterRef: corev1.LocalObjectReference{
+					Name: cluster.Name,
+				},
+				RoleConfiguration: apiv1.RoleConfiguration{Name: "dante"},
+			},
+		}
+		Expect(fakeClient.Create(ctx, roleDuplicate)).To(Succeed())
+
+		err := reconcileRole(ctx, fakeClient, r, roleDuplicate)
+		Expect(err).ToNot(HaveOccurred())
+
+		expectedError := fmt.Sprintf("%q is already managed by object %q",
+			roleDuplicate.Spec.Name, role.Name)
+		Expect(roleDuplicate.Status.Applied).To(HaveValue(BeFalse()))
+		Expect(roleDuplicate.Status.Message).To(ContainSubstring(expectedError))
+	})
+
+	It("refuses to manage a role reserved by the operator", func(ctx SpecContext) {
+		role.Spec.Name = "streaming_replica"
+		Expect(fakeClient.Update(ctx, role)).To(Succeed())
+
+		err := reconcileRole(ctx, fakeClient, r, role)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(role.Status.Applied).Should(HaveValue(BeFalse()))
+		Expect(role.Status.Message).Should(ContainSubstring("reserved"))
+	})
+
+	It("properly signals a role is on a replica cluster", func(ctx SpecContext) {
+		initialCluster := cluster.DeepCopy()
+		cluster.Spec.ReplicaCluster = &apiv1.ReplicaClusterConfiguration{
+			Enabled: ptr.To(true),
+		}
+		Expect(fakeClient.Patch(ctx, cluster, client.MergeFrom(initialCluster))).To(Succeed())
+
+		err := reconcileRole(ctx, fakeClient, r, role)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(role.Status.Applied).Should(BeNil())
+		Expect(role.Status.Message).Should(ContainSubstring("waiting for the cluster to become primary"))
+	})
+})
+
+func reconcileRole(
+	ctx context.Context,
+	fakeClient client.Client,
+	r *RoleReconciler,
+	role *apiv1.Role,
+) error {
+	GinkgoT().Helper()
+	_, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{
+		Namespace: role.GetNamespace(),
+		Name:      role.GetName(),
+	}})
+	Expect(err).ToNot(HaveOccurred())
+	return fakeClient.Get(ctx, client.ObjectKey{
+		Namespace: role.GetNamespace(),
+		Name:      role.GetName(),
+	}, role)
+}
@@ -0,0 +1,114 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package roles
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+)
+
+// ErrReservedRole is raised when a Role resource is targeting
+// one of the roles reserved by the operator
+var ErrReservedRole = errors.New("cannot manage a role reserved by the operator")
+
+// ApplyRole aligns a single role in the database to the passed configuration,
+// getting the password from the referenced Secret if so set.
+// This is used to reconcile the Role resources, sharing the logic applied
+// to the roles declared in the `spec.managed.roles` stanza of the Cluster.
+//
+// It returns the PasswordState of the role after the reconciliation, or an
+// error if the role could not be reconciled
+func ApplyRole(
+	ctx context.Context,
+	cli client.Client,
+	namespace string,
+	db *sql.DB,
+	role apiv1.RoleConfiguration,
+	lastPasswordState apiv1.PasswordState,
+) (apiv1.PasswordState, error) {
+	if postgres.IsRoleReserved(role.Name) {
+		return apiv1.PasswordState{}, fmt.Errorf("%w: %s", ErrReservedRole, role.Name)
+	}
+
+	config := &apiv1.ManagedConfiguration{Roles: []apiv1.RoleConfiguration{role}}
+	latestSecretResourceVersion, err := getPasswordSecretResourceVersion(ctx, cli, config.Roles, namespace)
+	if err != nil {
+		return apiv1.PasswordState{}, err
+	}
+
+	rolesInDB, err := List(ctx, db)
+	if err != nil {
+		return apiv1.PasswordState{}, err
+	}
+
+	rolesByAction := evaluateNextRoleActions(
+		ctx,
+		config,
+		rolesInDB,
+		map[string]apiv1.PasswordState{role.Name: lastPasswordState},
+		latestSecretResourceVersion,
+	)
+
+	passwordStates, irreconcilableRoles, err := applyRoleActions(ctx, cli, namespace, db, rolesByAction)
+	if err != nil {
+		return apiv1.PasswordState{}, err
+	}
+
+	if roleErrors := irreconcilableRoles[role.Name]; len(roleErrors) > 0 {
+		return apiv1.PasswordState{}, errors.New(strings.Join(roleErrors, "; "))
+	}
+
+	if role.Ensure == apiv1.EnsureAbsent {
+		return apiv1.PasswordState{}, nil
+	}
+
+	if passwordState, ok := passwordStates[role.Name]; ok {
+		return passwordState, nil
+	}
+
+	return lastPasswordState, nil
+}
+
+// DropRole drops the role with the passed name from the database, if it exists
+func DropRole(
+	ctx context.Context,
+	cli client.Client,
+	namespace string,
+	db *sql.DB,
+	name string,
+) error {
+	_, err := ApplyRole(
+		ctx,
+		cli,
+		namespace,
+		db,
+		apiv1.RoleConfiguration{Name: name, Ensure: apiv1.EnsureAbsent},
+		apiv1.PasswordState{},
+	)
+	return err
+}
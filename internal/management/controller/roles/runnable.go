@@ -206,7 +206,8 @@ func (sr *RoleSynchronizer) synchronizeRoles(
 	rolesByAction := evaluateNextRoleActions(
 		ctx, config, rolesInDB, storedPasswordState, latestSecretResourceVersion)
 
-	passwordStates, irreconcilableRoles, err := sr.applyRoleActions(ctx, db, rolesByAction)
+	passwordStates, irreconcilableRoles, err := applyRoleActions(
+		ctx, sr.client, sr.instance.GetNamespaceName(), db, rolesByAction)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -227,8 +228,10 @@ func (sr *RoleSynchronizer) synchronizeRoles(
 // due to an invalid request for postgres. This is so that other actions will not
 // be blocked by a user error.
 // It will, however, error out on unexpected errors.
-func (sr *RoleSynchronizer) applyRoleActions(
+func applyRoleActions(
 	ctx context.Context,
+	cli client.Client,
+	namespace string,
 	db *sql.DB,
 	rolesByAction rolesByAction,
 ) (map[string]apiv1.PasswordState, map[string][]string, error) {
@@ -255,7 +258,7 @@ func (sr *RoleSynchronizer) applyRoleActions(
 	actionsCreateUpdate := []roleAction{roleCreate, roleUpdate}
 	for _, action := range actionsCreateUpdate {
 		for _, role := range rolesByAction[action] {
-			appliedState, err := sr.applyRoleCreateUpdate(ctx, db, role, action)
+			appliedState, err := applyRoleCreateUpdate(ctx, cli, namespace, db, role, action)
 			if err == nil {
 				appliedChanges[role.Name] = appliedState
 			}
@@ -315,8 +318,10 @@ func getRoleMembershipDiff(
 // applyRoleCreateUpdate creates/updates a role, getting the password from Kubernetes
 // secrets if so set.
 // Returns the PasswordState, as well as any error encountered
-func (sr *RoleSynchronizer) applyRoleCreateUpdate(
+func applyRoleCreateUpdate(
 	ctx context.Context,
+	cli client.Client,
+	namespace string,
 	db *sql.DB,
 	role roleConfigurationAdapter,
 	action roleAction,
@@ -335,7 +340,7 @@ func (sr *RoleSynchronizer) applyRoleCreateUpdate(
 			fmt.Errorf("cannot reconcile: password both provided and disabled: %s",
 				role.PasswordSecret.Name)
 	case role.PasswordSecret != nil && !role.DisablePassword:
-		passwordSecret, err := getPassword(ctx, sr.client, role, sr.instance.GetNamespaceName())
+		passwordSecret, err := getPassword(ctx, cli, role, namespace)
 		if err != nil {
 			return apiv1.PasswordState{}, err
 		}
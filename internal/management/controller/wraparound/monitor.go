@@ -0,0 +1,324 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package wraparound
+
+import (
+	"cmp"
+	"context"
+	"database/sql"
+	"fmt"
+	"reflect"
+	"slices"
+	"sync/atomic"
+	"time"
+
+	"github.com/cloudnative-pg/machinery/pkg/log"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
+	pgpostgres "github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+)
+
+// Monitor periodically computes the age of the databases and, on the
+// primary instance, freezes the relations at risk of wraparound
+type Monitor struct {
+	instance *postgres.Instance
+
+	// freezing is true while the relations are being frozen
+	freezing atomic.Bool
+}
+
+// NewMonitor creates a new wraparound monitor
+func NewMonitor(instance *postgres.Instance) *Monitor {
+	return &Monitor{
+		instance: instance,
+	}
+}
+
+// databaseAge is the age of a database, together with the information
+// needed to connect to it
+type databaseAge struct {
+	pgpostgres.DatabaseAge
+	allowConnections bool
+}
+
+// Start starts running the wraparound monitor
+func (m *Monitor) Start(ctx context.Context) error {
+	contextLog := log.FromContext(ctx).WithName("WraparoundMonitor")
+	go func() {
+		var config *apiv1.WraparoundProtectionConfiguration
+		select {
+		case config = <-m.instance.WraparoundMonitorChan():
+		case <-ctx.Done():
+			return
+		}
+
+		checkInterval := config.GetCheckInterval()
+		ticker := time.NewTicker(checkInterval)
+
+		defer func() {
+			ticker.Stop()
+			contextLog.Info("Terminated wraparound monitor loop")
+		}()
+
+		for {
+			if err := m.check(ctx, config); err != nil {
+				contextLog.Warning("checking the age of the databases", "err", err)
+			}
+
+		wait:
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case newConfig := <-m.instance.WraparoundMonitorChan():
+					// The configuration is sent at every reconciliation of
+					// the instance, let's check again only when it changes
+					if reflect.DeepEqual(config, newConfig) {
+						continue
+					}
+					config = newConfig
+					break wait
+				case <-ticker.C:
+					break wait
+				}
+			}
+
+			// Update the ticker if the check interval has changed
+			if newCheckInterval := config.GetCheckInterval(); checkInterval != newCheckInterval {
+				ticker.Reset(newCheckInterval)
+				checkInterval = newCheckInterval
+			}
+		}
+	}()
+	<-ctx.Done()
+	return nil
+}
+
+func (m *Monitor) check(ctx context.Context, config *apiv1.WraparoundProtectionConfiguration) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("recovered from a panic: %s", r)
+		}
+	}()
+
+	contextLog := log.FromContext(ctx).WithName("WraparoundMonitor")
+
+	if m.instance.IsFenced() {
+		contextLog.Trace("Wraparound check skipped: instance is fenced.")
+		return nil
+	}
+
+	db, err := m.instance.GetSuperUserDB()
+	if err != nil {
+		return err
+	}
+
+	ages, err := getDatabaseAges(ctx, db)
+	if err != nil {
+		return err
+	}
+
+	reportedAges := make([]pgpostgres.DatabaseAge, len(ages))
+	for i := range ages {
+		reportedAges[i] = ages[i].DatabaseAge
+	}
+	m.instance.SetDatabaseAges(reportedAges)
+
+	if !config.IsFreezeEnabled() {
+		return nil
+	}
+
+	// VACUUM can only run on the primary instance, the replicas
+	// receive the frozen tuples through the WALs
+	isPrimary, err := m.instance.IsPrimary()
+	if err != nil {
+		return err
+	}
+	if !isPrimary {
+		contextLog.Trace("Wraparound freeze skipped: instance is a replica.")
+		return nil
+	}
+
+	// The freeze can take a long time, and runs separately from the
+	// monitoring loop so that the ages keep being computed in the meantime
+	if !m.freezing.CompareAndSwap(false, true) {
+		contextLog.Info("Wraparound freeze skipped: the previous one is still running.")
+		return nil
+	}
+	go func() {
+		defer m.freezing.Store(false)
+		if err := m.freeze(ctx, config, ages); err != nil {
+			contextLog.Warning("freezing the relations at risk of wraparound", "err", err)
+		}
+	}()
+
+	return nil
+}
+
+// freeze runs VACUUM (FREEZE) on the oldest relations of the
+// databases whose age crossed the thresholds
+func (m *Monitor) freeze(
+	ctx context.Context,
+	config *apiv1.WraparoundProtectionConfiguration,
+	ages []databaseAge,
+) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("recovered from a panic: %s", r)
+		}
+	}()
+
+	remaining := config.GetFreezeMaxRelations()
+	for _, age := range databasesToFreeze(ages, config) {
+		if remaining <= 0 {
+			break
+		}
+
+		databaseDB, err := m.instance.ConnectionPool().Connection(age.Name)
+		if err != nil {
+			return fmt.Errorf("while connecting to database %s: %w", age.Name, err)
+		}
+
+		frozen, err := freezeOldestRelations(ctx, databaseDB, config, remaining)
+		if err != nil {
+			return fmt.Errorf("while freezing the relations of database %s: %w", age.Name, err)
+		}
+		remaining -= frozen
+	}
+
+	return nil
+}
+
+// getDatabaseAges computes the transaction ID and multixact ID age of the
+// databases
+func getDatabaseAges(ctx context.Context, db *sql.DB) ([]databaseAge, error) {
+	rows, err := db.QueryContext(ctx,
+		`SELECT datname, pg_catalog.age(datfrozenxid), pg_catalog.mxid_age(datminmxid), datallowconn
+		FROM pg_catalog.pg_database
+		ORDER BY datname`)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	var ages []databaseAge
+	for rows.Next() {
+		var age databaseAge
+		if err := rows.Scan(&age.Name, &age.XIDAge, &age.MultiXactIDAge, &age.allowConnections); err != nil {
+			return nil, err
+		}
+		ages = append(ages, age)
+	}
+
+	return ages, rows.Err()
+}
+
+// databasesToFreeze returns the databases accepting connections whose age
+// crossed the thresholds, starting from the oldest one
+func databasesToFreeze(ages []databaseAge, config *apiv1.WraparoundProtectionConfiguration) []databaseAge {
+	var result []databaseAge
+	for _, age := range ages {
+		if age.allowConnections && config.IsAboveThreshold(apiv1.DatabaseAge{
+			Name:           age.Name,
+			XIDAge:         age.XIDAge,
+			MultiXactIDAge: age.MultiXactIDAge,
+		}) {
+			result = append(result, age)
+		}
+	}
+
+	slices.SortStableFunc(result, func(a, b databaseAge) int {
+		return cmp.Compare(max(b.XIDAge, b.MultiXactIDAge), max(a.XIDAge, a.MultiXactIDAge))
+	})
+	return result
+}
+
+// freezeOldestRelations runs VACUUM (FREEZE) on up to limit relations of
+// the database whose age crossed the thresholds, starting from the oldest
+// one, and returns the number of frozen relations. The freeze of a
+// relation is cancelled when it takes longer than the configured timeout
+func freezeOldestRelations(
+	ctx context.Context,
+	db *sql.DB,
+	config *apiv1.WraparoundProtectionConfiguration,
+	limit int,
+) (int, error) {
+	contextLog := log.FromContext(ctx).WithName("WraparoundMonitor")
+
+	rows, err := db.QueryContext(ctx,
+		`SELECT pg_catalog.quote_ident(n.nspname) || '.' || pg_catalog.quote_ident(c.relname)
+		FROM pg_catalog.pg_class c
+		JOIN pg_catalog.pg_namespace n ON n.oid = c.relnamespace
+		WHERE c.relkind IN ('r', 'm', 't') AND c.relpersistence <> 't'
+		  AND (pg_catalog.age(c.relfrozenxid) > $1 OR pg_catalog.mxid_age(c.relminmxid) > $2)
+		ORDER BY GREATEST(pg_catalog.age(c.relfrozenxid), pg_catalog.mxid_age(c.relminmxid)) DESC
+		LIMIT $3`,
+		config.GetXIDAgeThreshold(), config.GetMultiXactIDAgeThreshold(), limit)
+	if err != nil {
+		return 0, err
+	}
+
+	var relations []string
+	for rows.Next() {
+		var relation string
+		if err := rows.Scan(&relation); err != nil {
+			_ = rows.Close()
+			return 0, err
+		}
+		relations = append(relations, relation)
+	}
+	if err := rows.Close(); err != nil {
+		return 0, err
+	}
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	frozen := 0
+	for _, relation := range relations {
+		if ctx.Err() != nil {
+			return frozen, ctx.Err()
+		}
+
+		contextLog.Info("Freezing relation at risk of wraparound", "relation", relation)
+		if err := freezeRelation(ctx, db, relation, config.GetFreezeTimeout()); err != nil {
+			// The relation may have been dropped in the meantime, let's
+			// continue with the other ones
+			contextLog.Warning("unable to freeze relation", "relation", relation, "err", err)
+			continue
+		}
+		frozen++
+	}
+
+	return frozen, nil
+}
+
+// freezeRelation runs VACUUM (FREEZE) on the passed relation, cancelling
+// it when it doesn't complete within the timeout
+func freezeRelation(ctx context.Context, db *sql.DB, relation string, timeout time.Duration) error {
+	freezeCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	_, err := db.ExecContext(freezeCtx, "VACUUM (FREEZE) "+relation)
+	return err
+}
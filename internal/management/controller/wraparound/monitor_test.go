@@ -0,0 +1,110 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package wraparound
+
+import (
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"k8s.io/utils/ptr"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	pgpostgres "github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("wraparound monitor", func() {
+	config := &apiv1.WraparoundProtectionConfiguration{
+		XIDAgeThreshold:         ptr.To(int64(200000000)),
+		MultiXactIDAgeThreshold: ptr.To(int64(400000000)),
+		Freeze:                  &apiv1.WraparoundFreezeConfiguration{Enabled: true},
+	}
+
+	It("computes the age of the databases", func(ctx SpecContext) {
+		db, mock, err := sqlmock.New()
+		Expect(err).ToNot(HaveOccurred())
+
+		mock.ExpectQuery("FROM pg_catalog.pg_database").WillReturnRows(
+			sqlmock.NewRows([]string{"datname", "age", "mxid_age", "datallowconn"}).
+				AddRow("app", 250000000, 12, true).
+				AddRow("template0", 300000000, 1, false),
+		)
+
+		ages, err := getDatabaseAges(ctx, db)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(ages).To(Equal([]databaseAge{
+			{
+				DatabaseAge:      pgpostgres.DatabaseAge{Name: "app", XIDAge: 250000000, MultiXactIDAge: 12},
+				allowConnections: true,
+			},
+			{
+				DatabaseAge: pgpostgres.DatabaseAge{Name: "template0", XIDAge: 300000000, MultiXactIDAge: 1},
+			},
+		}))
+		Expect(mock.ExpectationsWereMet()).To(Succeed())
+	})
+
+	It("selects the databases to freeze, starting from the oldest one", func() {
+		ages := []databaseAge{
+			{DatabaseAge: pgpostgres.DatabaseAge{Name: "app", XIDAge: 250000000}, allowConnections: true},
+			{DatabaseAge: pgpostgres.DatabaseAge{Name: "postgres", XIDAge: 1000}, allowConnections: true},
+			{DatabaseAge: pgpostgres.DatabaseAge{Name: "template0", XIDAge: 900000000}},
+			{DatabaseAge: pgpostgres.DatabaseAge{Name: "queue", MultiXactIDAge: 500000000}, allowConnections: true},
+		}
+
+		var names []string
+		for _, age := range databasesToFreeze(ages, config) {
+			names = append(names, age.Name)
+		}
+		Expect(names).To(Equal([]string{"queue", "app"}))
+	})
+
+	It("freezes the oldest relations", func(ctx SpecContext) {
+		db, mock, err := sqlmock.New()
+		Expect(err).ToNot(HaveOccurred())
+
+		mock.ExpectQuery("FROM pg_catalog.pg_class").
+			WithArgs(int64(200000000), int64(400000000), 3).
+			WillReturnRows(sqlmock.NewRows([]string{"relation"}).
+				AddRow("public.orders").
+				AddRow("public.dropped"))
+		mock.ExpectExec(`VACUUM \(FREEZE\) public.orders`).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(`VACUUM \(FREEZE\) public.dropped`).WillReturnError(sqlmock.ErrCancelled)
+
+		frozen, err := freezeOldestRelations(ctx, db, config, 3)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(frozen).To(Equal(1))
+		Expect(mock.ExpectationsWereMet()).To(Succeed())
+	})
+
+	It("cancels the freeze of a relation taking longer than the timeout", func(ctx SpecContext) {
+		db, mock, err := sqlmock.New()
+		Expect(err).ToNot(HaveOccurred())
+
+		mock.ExpectExec(`VACUUM \(FREEZE\) public.orders`).
+			WillDelayFor(time.Second).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+
+		err = freezeRelation(ctx, db, "public.orders", 10*time.Millisecond)
+		Expect(err).To(MatchError(sqlmock.ErrCancelled))
+	})
+})
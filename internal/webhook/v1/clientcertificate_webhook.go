@@ -0,0 +1,122 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package v1
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cloudnative-pg/machinery/pkg/log"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+)
+
+// clientCertificateLog is for logging in this package.
+var clientCertificateLog = log.WithName("clientcertificate-resource").WithValues("version", "v1")
+
+// SetupClientCertificateWebhookWithManager registers the webhook for ClientCertificate in the manager.
+func SetupClientCertificateWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).For(&apiv1.ClientCertificate{}).
+		WithValidator(newBypassableValidator(&ClientCertificateCustomValidator{})).
+		Complete()
+}
+
+// NOTE: The 'path' attribute must follow a specific pattern and should not be modified directly here.
+// Modifying the path for an invalid path can cause API server errors; failing to locate the webhook.
+// +kubebuilder:webhook:webhookVersions={v1},admissionReviewVersions={v1},verbs=create;update,path=/validate-postgresql-cnpg-io-v1-clientcertificate,mutating=false,failurePolicy=fail,groups=postgresql.cnpg.io,resources=clientcertificates,versions=v1,name=vclientcertificate.cnpg.io,sideEffects=None
+
+// ClientCertificateCustomValidator is responsible for validating the
+// ClientCertificate resource when it is created, updated, or deleted.
+type ClientCertificateCustomValidator struct{}
+
+var _ webhook.CustomValidator = &ClientCertificateCustomValidator{}
+
+// ValidateCreate implements webhook.CustomValidator so a webhook will be registered for the type ClientCertificate.
+func (v *ClientCertificateCustomValidator) ValidateCreate(
+	_ context.Context,
+	obj runtime.Object,
+) (admission.Warnings, error) {
+	clientCertificate, ok := obj.(*apiv1.ClientCertificate)
+	if !ok {
+		return nil, fmt.Errorf("expected a ClientCertificate object but got %T", obj)
+	}
+	clientCertificateLog.Info("Validation for ClientCertificate upon creation",
+		"name", clientCertificate.GetName(), "namespace", clientCertificate.GetNamespace())
+
+	return nil, v.toInvalidError(clientCertificate, v.validate(clientCertificate))
+}
+
+// ValidateUpdate implements webhook.CustomValidator so a webhook will be registered for the type ClientCertificate.
+func (v *ClientCertificateCustomValidator) ValidateUpdate(
+	_ context.Context,
+	_, newObj runtime.Object,
+) (admission.Warnings, error) {
+	clientCertificate, ok := newObj.(*apiv1.ClientCertificate)
+	if !ok {
+		return nil, fmt.Errorf("expected a ClientCertificate object for the newObj but got %T", newObj)
+	}
+	clientCertificateLog.Info("Validation for ClientCertificate upon update",
+		"name", clientCertificate.GetName(), "namespace", clientCertificate.GetNamespace())
+
+	return nil, v.toInvalidError(clientCertificate, v.validate(clientCertificate))
+}
+
+// ValidateDelete implements webhook.CustomValidator so a webhook will be registered for the type ClientCertificate.
+func (v *ClientCertificateCustomValidator) ValidateDelete(
+	_ context.Context,
+	_ runtime.Object,
+) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// validate groups the validation logic for client certificates
+func (v *ClientCertificateCustomValidator) validate(clientCertificate *apiv1.ClientCertificate) field.ErrorList {
+	var result field.ErrorList
+
+	if postgres.IsRoleReserved(clientCertificate.Spec.Role) {
+		result = append(result, field.Invalid(
+			field.NewPath("spec", "role"),
+			clientCertificate.Spec.Role,
+			"certificates can't be issued for the roles reserved to PostgreSQL and to the operator"))
+	}
+
+	return result
+}
+
+func (v *ClientCertificateCustomValidator) toInvalidError(
+	clientCertificate *apiv1.ClientCertificate,
+	allErrs field.ErrorList,
+) error {
+	if len(allErrs) == 0 {
+		return nil
+	}
+
+	return apierrors.NewInvalid(
+		schema.GroupKind{Group: "postgresql.cnpg.io", Kind: "ClientCertificate"},
+		clientCertificate.Name, allErrs)
+}
@@ -0,0 +1,60 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ClientCertificate validation", func() {
+	var v *ClientCertificateCustomValidator
+
+	newClientCertificate := func(role string) *apiv1.ClientCertificate {
+		return &apiv1.ClientCertificate{
+			ObjectMeta: metav1.ObjectMeta{Name: "certificate"},
+			Spec:       apiv1.ClientCertificateSpec{Role: role},
+		}
+	}
+
+	BeforeEach(func() {
+		v = &ClientCertificateCustomValidator{}
+	})
+
+	It("accepts a regular role", func(ctx SpecContext) {
+		_, err := v.ValidateCreate(ctx, newClientCertificate("alice"))
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	DescribeTable("rejects the reserved roles",
+		func(ctx SpecContext, role string) {
+			_, err := v.ValidateCreate(ctx, newClientCertificate(role))
+			Expect(err).To(MatchError(ContainSubstring("spec.role")))
+		},
+		Entry("the superuser", "postgres"),
+		Entry("the streaming replication user", apiv1.StreamingReplicationUser),
+		Entry("the PostgreSQL roles", "pg_monitor"),
+		Entry("the operator roles", "cnpg_pooler_pgbouncer"),
+	)
+})
@@ -26,6 +26,7 @@ import (
 	"slices"
 	"strconv"
 	"strings"
+	"time"
 
 	barmanWebhooks "github.com/cloudnative-pg/barman-cloud/pkg/api/webhooks"
 	"github.com/cloudnative-pg/machinery/pkg/image/reference"
@@ -35,6 +36,7 @@ import (
 	"github.com/cloudnative-pg/machinery/pkg/types"
 	jsonpatch "github.com/evanphx/json-patch/v5"
 	volumesnapshotv1 "github.com/kubernetes-csi/external-snapshotter/client/v8/apis/volumesnapshot/v1"
+	"github.com/robfig/cron"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
@@ -193,6 +195,8 @@ func (v *ClusterCustomValidator) validate(r *apiv1.Cluster) (allErrs field.Error
 		v.validateWalStorageSize,
 		v.validateEphemeralVolumeSource,
 		v.validateTablespaceStorageSize,
+		v.validateStorageAutoResize,
+		v.validateDiskFullProtection,
 		v.validateName,
 		v.validateTablespaceNames,
 		v.validateBootstrapPgBaseBackupSource,
@@ -205,6 +209,8 @@ func (v *ClusterCustomValidator) validate(r *apiv1.Cluster) (allErrs field.Error
 		v.validateReplicaMode,
 		v.validateBackupConfiguration,
 		v.validateRetentionPolicy,
+		v.validateMaintenanceWindow,
+		v.validateBackupVerification,
 		v.validateConfiguration,
 		v.validateSynchronousReplicaConfiguration,
 		v.validateFailoverQuorumAlphaAnnotation,
@@ -571,6 +577,28 @@ func (v *ClusterCustomValidator) validateCerts(r *apiv1.Cluster) field.ErrorList
 		return result
 	}
 
+	if certificates.IssuerRef != nil {
+		secrets := []struct {
+			name  string
+			value string
+		}{
+			{name: "serverCASecret", value: certificates.ServerCASecret},
+			{name: "serverTLSSecret", value: certificates.ServerTLSSecret},
+			{name: "clientCASecret", value: certificates.ClientCASecret},
+			{name: "replicationTLSSecret", value: certificates.ReplicationTLSSecret},
+		}
+		for _, secret := range secrets {
+			if secret.value != "" {
+				result = append(
+					result,
+					field.Invalid(
+						field.NewPath("spec", "certificates", secret.name),
+						secret.value,
+						"Secrets can't be provided when the certificates are issued by cert-manager"))
+			}
+		}
+	}
+
 	if certificates.ServerTLSSecret != "" {
 		// Currently names are not validated, maybe add this check in future
 		if len(certificates.ServerAltDNSNames) != 0 {
@@ -1661,6 +1689,91 @@ func validateStorageConfigurationSize(
 	return result
 }
 
+// validateStorageAutoResize validates the automatic expansion policies of
+// the storage configurations
+func (v *ClusterCustomValidator) validateStorageAutoResize(r *apiv1.Cluster) field.ErrorList {
+	result := validateStorageConfigurationAutoResize(
+		r, field.NewPath("spec", "storage"), r.Spec.StorageConfiguration)
+
+	if r.ShouldCreateWalArchiveVolume() {
+		result = append(result, validateStorageConfigurationAutoResize(
+			r, field.NewPath("spec", "walStorage"), *r.Spec.WalStorage)...)
+	}
+
+	for idx, tablespaceConf := range r.Spec.Tablespaces {
+		result = append(result, validateStorageConfigurationAutoResize(
+			r, field.NewPath("spec", "tablespaces").Index(idx).Child("storage"), tablespaceConf.Storage)...)
+	}
+
+	return result
+}
+
+func validateStorageConfigurationAutoResize(
+	r *apiv1.Cluster,
+	structPath *field.Path,
+	storageConfiguration apiv1.StorageConfiguration,
+) field.ErrorList {
+	autoResize := storageConfiguration.AutoResize
+	if autoResize == nil {
+		return nil
+	}
+
+	var result field.ErrorList
+	autoResizePath := structPath.Child("autoResize")
+
+	if autoResize.IsEnabled() && !r.ShouldResizeInUseVolumes() {
+		result = append(result, field.Invalid(
+			autoResizePath.Child("enabled"),
+			autoResize.Enabled,
+			"the automatic expansion of the PVCs requires spec.storage.resizeInUseVolumes to be enabled"))
+	}
+
+	if _, err := autoResize.GetIncrement(resource.MustParse("1Gi")); err != nil {
+		result = append(result, field.Invalid(
+			autoResizePath.Child("increment"),
+			autoResize.Increment,
+			"increment must be either a positive quantity or a positive percentage"))
+	}
+
+	maxSize, err := resource.ParseQuantity(autoResize.MaxSize)
+	if err != nil {
+		result = append(result, field.Invalid(
+			autoResizePath.Child("maxSize"),
+			autoResize.MaxSize,
+			"maxSize value isn't valid"))
+		return result
+	}
+
+	if size := storageConfiguration.GetSizeOrNil(); size != nil && maxSize.Cmp(*size) < 0 {
+		result = append(result, field.Invalid(
+			autoResizePath.Child("maxSize"),
+			autoResize.MaxSize,
+			fmt.Sprintf("maxSize can't be lower than the size of the storage (%s)", size.String())))
+	}
+
+	return result
+}
+
+// validateDiskFullProtection validates the thresholds of the disk-full
+// protection
+func (v *ClusterCustomValidator) validateDiskFullProtection(r *apiv1.Cluster) field.ErrorList {
+	protection := r.Spec.DiskFullProtection
+	if protection == nil {
+		return nil
+	}
+
+	if protection.GetRecoveryFreeSpacePercentage() <= protection.GetCriticalFreeSpacePercentage() {
+		return field.ErrorList{
+			field.Invalid(
+				field.NewPath("spec", "diskFullProtection", "recoveryFreeSpacePercentage"),
+				protection.GetRecoveryFreeSpacePercentage(),
+				"recoveryFreeSpacePercentage must be greater than criticalFreeSpacePercentage"),
+		}
+	}
+
+	return nil
+}
+
 // Validate a change in the storage
 func (v *ClusterCustomValidator) validateStorageChange(r, old *apiv1.Cluster) field.ErrorList {
 	return validateStorageConfigurationChange(
@@ -1671,19 +1784,12 @@ func (v *ClusterCustomValidator) validateStorageChange(r, old *apiv1.Cluster) fi
 }
 
 func (v *ClusterCustomValidator) validateWalStorageChange(r, old *apiv1.Cluster) field.ErrorList {
-	if old.Spec.WalStorage == nil {
+	// The WAL storage can be added and removed, the operator will
+	// take care of relocating the WALs of every instance
+	if old.Spec.WalStorage == nil || r.Spec.WalStorage == nil {
 		return nil
 	}
 
-	if old.Spec.WalStorage != nil && r.Spec.WalStorage == nil {
-		return field.ErrorList{
-			field.Invalid(
-				field.NewPath("spec", "walStorage"),
-				r.Spec.WalStorage,
-				"walStorage cannot be disabled once configured"),
-		}
-	}
-
 	return validateStorageConfigurationChange(
 		field.NewPath("spec", "walStorage"),
 		*old.Spec.WalStorage,
@@ -2184,6 +2290,84 @@ func (v *ClusterCustomValidator) validateRetentionPolicy(r *apiv1.Cluster) field
 	)
 }
 
+// validateMaintenanceWindow validates the schedules, the durations and
+// the time zone of the maintenance windows
+func (v *ClusterCustomValidator) validateMaintenanceWindow(r *apiv1.Cluster) field.ErrorList {
+	if r.Spec.MaintenanceWindow == nil {
+		return nil
+	}
+
+	var result field.ErrorList
+	basePath := field.NewPath("spec", "maintenanceWindow")
+
+	if r.Spec.MaintenanceWindow.TimeZone != "" {
+		if _, err := time.LoadLocation(r.Spec.MaintenanceWindow.TimeZone); err != nil {
+			result = append(result, field.Invalid(
+				basePath.Child("timeZone"),
+				r.Spec.MaintenanceWindow.TimeZone,
+				err.Error(),
+			))
+		}
+	}
+
+	for i, window := range r.Spec.MaintenanceWindow.Windows {
+		windowPath := basePath.Child("windows").Index(i)
+		if _, err := cron.Parse(window.Schedule); err != nil {
+			result = append(result, field.Invalid(
+				windowPath.Child("schedule"),
+				window.Schedule,
+				err.Error(),
+			))
+		} else if !strings.HasPrefix(window.Schedule, "@") && len(strings.Fields(window.Schedule)) != 6 {
+			// A five-field crontab schedule would be silently
+			// interpreted with the seconds as first field
+			result = append(result, field.Invalid(
+				windowPath.Child("schedule"),
+				window.Schedule,
+				"the schedule must have six fields, the first one being the seconds",
+			))
+		}
+		if window.Duration.Duration <= 0 {
+			result = append(result, field.Invalid(
+				windowPath.Child("duration"),
+				window.Duration.String(),
+				"the duration of a maintenance window must be positive",
+			))
+		}
+	}
+
+	return result
+}
+
+// validateBackupVerification validates the schedule and the timeout
+// of the verification of the backups
+func (v *ClusterCustomValidator) validateBackupVerification(r *apiv1.Cluster) field.ErrorList {
+	if r.Spec.BackupVerification == nil {
+		return nil
+	}
+
+	var result field.ErrorList
+	basePath := field.NewPath("spec", "backupVerification")
+
+	if _, err := cron.Parse(r.Spec.BackupVerification.Schedule); err != nil {
+		result = append(result, field.Invalid(
+			basePath.Child("schedule"),
+			r.Spec.BackupVerification.Schedule,
+			err.Error(),
+		))
+	}
+
+	if r.Spec.BackupVerification.Timeout != nil && r.Spec.BackupVerification.Timeout.Duration <= 0 {
+		result = append(result, field.Invalid(
+			basePath.Child("timeout"),
+			r.Spec.BackupVerification.Timeout.String(),
+			"the timeout of the backup verification must be positive",
+		))
+	}
+
+	return result
+}
+
 func (v *ClusterCustomValidator) validateReplicationSlots(r *apiv1.Cluster) field.ErrorList {
 	if r.Spec.ReplicationSlots == nil {
 		r.Spec.ReplicationSlots = &apiv1.ReplicationSlotsConfiguration{
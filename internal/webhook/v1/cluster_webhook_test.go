@@ -144,6 +144,33 @@ var _ = Describe("certificates options validation", func() {
 		result := v.validateCerts(cluster)
 		Expect(result).To(HaveLen(1))
 	})
+
+	It("doesn't complain if the certificates are issued by cert-manager", func() {
+		cluster := &apiv1.Cluster{
+			Spec: apiv1.ClusterSpec{
+				Certificates: &apiv1.CertificatesConfiguration{
+					IssuerRef:         &apiv1.CertManagerIssuerReference{Name: "corporate-pki"},
+					ServerAltDNSNames: []string{"dns-name"},
+				},
+			},
+		}
+		result := v.validateCerts(cluster)
+		Expect(result).To(BeEmpty())
+	})
+
+	It("does complain if you specify secrets together with a cert-manager issuer", func() {
+		cluster := &apiv1.Cluster{
+			Spec: apiv1.ClusterSpec{
+				Certificates: &apiv1.CertificatesConfiguration{
+					IssuerRef:       &apiv1.CertManagerIssuerReference{Name: "corporate-pki"},
+					ServerCASecret:  "test-server-ca",
+					ServerTLSSecret: "test-server-tls",
+				},
+			},
+		}
+		result := v.validateCerts(cluster)
+		Expect(result).To(HaveLen(2))
+	})
 })
 
 var _ = Describe("initdb options validation", func() {
@@ -1838,6 +1865,88 @@ var _ = Describe("primary update strategy", func() {
 	})
 })
 
+var _ = Describe("maintenance window validation", func() {
+	var v *ClusterCustomValidator
+	BeforeEach(func() {
+		v = &ClusterCustomValidator{}
+	})
+
+	It("allows clusters without maintenance windows", func() {
+		Expect(v.validateMaintenanceWindow(&apiv1.Cluster{})).To(BeEmpty())
+	})
+
+	It("allows valid maintenance windows", func() {
+		cluster := &apiv1.Cluster{
+			Spec: apiv1.ClusterSpec{
+				MaintenanceWindow: &apiv1.MaintenanceWindowConfiguration{
+					TimeZone: "Europe/Rome",
+					Windows: []apiv1.MaintenanceWindow{
+						{Schedule: "0 0 2 * * 6", Duration: metav1.Duration{Duration: 2 * time.Hour}},
+						{Schedule: "@daily", Duration: metav1.Duration{Duration: 30 * time.Minute}},
+					},
+				},
+			},
+		}
+		Expect(v.validateMaintenanceWindow(cluster)).To(BeEmpty())
+	})
+
+	It("complains about invalid schedules, durations and time zones", func() {
+		cluster := &apiv1.Cluster{
+			Spec: apiv1.ClusterSpec{
+				MaintenanceWindow: &apiv1.MaintenanceWindowConfiguration{
+					TimeZone: "Mars/Olympus_Mons",
+					Windows: []apiv1.MaintenanceWindow{
+						{Schedule: "every saturday", Duration: metav1.Duration{Duration: time.Hour}},
+						{Schedule: "0 0 2 * * 6"},
+						{Schedule: "0 2 * * 6", Duration: metav1.Duration{Duration: time.Hour}},
+					},
+				},
+			},
+		}
+		errs := v.validateMaintenanceWindow(cluster)
+		Expect(errs).To(HaveLen(4))
+		Expect(errs[0].Field).To(Equal("spec.maintenanceWindow.timeZone"))
+		Expect(errs[1].Field).To(Equal("spec.maintenanceWindow.windows[0].schedule"))
+		Expect(errs[2].Field).To(Equal("spec.maintenanceWindow.windows[1].duration"))
+		Expect(errs[3].Field).To(Equal("spec.maintenanceWindow.windows[2].schedule"))
+		Expect(errs[3].Detail).To(ContainSubstring("six fields"))
+	})
+})
+
+var _ = Describe("validate backup verification", func() {
+	var v *ClusterCustomValidator
+	BeforeEach(func() {
+		v = &ClusterCustomValidator{}
+	})
+
+	It("allows a valid schedule", func() {
+		cluster := &apiv1.Cluster{
+			Spec: apiv1.ClusterSpec{
+				BackupVerification: &apiv1.BackupVerificationConfiguration{
+					Schedule: "0 0 3 * * 0",
+					Timeout:  &metav1.Duration{Duration: 2 * time.Hour},
+				},
+			},
+		}
+		Expect(v.validateBackupVerification(cluster)).To(BeEmpty())
+	})
+
+	It("complains about invalid schedules and timeouts", func() {
+		cluster := &apiv1.Cluster{
+			Spec: apiv1.ClusterSpec{
+				BackupVerification: &apiv1.BackupVerificationConfiguration{
+					Schedule: "every sunday",
+					Timeout:  &metav1.Duration{},
+				},
+			},
+		}
+		errs := v.validateBackupVerification(cluster)
+		Expect(errs).To(HaveLen(2))
+		Expect(errs[0].Field).To(Equal("spec.backupVerification.schedule"))
+		Expect(errs[1].Field).To(Equal("spec.backupVerification.timeout"))
+	})
+})
+
 var _ = Describe("Number of synchronous replicas", func() {
 	var v *ClusterCustomValidator
 	BeforeEach(func() {
@@ -2051,6 +2160,34 @@ var _ = Describe("storage configuration validation", func() {
 
 		Expect(v.validateStorageChange(clusterNew, clusterOld)).To(BeEmpty())
 	})
+
+	It("allows adding and removing the WAL storage", func() {
+		clusterOld := &apiv1.Cluster{}
+		clusterNew := &apiv1.Cluster{
+			Spec: apiv1.ClusterSpec{
+				WalStorage: &apiv1.StorageConfiguration{
+					Size: "1G",
+				},
+			},
+		}
+
+		Expect(v.validateWalStorageChange(clusterNew, clusterOld)).To(BeEmpty())
+		Expect(v.validateWalStorageChange(clusterOld, clusterNew)).To(BeEmpty())
+	})
+
+	It("complains if the WAL storage size is being reduced", func() {
+		clusterOld := &apiv1.Cluster{
+			Spec: apiv1.ClusterSpec{
+				WalStorage: &apiv1.StorageConfiguration{
+					Size: "1G",
+				},
+			},
+		}
+		clusterNew := clusterOld.DeepCopy()
+		clusterNew.Spec.WalStorage.Size = "512M"
+
+		Expect(v.validateWalStorageChange(clusterNew, clusterOld)).ToNot(BeEmpty())
+	})
 })
 
 var _ = Describe("Cluster name validation", func() {
@@ -3997,6 +4134,102 @@ var _ = Describe("Storage configuration validation", func() {
 			Expect(v.validateStorageSize(cluster)).To(BeEmpty())
 		})
 	})
+
+	When("the automatic expansion is configured", func() {
+		It("accepts a valid policy", func() {
+			cluster := &apiv1.Cluster{
+				Spec: apiv1.ClusterSpec{
+					StorageConfiguration: apiv1.StorageConfiguration{
+						Size: "10Gi",
+						AutoResize: &apiv1.AutoResizeConfiguration{
+							Enabled:   true,
+							Increment: "5Gi",
+							MaxSize:   "100Gi",
+						},
+					},
+					WalStorage: &apiv1.StorageConfiguration{
+						Size: "1Gi",
+						AutoResize: &apiv1.AutoResizeConfiguration{
+							Enabled: true,
+							MaxSize: "10Gi",
+						},
+					},
+				},
+			}
+			Expect(v.validateStorageAutoResize(cluster)).To(BeEmpty())
+		})
+
+		It("complains about invalid increments and maximum sizes", func() {
+			cluster := &apiv1.Cluster{
+				Spec: apiv1.ClusterSpec{
+					StorageConfiguration: apiv1.StorageConfiguration{
+						Size: "10Gi",
+						AutoResize: &apiv1.AutoResizeConfiguration{
+							Increment: "-5%",
+							MaxSize:   "5Gi",
+						},
+					},
+					Tablespaces: []apiv1.TablespaceConfiguration{
+						{
+							Name: "tbs",
+							Storage: apiv1.StorageConfiguration{
+								Size:       "1Gi",
+								AutoResize: &apiv1.AutoResizeConfiguration{MaxSize: "lots"},
+							},
+						},
+					},
+				},
+			}
+			Expect(v.validateStorageAutoResize(cluster)).To(HaveLen(3))
+		})
+
+		It("requires the resize of the in use volumes", func() {
+			cluster := &apiv1.Cluster{
+				Spec: apiv1.ClusterSpec{
+					StorageConfiguration: apiv1.StorageConfiguration{
+						Size:               "10Gi",
+						ResizeInUseVolumes: ptr.To(false),
+						AutoResize: &apiv1.AutoResizeConfiguration{
+							Enabled: true,
+							MaxSize: "100Gi",
+						},
+					},
+				},
+			}
+			Expect(v.validateStorageAutoResize(cluster)).To(HaveLen(1))
+		})
+	})
+})
+
+var _ = Describe("Disk-full protection validation", func() {
+	var v *ClusterCustomValidator
+	BeforeEach(func() {
+		v = &ClusterCustomValidator{}
+	})
+
+	It("accepts the default thresholds", func() {
+		cluster := &apiv1.Cluster{
+			Spec: apiv1.ClusterSpec{
+				DiskFullProtection: &apiv1.DiskFullProtectionConfiguration{Enabled: true},
+			},
+		}
+		Expect(v.validateDiskFullProtection(cluster)).To(BeEmpty())
+	})
+
+	It("complains when the recovery threshold isn't greater than the critical one", func() {
+		cluster := &apiv1.Cluster{
+			Spec: apiv1.ClusterSpec{
+				DiskFullProtection: &apiv1.DiskFullProtectionConfiguration{
+					Enabled:                     true,
+					CriticalFreeSpacePercentage: ptr.To(int32(15)),
+				},
+			},
+		}
+		Expect(v.validateDiskFullProtection(cluster)).To(HaveLen(1))
+
+		cluster.Spec.DiskFullProtection.RecoveryFreeSpacePercentage = ptr.To(int32(20))
+		Expect(v.validateDiskFullProtection(cluster)).To(BeEmpty())
+	})
 })
 
 var _ = Describe("Ephemeral volume configuration validation", func() {
@@ -94,6 +94,38 @@ var AllowedPgbouncerGenericConfigurationParameters = stringset.From([]string{
 	"verbose",
 })
 
+// AllowedPgCatGeneralConfigurationParameters is the list of allowed parameters
+// for the general section of the PgCat configuration
+var AllowedPgCatGeneralConfigurationParameters = stringset.From([]string{
+	"ban_time",
+	"connect_timeout",
+	"healthcheck_delay",
+	"healthcheck_timeout",
+	"idle_client_in_transaction_timeout",
+	"idle_timeout",
+	"log_client_connections",
+	"log_client_disconnections",
+	"prepared_statements",
+	"prepared_statements_cache_size",
+	"server_lifetime",
+	"server_round_robin",
+	"shutdown_timeout",
+	"tcp_keepalives_count",
+	"tcp_keepalives_idle",
+	"tcp_keepalives_interval",
+	"worker_threads",
+})
+
+const (
+	// pgBouncerAdminDatabase is the name of the virtual database
+	// used to reach the PgBouncer administration console
+	pgBouncerAdminDatabase = "pgbouncer"
+
+	// pgCatAdminDatabase is the name of the virtual database
+	// used to reach the PgCat administration console
+	pgCatAdminDatabase = "pgcat"
+)
+
 // poolerLog is for logging in this package.
 var poolerLog = log.WithName("pooler-resource").WithValues("version", "v1")
 
@@ -141,7 +173,7 @@ func (v *PoolerCustomValidator) ValidateCreate(_ context.Context, obj runtime.Ob
 func (v *PoolerCustomValidator) getAdmissionWarnings(r *apiv1.Pooler) admission.Warnings {
 	var warns admission.Warnings
 
-	if !r.IsAutomatedIntegration() {
+	if !r.IsAutomatedIntegration() && r.GetImplementation() == apiv1.PoolerImplementationPgBouncer {
 		poolerLog.Info("Pooler not automatically configured, manual configuration required",
 			"name", r.Name, "namespace", r.Namespace, "cluster", r.Spec.Cluster.Name)
 		warns = append(warns, fmt.Sprintf("The operator won't handle the Pooler %q integration with the Cluster %q (%q). "+
@@ -168,7 +200,7 @@ func (v *PoolerCustomValidator) ValidateUpdate(
 		return nil, fmt.Errorf("expected a Pooler object for the newObj but got %T", newObj)
 	}
 
-	_, ok = oldObj.(*apiv1.Pooler)
+	oldPooler, ok := oldObj.(*apiv1.Pooler)
 	if !ok {
 		return nil, fmt.Errorf("expected a Pooler object for the oldObj but got %T", oldObj)
 	}
@@ -180,6 +212,7 @@ func (v *PoolerCustomValidator) ValidateUpdate(
 	warns = append(warns, v.validateDeprecatedMonitoringFields(pooler)...)
 
 	allErrs := v.validate(pooler)
+	allErrs = append(allErrs, v.validateImplementationChange(pooler, oldPooler)...)
 	if len(allErrs) == 0 {
 		return warns, nil
 	}
@@ -203,6 +236,17 @@ func (v *PoolerCustomValidator) ValidateDelete(_ context.Context, obj runtime.Ob
 }
 
 func (v *PoolerCustomValidator) validatePgBouncer(r *apiv1.Pooler) field.ErrorList {
+	if r.GetImplementation() != apiv1.PoolerImplementationPgBouncer {
+		if r.Spec.PgBouncer != nil {
+			return field.ErrorList{
+				field.Forbidden(
+					field.NewPath("spec", "pgbouncer"),
+					"the pgbouncer configuration can only be used with the pgbouncer implementation"),
+			}
+		}
+		return nil
+	}
+
 	if r.Spec.PgBouncer == nil {
 		return field.ErrorList{
 			field.Invalid(
@@ -234,6 +278,39 @@ func (v *PoolerCustomValidator) validatePgBouncer(r *apiv1.Pooler) field.ErrorLi
 		result = append(result, v.validatePgbouncerGenericParameters(r)...)
 	}
 
+	result = append(result, v.validatePgbouncerDatabasesAndUsers(r)...)
+
+	return result
+}
+
+// validatePgbouncerDatabasesAndUsers validates the per-database and per-user
+// PgBouncer configuration
+func (v *PoolerCustomValidator) validatePgbouncerDatabasesAndUsers(r *apiv1.Pooler) field.ErrorList {
+	var result field.ErrorList
+
+	databasesPath := field.NewPath("spec", "pgbouncer", "databases")
+	databaseNames := stringset.New()
+	for idx, database := range r.Spec.PgBouncer.Databases {
+		namePath := databasesPath.Index(idx).Child("name")
+		switch {
+		case database.Name == pgBouncerAdminDatabase:
+			result = append(result,
+				field.Invalid(namePath, database.Name, "reserved for the PgBouncer administration console"))
+		case databaseNames.Has(database.Name):
+			result = append(result, field.Duplicate(namePath, database.Name))
+		}
+		databaseNames.Put(database.Name)
+	}
+
+	usersPath := field.NewPath("spec", "pgbouncer", "users")
+	userNames := stringset.New()
+	for idx, user := range r.Spec.PgBouncer.Users {
+		if userNames.Has(user.Name) {
+			result = append(result, field.Duplicate(usersPath.Index(idx).Child("name"), user.Name))
+		}
+		userNames.Put(user.Name)
+	}
+
 	return result
 }
 
@@ -259,9 +336,151 @@ func (v *PoolerCustomValidator) validateCluster(r *apiv1.Pooler) field.ErrorList
 func (v *PoolerCustomValidator) validate(r *apiv1.Pooler) (allErrs field.ErrorList) {
 	allErrs = append(allErrs, v.validatePgBouncer(r)...)
 	allErrs = append(allErrs, v.validateCluster(r)...)
+	allErrs = append(allErrs, v.validatePgCat(r)...)
+	allErrs = append(allErrs, v.validateAutoscaling(r)...)
 	return allErrs
 }
 
+// validateImplementationChange forbids changing the pooler implementation
+func (v *PoolerCustomValidator) validateImplementationChange(r, old *apiv1.Pooler) field.ErrorList {
+	if r.GetImplementation() == old.GetImplementation() {
+		return nil
+	}
+
+	return field.ErrorList{
+		field.Invalid(
+			field.NewPath("spec", "implementation"),
+			r.Spec.Implementation,
+			"the pooler implementation cannot be changed"),
+	}
+}
+
+// validatePgCat validates the PgCat configuration of a Pooler
+func (v *PoolerCustomValidator) validatePgCat(r *apiv1.Pooler) field.ErrorList {
+	path := field.NewPath("spec", "pgcat")
+
+	if r.GetImplementation() != apiv1.PoolerImplementationPgCat {
+		if r.Spec.PgCat != nil {
+			return field.ErrorList{
+				field.Forbidden(path, "the pgcat configuration can only be used with the pgcat implementation"),
+			}
+		}
+		return nil
+	}
+
+	if r.Spec.PgCat == nil {
+		return field.ErrorList{field.Required(path, "required pgcat configuration")}
+	}
+
+	var result field.ErrorList
+	spec := r.Spec.PgCat
+
+	if r.Spec.Type != "" && r.Spec.Type != apiv1.PoolerTypeRW {
+		if spec.ReadWriteSplitting {
+			result = append(result,
+				field.Invalid(path.Child("readWriteSplitting"), spec.ReadWriteSplitting,
+					"read/write splitting is only available with the rw pooler type"))
+		}
+		if spec.DefaultRole == apiv1.PgCatRolePrimary {
+			result = append(result,
+				field.Invalid(path.Child("defaultRole"), spec.DefaultRole,
+					"the primary is only available with the rw pooler type"))
+		}
+	}
+
+	if r.Spec.Monitoring != nil && r.Spec.Monitoring.OTLP != nil {
+		result = append(result,
+			field.Forbidden(field.NewPath("spec", "monitoring", "otlp"),
+				"pushing metrics to an OTLP collector is only supported by the pgbouncer implementation"))
+	}
+
+	for param := range spec.Parameters {
+		if !AllowedPgCatGeneralConfigurationParameters.Has(param) {
+			result = append(result,
+				field.Invalid(path.Child("parameters"), param, "Invalid or reserved parameter"))
+		}
+	}
+
+	if len(spec.Databases) == 0 {
+		result = append(result, field.Required(path.Child("databases"), "at least one database is required"))
+	}
+
+	databaseNames := stringset.New()
+	for idx, database := range spec.Databases {
+		databasePath := path.Child("databases").Index(idx)
+		switch {
+		case database.Name == pgBouncerAdminDatabase || database.Name == pgCatAdminDatabase:
+			result = append(result,
+				field.Invalid(databasePath.Child("name"), database.Name,
+					"reserved for the PgCat administration console"))
+		case databaseNames.Has(database.Name):
+			result = append(result, field.Duplicate(databasePath.Child("name"), database.Name))
+		}
+		databaseNames.Put(database.Name)
+
+		if len(database.Users) == 0 {
+			result = append(result, field.Required(databasePath.Child("users"), "at least one user is required"))
+		}
+		for userIdx, user := range database.Users {
+			if user.Secret.Name == "" {
+				result = append(result,
+					field.Required(databasePath.Child("users").Index(userIdx).Child("secret", "name"),
+						"the secret containing the user credentials is required"))
+			}
+		}
+	}
+
+	return result
+}
+
+// validateAutoscaling validates the autoscaling configuration of a Pooler
+func (v *PoolerCustomValidator) validateAutoscaling(r *apiv1.Pooler) field.ErrorList {
+	autoscaling := r.Spec.Autoscaling
+	if autoscaling == nil {
+		return nil
+	}
+
+	var result field.ErrorList
+	path := field.NewPath("spec", "autoscaling")
+
+	if r.GetImplementation() != apiv1.PoolerImplementationPgBouncer {
+		result = append(result,
+			field.Forbidden(path, "autoscaling is only available with the pgbouncer implementation"))
+	}
+
+	if autoscaling.MaxInstances < 1 {
+		result = append(result,
+			field.Invalid(path.Child("maxInstances"),
+				autoscaling.MaxInstances, "must be greater than zero"))
+	}
+
+	if autoscaling.MinInstances > autoscaling.MaxInstances {
+		result = append(result,
+			field.Invalid(path.Child("minInstances"),
+				autoscaling.MinInstances, "cannot be greater than maxInstances"))
+	}
+
+	if autoscaling.TargetClientsWaiting == nil && autoscaling.TargetMaxWait == nil {
+		result = append(result,
+			field.Required(path,
+				"at least one of targetClientsWaiting and targetMaxWait is required"))
+	}
+
+	if autoscaling.TargetMaxWait != nil && autoscaling.TargetMaxWait.Duration <= 0 {
+		result = append(result,
+			field.Invalid(path.Child("targetMaxWait"),
+				autoscaling.TargetMaxWait.String(), "must be a positive duration"))
+	}
+
+	if autoscaling.ScaleDownStabilizationWindow != nil && autoscaling.ScaleDownStabilizationWindow.Duration < 0 {
+		result = append(result,
+			field.Invalid(path.Child("scaleDownStabilizationWindow"),
+				autoscaling.ScaleDownStabilizationWindow.String(), "cannot be negative"))
+	}
+
+	return result
+}
+
 // validatePgbouncerGenericParameters validates pgbouncer parameters
 func (v *PoolerCustomValidator) validatePgbouncerGenericParameters(r *apiv1.Pooler) field.ErrorList {
 	var result field.ErrorList
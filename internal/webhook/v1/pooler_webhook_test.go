@@ -20,7 +20,10 @@ SPDX-License-Identifier: Apache-2.0
 package v1
 
 import (
+	"time"
+
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 
@@ -138,4 +141,176 @@ var _ = Describe("Pooler validation", func() {
 		}
 		Expect(v.validatePgbouncerGenericParameters(pooler)).To(BeEmpty())
 	})
+
+	Context("autoscaling", func() {
+		It("accepts a Pooler without autoscaling", func() {
+			Expect(v.validateAutoscaling(&apiv1.Pooler{})).To(BeEmpty())
+		})
+
+		It("accepts a valid autoscaling configuration", func() {
+			pooler := &apiv1.Pooler{
+				Spec: apiv1.PoolerSpec{
+					Autoscaling: &apiv1.PoolerAutoscalingSpec{
+						MinInstances:         1,
+						MaxInstances:         5,
+						TargetClientsWaiting: ptr.To(int32(10)),
+						TargetMaxWait:        &metav1.Duration{Duration: time.Second},
+					},
+				},
+			}
+			Expect(v.validateAutoscaling(pooler)).To(BeEmpty())
+		})
+
+		It("complains when minInstances is greater than maxInstances", func() {
+			pooler := &apiv1.Pooler{
+				Spec: apiv1.PoolerSpec{
+					Autoscaling: &apiv1.PoolerAutoscalingSpec{
+						MinInstances:         4,
+						MaxInstances:         2,
+						TargetClientsWaiting: ptr.To(int32(10)),
+					},
+				},
+			}
+			Expect(v.validateAutoscaling(pooler)).To(HaveLen(1))
+		})
+
+		It("complains when no target is set", func() {
+			pooler := &apiv1.Pooler{
+				Spec: apiv1.PoolerSpec{
+					Autoscaling: &apiv1.PoolerAutoscalingSpec{
+						MinInstances: 1,
+						MaxInstances: 2,
+					},
+				},
+			}
+			Expect(v.validateAutoscaling(pooler)).To(HaveLen(1))
+		})
+
+		It("complains when the target wait time is not positive", func() {
+			pooler := &apiv1.Pooler{
+				Spec: apiv1.PoolerSpec{
+					Autoscaling: &apiv1.PoolerAutoscalingSpec{
+						MinInstances:  1,
+						MaxInstances:  2,
+						TargetMaxWait: &metav1.Duration{},
+					},
+				},
+			}
+			Expect(v.validateAutoscaling(pooler)).To(HaveLen(1))
+		})
+	})
+
+	Context("databases and users", func() {
+		It("accepts unique databases and users", func() {
+			pooler := &apiv1.Pooler{
+				Spec: apiv1.PoolerSpec{
+					PgBouncer: &apiv1.PgBouncerSpec{
+						Databases: []apiv1.PgBouncerDatabase{{Name: "app"}, {Name: "reporting", DBName: "app"}},
+						Users:     []apiv1.PgBouncerUser{{Name: "app"}, {Name: "batch"}},
+					},
+				},
+			}
+			Expect(v.validatePgbouncerDatabasesAndUsers(pooler)).To(BeEmpty())
+		})
+
+		It("complains about the admin console database", func() {
+			pooler := &apiv1.Pooler{
+				Spec: apiv1.PoolerSpec{
+					PgBouncer: &apiv1.PgBouncerSpec{
+						Databases: []apiv1.PgBouncerDatabase{{Name: "pgbouncer"}},
+					},
+				},
+			}
+			Expect(v.validatePgbouncerDatabasesAndUsers(pooler)).To(HaveLen(1))
+		})
+
+		It("complains about duplicated names", func() {
+			pooler := &apiv1.Pooler{
+				Spec: apiv1.PoolerSpec{
+					PgBouncer: &apiv1.PgBouncerSpec{
+						Databases: []apiv1.PgBouncerDatabase{{Name: "app"}, {Name: "app"}},
+						Users:     []apiv1.PgBouncerUser{{Name: "batch"}, {Name: "batch"}},
+					},
+				},
+			}
+			Expect(v.validatePgbouncerDatabasesAndUsers(pooler)).To(HaveLen(2))
+		})
+	})
+
+	Context("pgcat", func() {
+		var pooler *apiv1.Pooler
+
+		BeforeEach(func() {
+			pooler = &apiv1.Pooler{
+				Spec: apiv1.PoolerSpec{
+					Cluster:        apiv1.LocalObjectReference{Name: "cluster-example"},
+					Type:           apiv1.PoolerTypeRW,
+					Implementation: apiv1.PoolerImplementationPgCat,
+					PgCat: &apiv1.PgCatSpec{
+						ReadWriteSplitting: true,
+						Parameters:         map[string]string{"connect_timeout": "1000"},
+						Databases: []apiv1.PgCatDatabase{
+							{
+								Name:  "app",
+								Users: []apiv1.PgCatUser{{Secret: apiv1.LocalObjectReference{Name: "app"}}},
+							},
+						},
+					},
+				},
+			}
+		})
+
+		It("accepts a valid configuration", func() {
+			Expect(v.validate(pooler)).To(BeEmpty())
+		})
+
+		It("requires the pgcat section", func() {
+			pooler.Spec.PgCat = nil
+			Expect(v.validatePgCat(pooler)).To(HaveLen(1))
+		})
+
+		It("forbids the sections of the other implementation", func() {
+			pooler.Spec.PgBouncer = &apiv1.PgBouncerSpec{}
+			Expect(v.validatePgBouncer(pooler)).To(HaveLen(1))
+
+			pooler.Spec.Implementation = apiv1.PoolerImplementationPgBouncer
+			Expect(v.validatePgCat(pooler)).To(HaveLen(1))
+		})
+
+		It("allows read/write splitting only with the rw type", func() {
+			pooler.Spec.Type = apiv1.PoolerTypeRO
+			pooler.Spec.PgCat.DefaultRole = apiv1.PgCatRolePrimary
+			Expect(v.validatePgCat(pooler)).To(HaveLen(2))
+		})
+
+		It("complains about reserved parameters and databases", func() {
+			pooler.Spec.PgCat.Parameters["port"] = "6432"
+			pooler.Spec.PgCat.Databases = append(pooler.Spec.PgCat.Databases,
+				apiv1.PgCatDatabase{Name: "pgcat", Users: []apiv1.PgCatUser{{}}})
+			Expect(v.validatePgCat(pooler)).To(HaveLen(3))
+		})
+
+		It("complains about OTLP metrics", func() {
+			pooler.Spec.Monitoring = &apiv1.PoolerMonitoringConfiguration{
+				OTLP: &apiv1.OTLPMetricsConfiguration{Endpoint: "http://otel-collector:4318"},
+			}
+			Expect(v.validatePgCat(pooler)).To(HaveLen(1))
+		})
+
+		It("complains about autoscaling", func() {
+			pooler.Spec.Autoscaling = &apiv1.PoolerAutoscalingSpec{
+				MinInstances:         1,
+				MaxInstances:         2,
+				TargetClientsWaiting: ptr.To(int32(1)),
+			}
+			Expect(v.validateAutoscaling(pooler)).To(HaveLen(1))
+		})
+
+		It("doesn't allow changing the implementation", func() {
+			oldPooler := pooler.DeepCopy()
+			oldPooler.Spec.Implementation = ""
+			Expect(v.validateImplementationChange(pooler, oldPooler)).To(HaveLen(1))
+			Expect(v.validateImplementationChange(pooler, pooler)).To(BeEmpty())
+		})
+	})
 })
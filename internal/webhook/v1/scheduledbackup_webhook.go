@@ -162,6 +162,29 @@ func (v *ScheduledBackupCustomValidator) validate(r *apiv1.ScheduledBackup) (adm
 		)
 	}
 
+	for i, tier := range r.Spec.Tiers {
+		if _, err := cron.Parse(tier.Schedule); err != nil {
+			result = append(result,
+				field.Invalid(
+					field.NewPath("spec", "tiers").Index(i).Child("schedule"),
+					tier.Schedule, err.Error()))
+		} else if len(strings.Fields(tier.Schedule)) != 6 {
+			warnings = append(
+				warnings,
+				fmt.Sprintf("Schedule parameter of tier %s may not have the right number of arguments "+
+					"(usually six arguments are needed)", tier.Name),
+			)
+		}
+	}
+
+	if _, err := r.GetLocation(); err != nil {
+		result = append(result, field.Invalid(
+			field.NewPath("spec", "timeZone"),
+			r.Spec.TimeZone,
+			err.Error(),
+		))
+	}
+
 	if r.Spec.Method == apiv1.BackupMethodVolumeSnapshot && !utils.HaveVolumeSnapshot() {
 		result = append(result, field.Invalid(
 			field.NewPath("spec", "method"),
@@ -59,6 +59,19 @@ var _ = Describe("Validate schedule", func() {
 		Expect(result).To(BeEmpty())
 	})
 
+	It("complains with an unknown time zone", func() {
+		schedule := &apiv1.ScheduledBackup{
+			Spec: apiv1.ScheduledBackupSpec{
+				Schedule: "0 0 0 * * *",
+				TimeZone: "Mars/Olympus_Mons",
+			},
+		}
+
+		_, result := v.validate(schedule)
+		Expect(result).To(HaveLen(1))
+		Expect(result[0].Field).To(Equal("spec.timeZone"))
+	})
+
 	It("complain with a wrong time", func() {
 		schedule := &apiv1.ScheduledBackup{
 			Spec: apiv1.ScheduledBackupSpec{
@@ -71,6 +84,23 @@ var _ = Describe("Validate schedule", func() {
 		Expect(result).To(HaveLen(1))
 	})
 
+	It("complains with a wrong schedule of a tier", func() {
+		schedule := &apiv1.ScheduledBackup{
+			Spec: apiv1.ScheduledBackupSpec{
+				Schedule: "0 0 * * * *",
+				Tiers: []apiv1.ScheduledBackupTier{
+					{Name: "daily", Schedule: "0 0 0 * * *"},
+					{Name: "weekly", Schedule: "0 0 0 * * * 1996"},
+				},
+			},
+		}
+
+		warnings, result := v.validate(schedule)
+		Expect(warnings).To(BeEmpty())
+		Expect(result).To(HaveLen(1))
+		Expect(result[0].Field).To(Equal("spec.tiers[1].schedule"))
+	})
+
 	It("doesn't complain if VolumeSnapshot CRD is present", func() {
 		schedule := &apiv1.ScheduledBackup{
 			Spec: apiv1.ScheduledBackupSpec{
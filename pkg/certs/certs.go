@@ -21,8 +21,8 @@ SPDX-License-Identifier: Apache-2.0
 package certs
 
 import (
+	"crypto"
 	"crypto/ecdsa"
-	"crypto/elliptic"
 	"crypto/rand"
 	"crypto/tls"
 	"crypto/x509"
@@ -57,6 +57,14 @@ const (
 	// CAPrivateKeyKey is the key for the private key field in a CA secret
 	CAPrivateKeyKey = "ca.key"
 
+	// CANextCertKey is the key for the certificate of the CA that will
+	// replace the current one, used while the CA is being rotated
+	CANextCertKey = "ca-next.crt"
+
+	// CANextPrivateKeyKey is the key for the private key of the CA that
+	// will replace the current one, used while the CA is being rotated
+	CANextPrivateKeyKey = "ca-next.key"
+
 	// TLSCertKey is the key for certificates in a CA secret
 	TLSCertKey = "tls.crt"
 
@@ -144,6 +152,22 @@ func (pair KeyPair) IsValid(caPair *KeyPair, opts *x509.VerifyOptions) error {
 	return nil
 }
 
+// IsIssuedBy checks if the certificate stored in the pair has been signed
+// by the first certificate of the passed CA keypair
+func (pair KeyPair) IsIssuedBy(caPair *KeyPair) (bool, error) {
+	certificate, err := pair.ParseCertificate()
+	if err != nil {
+		return false, err
+	}
+
+	caCertificate, err := caPair.ParseCertificate()
+	if err != nil {
+		return false, err
+	}
+
+	return certificate.CheckSignatureFrom(caCertificate) == nil, nil
+}
+
 // CreateAndSignPair given a CA keypair, generate and sign a leaf keypair
 func (pair KeyPair) CreateAndSignPair(host string, usage CertType, altDNSNames []string) (*KeyPair, error) {
 	certificateDuration := getCertificateDuration()
@@ -152,6 +176,19 @@ func (pair KeyPair) CreateAndSignPair(host string, usage CertType, altDNSNames [
 	return pair.createAndSignPairWithValidity(host, notBefore, notAfter, usage, altDNSNames)
 }
 
+// CreateAndSignPairWithDuration given a CA keypair, generate and sign a leaf
+// keypair valid for the passed duration
+func (pair KeyPair) CreateAndSignPairWithDuration(
+	host string,
+	usage CertType,
+	altDNSNames []string,
+	duration time.Duration,
+) (*KeyPair, error) {
+	notBefore := time.Now().Add(time.Minute * -5)
+	notAfter := time.Now().Add(duration)
+	return pair.createAndSignPairWithValidity(host, notBefore, notAfter, usage, altDNSNames)
+}
+
 func (pair KeyPair) createAndSignPairWithValidity(
 	host string,
 	notBefore,
@@ -164,13 +201,13 @@ func (pair KeyPair) createAndSignPairWithValidity(
 		return nil, err
 	}
 
-	caPrivateKey, err := pair.ParseECPrivateKey()
+	caPrivateKey, err := pair.ParsePrivateKey()
 	if err != nil {
 		return nil, err
 	}
 
-	// Generate a new private key
-	leafKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	// Generate a new private key, of the same type of the CA one
+	leafKey, leafPrivateKey, err := generatePrivateKeyLike(caPrivateKey.Public())
 	if err != nil {
 		return nil, err
 	}
@@ -216,18 +253,13 @@ func (pair KeyPair) createAndSignPairWithValidity(
 	}
 
 	certificateBytes, err := x509.CreateCertificate(
-		rand.Reader, &leafTemplate, caCertificate, &leafKey.PublicKey, caPrivateKey)
-	if err != nil {
-		return nil, err
-	}
-
-	privateKey, err := x509.MarshalECPrivateKey(leafKey)
+		rand.Reader, &leafTemplate, caCertificate, leafKey.Public(), caPrivateKey)
 	if err != nil {
 		return nil, err
 	}
 
 	return &KeyPair{
-		Private:     encodePrivateKey(privateKey),
+		Private:     leafPrivateKey,
 		Certificate: encodeCertificate(certificateBytes),
 	}, nil
 }
@@ -274,7 +306,7 @@ func (pair KeyPair) GenerateCertificateSecret(namespace, name string) *corev1.Se
 // parent certificate. If the parent certificate is nil the certificate
 // will be self-signed
 func (pair *KeyPair) RenewCertificate(
-	caPrivateKey *ecdsa.PrivateKey,
+	caPrivateKey crypto.Signer,
 	parentCertificate *x509.Certificate,
 	altDNSNames []string,
 ) error {
@@ -299,11 +331,15 @@ func (pair *KeyPair) RenewCertificate(
 	newCertificate.SerialNumber = serialNumber
 	newCertificate.DNSNames = altDNSNames
 
+	// The signature algorithm depends on the key of the CA, which
+	// may be different from the one that signed the old certificate
+	newCertificate.SignatureAlgorithm = x509.UnknownSignatureAlgorithm
+
 	if parentCertificate == nil {
 		parentCertificate = &newCertificate
 	}
 
-	tlsPrivateKey, err := pair.ParseECPrivateKey()
+	tlsPrivateKey, err := pair.ParsePrivateKey()
 	if err != nil {
 		return err
 	}
@@ -312,7 +348,7 @@ func (pair *KeyPair) RenewCertificate(
 		rand.Reader,
 		&newCertificate,
 		parentCertificate,
-		&tlsPrivateKey.PublicKey,
+		tlsPrivateKey.Public(),
 		caPrivateKey)
 	if err != nil {
 		return err
@@ -361,7 +397,12 @@ func (pair *KeyPair) CreateDerivedCA(commonName string, organizationalUnit strin
 		return nil, err
 	}
 
-	key, err := pair.ParseECPrivateKey()
+	key, err := pair.ParsePrivateKey()
+	if err != nil {
+		return nil, err
+	}
+
+	algorithm, err := getKeyAlgorithm(key.Public())
 	if err != nil {
 		return nil, err
 	}
@@ -370,15 +411,26 @@ func (pair *KeyPair) CreateDerivedCA(commonName string, organizationalUnit strin
 	notBefore := time.Now().Add(time.Minute * -5)
 	notAfter := notBefore.Add(certificateDuration)
 
-	return createCAWithValidity(notBefore, notAfter, certificate, key, commonName, organizationalUnit)
+	return createCAWithValidity(notBefore, notAfter, certificate, key, commonName, organizationalUnit, algorithm)
 }
 
 // CreateRootCA generates a CA returning its keys
 func CreateRootCA(commonName string, organizationalUnit string) (*KeyPair, error) {
+	return CreateRootCAWithKeyAlgorithm(commonName, organizationalUnit, DefaultKeyAlgorithm)
+}
+
+// CreateRootCAWithKeyAlgorithm generates a CA whose private key uses the
+// passed algorithm, returning its keys. The certificates signed by this
+// CA will use the same algorithm
+func CreateRootCAWithKeyAlgorithm(
+	commonName string,
+	organizationalUnit string,
+	algorithm KeyAlgorithm,
+) (*KeyPair, error) {
 	certificateDuration := getCertificateDuration()
 	notBefore := time.Now().Add(time.Minute * -5)
 	notAfter := notBefore.Add(certificateDuration)
-	return createCAWithValidity(notBefore, notAfter, nil, nil, commonName, organizationalUnit)
+	return createCAWithValidity(notBefore, notAfter, nil, nil, commonName, organizationalUnit, algorithm)
 }
 
 // ParseCASecret parse a CA secret to a key pair
@@ -430,21 +482,22 @@ func ParseServerSecret(secret *corev1.Secret) (*KeyPair, error) {
 }
 
 // createCAWithValidity create a CA with a certain validity, with a parent certificate and signed by a certain
-// private key. If the latest two parameters are nil, the CA will be a root one (self-signed)
+// private key. If the parent certificate and key are nil, the CA will be a root one (self-signed)
 func createCAWithValidity(
 	notBefore,
 	notAfter time.Time,
 	parentCertificate *x509.Certificate,
-	parentPrivateKey interface{},
+	parentPrivateKey crypto.Signer,
 	commonName string,
 	organizationalUnit string,
+	algorithm KeyAlgorithm,
 ) (*KeyPair, error) {
 	serialNumberLimit := new(big.Int).Lsh(big.NewInt(1), 128)
 	serialNumber, err := rand.Int(rand.Reader, serialNumberLimit)
 	if err != nil {
 		return nil, err
 	}
-	rootKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	rootKey, rootPrivateKey, err := generatePrivateKey(algorithm)
 	if err != nil {
 		return nil, err
 	}
@@ -476,23 +529,49 @@ func createCAWithValidity(
 		rand.Reader,
 		&rootTemplate,
 		parentCertificate,
-		&rootKey.PublicKey,
+		rootKey.Public(),
 		parentPrivateKey)
 	if err != nil {
 		return nil, err
 	}
 
-	privateKey, err := x509.MarshalECPrivateKey(rootKey)
-	if err != nil {
-		return nil, err
-	}
-
 	return &KeyPair{
-		Private:     encodePrivateKey(privateKey),
+		Private:     rootPrivateKey,
 		Certificate: encodeCertificate(certificateBytes),
 	}, nil
 }
 
+// AppendCertificates creates a certificate bundle containing all the
+// certificates of the passed PEM bundles, skipping the duplicates
+func AppendCertificates(bundles ...[]byte) []byte {
+	var result []byte
+	seen := make(map[string]struct{})
+	for _, bundle := range bundles {
+		for block, rest := pem.Decode(bundle); block != nil; block, rest = pem.Decode(rest) {
+			if block.Type != certificatePEMBlockType {
+				continue
+			}
+			if _, ok := seen[string(block.Bytes)]; ok {
+				continue
+			}
+			seen[string(block.Bytes)] = struct{}{}
+			result = append(result, encodeCertificate(block.Bytes)...)
+		}
+	}
+
+	return result
+}
+
+// FirstCertificate returns the first certificate of a PEM bundle
+func FirstCertificate(bundle []byte) ([]byte, error) {
+	block, _ := pem.Decode(bundle)
+	if block == nil || block.Type != certificatePEMBlockType {
+		return nil, fmt.Errorf("invalid public key PEM block type")
+	}
+
+	return encodeCertificate(block.Bytes), nil
+}
+
 func encodeCertificate(derBytes []byte) []byte {
 	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: derBytes})
 }
@@ -68,7 +68,7 @@ var _ = Describe("Keypair generation", func() {
 	It("should be able to renew an existing CA certificate", func() {
 		notAfter := time.Now().Add(-10 * time.Hour)
 		notBefore := notAfter.Add(-90 * 24 * time.Hour)
-		ca, err := createCAWithValidity(notBefore, notAfter, nil, nil, "root", "namespace")
+		ca, err := createCAWithValidity(notBefore, notAfter, nil, nil, "root", "namespace", DefaultKeyAlgorithm)
 		Expect(err).ToNot(HaveOccurred())
 
 		privateKey, err := ca.ParseECPrivateKey()
@@ -98,7 +98,7 @@ var _ = Describe("Keypair generation", func() {
 	It("marks expiring certificate as expiring", func() {
 		notAfter := time.Now().Add(-10 * time.Hour)
 		notBefore := notAfter.Add(-90 * 24 * time.Hour)
-		ca, err := createCAWithValidity(notBefore, notAfter, nil, nil, "root", "namespace")
+		ca, err := createCAWithValidity(notBefore, notAfter, nil, nil, "root", "namespace", DefaultKeyAlgorithm)
 		Expect(err).ToNot(HaveOccurred())
 		isExpiring, _, err := ca.IsExpiring()
 		Expect(isExpiring, err).To(BeTrue())
@@ -338,6 +338,55 @@ var _ = Describe("Keypair generation", func() {
 			err = tlsCert.IsValid(caBundleIncomplete, nil)
 			Expect(err).Should(HaveOccurred())
 		})
+
+		It("should detect the CA which signed a certificate", func() {
+			rootCA, err := CreateRootCA("test", "namespace")
+			Expect(err).ToNot(HaveOccurred())
+			otherCA, err := CreateRootCA("test", "namespace")
+			Expect(err).ToNot(HaveOccurred())
+
+			pair, err := rootCA.CreateAndSignPair("this.host.name.com", CertTypeServer, nil)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(pair.IsIssuedBy(rootCA)).To(BeTrue())
+			Expect(pair.IsIssuedBy(otherCA)).To(BeFalse())
+
+			bundle := &KeyPair{Certificate: AppendCertificates(otherCA.Certificate, rootCA.Certificate)}
+			Expect(pair.IsIssuedBy(bundle)).To(BeFalse())
+			Expect(pair.IsValid(bundle, nil)).To(Succeed())
+		})
+	})
+})
+
+var _ = Describe("Certificate bundles", func() {
+	var firstCA, secondCA *KeyPair
+
+	BeforeEach(func() {
+		var err error
+		firstCA, err = CreateRootCA("test", "namespace")
+		Expect(err).ToNot(HaveOccurred())
+		secondCA, err = CreateRootCA("test", "namespace")
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("appends the certificates skipping the duplicates", func() {
+		bundle := AppendCertificates(firstCA.Certificate, secondCA.Certificate, firstCA.Certificate)
+		Expect(bundle).To(Equal(append(append([]byte{}, firstCA.Certificate...), secondCA.Certificate...)))
+	})
+
+	It("ignores the blocks which are not certificates", func() {
+		Expect(AppendCertificates(firstCA.Private, secondCA.Certificate)).To(Equal(secondCA.Certificate))
+	})
+
+	It("extracts the first certificate of a bundle", func() {
+		first, err := FirstCertificate(AppendCertificates(secondCA.Certificate, firstCA.Certificate))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(first).To(Equal(secondCA.Certificate))
+	})
+
+	It("fails to extract a certificate from an invalid bundle", func() {
+		_, err := FirstCertificate(firstCA.Private)
+		Expect(err).To(HaveOccurred())
 	})
 })
 
@@ -105,26 +105,49 @@ func RenewLeafCertificate(caSecret *corev1.Secret, secret *corev1.Secret, altDNS
 		return false, err
 	}
 
-	if !expiring && altDNSNamesMatch {
-		return false, nil
-	}
-
 	// Parse the CA secret to get the private key
 	caPair, err := ParseCASecret(caSecret)
 	if err != nil {
 		return false, err
 	}
 
-	caPrivateKey, err := caPair.ParseECPrivateKey()
+	// The certificate needs to be signed again when the CA has been rotated
+	issuedByCA, err := pair.IsIssuedBy(caPair)
+	if err != nil {
+		return false, err
+	}
+
+	caPrivateKey, err := caPair.ParsePrivateKey()
+	if err != nil {
+		return false, err
+	}
+
+	// The certificate uses the same key type of the CA, which may have
+	// been rotated to a different one
+	privateKey, err := pair.ParsePrivateKey()
 	if err != nil {
 		return false, err
 	}
+	keyTypeMatch := isSameKeyType(privateKey.Public(), caPrivateKey.Public())
+
+	if !expiring && altDNSNamesMatch && issuedByCA && keyTypeMatch {
+		return false, nil
+	}
 
 	caCertificate, err := caPair.ParseCertificate()
 	if err != nil {
 		return false, err
 	}
 
+	if !keyTypeMatch {
+		_, newPrivateKey, err := generatePrivateKeyLike(caPrivateKey.Public())
+		if err != nil {
+			return false, err
+		}
+		pair.Private = newPrivateKey
+		secret.Data[TLSPrivateKeyKey] = newPrivateKey
+	}
+
 	err = pair.RenewCertificate(caPrivateKey, caCertificate, altDNSNames)
 	if err != nil {
 		return false, err
@@ -213,7 +236,7 @@ func renewCACertificate(ctx context.Context, kubeClient client.Client, secret *c
 		return secret, nil
 	}
 
-	privateKey, err := pair.ParseECPrivateKey()
+	privateKey, err := pair.ParsePrivateKey()
 	if err != nil {
 		return nil, err
 	}
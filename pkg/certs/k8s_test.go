@@ -141,7 +141,7 @@ var _ = Describe("Root CA secret generation", func() {
 		notAfter := time.Now().Add(-10 * time.Hour)
 		notBefore := notAfter.Add(-90 * 24 * time.Hour)
 		ca, err := createCAWithValidity(notBefore, notAfter,
-			nil, nil, "root", operatorNamespaceName)
+			nil, nil, "root", operatorNamespaceName, DefaultKeyAlgorithm)
 		Expect(err).ToNot(HaveOccurred())
 
 		secret := ca.GenerateCASecret(operatorNamespaceName, "ca-secret-name")
@@ -374,3 +374,31 @@ var _ = Describe("Webhook environment creation", func() {
 		Expect(updatedValidatingWebhook.Webhooks[0].ClientConfig.CABundle).To(Equal(webhookSecret.Data["tls.crt"]))
 	})
 })
+
+var _ = Describe("Leaf certificate renewal", func() {
+	It("renews the certificate only when it has been signed by another CA", func() {
+		oldCA, err := CreateRootCA("test", "namespace")
+		Expect(err).ToNot(HaveOccurred())
+		newCA, err := CreateRootCA("test", "namespace")
+		Expect(err).ToNot(HaveOccurred())
+
+		pair, err := oldCA.CreateAndSignPair("this.host.name.com", CertTypeServer, nil)
+		Expect(err).ToNot(HaveOccurred())
+		secret := pair.GenerateCertificateSecret("namespace", "name")
+
+		renewed, err := RenewLeafCertificate(
+			oldCA.GenerateCASecret("namespace", "ca"), secret, []string{"this.host.name.com"})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(renewed).To(BeFalse())
+
+		renewed, err = RenewLeafCertificate(
+			newCA.GenerateCASecret("namespace", "ca"), secret, []string{"this.host.name.com"})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(renewed).To(BeTrue())
+
+		renewedPair, err := ParseServerSecret(secret)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(renewedPair.IsIssuedBy(newCA)).To(BeTrue())
+		Expect(renewedPair.Private).To(Equal(pair.Private))
+	})
+})
@@ -0,0 +1,216 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package certs
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+)
+
+const (
+	// This is the PEM block type of RSA private keys
+	rsaPrivateKeyPEMBlockType = "RSA PRIVATE KEY"
+
+	// This is the PEM block type of PKCS #8 private keys, used for Ed25519
+	pkcs8PrivateKeyPEMBlockType = "PRIVATE KEY"
+
+	// rsaKeySize is the size of the generated RSA keys
+	rsaKeySize = 3072
+)
+
+// KeyAlgorithm is the algorithm used to generate the private keys
+type KeyAlgorithm string
+
+const (
+	// KeyAlgorithmECDSAP256 generates ECDSA keys on the P-256 curve
+	KeyAlgorithmECDSAP256 KeyAlgorithm = "ecdsa-p256"
+
+	// KeyAlgorithmRSA3072 generates 3072 bits RSA keys
+	KeyAlgorithmRSA3072 KeyAlgorithm = "rsa-3072"
+
+	// KeyAlgorithmEd25519 generates Ed25519 keys
+	KeyAlgorithmEd25519 KeyAlgorithm = "ed25519"
+
+	// DefaultKeyAlgorithm is the algorithm used when none is specified
+	DefaultKeyAlgorithm = KeyAlgorithmECDSAP256
+)
+
+// generatePrivateKey generates a new private key with the passed algorithm,
+// returning it together with its PEM encoding
+func generatePrivateKey(algorithm KeyAlgorithm) (crypto.Signer, []byte, error) {
+	switch algorithm {
+	case KeyAlgorithmECDSAP256, "":
+		return generateECDSAPrivateKey(elliptic.P256())
+	case KeyAlgorithmRSA3072:
+		return generateRSAPrivateKey(rsaKeySize)
+	case KeyAlgorithmEd25519:
+		return generateEd25519PrivateKey()
+	default:
+		return nil, nil, fmt.Errorf("unknown key algorithm: %s", algorithm)
+	}
+}
+
+// generatePrivateKeyLike generates a new private key having the same
+// type and size of the passed public key
+func generatePrivateKeyLike(publicKey crypto.PublicKey) (crypto.Signer, []byte, error) {
+	switch key := publicKey.(type) {
+	case *ecdsa.PublicKey:
+		return generateECDSAPrivateKey(key.Curve)
+	case *rsa.PublicKey:
+		return generateRSAPrivateKey(key.N.BitLen())
+	case ed25519.PublicKey:
+		return generateEd25519PrivateKey()
+	default:
+		return nil, nil, fmt.Errorf("unsupported public key type %T", publicKey)
+	}
+}
+
+func generateECDSAPrivateKey(curve elliptic.Curve) (crypto.Signer, []byte, error) {
+	key, err := ecdsa.GenerateKey(curve, rand.Reader)
+	if err != nil {
+		return nil, nil, err
+	}
+	der, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return nil, nil, err
+	}
+	return key, encodePrivateKey(der), nil
+}
+
+func generateRSAPrivateKey(bits int) (crypto.Signer, []byte, error) {
+	key, err := rsa.GenerateKey(rand.Reader, bits)
+	if err != nil {
+		return nil, nil, err
+	}
+	der := x509.MarshalPKCS1PrivateKey(key)
+	return key, pem.EncodeToMemory(&pem.Block{Type: rsaPrivateKeyPEMBlockType, Bytes: der}), nil
+}
+
+func generateEd25519PrivateKey() (crypto.Signer, []byte, error) {
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, nil, err
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return nil, nil, err
+	}
+	return key, pem.EncodeToMemory(&pem.Block{Type: pkcs8PrivateKeyPEMBlockType, Bytes: der}), nil
+}
+
+// isSameKeyType checks if two public keys have the same type and size
+func isSameKeyType(a, b crypto.PublicKey) bool {
+	switch keyA := a.(type) {
+	case *ecdsa.PublicKey:
+		keyB, ok := b.(*ecdsa.PublicKey)
+		return ok && keyA.Curve == keyB.Curve
+	case *rsa.PublicKey:
+		keyB, ok := b.(*rsa.PublicKey)
+		return ok && keyA.N.BitLen() == keyB.N.BitLen()
+	case ed25519.PublicKey:
+		_, ok := b.(ed25519.PublicKey)
+		return ok
+	default:
+		return false
+	}
+}
+
+// HasSameKeyTypeOf checks if the private key stored in the pair has the
+// same type and size of the one stored in the passed pair
+func (pair KeyPair) HasSameKeyTypeOf(other *KeyPair) (bool, error) {
+	privateKey, err := pair.ParsePrivateKey()
+	if err != nil {
+		return false, err
+	}
+
+	otherPrivateKey, err := other.ParsePrivateKey()
+	if err != nil {
+		return false, err
+	}
+
+	return isSameKeyType(privateKey.Public(), otherPrivateKey.Public()), nil
+}
+
+// ParsePrivateKey parse the private key stored in the pair, whatever
+// algorithm has been used to generate it
+func (pair KeyPair) ParsePrivateKey() (crypto.Signer, error) {
+	block, _ := pem.Decode(pair.Private)
+	if block == nil {
+		return nil, fmt.Errorf("invalid private key PEM block")
+	}
+
+	switch block.Type {
+	case ecPrivateKeyPEMBlockType:
+		return x509.ParseECPrivateKey(block.Bytes)
+
+	case rsaPrivateKeyPEMBlockType:
+		return x509.ParsePKCS1PrivateKey(block.Bytes)
+
+	case pkcs8PrivateKeyPEMBlockType:
+		key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		signer, ok := key.(crypto.Signer)
+		if !ok {
+			return nil, fmt.Errorf("unsupported private key type %T", key)
+		}
+		return signer, nil
+
+	default:
+		return nil, fmt.Errorf("invalid private key PEM block type: %s", block.Type)
+	}
+}
+
+// GetKeyAlgorithm detects the algorithm used to generate the private
+// key stored in the pair
+func (pair KeyPair) GetKeyAlgorithm() (KeyAlgorithm, error) {
+	key, err := pair.ParsePrivateKey()
+	if err != nil {
+		return "", err
+	}
+
+	return getKeyAlgorithm(key.Public())
+}
+
+// getKeyAlgorithm detects the algorithm of a public key
+func getKeyAlgorithm(publicKey crypto.PublicKey) (KeyAlgorithm, error) {
+	switch key := publicKey.(type) {
+	case *ecdsa.PublicKey:
+		if key.Curve == elliptic.P256() {
+			return KeyAlgorithmECDSAP256, nil
+		}
+	case *rsa.PublicKey:
+		if key.N.BitLen() == rsaKeySize {
+			return KeyAlgorithmRSA3072, nil
+		}
+	case ed25519.PublicKey:
+		return KeyAlgorithmEd25519, nil
+	}
+
+	return "", fmt.Errorf("unsupported public key type %T", publicKey)
+}
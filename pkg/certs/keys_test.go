@@ -0,0 +1,85 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package certs
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/tls"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Key algorithms", func() {
+	DescribeTable("generates a CA and signs certificates with the requested algorithm",
+		func(algorithm KeyAlgorithm, expectedKey any) {
+			ca, err := CreateRootCAWithKeyAlgorithm("test", "namespace", algorithm)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(ca.GetKeyAlgorithm()).To(Equal(algorithm))
+
+			caSecret := ca.GenerateCASecret("namespace", "ca")
+			_, err = ParseCASecret(caSecret)
+			Expect(err).ToNot(HaveOccurred())
+
+			pair, err := ca.CreateAndSignPair("this.host.name.com", CertTypeServer, nil)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(pair.GetKeyAlgorithm()).To(Equal(algorithm))
+			Expect(pair.IsIssuedBy(ca)).To(BeTrue())
+
+			key, err := pair.ParsePrivateKey()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(key).To(BeAssignableToTypeOf(expectedKey))
+
+			_, err = tls.X509KeyPair(pair.Certificate, pair.Private)
+			Expect(err).ToNot(HaveOccurred())
+		},
+		Entry("ECDSA P-256", KeyAlgorithmECDSAP256, &ecdsa.PrivateKey{}),
+		Entry("RSA 3072", KeyAlgorithmRSA3072, &rsa.PrivateKey{}),
+		Entry("Ed25519", KeyAlgorithmEd25519, ed25519.PrivateKey{}),
+	)
+
+	It("refuses unknown algorithms", func() {
+		_, err := CreateRootCAWithKeyAlgorithm("test", "namespace", "dsa")
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("replaces the key of a certificate when the CA uses another algorithm", func() {
+		oldCA, err := CreateRootCAWithKeyAlgorithm("test", "namespace", KeyAlgorithmECDSAP256)
+		Expect(err).ToNot(HaveOccurred())
+		newCA, err := CreateRootCAWithKeyAlgorithm("test", "namespace", KeyAlgorithmEd25519)
+		Expect(err).ToNot(HaveOccurred())
+
+		pair, err := oldCA.CreateAndSignPair("this.host.name.com", CertTypeServer, nil)
+		Expect(err).ToNot(HaveOccurred())
+		secret := pair.GenerateCertificateSecret("namespace", "name")
+
+		renewed, err := RenewLeafCertificate(
+			newCA.GenerateCASecret("namespace", "ca"), secret, []string{"this.host.name.com"})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(renewed).To(BeTrue())
+
+		renewedPair, err := ParseServerSecret(secret)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(renewedPair.GetKeyAlgorithm()).To(Equal(KeyAlgorithmEd25519))
+		Expect(renewedPair.IsIssuedBy(newCA)).To(BeTrue())
+	})
+})
@@ -0,0 +1,187 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package otlp
+
+import (
+	"math"
+	"time"
+
+	dto "github.com/prometheus/client_model/go"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+// convertMetricFamilies translates the metric families gathered from a
+// Prometheus registry into their OpenTelemetry representation.
+// Counters are mapped to cumulative monotonic sums, gauges and untyped
+// metrics to gauges, histograms to explicit-bucket histograms and summaries
+// to summaries
+func convertMetricFamilies(
+	families []*dto.MetricFamily,
+	startTime time.Time,
+	now time.Time,
+) []metricdata.Metrics {
+	result := make([]metricdata.Metrics, 0, len(families))
+	for _, family := range families {
+		if len(family.GetMetric()) == 0 {
+			continue
+		}
+
+		var data metricdata.Aggregation
+		switch family.GetType() {
+		case dto.MetricType_COUNTER:
+			data = convertCounter(family, startTime, now)
+		case dto.MetricType_GAUGE, dto.MetricType_UNTYPED:
+			data = convertGauge(family, now)
+		case dto.MetricType_HISTOGRAM:
+			data = convertHistogram(family, startTime, now)
+		case dto.MetricType_SUMMARY:
+			data = convertSummary(family, startTime, now)
+		default:
+			continue
+		}
+
+		result = append(result, metricdata.Metrics{
+			Name:        family.GetName(),
+			Description: family.GetHelp(),
+			Unit:        family.GetUnit(),
+			Data:        data,
+		})
+	}
+
+	return result
+}
+
+func convertCounter(family *dto.MetricFamily, startTime, now time.Time) metricdata.Sum[float64] {
+	dataPoints := make([]metricdata.DataPoint[float64], 0, len(family.GetMetric()))
+	for _, metric := range family.GetMetric() {
+		dataPoints = append(dataPoints, metricdata.DataPoint[float64]{
+			Attributes: convertLabels(metric.GetLabel()),
+			StartTime:  startTime,
+			Time:       getTimestamp(metric, now),
+			Value:      metric.GetCounter().GetValue(),
+		})
+	}
+
+	return metricdata.Sum[float64]{
+		DataPoints:  dataPoints,
+		Temporality: metricdata.CumulativeTemporality,
+		IsMonotonic: true,
+	}
+}
+
+func convertGauge(family *dto.MetricFamily, now time.Time) metricdata.Gauge[float64] {
+	dataPoints := make([]metricdata.DataPoint[float64], 0, len(family.GetMetric()))
+	for _, metric := range family.GetMetric() {
+		value := metric.GetGauge().GetValue()
+		if family.GetType() == dto.MetricType_UNTYPED {
+			value = metric.GetUntyped().GetValue()
+		}
+		dataPoints = append(dataPoints, metricdata.DataPoint[float64]{
+			Attributes: convertLabels(metric.GetLabel()),
+			Time:       getTimestamp(metric, now),
+			Value:      value,
+		})
+	}
+
+	return metricdata.Gauge[float64]{DataPoints: dataPoints}
+}
+
+func convertHistogram(family *dto.MetricFamily, startTime, now time.Time) metricdata.Histogram[float64] {
+	dataPoints := make([]metricdata.HistogramDataPoint[float64], 0, len(family.GetMetric()))
+	for _, metric := range family.GetMetric() {
+		histogram := metric.GetHistogram()
+
+		// Prometheus buckets are cumulative and may include the +Inf one,
+		// while OTLP wants the count of every bucket plus an implicit
+		// overflow bucket
+		bounds := make([]float64, 0, len(histogram.GetBucket()))
+		bucketCounts := make([]uint64, 0, len(histogram.GetBucket())+1)
+		var previousCount uint64
+		for _, bucket := range histogram.GetBucket() {
+			if math.IsInf(bucket.GetUpperBound(), +1) {
+				continue
+			}
+			bounds = append(bounds, bucket.GetUpperBound())
+			bucketCounts = append(bucketCounts, bucket.GetCumulativeCount()-previousCount)
+			previousCount = bucket.GetCumulativeCount()
+		}
+		var overflowCount uint64
+		if histogram.GetSampleCount() > previousCount {
+			overflowCount = histogram.GetSampleCount() - previousCount
+		}
+		bucketCounts = append(bucketCounts, overflowCount)
+
+		dataPoints = append(dataPoints, metricdata.HistogramDataPoint[float64]{
+			Attributes:   convertLabels(metric.GetLabel()),
+			StartTime:    startTime,
+			Time:         getTimestamp(metric, now),
+			Count:        histogram.GetSampleCount(),
+			Sum:          histogram.GetSampleSum(),
+			Bounds:       bounds,
+			BucketCounts: bucketCounts,
+		})
+	}
+
+	return metricdata.Histogram[float64]{
+		DataPoints:  dataPoints,
+		Temporality: metricdata.CumulativeTemporality,
+	}
+}
+
+func convertSummary(family *dto.MetricFamily, startTime, now time.Time) metricdata.Summary {
+	dataPoints := make([]metricdata.SummaryDataPoint, 0, len(family.GetMetric()))
+	for _, metric := range family.GetMetric() {
+		summary := metric.GetSummary()
+		quantiles := make([]metricdata.QuantileValue, 0, len(summary.GetQuantile()))
+		for _, quantile := range summary.GetQuantile() {
+			quantiles = append(quantiles, metricdata.QuantileValue{
+				Quantile: quantile.GetQuantile(),
+				Value:    quantile.GetValue(),
+			})
+		}
+
+		dataPoints = append(dataPoints, metricdata.SummaryDataPoint{
+			Attributes:     convertLabels(metric.GetLabel()),
+			StartTime:      startTime,
+			Time:           getTimestamp(metric, now),
+			Count:          summary.GetSampleCount(),
+			Sum:            summary.GetSampleSum(),
+			QuantileValues: quantiles,
+		})
+	}
+
+	return metricdata.Summary{DataPoints: dataPoints}
+}
+
+func convertLabels(labels []*dto.LabelPair) attribute.Set {
+	attributes := make([]attribute.KeyValue, 0, len(labels))
+	for _, label := range labels {
+		attributes = append(attributes, attribute.String(label.GetName(), label.GetValue()))
+	}
+	return attribute.NewSet(attributes...)
+}
+
+func getTimestamp(metric *dto.Metric, now time.Time) time.Time {
+	if metric.TimestampMs == nil {
+		return now
+	}
+	return time.UnixMilli(metric.GetTimestampMs())
+}
@@ -0,0 +1,127 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package otlp
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Prometheus to OTLP conversion", func() {
+	var registry *prometheus.Registry
+	startTime := time.Now().Add(-time.Hour)
+	now := time.Now()
+
+	BeforeEach(func() {
+		registry = prometheus.NewRegistry()
+	})
+
+	convert := func() map[string]metricdata.Metrics {
+		families, err := registry.Gather()
+		Expect(err).ToNot(HaveOccurred())
+		result := make(map[string]metricdata.Metrics)
+		for _, metric := range convertMetricFamilies(families, startTime, now) {
+			result[metric.Name] = metric
+		}
+		return result
+	}
+
+	It("converts counters to cumulative monotonic sums", func() {
+		counter := prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "test_total",
+			Help: "a counter",
+		}, []string{"datname"})
+		registry.MustRegister(counter)
+		counter.WithLabelValues("app").Add(3)
+
+		metrics := convert()
+		Expect(metrics).To(HaveKey("test_total"))
+		Expect(metrics["test_total"].Description).To(Equal("a counter"))
+		sum, ok := metrics["test_total"].Data.(metricdata.Sum[float64])
+		Expect(ok).To(BeTrue())
+		Expect(sum.IsMonotonic).To(BeTrue())
+		Expect(sum.Temporality).To(Equal(metricdata.CumulativeTemporality))
+		Expect(sum.DataPoints).To(HaveLen(1))
+		Expect(sum.DataPoints[0].Value).To(BeEquivalentTo(3))
+		Expect(sum.DataPoints[0].StartTime).To(Equal(startTime))
+		expectedAttributes := attribute.NewSet(attribute.String("datname", "app"))
+		Expect(sum.DataPoints[0].Attributes.Equals(&expectedAttributes)).To(BeTrue())
+	})
+
+	It("converts gauges and untyped metrics to gauges", func() {
+		gauge := prometheus.NewGauge(prometheus.GaugeOpts{Name: "test_gauge"})
+		registry.MustRegister(gauge)
+		gauge.Set(42)
+		untyped := prometheus.NewUntypedFunc(prometheus.UntypedOpts{Name: "test_untyped"}, func() float64 {
+			return 7
+		})
+		registry.MustRegister(untyped)
+
+		metrics := convert()
+		Expect(metrics["test_gauge"].Data).To(BeAssignableToTypeOf(metricdata.Gauge[float64]{}))
+		Expect(metrics["test_gauge"].Data.(metricdata.Gauge[float64]).DataPoints[0].Value).To(BeEquivalentTo(42))
+		Expect(metrics["test_untyped"].Data.(metricdata.Gauge[float64]).DataPoints[0].Value).To(BeEquivalentTo(7))
+	})
+
+	It("converts cumulative Prometheus buckets to per-bucket counts", func() {
+		histogram := prometheus.NewHistogram(prometheus.HistogramOpts{
+			Name:    "test_duration_seconds",
+			Buckets: []float64{1, 5, 10},
+		})
+		registry.MustRegister(histogram)
+		for _, value := range []float64{0.5, 0.7, 3, 7, 20, 30} {
+			histogram.Observe(value)
+		}
+
+		metrics := convert()
+		data, ok := metrics["test_duration_seconds"].Data.(metricdata.Histogram[float64])
+		Expect(ok).To(BeTrue())
+		Expect(data.DataPoints).To(HaveLen(1))
+		dataPoint := data.DataPoints[0]
+		Expect(dataPoint.Count).To(BeEquivalentTo(6))
+		Expect(dataPoint.Sum).To(BeNumerically("~", 61.2))
+		Expect(dataPoint.Bounds).To(Equal([]float64{1, 5, 10}))
+		Expect(dataPoint.BucketCounts).To(Equal([]uint64{2, 1, 1, 2}))
+	})
+
+	It("converts summaries", func() {
+		summary := prometheus.NewSummary(prometheus.SummaryOpts{
+			Name:       "test_summary",
+			Objectives: map[float64]float64{0.5: 0.05},
+		})
+		registry.MustRegister(summary)
+		summary.Observe(1)
+		summary.Observe(3)
+
+		metrics := convert()
+		data, ok := metrics["test_summary"].Data.(metricdata.Summary)
+		Expect(ok).To(BeTrue())
+		Expect(data.DataPoints[0].Count).To(BeEquivalentTo(2))
+		Expect(data.DataPoints[0].Sum).To(BeEquivalentTo(4))
+		Expect(data.DataPoints[0].QuantileValues).To(HaveLen(1))
+		Expect(data.DataPoints[0].QuantileValues[0].Quantile).To(Equal(0.5))
+	})
+})
@@ -0,0 +1,208 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package otlp
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/cloudnative-pg/machinery/pkg/log"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetrichttp"
+	"go.opentelemetry.io/otel/sdk/instrumentation"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"go.opentelemetry.io/otel/sdk/resource"
+)
+
+const (
+	// DefaultPushInterval is the interval between two pushes
+	// when it is not specified
+	DefaultPushInterval = 30 * time.Second
+
+	// defaultMetricsPath is the path where OTLP collectors receive metrics
+	defaultMetricsPath = "/v1/metrics"
+
+	// scopeName is the instrumentation scope of every pushed metric
+	scopeName = "github.com/cloudnative-pg/cloudnative-pg"
+)
+
+// Resource attributes identifying the source of the pushed metrics
+const (
+	// ClusterAttribute is the name of the cluster
+	ClusterAttribute = attribute.Key("cnpg.cluster")
+
+	// InstanceAttribute is the name of the Pod producing the metrics
+	InstanceAttribute = attribute.Key("cnpg.instance")
+
+	// RoleAttribute is the role of the producer of the metrics: "primary"
+	// or "replica" for PostgreSQL instances, the pooler type for
+	// PgBouncer
+	RoleAttribute = attribute.Key("cnpg.role")
+
+	// NamespaceAttribute is the namespace of the producer
+	NamespaceAttribute = attribute.Key("k8s.namespace.name")
+
+	// PodAttribute is the name of the Pod producing the metrics
+	PodAttribute = attribute.Key("k8s.pod.name")
+
+	// ServiceNameAttribute is the name of the service producing the metrics
+	ServiceNameAttribute = attribute.Key("service.name")
+)
+
+// AttributesFunc computes the resource attributes to be attached to
+// a push. It is invoked before every push, so that attributes that
+// may change over time, such as the role of an instance, are always current
+type AttributesFunc func(ctx context.Context) []attribute.KeyValue
+
+// metricsExporter is the subset of the OTLP exporter used by the pusher
+type metricsExporter interface {
+	Export(ctx context.Context, rm *metricdata.ResourceMetrics) error
+	Shutdown(ctx context.Context) error
+}
+
+// MetricsPusher periodically gathers the metrics from a Prometheus
+// registry and pushes them to an OpenTelemetry collector
+type MetricsPusher struct {
+	gatherer   prometheus.Gatherer
+	exporter   metricsExporter
+	interval   time.Duration
+	attributes AttributesFunc
+	startTime  time.Time
+}
+
+// NewMetricsPusher creates a new pusher sending the metrics collected by
+// the passed gatherer to the collector listening at the passed endpoint,
+// which must be an http or https URL. When the URL has no path,
+// the standard "/v1/metrics" one is used
+func NewMetricsPusher(
+	ctx context.Context,
+	gatherer prometheus.Gatherer,
+	endpoint string,
+	interval time.Duration,
+	attributes AttributesFunc,
+) (*MetricsPusher, error) {
+	options, err := buildExporterOptions(endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	exporter, err := otlpmetrichttp.New(ctx, options...)
+	if err != nil {
+		return nil, fmt.Errorf("while creating the OTLP metrics exporter: %w", err)
+	}
+
+	if interval <= 0 {
+		interval = DefaultPushInterval
+	}
+
+	return &MetricsPusher{
+		gatherer:   gatherer,
+		exporter:   exporter,
+		interval:   interval,
+		attributes: attributes,
+		startTime:  time.Now(),
+	}, nil
+}
+
+func buildExporterOptions(endpoint string) ([]otlpmetrichttp.Option, error) {
+	endpointURL, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("while parsing the OTLP endpoint: %w", err)
+	}
+
+	options := []otlpmetrichttp.Option{otlpmetrichttp.WithEndpoint(endpointURL.Host)}
+	switch endpointURL.Scheme {
+	case "http":
+		options = append(options, otlpmetrichttp.WithInsecure())
+	case "https":
+	default:
+		return nil, fmt.Errorf("unsupported OTLP endpoint scheme %q, expected http or https", endpointURL.Scheme)
+	}
+
+	urlPath := endpointURL.Path
+	if urlPath == "" || urlPath == "/" {
+		urlPath = defaultMetricsPath
+	}
+	options = append(options, otlpmetrichttp.WithURLPath(urlPath))
+
+	return options, nil
+}
+
+// Start pushes the metrics at every interval until the context is
+// cancelled. It implements the controller-runtime Runnable interface
+func (pusher *MetricsPusher) Start(ctx context.Context) error {
+	contextLogger := log.FromContext(ctx).WithName("otlp_metrics_pusher")
+
+	ticker := time.NewTicker(pusher.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			// The context is already done, but we still want
+			// to flush what is pending
+			shutdownCtx, cancel := context.WithTimeout(context.Background(), pusher.interval)
+			defer cancel()
+			if err := pusher.exporter.Shutdown(shutdownCtx); err != nil {
+				contextLogger.Warning("Error while shutting down the OTLP metrics exporter", "err", err)
+			}
+			return nil
+
+		case <-ticker.C:
+			if err := pusher.Push(ctx); err != nil {
+				// The collector could be temporarily unavailable, we'll
+				// try again at the next tick
+				contextLogger.Warning("Error while pushing metrics to the OTLP collector", "err", err)
+			}
+		}
+	}
+}
+
+// Push gathers the metrics and sends them to the OTLP collector
+func (pusher *MetricsPusher) Push(ctx context.Context) error {
+	families, err := pusher.gatherer.Gather()
+	if err != nil && len(families) == 0 {
+		return fmt.Errorf("while gathering metrics: %w", err)
+	}
+
+	var attributes []attribute.KeyValue
+	if pusher.attributes != nil {
+		attributes = pusher.attributes(ctx)
+	}
+
+	resourceMetrics := &metricdata.ResourceMetrics{
+		Resource: resource.NewSchemaless(attributes...),
+		ScopeMetrics: []metricdata.ScopeMetrics{
+			{
+				Scope:   instrumentation.Scope{Name: scopeName},
+				Metrics: convertMetricFamilies(families, pusher.startTime, time.Now()),
+			},
+		},
+	}
+
+	if err := pusher.exporter.Export(ctx, resourceMetrics); err != nil {
+		return fmt.Errorf("while exporting metrics: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,126 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package otlp
+
+import (
+	"context"
+	"errors"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+type fakeExporter struct {
+	exported []*metricdata.ResourceMetrics
+	err      error
+	shutdown bool
+}
+
+func (f *fakeExporter) Export(_ context.Context, rm *metricdata.ResourceMetrics) error {
+	if f.err != nil {
+		return f.err
+	}
+	f.exported = append(f.exported, rm)
+	return nil
+}
+
+func (f *fakeExporter) Shutdown(_ context.Context) error {
+	f.shutdown = true
+	return nil
+}
+
+var _ = Describe("MetricsPusher", func() {
+	var (
+		registry *prometheus.Registry
+		exporter *fakeExporter
+		pusher   *MetricsPusher
+		role     string
+	)
+
+	BeforeEach(func() {
+		registry = prometheus.NewRegistry()
+		gauge := prometheus.NewGauge(prometheus.GaugeOpts{Name: "test_gauge"})
+		registry.MustRegister(gauge)
+		exporter = &fakeExporter{}
+		role = "primary"
+		pusher = &MetricsPusher{
+			gatherer: registry,
+			exporter: exporter,
+			interval: DefaultPushInterval,
+			attributes: func(context.Context) []attribute.KeyValue {
+				return []attribute.KeyValue{
+					ClusterAttribute.String("cluster-example"),
+					RoleAttribute.String(role),
+				}
+			},
+		}
+	})
+
+	It("pushes the gathered metrics with the current resource attributes", func(ctx SpecContext) {
+		Expect(pusher.Push(ctx)).To(Succeed())
+		role = "replica"
+		Expect(pusher.Push(ctx)).To(Succeed())
+
+		Expect(exporter.exported).To(HaveLen(2))
+		for idx, expectedRole := range []string{"primary", "replica"} {
+			resourceMetrics := exporter.exported[idx]
+			value, found := resourceMetrics.Resource.Set().Value(RoleAttribute)
+			Expect(found).To(BeTrue())
+			Expect(value.AsString()).To(Equal(expectedRole))
+			Expect(resourceMetrics.ScopeMetrics).To(HaveLen(1))
+			Expect(resourceMetrics.ScopeMetrics[0].Scope.Name).To(Equal(scopeName))
+			Expect(resourceMetrics.ScopeMetrics[0].Metrics).To(HaveLen(1))
+			Expect(resourceMetrics.ScopeMetrics[0].Metrics[0].Name).To(Equal("test_gauge"))
+		}
+	})
+
+	It("reports the exporter errors", func(ctx SpecContext) {
+		exporter.err = errors.New("collector unavailable")
+		Expect(pusher.Push(ctx)).To(MatchError(ContainSubstring("collector unavailable")))
+	})
+
+	It("shuts down the exporter when the context is cancelled", func(ctx SpecContext) {
+		cancelledCtx, cancel := context.WithCancel(ctx)
+		cancel()
+		Expect(pusher.Start(cancelledCtx)).To(Succeed())
+		Expect(exporter.shutdown).To(BeTrue())
+	})
+})
+
+var _ = Describe("OTLP exporter options", func() {
+	It("accepts http and https endpoints", func() {
+		options, err := buildExporterOptions("http://collector:4318")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(options).To(HaveLen(3))
+
+		options, err = buildExporterOptions("https://collector:4318/custom/path")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(options).To(HaveLen(2))
+	})
+
+	It("refuses other schemes", func() {
+		_, err := buildExporterOptions("grpc://collector:4317")
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -82,9 +82,9 @@ const (
 
 	pgBouncerIniTemplateString = `
 [databases]
-* = host={{.Pooler.Spec.Cluster.Name}}-{{.Pooler.Spec.Type}}
+{{ .Databases }}* = host={{.Pooler.Spec.Cluster.Name}}-{{.Pooler.Spec.Type}}
 
-[pgbouncer]
+{{ .Users }}[pgbouncer]
 pool_mode = {{ .Pooler.Spec.PgBouncer.PoolMode }}
 auth_user = {{ .AuthQueryUser }}
 auth_query = {{ .AuthQuery }}
@@ -213,6 +213,8 @@ func BuildConfigurationFiles(pooler *apiv1.Pooler, secrets *Secrets) (Configurat
 		AuthQueryPassword string
 		AuthDBName        string
 		Parameters        string
+		Databases         string
+		Users             string
 		PgHba             []string
 	}{
 		Pooler:            pooler,
@@ -228,7 +230,11 @@ func BuildConfigurationFiles(pooler *apiv1.Pooler, secrets *Secrets) (Configurat
 		// Also, we want the list of parameters inside the PgBouncer configuration
 		// to be stable.
 		Parameters: stringifyPgBouncerParameters(parameters),
-		PgHba:      pooler.Spec.PgBouncer.PgHBA,
+		Databases: stringifyPgBouncerDatabases(
+			fmt.Sprintf("%s-%s", pooler.Spec.Cluster.Name, pooler.Spec.Type),
+			pooler.Spec.PgBouncer.Databases),
+		Users: stringifyPgBouncerUsers(pooler.Spec.PgBouncer.Users),
+		PgHba: pooler.Spec.PgBouncer.PgHBA,
 	}
 
 	if err := pgBouncerIniTemplate.Execute(&pgbouncerIni, templateData); err != nil {
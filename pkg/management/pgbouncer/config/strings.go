@@ -24,6 +24,8 @@ import (
 	"regexp"
 	"sort"
 	"strings"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 )
 
 // stringifyPgBouncerParameters will take map of PgBouncer parameters and emit
@@ -42,6 +44,66 @@ func stringifyPgBouncerParameters(parameters map[string]string) (paramsString st
 	return paramsString
 }
 
+// stringifyPgBouncerDatabases emits the entries of the `[databases]` section
+// for the databases having a specific configuration, all pointing to the passed
+// host. The entries keep the order in which they were declared in the Pooler.
+func stringifyPgBouncerDatabases(host string, databases []apiv1.PgBouncerDatabase) string {
+	var result strings.Builder
+	for _, database := range databases {
+		options := []string{fmt.Sprintf("host=%s", host)}
+		if database.DBName != "" {
+			options = append(options, fmt.Sprintf("dbname=%s", database.DBName))
+		}
+		if database.PoolMode != "" {
+			options = append(options, fmt.Sprintf("pool_mode=%s", database.PoolMode))
+		}
+		if database.PoolSize != nil {
+			options = append(options, fmt.Sprintf("pool_size=%d", *database.PoolSize))
+		}
+		if database.MinPoolSize != nil {
+			options = append(options, fmt.Sprintf("min_pool_size=%d", *database.MinPoolSize))
+		}
+		if database.ReservePool != nil {
+			options = append(options, fmt.Sprintf("reserve_pool=%d", *database.ReservePool))
+		}
+		if database.MaxDBConnections != nil {
+			options = append(options, fmt.Sprintf("max_db_connections=%d", *database.MaxDBConnections))
+		}
+
+		_, _ = fmt.Fprintf(&result, "%s = %s\n",
+			cleanupPgBouncerValue(database.Name),
+			cleanupPgBouncerValue(strings.Join(options, " ")))
+	}
+	return result.String()
+}
+
+// stringifyPgBouncerUsers emits the `[users]` section for the users having
+// a specific configuration, or nothing if there are none
+func stringifyPgBouncerUsers(users []apiv1.PgBouncerUser) string {
+	var result strings.Builder
+	for _, user := range users {
+		var options []string
+		if user.PoolMode != "" {
+			options = append(options, fmt.Sprintf("pool_mode=%s", user.PoolMode))
+		}
+		if user.MaxUserConnections != nil {
+			options = append(options, fmt.Sprintf("max_user_connections=%d", *user.MaxUserConnections))
+		}
+		if len(options) == 0 {
+			continue
+		}
+
+		_, _ = fmt.Fprintf(&result, "%s = %s\n",
+			cleanupPgBouncerValue(user.Name),
+			cleanupPgBouncerValue(strings.Join(options, " ")))
+	}
+
+	if result.Len() == 0 {
+		return ""
+	}
+	return "[users]\n" + result.String() + "\n"
+}
+
 // buildPgBouncerParameters will build a PgBouncer configuration applying any
 // default parameters and forcing any required parameter needed for the
 // controller to work correctly
@@ -20,6 +20,10 @@ SPDX-License-Identifier: Apache-2.0
 package config
 
 import (
+	"k8s.io/utils/ptr"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 )
@@ -48,3 +52,41 @@ var _ = Describe("PgBouncer configuration", func() {
 		Expect(params).NotTo(MatchRegexp("^pid_file.*"))
 	})
 })
+
+var _ = Describe("PgBouncer databases and users", func() {
+	It("emits nothing when there are no specific configurations", func() {
+		Expect(stringifyPgBouncerDatabases("cluster-rw", nil)).To(BeEmpty())
+		Expect(stringifyPgBouncerUsers(nil)).To(BeEmpty())
+	})
+
+	It("emits the databases in the declared order", func() {
+		databases := []apiv1.PgBouncerDatabase{
+			{
+				Name:             "reporting",
+				DBName:           "app",
+				PoolMode:         apiv1.PgBouncerPoolModeSession,
+				PoolSize:         ptr.To(int32(5)),
+				ReservePool:      ptr.To(int32(2)),
+				MaxDBConnections: ptr.To(int32(20)),
+			},
+			{
+				Name:        "app",
+				PoolMode:    apiv1.PgBouncerPoolModeTransaction,
+				MinPoolSize: ptr.To(int32(0)),
+			},
+		}
+		Expect(stringifyPgBouncerDatabases("cluster-rw", databases)).To(Equal(
+			"reporting = host=cluster-rw dbname=app pool_mode=session pool_size=5 reserve_pool=2 " +
+				"max_db_connections=20\n" +
+				"app = host=cluster-rw pool_mode=transaction min_pool_size=0\n"))
+	})
+
+	It("emits the users section", func() {
+		users := []apiv1.PgBouncerUser{
+			{Name: "batch", PoolMode: apiv1.PgBouncerPoolModeSession, MaxUserConnections: ptr.To(int32(10))},
+			{Name: "nothing"},
+		}
+		Expect(stringifyPgBouncerUsers(users)).To(Equal(
+			"[users]\nbatch = pool_mode=session max_user_connections=10\n\n"))
+	})
+})
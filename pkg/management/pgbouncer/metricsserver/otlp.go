@@ -0,0 +1,56 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package metricsserver
+
+import (
+	"context"
+	"os"
+
+	"go.opentelemetry.io/otel/attribute"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/otlp"
+)
+
+// NewOTLPPusher creates a pusher sending the PgBouncer metrics to the
+// OpenTelemetry collector configured in the passed Pooler. Setup must
+// have been invoked before
+func NewOTLPPusher(ctx context.Context, pooler *apiv1.Pooler) (*otlp.MetricsPusher, error) {
+	config := pooler.Spec.Monitoring.OTLP
+
+	// The hostname of a Pod is its name
+	podName, _ := os.Hostname()
+	attributes := []attribute.KeyValue{
+		otlp.ServiceNameAttribute.String("cloudnative-pg-pooler"),
+		otlp.NamespaceAttribute.String(pooler.Namespace),
+		otlp.PodAttribute.String(podName),
+		otlp.ClusterAttribute.String(pooler.Spec.Cluster.Name),
+		otlp.InstanceAttribute.String(podName),
+		otlp.RoleAttribute.String(string(pooler.Spec.Type)),
+	}
+
+	return otlp.NewMetricsPusher(
+		ctx,
+		registry,
+		config.Endpoint,
+		config.GetInterval(),
+		func(context.Context) []attribute.KeyValue { return attributes },
+	)
+}
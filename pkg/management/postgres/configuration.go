@@ -21,6 +21,7 @@ package postgres
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"path"
@@ -331,10 +332,17 @@ var cleanupAutoConfOptions = []string{
 func (instance *Instance) migratePostgresAutoConfFile(ctx context.Context) (changed bool, err error) {
 	contextLogger := log.FromContext(ctx).WithName("migratePostgresAutoConfFile")
 
+	// The configuration file of the disk-full protection must exist
+	// before being included
+	if err := ensureDiskFullConfFile(instance.PgData); err != nil {
+		return false, fmt.Errorf("creating the disk-full configuration file: %w", err)
+	}
+
 	// this is an idempotent operation. Ensures that we always include the override import.
 	// See: #5747
 	if changed, err = configfile.EnsureIncludes(path.Join(instance.PgData, "postgresql.conf"),
-		constants.PostgresqlOverrideConfigurationFile); err != nil {
+		constants.PostgresqlOverrideConfigurationFile,
+		constants.PostgresqlDiskFullConfigurationFile); err != nil {
 		return false, fmt.Errorf("migrating replication settings: %w",
 			err)
 	}
@@ -385,6 +393,25 @@ func (instance *Instance) migratePostgresAutoConfFile(ctx context.Context) (chan
 	return true, nil
 }
 
+// ensureDiskFullConfFile creates the configuration file of the disk-full
+// protection if it doesn't exist. An existing file is never changed, as
+// it's concurrently managed by the disk-full monitor
+func ensureDiskFullConfFile(pgData string) error {
+	file, err := os.OpenFile( // #nosec
+		path.Join(pgData, constants.PostgresqlDiskFullConfigurationFile),
+		os.O_CREATE|os.O_EXCL|os.O_WRONLY,
+		0o600,
+	)
+	if errors.Is(err, os.ErrExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	return file.Close()
+}
+
 // createPostgresqlConfiguration creates the PostgreSQL configuration to be
 // used for this cluster and return it and its sha256 checksum
 func createPostgresqlConfiguration(
@@ -32,6 +32,11 @@ const (
 	// contain HA and DR settings)
 	PostgresqlOverrideConfigurationFile = "override.conf"
 
+	// PostgresqlDiskFullConfigurationFile is the name of the file containing
+	// the PostgreSQL configuration parameters applied by the disk-full
+	// protection, which is managed only by the disk-full monitor
+	PostgresqlDiskFullConfigurationFile = "diskfull.conf"
+
 	// PostgresqlHBARulesFile is the name of the file which contains
 	// the host-based access rules
 	PostgresqlHBARulesFile = "pg_hba.conf"
@@ -268,6 +268,7 @@ func (info InitInfo) CreateDataDirectory() error {
 	_, err = configfile.EnsureIncludes(path.Join(info.PgData, "postgresql.conf"),
 		constants.PostgresqlCustomConfigurationFile,
 		constants.PostgresqlOverrideConfigurationFile,
+		constants.PostgresqlDiskFullConfigurationFile,
 	)
 	if err != nil {
 		return fmt.Errorf("appending inclusion directives to postgresql.conf file resulted in an error: %w", err)
@@ -291,6 +292,15 @@ func (info InitInfo) CreateDataDirectory() error {
 			constants.PostgresqlOverrideConfigurationFile, err)
 	}
 
+	// Create a stub for the configuration file
+	// to be filled by the disk-full monitor
+	err = fileutils.CreateEmptyFile(
+		path.Join(info.PgData, constants.PostgresqlDiskFullConfigurationFile))
+	if err != nil {
+		return fmt.Errorf("creating the operator managed configuration file '%v' resulted in an error: %w",
+			constants.PostgresqlDiskFullConfigurationFile, err)
+	}
+
 	return nil
 }
 
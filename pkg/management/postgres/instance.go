@@ -218,6 +218,29 @@ type Instance struct {
 	// tablespaceSynchronizerChan is used to send tablespace configuration to the tablespace synchronizer
 	tablespaceSynchronizerChan chan map[string]apiv1.TablespaceConfiguration
 
+	// wraparoundMonitorChan is used to send the wraparound protection configuration to the wraparound monitor
+	wraparoundMonitorChan chan *apiv1.WraparoundProtectionConfiguration
+
+	// databaseAges contains the age of the databases, as last computed by the wraparound monitor
+	databaseAges atomic.Pointer[[]postgres.DatabaseAge]
+
+	// diskFullProtectionChan is used to send the disk-full protection configuration to the disk-full monitor
+	diskFullProtectionChan chan *apiv1.DiskFullProtectionConfiguration
+
+	// otlpMetricsChan is used to send the OTLP metrics configuration to the OTLP metrics pusher
+	otlpMetricsChan chan *apiv1.OTLPMetricsConfiguration
+
+	// slowQueryText is how the text of the slow queries is reported
+	// in the logs, as configured in the Cluster
+	slowQueryText atomic.Pointer[apiv1.SlowQueryTextMode]
+
+	// otlpLogsChan is used to send the OTLP logs configuration to the PostgreSQL log writer
+	otlpLogsChan chan *apiv1.OTLPLogsConfiguration
+
+	// diskFullProtectionActive specifies whether the instance is in read-only mode
+	// because its volumes are running out of space
+	diskFullProtectionActive atomic.Bool
+
 	// StatusPortTLS enables TLS on the status port used to communicate with the operator
 	StatusPortTLS bool
 
@@ -345,6 +368,101 @@ func (instance *Instance) RoleSynchronizerChan() <-chan *apiv1.ManagedConfigurat
 	return instance.roleSynchronizerChan
 }
 
+// ConfigureWraparoundMonitor sends the configuration to the wraparound monitor
+func (instance *Instance) ConfigureWraparoundMonitor(config *apiv1.WraparoundProtectionConfiguration) {
+	go func() {
+		instance.wraparoundMonitorChan <- config
+	}()
+}
+
+// WraparoundMonitorChan returns the communication channel to the wraparound monitor
+func (instance *Instance) WraparoundMonitorChan() <-chan *apiv1.WraparoundProtectionConfiguration {
+	return instance.wraparoundMonitorChan
+}
+
+// SetDatabaseAges stores the age of the databases computed by the wraparound monitor
+func (instance *Instance) SetDatabaseAges(ages []postgres.DatabaseAge) {
+	instance.databaseAges.Store(&ages)
+}
+
+// GetDatabaseAges returns the age of the databases, as last computed by
+// the wraparound monitor, or nil if they have not been computed yet
+func (instance *Instance) GetDatabaseAges() []postgres.DatabaseAge {
+	ages := instance.databaseAges.Load()
+	if ages == nil {
+		return nil
+	}
+	return *ages
+}
+
+// ConfigureDiskFullProtection sends the configuration to the disk-full monitor
+func (instance *Instance) ConfigureDiskFullProtection(config *apiv1.DiskFullProtectionConfiguration) {
+	go func() {
+		instance.diskFullProtectionChan <- config
+	}()
+}
+
+// DiskFullProtectionChan returns the communication channel to the disk-full monitor
+func (instance *Instance) DiskFullProtectionChan() <-chan *apiv1.DiskFullProtectionConfiguration {
+	return instance.diskFullProtectionChan
+}
+
+// SetDiskFullProtectionActive records whether the instance is in read-only
+// mode because its volumes are running out of space
+func (instance *Instance) SetDiskFullProtectionActive(active bool) {
+	instance.diskFullProtectionActive.Store(active)
+}
+
+// IsDiskFullProtectionActive checks whether the instance is in read-only
+// mode because its volumes are running out of space
+func (instance *Instance) IsDiskFullProtectionActive() bool {
+	return instance.diskFullProtectionActive.Load()
+}
+
+// ConfigureOTLPMetrics sends the configuration to the OTLP metrics pusher
+func (instance *Instance) ConfigureOTLPMetrics(config *apiv1.OTLPMetricsConfiguration) {
+	go func() {
+		instance.otlpMetricsChan <- config
+	}()
+}
+
+// OTLPMetricsChan returns the communication channel to the OTLP metrics pusher
+func (instance *Instance) OTLPMetricsChan() <-chan *apiv1.OTLPMetricsConfiguration {
+	return instance.otlpMetricsChan
+}
+
+// ConfigureOTLPLogs sends the configuration to the PostgreSQL log writer
+func (instance *Instance) ConfigureOTLPLogs(config *apiv1.OTLPLogsConfiguration) {
+	go func() {
+		instance.otlpLogsChan <- config
+	}()
+}
+
+// OTLPLogsChan returns the communication channel to the PostgreSQL log writer
+func (instance *Instance) OTLPLogsChan() <-chan *apiv1.OTLPLogsConfiguration {
+	return instance.otlpLogsChan
+}
+
+// SetSlowQueryText sets how the text of the slow queries is reported in the logs
+func (instance *Instance) SetSlowQueryText(mode apiv1.SlowQueryTextMode) {
+	instance.slowQueryText.Store(&mode)
+}
+
+// GetSlowQueryText returns how the text of the slow queries is reported
+// in the logs. The text is redacted until the configuration of the
+// Cluster is known, as it could contain sensitive data
+func (instance *Instance) GetSlowQueryText() apiv1.SlowQueryTextMode {
+	mode := instance.slowQueryText.Load()
+	switch {
+	case mode == nil:
+		return apiv1.SlowQueryTextRedacted
+	case *mode == "":
+		return apiv1.SlowQueryTextFull
+	default:
+		return *mode
+	}
+}
+
 // TriggerTablespaceSynchronizer sends the configuration to the tablespace synchronizer
 func (instance *Instance) TriggerTablespaceSynchronizer(config map[string]apiv1.TablespaceConfiguration) {
 	go func() {
@@ -402,6 +520,10 @@ func NewInstance() *Instance {
 		slotsReplicatorChan:        make(chan *apiv1.ReplicationSlotsConfiguration),
 		roleSynchronizerChan:       make(chan *apiv1.ManagedConfiguration),
 		tablespaceSynchronizerChan: make(chan map[string]apiv1.TablespaceConfiguration),
+		wraparoundMonitorChan:      make(chan *apiv1.WraparoundProtectionConfiguration),
+		diskFullProtectionChan:     make(chan *apiv1.DiskFullProtectionConfiguration),
+		otlpMetricsChan:            make(chan *apiv1.OTLPMetricsConfiguration),
+		otlpLogsChan:               make(chan *apiv1.OTLPLogsConfiguration),
 	}
 }
 
@@ -233,6 +233,19 @@ var _ = Describe("check atomic bool", func() {
 	})
 })
 
+var _ = Describe("slow query text mode", func() {
+	It("redacts the slow queries until the configuration is known", func() {
+		instance := NewInstance()
+		Expect(instance.GetSlowQueryText()).To(Equal(apiv1.SlowQueryTextRedacted))
+
+		instance.SetSlowQueryText("")
+		Expect(instance.GetSlowQueryText()).To(Equal(apiv1.SlowQueryTextFull))
+
+		instance.SetSlowQueryText(apiv1.SlowQueryTextNormalized)
+		Expect(instance.GetSlowQueryText()).To(Equal(apiv1.SlowQueryTextNormalized))
+	})
+})
+
 var _ = Describe("ALTER SYSTEM enable and disable in PostgreSQL <17", func() {
 	var instance Instance
 	var autoConfFile string
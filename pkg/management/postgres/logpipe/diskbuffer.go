@@ -0,0 +1,300 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package logpipe
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// diskBufferSegmentMaxSize is the size after which a new segment
+	// of the disk buffer is started
+	diskBufferSegmentMaxSize = 1024 * 1024
+
+	// diskBufferSegmentSuffix is the suffix of the disk buffer segments
+	diskBufferSegmentSuffix = ".jsonl"
+)
+
+// diskBuffer is a bounded, segmented, on-disk FIFO queue of log records.
+// Every segment is a file containing one JSON-encoded record per line.
+// When the maximum size is reached, the oldest segments are discarded
+type diskBuffer struct {
+	mu sync.Mutex
+
+	directory string
+	maxSize   int64
+
+	// segments is the list of the segment names, the oldest first.
+	// The last one is the one being written, if current is not nil
+	segments    []string
+	size        int64
+	current     *os.File
+	currentSize int64
+
+	// droppedRecords is the number of records that were discarded
+	// because the buffer was full
+	droppedRecords int
+}
+
+// newDiskBuffer creates a disk buffer in the passed directory, recovering
+// the segments written by a previous run
+func newDiskBuffer(directory string, maxSize int64) (*diskBuffer, error) {
+	if err := os.MkdirAll(directory, 0o700); err != nil {
+		return nil, fmt.Errorf("while creating the log buffer directory: %w", err)
+	}
+
+	entries, err := os.ReadDir(directory)
+	if err != nil {
+		return nil, fmt.Errorf("while reading the log buffer directory: %w", err)
+	}
+
+	buffer := &diskBuffer{
+		directory: directory,
+		maxSize:   maxSize,
+	}
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), diskBufferSegmentSuffix) {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return nil, err
+		}
+		buffer.segments = append(buffer.segments, entry.Name())
+		buffer.size += info.Size()
+	}
+	// Segment names are zero-padded timestamps, so the lexicographic
+	// order is the chronological one
+	slices.Sort(buffer.segments)
+
+	return buffer, nil
+}
+
+// isEmpty is true when there are no records in the buffer
+func (buffer *diskBuffer) isEmpty() bool {
+	buffer.mu.Lock()
+	defer buffer.mu.Unlock()
+
+	return buffer.size == 0
+}
+
+// getDroppedRecords returns the number of records discarded so far
+func (buffer *diskBuffer) getDroppedRecords() int {
+	buffer.mu.Lock()
+	defer buffer.mu.Unlock()
+
+	return buffer.droppedRecords
+}
+
+// append stores the passed records at the end of the buffer, discarding
+// the oldest segments when there's not enough space
+func (buffer *diskBuffer) append(records []otlpLogRecord) error {
+	if len(records) == 0 {
+		return nil
+	}
+
+	var data bytes.Buffer
+	encoder := json.NewEncoder(&data)
+	for idx := range records {
+		if err := encoder.Encode(&records[idx]); err != nil {
+			return err
+		}
+	}
+
+	buffer.mu.Lock()
+	defer buffer.mu.Unlock()
+
+	dataSize := int64(data.Len())
+	if dataSize > buffer.maxSize {
+		buffer.droppedRecords += len(records)
+		return nil
+	}
+
+	for buffer.size+dataSize > buffer.maxSize && len(buffer.segments) > 0 {
+		if err := buffer.dropOldestSegment(); err != nil {
+			return err
+		}
+	}
+
+	if buffer.current == nil || buffer.currentSize >= diskBufferSegmentMaxSize {
+		if err := buffer.startSegment(); err != nil {
+			return err
+		}
+	}
+
+	written, err := buffer.current.Write(data.Bytes())
+	buffer.currentSize += int64(written)
+	buffer.size += int64(written)
+	return err
+}
+
+// peek returns the name and the records of the oldest segment, that
+// will be kept until removed. An empty name is returned when the buffer
+// is empty
+func (buffer *diskBuffer) peek() (string, []otlpLogRecord, error) {
+	buffer.mu.Lock()
+	defer buffer.mu.Unlock()
+
+	if len(buffer.segments) == 0 {
+		return "", nil, nil
+	}
+
+	// The segment being written needs to be closed before being read,
+	// the next append will start a new one
+	name := buffer.segments[0]
+	if len(buffer.segments) == 1 && buffer.current != nil {
+		if err := buffer.closeSegment(); err != nil {
+			return "", nil, err
+		}
+	}
+
+	records, err := readSegment(filepath.Join(buffer.directory, name))
+	return name, records, err
+}
+
+// remove deletes a segment returned by peek
+func (buffer *diskBuffer) remove(name string) error {
+	buffer.mu.Lock()
+	defer buffer.mu.Unlock()
+
+	idx := slices.Index(buffer.segments, name)
+	if idx < 0 {
+		// The segment was already discarded to make space for new records
+		return nil
+	}
+
+	return buffer.removeSegment(idx)
+}
+
+// close closes the segment being written, keeping the buffer content
+// on disk for the next run
+func (buffer *diskBuffer) close() error {
+	buffer.mu.Lock()
+	defer buffer.mu.Unlock()
+
+	return buffer.closeSegment()
+}
+
+func (buffer *diskBuffer) startSegment() error {
+	if err := buffer.closeSegment(); err != nil {
+		return err
+	}
+
+	name := fmt.Sprintf("%020d%s", time.Now().UnixNano(), diskBufferSegmentSuffix)
+	if len(buffer.segments) > 0 && name <= buffer.segments[len(buffer.segments)-1] {
+		// Keep the ordering even if the clock went backwards
+		last := strings.TrimSuffix(buffer.segments[len(buffer.segments)-1], diskBufferSegmentSuffix)
+		name = fmt.Sprintf("%s0%s", last, diskBufferSegmentSuffix)
+	}
+
+	file, err := os.OpenFile( //nolint:gosec
+		filepath.Join(buffer.directory, name), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return fmt.Errorf("while creating a log buffer segment: %w", err)
+	}
+
+	buffer.segments = append(buffer.segments, name)
+	buffer.current = file
+	buffer.currentSize = 0
+	return nil
+}
+
+func (buffer *diskBuffer) closeSegment() error {
+	if buffer.current == nil {
+		return nil
+	}
+
+	err := buffer.current.Close()
+	buffer.current = nil
+	buffer.currentSize = 0
+	return err
+}
+
+func (buffer *diskBuffer) dropOldestSegment() error {
+	records, err := readSegment(filepath.Join(buffer.directory, buffer.segments[0]))
+	if err != nil {
+		return err
+	}
+	buffer.droppedRecords += len(records)
+
+	if len(buffer.segments) == 1 {
+		if err := buffer.closeSegment(); err != nil {
+			return err
+		}
+	}
+
+	return buffer.removeSegment(0)
+}
+
+func (buffer *diskBuffer) removeSegment(idx int) error {
+	fileName := filepath.Join(buffer.directory, buffer.segments[idx])
+	info, err := os.Stat(fileName)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(fileName); err != nil {
+		return err
+	}
+
+	buffer.size -= info.Size()
+	buffer.segments = slices.Delete(buffer.segments, idx, idx+1)
+	return nil
+}
+
+// readSegment decodes the records stored in a segment, skipping
+// the lines that cannot be decoded
+func readSegment(fileName string) ([]otlpLogRecord, error) {
+	file, err := os.Open(fileName) //nolint:gosec
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	var records []otlpLogRecord
+	reader := bufio.NewReader(file)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if len(line) > 0 {
+			var record otlpLogRecord
+			if json.Unmarshal(line, &record) == nil {
+				records = append(records, record)
+			}
+		}
+		if errors.Is(err, io.EOF) {
+			return records, nil
+		}
+		if err != nil {
+			return records, err
+		}
+	}
+}
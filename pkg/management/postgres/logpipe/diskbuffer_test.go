@@ -0,0 +1,115 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package logpipe
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Disk buffer", func() {
+	var directory string
+
+	newRecords := func(bodies ...string) []otlpLogRecord {
+		records := make([]otlpLogRecord, 0, len(bodies))
+		for _, body := range bodies {
+			records = append(records, otlpLogRecord{Timestamp: time.Now(), Body: body})
+		}
+		return records
+	}
+
+	BeforeEach(func() {
+		directory = GinkgoT().TempDir()
+	})
+
+	It("returns the records in the order they were stored", func() {
+		buffer, err := newDiskBuffer(directory, 1024*1024)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(buffer.isEmpty()).To(BeTrue())
+
+		Expect(buffer.append(newRecords("one", "two"))).To(Succeed())
+		Expect(buffer.append(newRecords("three"))).To(Succeed())
+		Expect(buffer.isEmpty()).To(BeFalse())
+
+		name, records, err := buffer.peek()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(records).To(HaveLen(3))
+		Expect(records[0].Body).To(Equal("one"))
+		Expect(records[2].Body).To(Equal("three"))
+
+		// New records go into a new segment, once the current one is read
+		Expect(buffer.append(newRecords("four"))).To(Succeed())
+		Expect(buffer.remove(name)).To(Succeed())
+
+		name, records, err = buffer.peek()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(records).To(HaveLen(1))
+		Expect(records[0].Body).To(Equal("four"))
+		Expect(buffer.remove(name)).To(Succeed())
+		Expect(buffer.isEmpty()).To(BeTrue())
+
+		name, _, err = buffer.peek()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(name).To(BeEmpty())
+	})
+
+	It("discards the oldest segments when full", func() {
+		buffer, err := newDiskBuffer(directory, 300)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(buffer.append(newRecords("first"))).To(Succeed())
+		_, _, err = buffer.peek()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(buffer.append(newRecords("second"))).To(Succeed())
+		Expect(buffer.append(newRecords("third"))).To(Succeed())
+
+		Expect(buffer.getDroppedRecords()).To(Equal(1))
+		_, records, err := buffer.peek()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(records).ToNot(BeEmpty())
+		Expect(records[0].Body).To(Equal("second"))
+	})
+
+	It("discards the records bigger than the buffer", func() {
+		buffer, err := newDiskBuffer(directory, 10)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(buffer.append(newRecords("too big to fit"))).To(Succeed())
+		Expect(buffer.isEmpty()).To(BeTrue())
+		Expect(buffer.getDroppedRecords()).To(Equal(1))
+	})
+
+	It("recovers the content written by a previous run", func() {
+		buffer, err := newDiskBuffer(directory, 1024*1024)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(buffer.append(newRecords("one"))).To(Succeed())
+		Expect(buffer.close()).To(Succeed())
+
+		buffer, err = newDiskBuffer(directory, 1024*1024)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(buffer.isEmpty()).To(BeFalse())
+		_, records, err := buffer.peek()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(records).To(HaveLen(1))
+		Expect(records[0].Body).To(Equal("one"))
+	})
+})
@@ -45,6 +45,7 @@ type LogPipe struct {
 	fileName        string
 	record          CSVRecordParser
 	fieldsValidator FieldsValidator
+	writer          RecordWriter
 
 	initialized *concurrency.Executed
 	exited      *concurrency.Executed
@@ -60,14 +61,29 @@ type FieldsValidator func(int) *ErrFieldCountExtended
 func NewLogPipe() *LogPipe {
 	return &LogPipe{
 		fileName:        filepath.Join(postgres.LogPath, postgres.LogFileName+".csv"),
-		record:          NewPgAuditLoggingDecorator(),
+		record:          NewSlowQueryLoggingDecorator(NewPgAuditLoggingDecorator(), getFullQueryTextMode, nil),
 		fieldsValidator: LogFieldValidator,
+		writer:          &LogRecordWriter{},
 
 		initialized: concurrency.NewExecuted(),
 		exited:      concurrency.NewExecuted(),
 	}
 }
 
+// WithRecordWriter sets the writer receiving the parsed records, which
+// are written to the instance manager log by default
+func (p *LogPipe) WithRecordWriter(writer RecordWriter) *LogPipe {
+	p.writer = writer
+	return p
+}
+
+// WithSlowQueries configures the function returning how the text of the
+// slow queries is reported, and the observer receiving their duration
+func (p *LogPipe) WithSlowQueries(getTextMode func() QueryTextMode, observer SlowQueryObserver) *LogPipe {
+	p.record = NewSlowQueryLoggingDecorator(NewPgAuditLoggingDecorator(), getTextMode, observer)
+	return p
+}
+
 // GetInitializedCondition returns the condition that can be checked in order to
 // be sure initialization has been done
 func (p *LogPipe) GetInitializedCondition() *concurrency.Executed {
@@ -151,7 +167,7 @@ func (p *LogPipe) collectLogsFromFile(ctx context.Context) error {
 	// the cancellation signal happened
 	go func() {
 		defer close(errChan)
-		errChan <- p.streamLogFromCSVFile(ctx, f, &LogRecordWriter{})
+		errChan <- p.streamLogFromCSVFile(ctx, f, p.writer)
 	}()
 	select {
 	case <-ctx.Done():
@@ -0,0 +1,217 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package logpipe
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var queryParameterRegex = regexp.MustCompile(`\$([0-9]+)`)
+
+// NormalizeQuery replaces the constants of a query, like strings and
+// numbers, with parameter placeholders. The numbering of the placeholders
+// starts after the highest parameter already in the query, as
+// pg_stat_statements does
+func NormalizeQuery(query string) string {
+	nextParameter := 1
+	for _, match := range queryParameterRegex.FindAllStringSubmatch(query, -1) {
+		if value, err := strconv.Atoi(match[1]); err == nil && value >= nextParameter {
+			nextParameter = value + 1
+		}
+	}
+
+	var result strings.Builder
+	result.Grow(len(query))
+	placeholder := func() {
+		result.WriteByte('$')
+		result.WriteString(strconv.Itoa(nextParameter))
+		nextParameter++
+	}
+
+	for i := 0; i < len(query); {
+		c := query[i]
+		switch {
+		// Comments are copied as they are
+		case c == '-' && strings.HasPrefix(query[i:], "--"):
+			end := strings.IndexByte(query[i:], '\n')
+			if end < 0 {
+				end = len(query) - i
+			}
+			result.WriteString(query[i : i+end])
+			i += end
+
+		case c == '/' && strings.HasPrefix(query[i:], "/*"):
+			end := strings.Index(query[i+2:], "*/")
+			if end < 0 {
+				end = len(query) - i
+			} else {
+				end += 4
+			}
+			result.WriteString(query[i : i+end])
+			i += end
+
+		// Quoted identifiers are copied as they are
+		case c == '"':
+			end := skipQuoted(query, i, '"', false)
+			result.WriteString(query[i:end])
+			i = end
+
+		// String constants, including the ones with a prefix
+		// like E'', B'', X'' and U&''
+		case c == '\'':
+			i = skipQuoted(query, i, '\'', false)
+			placeholder()
+
+		case isStringPrefix(query, i):
+			quote := strings.IndexByte(query[i:], '\'') + i
+			escapes := c == 'e' || c == 'E'
+			i = skipQuoted(query, quote, '\'', escapes)
+			placeholder()
+
+		// Dollar-quoted strings, while $1 is a parameter
+		case c == '$' && i+1 < len(query) && !isDigit(query[i+1]):
+			tagEnd := strings.IndexByte(query[i+1:], '$')
+			tag := ""
+			if tagEnd >= 0 {
+				tag = query[i : i+tagEnd+2]
+			}
+			if tag == "" || !isDollarTag(tag[1:len(tag)-1]) {
+				result.WriteByte(c)
+				i++
+				continue
+			}
+			end := strings.Index(query[i+len(tag):], tag)
+			if end < 0 {
+				i = len(query)
+			} else {
+				i += len(tag) + end + len(tag)
+			}
+			placeholder()
+
+		case c == '$':
+			end := i + 1
+			for end < len(query) && isDigit(query[end]) {
+				end++
+			}
+			result.WriteString(query[i:end])
+			i = end
+
+		// Numbers that are not part of identifiers
+		case isDigit(c) || (c == '.' && i+1 < len(query) && isDigit(query[i+1])):
+			if i > 0 && isIdentifierChar(query[i-1]) {
+				result.WriteByte(c)
+				i++
+				continue
+			}
+			i = skipNumber(query, i)
+			placeholder()
+
+		// Identifiers and keywords
+		case isIdentifierChar(c):
+			end := i
+			for end < len(query) && isIdentifierChar(query[end]) {
+				end++
+			}
+			result.WriteString(query[i:end])
+			i = end
+
+		default:
+			result.WriteByte(c)
+			i++
+		}
+	}
+
+	return result.String()
+}
+
+// skipQuoted returns the position after the quoted section starting at
+// the passed position, considering doubled quotes and, if required,
+// backslash escapes
+func skipQuoted(query string, start int, quote byte, escapes bool) int {
+	for i := start + 1; i < len(query); i++ {
+		switch {
+		case escapes && query[i] == '\\':
+			i++
+		case query[i] == quote:
+			if i+1 < len(query) && query[i+1] == quote {
+				i++
+				continue
+			}
+			return i + 1
+		}
+	}
+	return len(query)
+}
+
+// skipNumber returns the position after the numeric constant
+// starting at the passed position
+func skipNumber(query string, start int) int {
+	i := start
+	for i < len(query) && (isDigit(query[i]) || query[i] == '.' || query[i] == '_') {
+		i++
+	}
+	if i < len(query) && (query[i] == 'e' || query[i] == 'E') {
+		next := i + 1
+		if next < len(query) && (query[next] == '+' || query[next] == '-') {
+			next++
+		}
+		if next < len(query) && isDigit(query[next]) {
+			i = next
+			for i < len(query) && isDigit(query[i]) {
+				i++
+			}
+		}
+	}
+	return i
+}
+
+// isStringPrefix checks if a string constant with a prefix,
+// like E'...' or U&'...', starts at the passed position
+func isStringPrefix(query string, i int) bool {
+	if i > 0 && isIdentifierChar(query[i-1]) {
+		return false
+	}
+	rest := query[i:]
+	for _, prefix := range []string{"E'", "e'", "B'", "b'", "X'", "x'", "U&'", "u&'", "N'", "n'"} {
+		if strings.HasPrefix(rest, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func isDollarTag(tag string) bool {
+	for i := 0; i < len(tag); i++ {
+		if !isIdentifierChar(tag[i]) || tag[i] == '$' || (i == 0 && isDigit(tag[i])) {
+			return false
+		}
+	}
+	return true
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isIdentifierChar(c byte) bool {
+	return c == '_' || c == '$' || isDigit(c) || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || c >= 0x80
+}
@@ -0,0 +1,171 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package logpipe
+
+import (
+	"context"
+	"reflect"
+	"sync"
+
+	"github.com/cloudnative-pg/machinery/pkg/log"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+)
+
+// ConfigurableRecordWriter implements the `RecordWriter` interface
+// writing the records to the instance manager log and exporting them to
+// the OpenTelemetry collector configured in the Cluster, if any. The
+// configuration is received from the instance reconciler, so that
+// changing it doesn't require restarting the instance. Until the first
+// configuration is received, the records are only written to the log
+type ConfigurableRecordWriter struct {
+	configChan <-chan *apiv1.OTLPLogsConfiguration
+
+	// options contains the settings of the OTLP writer not coming
+	// from the Cluster, such as the buffer directory and the attributes
+	options OTLPWriterOptions
+
+	// stdout is the writer used when the records are not
+	// only exported to the collector
+	stdout RecordWriter
+
+	mu     sync.RWMutex
+	writer RecordWriter
+}
+
+// NewConfigurableRecordWriter creates a new writer receiving the
+// OTLP configuration from the passed channel
+func NewConfigurableRecordWriter(
+	configChan <-chan *apiv1.OTLPLogsConfiguration,
+	options OTLPWriterOptions,
+) *ConfigurableRecordWriter {
+	return &ConfigurableRecordWriter{
+		configChan: configChan,
+		options:    options,
+		stdout:     &LogRecordWriter{},
+	}
+}
+
+// Write writes the PostgreSQL log record to the currently
+// configured writers
+func (writer *ConfigurableRecordWriter) Write(record NamedRecord) {
+	writer.mu.RLock()
+	defer writer.mu.RUnlock()
+
+	if writer.writer == nil {
+		writer.stdout.Write(record)
+		return
+	}
+	writer.writer.Write(record)
+}
+
+// setWriter replaces the writer receiving the records, waiting for
+// the records being written to the previous one
+func (writer *ConfigurableRecordWriter) setWriter(recordWriter RecordWriter) {
+	writer.mu.Lock()
+	defer writer.mu.Unlock()
+
+	writer.writer = recordWriter
+}
+
+// Start applies the received configurations until the context is
+// cancelled. It implements the controller-runtime Runnable interface
+func (writer *ConfigurableRecordWriter) Start(ctx context.Context) error {
+	contextLogger := log.FromContext(ctx).WithName("otlp_log_writer")
+	ctx = log.IntoContext(ctx, contextLogger)
+
+	var config *apiv1.OTLPLogsConfiguration
+	var stopExporting func()
+	defer func() {
+		if stopExporting != nil {
+			stopExporting()
+		}
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+
+		case newConfig := <-writer.configChan:
+			// The configuration is sent at every reconciliation of
+			// the instance, let's act only when it changes
+			if reflect.DeepEqual(config, newConfig) {
+				continue
+			}
+			config = newConfig
+
+			// The records are written to the log while the exporter
+			// is replaced, as the disk buffer can't be shared
+			writer.setWriter(writer.stdout)
+			if stopExporting != nil {
+				stopExporting()
+				stopExporting = nil
+			}
+
+			if config == nil || config.Endpoint == "" {
+				contextLogger.Info("OTLP logs export disabled")
+				continue
+			}
+
+			otlpWriter, err := NewOTLPRecordWriter(ctx, writer.getWriterOptions(config))
+			if err != nil {
+				contextLogger.Error(err, "Unable to create the OTLP logs writer", "endpoint", config.Endpoint)
+				continue
+			}
+
+			contextLogger.Info("Exporting logs to the OTLP collector",
+				"endpoint", config.Endpoint, "protocol", config.GetProtocol(),
+				"disableStdout", config.DisableStdout)
+			stopExporting = startOTLPRecordWriter(ctx, otlpWriter)
+			if config.DisableStdout {
+				writer.setWriter(otlpWriter)
+			} else {
+				writer.setWriter(MultiRecordWriter{writer.stdout, otlpWriter})
+			}
+		}
+	}
+}
+
+// getWriterOptions merges the passed configuration with
+// the options of this writer
+func (writer *ConfigurableRecordWriter) getWriterOptions(config *apiv1.OTLPLogsConfiguration) OTLPWriterOptions {
+	options := writer.options
+	options.Endpoint = config.Endpoint
+	options.Protocol = string(config.GetProtocol())
+	options.BufferSize = config.GetBufferSize()
+	return options
+}
+
+// startOTLPRecordWriter runs the passed writer in the background, returning
+// a function stopping it and waiting for the pending records to be stored
+func startOTLPRecordWriter(ctx context.Context, otlpWriter *OTLPRecordWriter) func() {
+	writerCtx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_ = otlpWriter.Start(writerCtx)
+	}()
+
+	return func() {
+		cancel()
+		<-done
+	}
+}
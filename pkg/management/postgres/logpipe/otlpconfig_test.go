@@ -0,0 +1,112 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package logpipe
+
+import (
+	"context"
+	"sync"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+type countingRecordWriter struct {
+	mu      sync.Mutex
+	records int
+}
+
+func (writer *countingRecordWriter) Write(NamedRecord) {
+	writer.mu.Lock()
+	defer writer.mu.Unlock()
+	writer.records++
+}
+
+func (writer *countingRecordWriter) count() int {
+	writer.mu.Lock()
+	defer writer.mu.Unlock()
+	return writer.records
+}
+
+var _ = Describe("configurable record writer", func() {
+	var (
+		configChan chan *apiv1.OTLPLogsConfiguration
+		stdout     *countingRecordWriter
+		writer     *ConfigurableRecordWriter
+	)
+
+	BeforeEach(func() {
+		configChan = make(chan *apiv1.OTLPLogsConfiguration)
+		stdout = &countingRecordWriter{}
+		writer = NewConfigurableRecordWriter(configChan, OTLPWriterOptions{
+			BufferDirectory: GinkgoT().TempDir(),
+		})
+		writer.stdout = stdout
+
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			_ = writer.Start(ctx)
+		}()
+		DeferCleanup(func() {
+			cancel()
+			<-done
+		})
+	})
+
+	It("writes to the log until the configuration is received", func() {
+		writer.Write(&LoggingRecord{Message: "test"})
+		Expect(stdout.count()).To(Equal(1))
+	})
+
+	It("follows the changes of the configuration", func() {
+		configChan <- &apiv1.OTLPLogsConfiguration{
+			Endpoint:      "http://otel-collector:4317",
+			DisableStdout: true,
+		}
+		Eventually(func(g Gomega) {
+			writer.mu.RLock()
+			defer writer.mu.RUnlock()
+			g.Expect(writer.writer).To(BeAssignableToTypeOf(&OTLPRecordWriter{}))
+		}).Should(Succeed())
+		writer.Write(&LoggingRecord{Message: "test"})
+		Expect(stdout.count()).To(BeZero())
+
+		configChan <- &apiv1.OTLPLogsConfiguration{
+			Endpoint: "http://otel-collector:4317",
+		}
+		Eventually(func(g Gomega) {
+			writer.mu.RLock()
+			defer writer.mu.RUnlock()
+			g.Expect(writer.writer).To(BeAssignableToTypeOf(MultiRecordWriter{}))
+		}).Should(Succeed())
+		writer.Write(&LoggingRecord{Message: "test"})
+		Expect(stdout.count()).To(Equal(1))
+
+		configChan <- nil
+		Eventually(func(g Gomega) {
+			writer.mu.RLock()
+			defer writer.mu.RUnlock()
+			g.Expect(writer.writer).To(BeIdenticalTo(stdout))
+		}).Should(Succeed())
+	})
+})
@@ -0,0 +1,498 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package logpipe
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/cloudnative-pg/machinery/pkg/log"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlplog/otlploggrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlplog/otlploghttp"
+	otellog "go.opentelemetry.io/otel/log"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+	"go.opentelemetry.io/otel/sdk/resource"
+)
+
+const (
+	// OTLPProtocolGRPC exports the log records using OTLP over gRPC
+	OTLPProtocolGRPC = "grpc"
+
+	// OTLPProtocolHTTP exports the log records using OTLP over HTTP
+	OTLPProtocolHTTP = "http"
+
+	// defaultOTLPBatchSize is the maximum number of records exported at once
+	defaultOTLPBatchSize = 512
+
+	// defaultOTLPFlushInterval is the maximum time a record waits
+	// before being exported
+	defaultOTLPFlushInterval = 5 * time.Second
+
+	// maxReplayedSegments is the maximum number of disk buffer segments
+	// replayed at every flush interval, to give room to the fresh records
+	maxReplayedSegments = 8
+
+	// otlpLogTimeLayout is the layout of the log_time field in the CSV logs
+	otlpLogTimeLayout = "2006-01-02 15:04:05.999 MST"
+
+	// otlpScopeName is the instrumentation scope of every exported record
+	otlpScopeName = "github.com/cloudnative-pg/cloudnative-pg/logpipe"
+)
+
+// OTLPDroppedRecordsTotal counts the log records discarded because
+// the OTLP disk buffer was full
+var OTLPDroppedRecordsTotal = prometheus.NewCounter(prometheus.CounterOpts{
+	Namespace: "cnpg",
+	Subsystem: "otlp",
+	Name:      "logs_dropped_total",
+	Help:      "Total number of log records discarded because the OTLP disk buffer was full.",
+})
+
+// OTLPWriterOptions is the configuration of an OTLPRecordWriter
+type OTLPWriterOptions struct {
+	// Endpoint is the http or https URL of the collector
+	Endpoint string
+
+	// Protocol is either OTLPProtocolGRPC or OTLPProtocolHTTP
+	Protocol string
+
+	// BufferDirectory is where the records are stored while
+	// the collector is not available
+	BufferDirectory string
+
+	// BufferSize is the maximum size, in bytes, of the disk buffer
+	BufferSize int64
+
+	// Attributes are the resource attributes identifying the instance
+	Attributes []attribute.KeyValue
+
+	// BatchSize is the maximum number of records exported at once.
+	// Defaults to 512
+	BatchSize int
+
+	// FlushInterval is the maximum time a record is kept in memory
+	// before being exported. Defaults to 5 seconds
+	FlushInterval time.Duration
+}
+
+// otlpLogRecord is the representation of a log record waiting to be
+// exported, both in memory and in the disk buffer
+type otlpLogRecord struct {
+	Timestamp         time.Time         `json:"timestamp"`
+	ObservedTimestamp time.Time         `json:"observed_timestamp"`
+	Severity          otellog.Severity  `json:"severity,omitempty"`
+	SeverityText      string            `json:"severity_text,omitempty"`
+	Body              string            `json:"body,omitempty"`
+	Attributes        map[string]string `json:"attributes,omitempty"`
+}
+
+// OTLPRecordWriter implements the `RecordWriter` interface exporting the
+// records as OpenTelemetry log records. Records are exported in batches
+// by a background loop, that needs to be started with Start. When the
+// in-memory queue is full or the collector is not reachable, the records
+// are stored in a bounded disk buffer and exported later, discarding the
+// oldest ones when the buffer is full
+type OTLPRecordWriter struct {
+	exporter  sdklog.Exporter
+	provider  *sdklog.LoggerProvider
+	logger    otellog.Logger
+	collector *recordCollector
+
+	queue         chan otlpLogRecord
+	buffer        *diskBuffer
+	batchSize     int
+	flushInterval time.Duration
+
+	// reportedDroppedRecords is the number of records discarded
+	// by the disk buffer which have already been reported
+	reportedDroppedRecords int
+}
+
+// recordCollector is a log processor collecting the emitted records,
+// so that they can be exported in batches
+type recordCollector struct {
+	records []sdklog.Record
+}
+
+// OnEmit implements the sdklog.Processor interface
+func (collector *recordCollector) OnEmit(_ context.Context, record *sdklog.Record) error {
+	collector.records = append(collector.records, record.Clone())
+	return nil
+}
+
+// Shutdown implements the sdklog.Processor interface
+func (collector *recordCollector) Shutdown(context.Context) error {
+	return nil
+}
+
+// ForceFlush implements the sdklog.Processor interface
+func (collector *recordCollector) ForceFlush(context.Context) error {
+	return nil
+}
+
+// NewOTLPRecordWriter creates a new writer exporting the records to
+// an OpenTelemetry collector
+func NewOTLPRecordWriter(ctx context.Context, options OTLPWriterOptions) (*OTLPRecordWriter, error) {
+	exporter, err := newOTLPLogExporter(ctx, options.Endpoint, options.Protocol)
+	if err != nil {
+		return nil, err
+	}
+
+	return newOTLPRecordWriterWithExporter(exporter, options)
+}
+
+func newOTLPRecordWriterWithExporter(
+	exporter sdklog.Exporter,
+	options OTLPWriterOptions,
+) (*OTLPRecordWriter, error) {
+	buffer, err := newDiskBuffer(options.BufferDirectory, options.BufferSize)
+	if err != nil {
+		return nil, err
+	}
+
+	if options.BatchSize <= 0 {
+		options.BatchSize = defaultOTLPBatchSize
+	}
+	if options.FlushInterval <= 0 {
+		options.FlushInterval = defaultOTLPFlushInterval
+	}
+
+	collector := &recordCollector{}
+	provider := sdklog.NewLoggerProvider(
+		sdklog.WithResource(resource.NewSchemaless(options.Attributes...)),
+		sdklog.WithProcessor(collector),
+	)
+
+	return &OTLPRecordWriter{
+		exporter:      exporter,
+		provider:      provider,
+		logger:        provider.Logger(otlpScopeName),
+		collector:     collector,
+		queue:         make(chan otlpLogRecord, 4*options.BatchSize),
+		buffer:        buffer,
+		batchSize:     options.BatchSize,
+		flushInterval: options.FlushInterval,
+	}, nil
+}
+
+func newOTLPLogExporter(ctx context.Context, endpoint, protocol string) (sdklog.Exporter, error) {
+	endpointURL, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("while parsing the OTLP endpoint: %w", err)
+	}
+	if endpointURL.Scheme != "http" && endpointURL.Scheme != "https" {
+		return nil, fmt.Errorf("unsupported OTLP endpoint scheme %q, expected http or https", endpointURL.Scheme)
+	}
+	insecure := endpointURL.Scheme == "http"
+
+	// Retries are handled by the writer using the disk buffer
+	switch protocol {
+	case OTLPProtocolGRPC, "":
+		options := []otlploggrpc.Option{
+			otlploggrpc.WithEndpoint(endpointURL.Host),
+			otlploggrpc.WithRetry(otlploggrpc.RetryConfig{Enabled: false}),
+		}
+		if insecure {
+			options = append(options, otlploggrpc.WithInsecure())
+		}
+		return otlploggrpc.New(ctx, options...)
+
+	case OTLPProtocolHTTP:
+		options := []otlploghttp.Option{
+			otlploghttp.WithEndpoint(endpointURL.Host),
+			otlploghttp.WithRetry(otlploghttp.RetryConfig{Enabled: false}),
+		}
+		if insecure {
+			options = append(options, otlploghttp.WithInsecure())
+		}
+		if endpointURL.Path != "" && endpointURL.Path != "/" {
+			options = append(options, otlploghttp.WithURLPath(endpointURL.Path))
+		}
+		return otlploghttp.New(ctx, options...)
+
+	default:
+		return nil, fmt.Errorf("unsupported OTLP protocol %q", protocol)
+	}
+}
+
+// Write queues the PostgreSQL log record to be exported. It never
+// blocks waiting for the collector: when the queue is full the record
+// is stored in the disk buffer
+func (writer *OTLPRecordWriter) Write(record NamedRecord) {
+	// The passed record is reused by the caller, so it must be
+	// converted before returning
+	otlpRecord := newOTLPLogRecord(record)
+
+	select {
+	case writer.queue <- otlpRecord:
+	default:
+		if err := writer.buffer.append([]otlpLogRecord{otlpRecord}); err != nil {
+			log.Error(err, "Error while storing a log record in the OTLP disk buffer")
+		}
+	}
+}
+
+// Start exports the queued records until the context is cancelled.
+// It implements the controller-runtime Runnable interface
+func (writer *OTLPRecordWriter) Start(ctx context.Context) error {
+	contextLogger := log.FromContext(ctx).WithName("otlp_log_writer")
+	ctx = log.IntoContext(ctx, contextLogger)
+
+	ticker := time.NewTicker(writer.flushInterval)
+	defer ticker.Stop()
+
+	batch := make([]otlpLogRecord, 0, writer.batchSize)
+	for {
+		select {
+		case <-ctx.Done():
+			writer.shutdown(contextLogger, batch)
+			return nil
+
+		case record := <-writer.queue:
+			batch = append(batch, record)
+			if len(batch) >= writer.batchSize {
+				writer.flush(ctx, batch)
+				batch = batch[:0]
+			}
+
+		case <-ticker.C:
+			writer.flush(ctx, batch)
+			batch = batch[:0]
+			writer.replay(ctx)
+			writer.reportDroppedRecords(contextLogger)
+		}
+	}
+}
+
+// reportDroppedRecords logs and counts the records discarded by
+// the disk buffer since the last report
+func (writer *OTLPRecordWriter) reportDroppedRecords(contextLogger log.Logger) {
+	dropped := writer.buffer.getDroppedRecords()
+	if dropped <= writer.reportedDroppedRecords {
+		return
+	}
+
+	newlyDropped := dropped - writer.reportedDroppedRecords
+	writer.reportedDroppedRecords = dropped
+	OTLPDroppedRecordsTotal.Add(float64(newlyDropped))
+	contextLogger.Warning("Log records discarded because the OTLP disk buffer is full",
+		"droppedRecords", newlyDropped, "totalDroppedRecords", dropped)
+}
+
+// shutdown stores the pending records in the disk buffer, to be exported
+// by the next run, and releases the exporter
+func (writer *OTLPRecordWriter) shutdown(contextLogger log.Logger, batch []otlpLogRecord) {
+drain:
+	for {
+		select {
+		case record := <-writer.queue:
+			batch = append(batch, record)
+		default:
+			break drain
+		}
+	}
+
+	if err := writer.buffer.append(batch); err != nil {
+		contextLogger.Error(err, "Error while storing the pending log records in the OTLP disk buffer")
+	}
+	if err := writer.buffer.close(); err != nil {
+		contextLogger.Error(err, "Error while closing the OTLP disk buffer")
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), writer.flushInterval)
+	defer cancel()
+	if err := writer.exporter.Shutdown(shutdownCtx); err != nil {
+		contextLogger.Warning("Error while shutting down the OTLP log exporter", "err", err)
+	}
+}
+
+// flush exports a batch of records, storing them in the disk buffer
+// when the collector is not available. To preserve the ordering, fresh
+// records are buffered as long as there are older records in the buffer
+func (writer *OTLPRecordWriter) flush(ctx context.Context, batch []otlpLogRecord) {
+	if len(batch) == 0 {
+		return
+	}
+
+	contextLogger := log.FromContext(ctx)
+	if writer.buffer.isEmpty() {
+		err := writer.export(ctx, batch)
+		if err == nil {
+			return
+		}
+		contextLogger.Warning("Error while exporting log records, storing them in the disk buffer", "err", err)
+	}
+
+	if err := writer.buffer.append(batch); err != nil {
+		contextLogger.Error(err, "Error while storing log records in the OTLP disk buffer")
+	}
+}
+
+// replay exports the records stored in the disk buffer, oldest first
+func (writer *OTLPRecordWriter) replay(ctx context.Context) {
+	contextLogger := log.FromContext(ctx)
+
+	for range maxReplayedSegments {
+		name, records, err := writer.buffer.peek()
+		if name == "" {
+			return
+		}
+		if err != nil {
+			contextLogger.Error(err, "Error while reading a segment of the OTLP disk buffer, discarding it",
+				"segment", name)
+		} else if err := writer.export(ctx, records); err != nil {
+			contextLogger.Debug("Collector still not available, keeping the disk buffer", "err", err)
+			return
+		}
+
+		if err := writer.buffer.remove(name); err != nil {
+			contextLogger.Error(err, "Error while removing a segment of the OTLP disk buffer",
+				"segment", name)
+			return
+		}
+	}
+}
+
+// export sends a batch of records to the collector
+func (writer *OTLPRecordWriter) export(ctx context.Context, records []otlpLogRecord) error {
+	writer.collector.records = writer.collector.records[:0]
+	for idx := range records {
+		writer.logger.Emit(ctx, records[idx].toLogRecord())
+	}
+
+	for start := 0; start < len(writer.collector.records); start += writer.batchSize {
+		end := min(start+writer.batchSize, len(writer.collector.records))
+		if err := writer.exporter.Export(ctx, writer.collector.records[start:end]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// toLogRecord converts the record into an OpenTelemetry one
+func (record *otlpLogRecord) toLogRecord() otellog.Record {
+	var result otellog.Record
+	result.SetTimestamp(record.Timestamp)
+	result.SetObservedTimestamp(record.ObservedTimestamp)
+	result.SetSeverity(record.Severity)
+	result.SetSeverityText(record.SeverityText)
+	result.SetBody(otellog.StringValue(record.Body))
+
+	attributes := make([]otellog.KeyValue, 0, len(record.Attributes))
+	for key, value := range record.Attributes {
+		attributes = append(attributes, otellog.String(key, value))
+	}
+	result.AddAttributes(attributes...)
+
+	return result
+}
+
+// newOTLPLogRecord converts a PostgreSQL log record into the format
+// used for the export. Every non-empty field of the record becomes an
+// attribute, named after its JSON representation, with the nested ones
+// prefixed by the name of the enclosing field, i.e. `audit.statement`.
+// Deeper structures are encoded as JSON
+func newOTLPLogRecord(record NamedRecord) otlpLogRecord {
+	now := time.Now()
+	result := otlpLogRecord{
+		Timestamp:         now,
+		ObservedTimestamp: now,
+		Attributes:        map[string]string{"logger": record.GetName()},
+	}
+
+	data, err := json.Marshal(record)
+	if err != nil {
+		return result
+	}
+	var fields map[string]any
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return result
+	}
+	flattenLogFields("", fields, result.Attributes)
+
+	if logTime, err := time.Parse(otlpLogTimeLayout, result.Attributes["log_time"]); err == nil {
+		result.Timestamp = logTime
+	}
+	result.SeverityText = result.Attributes["error_severity"]
+	result.Severity = getOTLPSeverity(result.SeverityText)
+	result.Body = result.Attributes["message"]
+	if result.Body == "" {
+		result.Body = result.Attributes["audit.statement"]
+	}
+	if result.Body == "" {
+		result.Body = result.Attributes["slow_query.query_text"]
+	}
+	delete(result.Attributes, "message")
+
+	return result
+}
+
+func flattenLogFields(prefix string, fields map[string]any, attributes map[string]string) {
+	for key, value := range fields {
+		switch v := value.(type) {
+		case map[string]any:
+			if prefix == "" {
+				flattenLogFields(key+".", v, attributes)
+				continue
+			}
+			// Deeper structures, like the plans, are kept as JSON
+			if data, err := json.Marshal(v); err == nil {
+				attributes[prefix+key] = string(data)
+			}
+		case string:
+			if v != "" {
+				attributes[prefix+key] = v
+			}
+		case nil:
+		default:
+			attributes[prefix+key] = fmt.Sprint(v)
+		}
+	}
+}
+
+// getOTLPSeverity maps the PostgreSQL message severity levels
+// to the OpenTelemetry ones
+func getOTLPSeverity(errorSeverity string) otellog.Severity {
+	switch strings.ToUpper(errorSeverity) {
+	case "DEBUG5", "DEBUG4", "DEBUG3", "DEBUG2", "DEBUG1", "DEBUG":
+		return otellog.SeverityDebug
+	case "LOG", "INFO":
+		return otellog.SeverityInfo
+	case "NOTICE":
+		return otellog.SeverityInfo2
+	case "WARNING":
+		return otellog.SeverityWarn
+	case "ERROR":
+		return otellog.SeverityError
+	case "FATAL":
+		return otellog.SeverityFatal
+	case "PANIC":
+		return otellog.SeverityFatal2
+	default:
+		return otellog.SeverityUndefined
+	}
+}
@@ -0,0 +1,197 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package logpipe
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/cloudnative-pg/machinery/pkg/log"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"go.opentelemetry.io/otel/attribute"
+	otellog "go.opentelemetry.io/otel/log"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/otlp"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+type fakeLogExporter struct {
+	records []sdklog.Record
+	err     error
+}
+
+func (f *fakeLogExporter) Export(_ context.Context, records []sdklog.Record) error {
+	if f.err != nil {
+		return f.err
+	}
+	for _, record := range records {
+		f.records = append(f.records, record.Clone())
+	}
+	return nil
+}
+
+func (f *fakeLogExporter) Shutdown(context.Context) error {
+	return nil
+}
+
+func (f *fakeLogExporter) ForceFlush(context.Context) error {
+	return nil
+}
+
+var _ = Describe("OTLP record writer", func() {
+	var (
+		exporter *fakeLogExporter
+		writer   *OTLPRecordWriter
+	)
+
+	newLoggingRecord := func(message string) *LoggingRecord {
+		return &LoggingRecord{
+			LogTime:       "2024-01-02 10:11:12.345 UTC",
+			ErrorSeverity: "WARNING",
+			DatabaseName:  "app",
+			Message:       message,
+		}
+	}
+
+	BeforeEach(func() {
+		var err error
+		exporter = &fakeLogExporter{}
+		writer, err = newOTLPRecordWriterWithExporter(exporter, OTLPWriterOptions{
+			BufferDirectory: GinkgoT().TempDir(),
+			BufferSize:      1024 * 1024,
+			Attributes:      []attribute.KeyValue{otlp.ClusterAttribute.String("cluster-example")},
+			BatchSize:       2,
+			FlushInterval:   time.Hour,
+		})
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("converts the logging collector records", func() {
+		record := newOTLPLogRecord(newLoggingRecord("checkpoint starting"))
+		Expect(record.Timestamp).To(Equal(time.Date(2024, 1, 2, 10, 11, 12, 345000000, time.UTC)))
+		Expect(record.Severity).To(Equal(otellog.SeverityWarn))
+		Expect(record.SeverityText).To(Equal("WARNING"))
+		Expect(record.Body).To(Equal("checkpoint starting"))
+		Expect(record.Attributes).To(HaveKeyWithValue("logger", LoggingCollectorRecordName))
+		Expect(record.Attributes).To(HaveKeyWithValue("database_name", "app"))
+		Expect(record.Attributes).ToNot(HaveKey("message"))
+		Expect(record.Attributes).ToNot(HaveKey("user_name"))
+	})
+
+	It("converts the pgaudit records", func() {
+		auditRecord := NewPgAuditLoggingDecorator()
+		auditRecord.LoggingRecord = newLoggingRecord("")
+		auditRecord.Audit = &PgAuditRecord{
+			AuditType: "SESSION",
+			Class:     "READ",
+			Statement: "SELECT 1",
+		}
+
+		record := newOTLPLogRecord(auditRecord)
+		Expect(record.Body).To(Equal("SELECT 1"))
+		Expect(record.Attributes).To(HaveKeyWithValue("logger", PgAuditRecordName))
+		Expect(record.Attributes).To(HaveKeyWithValue("audit.class", "READ"))
+		Expect(record.Attributes).To(HaveKeyWithValue("audit.audit_type", "SESSION"))
+	})
+
+	It("exports the records in batches", func(ctx SpecContext) {
+		writer.Write(newLoggingRecord("one"))
+		writer.Write(newLoggingRecord("two"))
+		writer.flush(ctx, []otlpLogRecord{<-writer.queue, <-writer.queue})
+
+		Expect(exporter.records).To(HaveLen(2))
+		Expect(exporter.records[0].Body().AsString()).To(Equal("one"))
+		Expect(exporter.records[0].Resource().Attributes()).To(ContainElement(
+			otlp.ClusterAttribute.String("cluster-example")))
+		Expect(writer.buffer.isEmpty()).To(BeTrue())
+	})
+
+	It("buffers the records when the collector is not available", func(ctx SpecContext) {
+		exporter.err = errors.New("collector unavailable")
+		writer.flush(ctx, []otlpLogRecord{newOTLPLogRecord(newLoggingRecord("one"))})
+		Expect(writer.buffer.isEmpty()).To(BeFalse())
+
+		// While the buffer is not empty, fresh records are appended to it
+		// to preserve the ordering
+		exporter.err = nil
+		writer.flush(ctx, []otlpLogRecord{newOTLPLogRecord(newLoggingRecord("two"))})
+		Expect(exporter.records).To(BeEmpty())
+
+		writer.replay(ctx)
+		Expect(writer.buffer.isEmpty()).To(BeTrue())
+		Expect(exporter.records).To(HaveLen(2))
+		Expect(exporter.records[0].Body().AsString()).To(Equal("one"))
+		Expect(exporter.records[1].Body().AsString()).To(Equal("two"))
+	})
+
+	It("spills to the disk buffer when the queue is full", func() {
+		for range cap(writer.queue) + 1 {
+			writer.Write(newLoggingRecord("message"))
+		}
+		Expect(writer.queue).To(HaveLen(cap(writer.queue)))
+		Expect(writer.buffer.isEmpty()).To(BeFalse())
+	})
+
+	It("reports the records discarded by the disk buffer", func(ctx SpecContext) {
+		var err error
+		writer, err = newOTLPRecordWriterWithExporter(exporter, OTLPWriterOptions{
+			BufferDirectory: GinkgoT().TempDir(),
+			BufferSize:      10,
+		})
+		Expect(err).ToNot(HaveOccurred())
+
+		initial := testutil.ToFloat64(OTLPDroppedRecordsTotal)
+		exporter.err = errors.New("collector unavailable")
+		writer.flush(ctx, []otlpLogRecord{newOTLPLogRecord(newLoggingRecord("too big to fit"))})
+		writer.reportDroppedRecords(log.FromContext(ctx))
+		Expect(testutil.ToFloat64(OTLPDroppedRecordsTotal)).To(Equal(initial + 1))
+
+		// Records already reported are not counted again
+		writer.reportDroppedRecords(log.FromContext(ctx))
+		Expect(testutil.ToFloat64(OTLPDroppedRecordsTotal)).To(Equal(initial + 1))
+	})
+
+	It("stores the pending records in the disk buffer on shutdown", func(ctx SpecContext) {
+		writer.Write(newLoggingRecord("pending"))
+		cancelledCtx, cancel := context.WithCancel(ctx)
+		cancel()
+		Expect(writer.Start(cancelledCtx)).To(Succeed())
+
+		Expect(exporter.records).To(BeEmpty())
+		_, records, err := writer.buffer.peek()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(records).To(HaveLen(1))
+		Expect(records[0].Body).To(Equal("pending"))
+	})
+})
+
+var _ = Describe("MultiRecordWriter", func() {
+	It("writes the records to every writer", func() {
+		first := &SpyRecordWriter{}
+		second := &SpyRecordWriter{}
+		MultiRecordWriter{first, second}.Write(&LoggingRecord{})
+		Expect(first.records).To(HaveLen(1))
+		Expect(second.records).To(HaveLen(1))
+	})
+})
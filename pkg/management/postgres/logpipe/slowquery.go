@@ -0,0 +1,326 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package logpipe
+
+import (
+	"bytes"
+	"encoding/json"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// QueryTextMode defines how the text of the queries is reported
+// in the slow query records
+type QueryTextMode string
+
+const (
+	// QueryTextFull reports the query text as it was logged by PostgreSQL
+	QueryTextFull = QueryTextMode("full")
+
+	// QueryTextNormalized replaces the constants in the query text
+	// with placeholders, as pg_stat_statements does
+	QueryTextNormalized = QueryTextMode("normalized")
+
+	// QueryTextRedacted removes the query text, the parameters and the plans
+	QueryTextRedacted = QueryTextMode("redacted")
+)
+
+// getFullQueryTextMode always reports the query text as it was logged
+func getFullQueryTextMode() QueryTextMode {
+	return QueryTextFull
+}
+
+const (
+	// SlowQuerySourceDuration is the source of the records produced
+	// by log_min_duration_statement
+	SlowQuerySourceDuration = "duration"
+
+	// SlowQuerySourceAutoExplain is the source of the records produced
+	// by the auto_explain extension
+	SlowQuerySourceAutoExplain = "auto_explain"
+)
+
+var (
+	durationMessageRegex = regexp.MustCompile(`(?s)^duration: ([0-9.]+) ms(?:  (.*))?$`)
+	statementRegex       = regexp.MustCompile(`(?s)^(statement|parse [^:]*|bind [^:]*|execute [^:]*|fastpath function call): (.*)$`)
+)
+
+// planConditionFields are the fields of the plan nodes which may
+// contain the constants of the query
+var planConditionFields = []string{
+	"Filter", "Index Cond", "Recheck Cond", "Hash Cond", "Merge Cond",
+	"Join Filter", "TID Cond", "One-Time Filter", "Run Condition",
+	"Order By", "Sort Key", "Presorted Key", "Group Key", "Hash Key",
+	"Cache Key", "Output", "Function Call", "Table Function Call",
+}
+
+// SlowQueryObserver receives the duration of every slow query
+type SlowQueryObserver interface {
+	ObserveSlowQuery(database, user, source string, duration time.Duration)
+}
+
+// SlowQueryRecord stores the fields extracted from the messages produced
+// by log_min_duration_statement and by the auto_explain extension
+type SlowQueryRecord struct {
+	DurationMs float64         `json:"duration_ms"`
+	Source     string          `json:"source"`
+	Command    string          `json:"command,omitempty"`
+	QueryText  string          `json:"query_text,omitempty"`
+	QueryID    string          `json:"query_id,omitempty"`
+	Plan       json.RawMessage `json:"plan,omitempty"`
+	PlanText   string          `json:"plan_text,omitempty"`
+}
+
+// SlowQueryLoggingDecorator extracts the slow query information
+// from the records produced by another parser
+type SlowQueryLoggingDecorator struct {
+	*LoggingRecord
+	SlowQuery *SlowQueryRecord `json:"slow_query,omitempty"`
+
+	parser      CSVRecordParser
+	getTextMode func() QueryTextMode
+	observer    SlowQueryObserver
+
+	// textMode is the mode used for the record being parsed
+	textMode QueryTextMode
+}
+
+// NewSlowQueryLoggingDecorator builds a SlowQueryLoggingDecorator on
+// top of the passed parser. The text mode is read for every slow query,
+// so that it can be changed at runtime. The observer, if not nil,
+// receives the duration of every slow query
+func NewSlowQueryLoggingDecorator(
+	parser CSVRecordParser,
+	getTextMode func() QueryTextMode,
+	observer SlowQueryObserver,
+) *SlowQueryLoggingDecorator {
+	return &SlowQueryLoggingDecorator{
+		SlowQuery:   &SlowQueryRecord{},
+		parser:      parser,
+		getTextMode: getTextMode,
+		observer:    observer,
+	}
+}
+
+// GetName implements the NamedRecord interface
+func (r *SlowQueryLoggingDecorator) GetName() string {
+	return LoggingCollectorRecordName
+}
+
+// FromCSV implements the CSVRecordParser interface, parsing the record with
+// the decorated parser and then extracting the slow query information
+// from the message, when available
+func (r *SlowQueryLoggingDecorator) FromCSV(content []string) NamedRecord {
+	record := r.parser.FromCSV(content)
+	loggingRecord, ok := record.(*LoggingRecord)
+	if !ok {
+		return record
+	}
+
+	matches := durationMessageRegex.FindStringSubmatch(loggingRecord.Message)
+	if matches == nil {
+		return record
+	}
+	duration, err := strconv.ParseFloat(matches[1], 64)
+	if err != nil {
+		return record
+	}
+
+	r.LoggingRecord = loggingRecord
+	r.textMode = r.getTextMode()
+	*r.SlowQuery = SlowQueryRecord{
+		DurationMs: duration,
+		Source:     SlowQuerySourceDuration,
+	}
+	if loggingRecord.QueryID != "" && loggingRecord.QueryID != "0" {
+		r.SlowQuery.QueryID = loggingRecord.QueryID
+	}
+
+	if plan, isPlan := strings.CutPrefix(matches[2], "plan:\n"); isPlan {
+		r.SlowQuery.Source = SlowQuerySourceAutoExplain
+		r.parsePlan(plan)
+	} else if statement := statementRegex.FindStringSubmatch(matches[2]); statement != nil {
+		r.SlowQuery.Command, _, _ = strings.Cut(statement[1], " ")
+		r.SlowQuery.QueryText = r.processQueryText(statement[2])
+	}
+
+	// The parameters of the prepared statements are reported in the detail
+	if r.textMode != QueryTextFull && strings.HasPrefix(loggingRecord.Detail, "parameters: ") {
+		loggingRecord.Detail = ""
+	}
+	loggingRecord.Message = ""
+
+	if r.observer != nil {
+		r.observer.ObserveSlowQuery(
+			loggingRecord.DatabaseName,
+			loggingRecord.Username,
+			r.SlowQuery.Source,
+			time.Duration(duration*float64(time.Millisecond)),
+		)
+	}
+
+	return r
+}
+
+// parsePlan extracts the query and the plan from the output of
+// auto_explain, that can be in JSON or in text format
+func (r *SlowQueryLoggingDecorator) parsePlan(plan string) {
+	if r.textMode == QueryTextRedacted {
+		return
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(plan), &fields); err != nil {
+		// Text format, where the query can span multiple lines
+		// and can't be reliably separated from the plan
+		if r.textMode == QueryTextNormalized {
+			plan = normalizeTextPlan(plan)
+		}
+		if queryText, found := strings.CutPrefix(plan, "Query Text: "); found {
+			r.SlowQuery.QueryText, _, _ = strings.Cut(queryText, "\n")
+		}
+		r.SlowQuery.PlanText = plan
+		return
+	}
+
+	var queryText string
+	if err := json.Unmarshal(fields["Query Text"], &queryText); err == nil {
+		queryText = r.processQueryText(queryText)
+		r.SlowQuery.QueryText = queryText
+		fields["Query Text"], _ = json.Marshal(queryText)
+	}
+	if queryID, ok := fields["Query Identifier"]; ok && r.SlowQuery.QueryID == "" {
+		r.SlowQuery.QueryID = strings.Trim(string(queryID), `"`)
+	}
+	if r.textMode != QueryTextFull {
+		delete(fields, "Query Parameters")
+	}
+	if r.textMode == QueryTextNormalized {
+		for key, value := range fields {
+			if key != "Query Text" {
+				fields[key] = normalizeJSONPlanField(value)
+			}
+		}
+	}
+
+	if encodedPlan, err := json.Marshal(fields); err == nil {
+		r.SlowQuery.Plan = encodedPlan
+	}
+}
+
+func (r *SlowQueryLoggingDecorator) processQueryText(query string) string {
+	switch r.textMode {
+	case QueryTextRedacted:
+		return ""
+	case QueryTextNormalized:
+		return NormalizeQuery(query)
+	default:
+		return query
+	}
+}
+
+// normalizeJSONPlanField replaces the constants in the conditions of the
+// plan nodes contained in a field of a JSON plan
+func normalizeJSONPlanField(field json.RawMessage) json.RawMessage {
+	decoder := json.NewDecoder(bytes.NewReader(field))
+	decoder.UseNumber()
+
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return field
+	}
+
+	result, err := json.Marshal(normalizePlanNode(value))
+	if err != nil {
+		return field
+	}
+	return result
+}
+
+// normalizePlanNode replaces the constants in the conditions of a
+// node of a JSON plan, and of the nodes nested in it
+func normalizePlanNode(node any) any {
+	switch v := node.(type) {
+	case map[string]any:
+		for key, value := range v {
+			if slices.Contains(planConditionFields, key) {
+				v[key] = normalizePlanCondition(value)
+			} else {
+				v[key] = normalizePlanNode(value)
+			}
+		}
+	case []any:
+		for i := range v {
+			v[i] = normalizePlanNode(v[i])
+		}
+	}
+	return node
+}
+
+// normalizePlanCondition replaces the constants in a condition of a JSON
+// plan, which can be a single expression or a list of expressions
+func normalizePlanCondition(condition any) any {
+	switch v := condition.(type) {
+	case string:
+		return NormalizeQuery(v)
+	case []any:
+		for i := range v {
+			v[i] = normalizePlanCondition(v[i])
+		}
+	}
+	return condition
+}
+
+// normalizeTextPlan replaces the constants of a plan in text format. As the
+// query text can span multiple lines, everything before the first line
+// recognized as a plan node is normalized as the query. In the rest of
+// the plan, the constants in the conditions of the nodes are normalized
+func normalizeTextPlan(plan string) string {
+	lines := strings.Split(plan, "\n")
+	firstNode := slices.IndexFunc(lines, isTextPlanNode)
+	if firstNode < 0 {
+		return NormalizeQuery(plan)
+	}
+
+	result := make([]string, 0, len(lines)-firstNode+1)
+	if firstNode > 0 {
+		result = append(result, NormalizeQuery(strings.Join(lines[:firstNode], "\n")))
+	}
+	for _, line := range lines[firstNode:] {
+		key, value, found := strings.Cut(strings.TrimLeft(line, " "), ": ")
+		if found && slices.Contains(planConditionFields, key) {
+			line = line[:len(line)-len(value)] + NormalizeQuery(value)
+		}
+		result = append(result, line)
+	}
+
+	return strings.Join(result, "\n")
+}
+
+// isTextPlanNode checks if a line of a plan in text format is a plan node
+func isTextPlanNode(line string) bool {
+	return strings.HasPrefix(strings.TrimLeft(line, " "), "->") ||
+		strings.Contains(line, "  (cost=") ||
+		strings.Contains(line, "  (actual ") ||
+		strings.Contains(line, "  (never executed)")
+}
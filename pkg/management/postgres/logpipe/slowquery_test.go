@@ -0,0 +1,216 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package logpipe
+
+import (
+	"encoding/json"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+type slowQueryObservation struct {
+	database string
+	user     string
+	source   string
+	duration time.Duration
+}
+
+type fakeSlowQueryObserver struct {
+	observations []slowQueryObservation
+}
+
+func (f *fakeSlowQueryObserver) ObserveSlowQuery(database, user, source string, duration time.Duration) {
+	f.observations = append(f.observations, slowQueryObservation{
+		database: database,
+		user:     user,
+		source:   source,
+		duration: duration,
+	})
+}
+
+var _ = Describe("Slow query records", func() {
+	var observer *fakeSlowQueryObserver
+
+	newCSVRecord := func(message, detail string) []string {
+		values := make([]string, FieldsPerRecord14)
+		values[1] = "app_user"
+		values[2] = "app"
+		values[11] = "LOG"
+		values[13] = message
+		values[14] = detail
+		values[25] = "0"
+		return values
+	}
+
+	parse := func(textMode QueryTextMode, message, detail string) NamedRecord {
+		getTextMode := func() QueryTextMode { return textMode }
+		decorator := NewSlowQueryLoggingDecorator(NewPgAuditLoggingDecorator(), getTextMode, observer)
+		return decorator.FromCSV(newCSVRecord(message, detail))
+	}
+
+	BeforeEach(func() {
+		observer = &fakeSlowQueryObserver{}
+	})
+
+	It("leaves the other records untouched", func() {
+		result := parse(QueryTextFull, "checkpoint starting: time", "")
+		Expect(result).To(BeAssignableToTypeOf(&LoggingRecord{}))
+		Expect(result.(*LoggingRecord).Message).To(Equal("checkpoint starting: time"))
+		Expect(observer.observations).To(BeEmpty())
+	})
+
+	It("parses the records of log_min_duration_statement", func() {
+		result := parse(QueryTextFull, "duration: 1500.250 ms  statement: SELECT * FROM t WHERE id = 42", "")
+		Expect(result.GetName()).To(Equal(LoggingCollectorRecordName))
+		decorator, ok := result.(*SlowQueryLoggingDecorator)
+		Expect(ok).To(BeTrue())
+		Expect(decorator.Message).To(BeEmpty())
+		Expect(*decorator.SlowQuery).To(Equal(SlowQueryRecord{
+			DurationMs: 1500.25,
+			Source:     SlowQuerySourceDuration,
+			Command:    "statement",
+			QueryText:  "SELECT * FROM t WHERE id = 42",
+		}))
+		Expect(observer.observations).To(ConsistOf(slowQueryObservation{
+			database: "app",
+			user:     "app_user",
+			source:   SlowQuerySourceDuration,
+			duration: 1500250 * time.Microsecond,
+		}))
+	})
+
+	It("parses the records of the extended query protocol, hiding the parameters when required", func() {
+		result := parse(QueryTextNormalized,
+			"duration: 2.000 ms  execute S_1: SELECT * FROM t WHERE name = $1 AND id > 10",
+			"parameters: $1 = 'secret'")
+		decorator := result.(*SlowQueryLoggingDecorator)
+		Expect(decorator.SlowQuery.Command).To(Equal("execute"))
+		Expect(decorator.SlowQuery.QueryText).To(Equal("SELECT * FROM t WHERE name = $1 AND id > $2"))
+		Expect(decorator.Detail).To(BeEmpty())
+	})
+
+	It("parses the JSON plans of auto_explain", func() {
+		plan := map[string]any{
+			"Query Text":       "SELECT * FROM t WHERE name = 'secret'",
+			"Query Identifier": 1234,
+			"Plan":             map[string]any{"Node Type": "Seq Scan"},
+		}
+		encodedPlan, err := json.MarshalIndent(plan, "", "  ")
+		Expect(err).ToNot(HaveOccurred())
+
+		result := parse(QueryTextNormalized, "duration: 10.500 ms  plan:\n"+string(encodedPlan), "")
+		decorator := result.(*SlowQueryLoggingDecorator)
+		Expect(decorator.SlowQuery.Source).To(Equal(SlowQuerySourceAutoExplain))
+		Expect(decorator.SlowQuery.QueryText).To(Equal("SELECT * FROM t WHERE name = $1"))
+		Expect(decorator.SlowQuery.QueryID).To(Equal("1234"))
+
+		var decodedPlan map[string]any
+		Expect(json.Unmarshal(decorator.SlowQuery.Plan, &decodedPlan)).To(Succeed())
+		Expect(decodedPlan).To(HaveKeyWithValue("Query Text", "SELECT * FROM t WHERE name = $1"))
+		Expect(decodedPlan).To(HaveKey("Plan"))
+		Expect(observer.observations).To(HaveLen(1))
+		Expect(observer.observations[0].source).To(Equal(SlowQuerySourceAutoExplain))
+	})
+
+	It("normalizes the constants in the conditions of the JSON plans", func() {
+		plan := map[string]any{
+			"Query Text": "SELECT * FROM t JOIN u ON t.id = u.id WHERE t.name = 'secret'",
+			"Plan": map[string]any{
+				"Node Type": "Hash Join",
+				"Hash Cond": "(t.id = u.id)",
+				"Plans": []any{
+					map[string]any{
+						"Node Type":  "Seq Scan",
+						"Filter":     "(name = 'secret'::text)",
+						"Plan Width": 36,
+					},
+					map[string]any{
+						"Node Type":  "Index Scan",
+						"Index Cond": "(id > 42)",
+						"Output":     []any{"id", "(value * 10)"},
+					},
+				},
+			},
+		}
+		encodedPlan, err := json.Marshal(plan)
+		Expect(err).ToNot(HaveOccurred())
+
+		decorator := parse(QueryTextNormalized, "duration: 10.500 ms  plan:\n"+string(encodedPlan), "").(
+			*SlowQueryLoggingDecorator)
+		Expect(string(decorator.SlowQuery.Plan)).ToNot(ContainSubstring("secret"))
+
+		var decodedPlan struct {
+			Plan struct {
+				HashCond string           `json:"Hash Cond"`
+				Plans    []map[string]any `json:"Plans"`
+			} `json:"Plan"`
+		}
+		Expect(json.Unmarshal(decorator.SlowQuery.Plan, &decodedPlan)).To(Succeed())
+		Expect(decodedPlan.Plan.HashCond).To(Equal("(t.id = u.id)"))
+		Expect(decodedPlan.Plan.Plans[0]).To(HaveKeyWithValue("Filter", "(name = $1::text)"))
+		Expect(decodedPlan.Plan.Plans[0]).To(HaveKeyWithValue("Plan Width", BeNumerically("==", 36)))
+		Expect(decodedPlan.Plan.Plans[1]).To(HaveKeyWithValue("Index Cond", "(id > $1)"))
+		Expect(decodedPlan.Plan.Plans[1]).To(HaveKeyWithValue("Output", ConsistOf("id", "(value * $1)")))
+
+		decorator = parse(QueryTextFull, "duration: 10.500 ms  plan:\n"+string(encodedPlan), "").(
+			*SlowQueryLoggingDecorator)
+		Expect(string(decorator.SlowQuery.Plan)).To(ContainSubstring("(name = 'secret'::text)"))
+	})
+
+	It("normalizes the constants in the text plans of auto_explain", func() {
+		message := "duration: 10.500 ms  plan:\n" +
+			"Query Text: SELECT * FROM t\n  WHERE name = 'secret'\n" +
+			"Seq Scan on t  (cost=0.00..25.88 rows=6 width=36)\n" +
+			"  Filter: (name = 'secret'::text)"
+
+		decorator := parse(QueryTextFull, message, "").(*SlowQueryLoggingDecorator)
+		Expect(decorator.SlowQuery.QueryText).To(Equal("SELECT * FROM t"))
+		Expect(decorator.SlowQuery.PlanText).To(ContainSubstring("Filter: (name = 'secret'::text)"))
+
+		decorator = parse(QueryTextNormalized, message, "").(*SlowQueryLoggingDecorator)
+		Expect(decorator.SlowQuery.QueryText).To(Equal("SELECT * FROM t"))
+		Expect(decorator.SlowQuery.PlanText).To(Equal("Query Text: SELECT * FROM t\n  WHERE name = $1\n" +
+			"Seq Scan on t  (cost=0.00..25.88 rows=6 width=36)\n" +
+			"  Filter: (name = $1::text)"))
+	})
+
+	It("removes the query text and the plan when redacted", func() {
+		decorator := parse(QueryTextRedacted,
+			"duration: 10.500 ms  plan:\n{\"Query Text\": \"SELECT 1\", \"Plan\": {}}", "").(*SlowQueryLoggingDecorator)
+		Expect(decorator.SlowQuery.QueryText).To(BeEmpty())
+		Expect(decorator.SlowQuery.Plan).To(BeEmpty())
+		Expect(decorator.SlowQuery.DurationMs).To(Equal(10.5))
+	})
+})
+
+var _ = DescribeTable("Query normalization",
+	func(query, expected string) {
+		Expect(NormalizeQuery(query)).To(Equal(expected))
+	},
+	Entry("numbers", "SELECT * FROM t1 WHERE a = 1 AND b > 2.5e3", "SELECT * FROM t1 WHERE a = $1 AND b > $2"),
+	Entry("strings", "SELECT 'it''s', E'a\\'b' FROM t", "SELECT $1, $2 FROM t"),
+	Entry("existing parameters", "SELECT $1, 42 FROM t WHERE x = $2", "SELECT $1, $3 FROM t WHERE x = $2"),
+	Entry("dollar quoting", "SELECT $fn$ body $fn$, $$x$$", "SELECT $1, $2"),
+	Entry("quoted identifiers", `SELECT "col 1" FROM "t2" WHERE "x" = 'y'`, `SELECT "col 1" FROM "t2" WHERE "x" = $1`),
+	Entry("comments", "SELECT 1 -- comment 2\n/* 3 */ FROM t", "SELECT $1 -- comment 2\n/* 3 */ FROM t"),
+	Entry("identifiers with numbers", "SELECT col_1, t2.x FROM schema1.t2", "SELECT col_1, t2.x FROM schema1.t2"),
+)
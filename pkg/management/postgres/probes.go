@@ -41,9 +41,11 @@ import (
 // GetStatus Extract the status of this PostgreSQL database
 func (instance *Instance) GetStatus() (result *postgres.PostgresqlStatus, err error) {
 	result = &postgres.PostgresqlStatus{
-		Pod:                    &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: instance.GetPodName()}},
-		InstanceManagerVersion: versions.Version,
-		MightBeUnavailable:     instance.MightBeUnavailable(),
+		Pod:                        &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: instance.GetPodName()}},
+		InstanceManagerVersion:     versions.Version,
+		MightBeUnavailable:         instance.MightBeUnavailable(),
+		VolumesUsage:               instance.GetVolumesUsage(),
+		IsDiskFullProtectionActive: instance.IsDiskFullProtectionActive(),
 	}
 
 	// this deferred function may override the error returned. Take extra care.
@@ -115,6 +117,7 @@ func (instance *Instance) GetStatus() (result *postgres.PostgresqlStatus, err er
 	}
 
 	result.InstanceArch = instance.GetArchitecture()
+	result.DatabaseAges = instance.GetDatabaseAges()
 
 	result.ExecutableHash, err = executablehash.Get()
 	if err != nil {
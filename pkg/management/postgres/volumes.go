@@ -0,0 +1,86 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package postgres
+
+import (
+	"os"
+	"path"
+
+	"github.com/cloudnative-pg/machinery/pkg/fileutils"
+	"github.com/cloudnative-pg/machinery/pkg/log"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/system/compatibility"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+// GetVolumesUsage returns the usage of the filesystems of the volumes of
+// the instance: the PGDATA one and, when present, the WAL and the
+// tablespaces ones
+func (instance *Instance) GetVolumesUsage() []postgres.VolumeUsage {
+	return getVolumesUsage(instance.PgData, specs.PgWalVolumePath, specs.PgTablespaceVolumePath)
+}
+
+func getVolumesUsage(pgData, walVolumePath, tablespacesPath string) []postgres.VolumeUsage {
+	type volume struct {
+		role       utils.PVCRole
+		tablespace string
+		path       string
+	}
+
+	volumes := []volume{{role: utils.PVCRolePgData, path: pgData}}
+	if exists, _ := fileutils.FileExists(walVolumePath); exists {
+		volumes = append(volumes, volume{role: utils.PVCRolePgWal, path: walVolumePath})
+	}
+	if entries, err := os.ReadDir(tablespacesPath); err == nil {
+		for _, entry := range entries {
+			if !entry.IsDir() {
+				continue
+			}
+			volumes = append(volumes, volume{
+				role:       utils.PVCRolePgTablespace,
+				tablespace: entry.Name(),
+				path:       path.Join(tablespacesPath, entry.Name()),
+			})
+		}
+	}
+
+	result := make([]postgres.VolumeUsage, 0, len(volumes))
+	for _, volume := range volumes {
+		usage, err := compatibility.GetFilesystemUsage(volume.path)
+		if err != nil {
+			log.Debug("Unable to get the usage of the volume", "path", volume.path, "err", err)
+			continue
+		}
+
+		result = append(result, postgres.VolumeUsage{
+			Role:           volume.role,
+			Tablespace:     volume.tablespace,
+			TotalBytes:     usage.TotalBytes,
+			UsedBytes:      usage.UsedBytes,
+			AvailableBytes: usage.AvailableBytes,
+			TotalInodes:    usage.TotalInodes,
+			FreeInodes:     usage.FreeInodes,
+		})
+	}
+
+	return result
+}
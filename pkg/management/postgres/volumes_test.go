@@ -0,0 +1,60 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package postgres
+
+import (
+	"os"
+	"path"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("volumes usage", func() {
+	It("reports the PGDATA, WAL and tablespaces volumes", func() {
+		baseDir := GinkgoT().TempDir()
+		pgData := path.Join(baseDir, "data")
+		walVolume := path.Join(baseDir, "wal")
+		tablespaces := path.Join(baseDir, "tablespaces")
+		for _, dir := range []string{pgData, walVolume, path.Join(tablespaces, "tbs1")} {
+			Expect(os.MkdirAll(dir, 0o700)).To(Succeed())
+		}
+		Expect(os.WriteFile(path.Join(tablespaces, "file"), nil, 0o600)).To(Succeed())
+
+		usage := getVolumesUsage(pgData, walVolume, tablespaces)
+		Expect(usage).To(HaveLen(3))
+		Expect(usage[0].Role).To(Equal(utils.PVCRolePgData))
+		Expect(usage[0].TotalBytes).To(BeNumerically(">", 0))
+		Expect(usage[0].UsedBytes + usage[0].AvailableBytes).To(BeNumerically("<=", usage[0].TotalBytes))
+		Expect(usage[1].Role).To(Equal(utils.PVCRolePgWal))
+		Expect(usage[2].Role).To(Equal(utils.PVCRolePgTablespace))
+		Expect(usage[2].Tablespace).To(Equal("tbs1"))
+	})
+
+	It("only reports PGDATA when there are no other volumes", func() {
+		baseDir := GinkgoT().TempDir()
+
+		usage := getVolumesUsage(baseDir, path.Join(baseDir, "wal"), path.Join(baseDir, "tablespaces"))
+		Expect(usage).To(HaveLen(1))
+		Expect(usage[0].Role).To(Equal(utils.PVCRolePgData))
+	})
+})
@@ -20,15 +20,20 @@ SPDX-License-Identifier: Apache-2.0
 package metricserver
 
 import (
+	"context"
 	"crypto/tls"
 	"fmt"
 	"net/http"
 
+	"github.com/cloudnative-pg/machinery/pkg/log"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/collectors"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.opentelemetry.io/otel/attribute"
 
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/otlp"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/logpipe"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/webserver"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/url"
 )
@@ -40,6 +45,12 @@ type MetricsServer struct {
 	// exporter is the exporter for predefined queries and for
 	// custom ones
 	exporter *Exporter
+
+	// registry is the registry the exporters are registered into
+	registry *prometheus.Registry
+
+	// instance is the PostgreSQL instance whose metrics are served
+	instance *postgres.Instance
 }
 
 // New configure the web statusServer for a certain PostgreSQL instance, and
@@ -52,6 +63,9 @@ func New(serverInstance *postgres.Instance, exporter *Exporter) (*MetricsServer,
 	if err := registry.Register(collectors.NewGoCollector()); err != nil {
 		return nil, fmt.Errorf("while registering Go exporters: %w", err)
 	}
+	if err := registry.Register(logpipe.OTLPDroppedRecordsTotal); err != nil {
+		return nil, fmt.Errorf("while registering OTLP log exporters: %w", err)
+	}
 	serveMux := http.NewServeMux()
 	serveMux.Handle(url.PathMetrics, promhttp.HandlerFor(registry, promhttp.HandlerOpts{}))
 
@@ -74,7 +88,31 @@ func New(serverInstance *postgres.Instance, exporter *Exporter) (*MetricsServer,
 	metricServer := &MetricsServer{
 		Webserver: webserver.NewWebServer(server),
 		exporter:  exporter,
+		registry:  registry,
+		instance:  serverInstance,
 	}
 
 	return metricServer, nil
 }
+
+// getOTLPResourceAttributes returns the attributes identifying this
+// instance, including its current role
+func (ms *MetricsServer) getOTLPResourceAttributes(ctx context.Context) []attribute.KeyValue {
+	role := "replica"
+	isPrimary, err := ms.instance.IsPrimary()
+	if err != nil {
+		log.FromContext(ctx).Warning("Unable to detect the instance role for OTLP metrics", "err", err)
+	}
+	if isPrimary {
+		role = "primary"
+	}
+
+	return []attribute.KeyValue{
+		otlp.ServiceNameAttribute.String("cloudnative-pg"),
+		otlp.NamespaceAttribute.String(ms.instance.GetNamespaceName()),
+		otlp.PodAttribute.String(ms.instance.GetPodName()),
+		otlp.ClusterAttribute.String(ms.instance.GetClusterName()),
+		otlp.InstanceAttribute.String(ms.instance.GetPodName()),
+		otlp.RoleAttribute.String(role),
+	}
+}
@@ -0,0 +1,120 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package metricserver
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/cloudnative-pg/machinery/pkg/log"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/otlp"
+)
+
+// OTLPPusher pushes the metrics served by a MetricsServer to the
+// OpenTelemetry collector configured in the Cluster. The configuration
+// is received from the instance reconciler, so that changing it
+// doesn't require restarting the instance
+type OTLPPusher struct {
+	metricsServer *MetricsServer
+}
+
+// NewOTLPPusher creates a runnable pushing the metrics served by this
+// server to the OpenTelemetry collector configured in the Cluster
+func (ms *MetricsServer) NewOTLPPusher() *OTLPPusher {
+	return &OTLPPusher{
+		metricsServer: ms,
+	}
+}
+
+// Start waits for the OTLP configuration and pushes the metrics
+// accordingly, until the context is cancelled. It implements the
+// controller-runtime Runnable interface
+func (pusher *OTLPPusher) Start(ctx context.Context) error {
+	contextLogger := log.FromContext(ctx).WithName("otlp_metrics_pusher")
+	ctx = log.IntoContext(ctx, contextLogger)
+
+	var config *apiv1.OTLPMetricsConfiguration
+	var stopPushing func()
+	defer func() {
+		if stopPushing != nil {
+			stopPushing()
+		}
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+
+		case newConfig := <-pusher.metricsServer.instance.OTLPMetricsChan():
+			// The configuration is sent at every reconciliation of
+			// the instance, let's act only when it changes
+			if reflect.DeepEqual(config, newConfig) {
+				continue
+			}
+			config = newConfig
+
+			if stopPushing != nil {
+				stopPushing()
+				stopPushing = nil
+			}
+
+			if config == nil || config.Endpoint == "" {
+				contextLogger.Info("OTLP metrics push disabled")
+				continue
+			}
+
+			metricsPusher, err := otlp.NewMetricsPusher(
+				ctx,
+				pusher.metricsServer.registry,
+				config.Endpoint,
+				config.GetInterval(),
+				pusher.metricsServer.getOTLPResourceAttributes,
+			)
+			if err != nil {
+				contextLogger.Error(err, "Unable to create the OTLP metrics pusher",
+					"endpoint", config.Endpoint)
+				continue
+			}
+
+			contextLogger.Info("Pushing metrics to the OTLP collector",
+				"endpoint", config.Endpoint, "interval", config.GetInterval())
+			stopPushing = startMetricsPusher(ctx, metricsPusher)
+		}
+	}
+}
+
+// startMetricsPusher runs the passed pusher in the background, returning
+// a function stopping it and waiting for the pending metrics to be flushed
+func startMetricsPusher(ctx context.Context, metricsPusher *otlp.MetricsPusher) func() {
+	pushCtx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_ = metricsPusher.Start(pushCtx)
+	}()
+
+	return func() {
+		cancel()
+		<-done
+	}
+}
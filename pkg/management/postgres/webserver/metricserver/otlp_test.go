@@ -0,0 +1,97 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package metricserver
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("OTLP metrics pusher", func() {
+	var (
+		collector *httptest.Server
+		pushes    atomic.Int32
+		instance  *postgres.Instance
+		pusher    *OTLPPusher
+	)
+
+	BeforeEach(func() {
+		pushes.Store(0)
+		collector = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			pushes.Add(1)
+			w.WriteHeader(http.StatusOK)
+		}))
+		DeferCleanup(collector.Close)
+
+		instance = postgres.NewInstance()
+		ms := &MetricsServer{
+			registry: prometheus.NewRegistry(),
+			instance: instance,
+		}
+		pusher = ms.NewOTLPPusher()
+	})
+
+	startPusher := func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			_ = pusher.Start(ctx)
+		}()
+		DeferCleanup(func() {
+			cancel()
+			<-done
+		})
+	}
+
+	It("doesn't push the metrics until the configuration is received", func() {
+		startPusher()
+		instance.ConfigureOTLPMetrics(nil)
+		Consistently(pushes.Load, 200*time.Millisecond).Should(BeZero())
+	})
+
+	It("starts and stops pushing the metrics as the configuration changes", func() {
+		startPusher()
+		instance.ConfigureOTLPMetrics(&apiv1.OTLPMetricsConfiguration{
+			Endpoint: collector.URL,
+			Interval: &metav1.Duration{Duration: 20 * time.Millisecond},
+		})
+		Eventually(pushes.Load).Should(BeNumerically(">", 0))
+
+		instance.ConfigureOTLPMetrics(nil)
+		// Wait for the configuration to be applied before checking
+		// that the pushes are stopped
+		time.Sleep(100 * time.Millisecond)
+		stoppedAt := pushes.Load()
+		Consistently(pushes.Load, 200*time.Millisecond).Should(Equal(stoppedAt))
+	})
+})
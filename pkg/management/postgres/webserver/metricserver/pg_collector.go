@@ -80,6 +80,17 @@ type metrics struct {
 	FencingOn                    prometheus.Gauge
 	PgStatWalMetrics             PgStatWalMetrics
 	NodesUsed                    prometheus.Gauge
+	SlowQueryDuration            *prometheus.HistogramVec
+	DatabaseXIDAge               *prometheus.GaugeVec
+	DatabaseMultiXactIDAge       *prometheus.GaugeVec
+	VolumeSize                   *prometheus.GaugeVec
+	VolumeUsed                   *prometheus.GaugeVec
+	VolumeAvailable              *prometheus.GaugeVec
+	VolumeInodes                 *prometheus.GaugeVec
+	VolumeInodesUsed             *prometheus.GaugeVec
+	VolumeInodesFree             *prometheus.GaugeVec
+	WALDirectorySize             prometheus.Gauge
+	WALOldestSegmentAge          prometheus.Gauge
 }
 
 // PgStatWalMetrics is available from PG14+
@@ -108,6 +119,7 @@ func NewExporter(instance *postgres.Instance, pluginCollector m.PluginCollector)
 // newMetrics returns collector metrics
 func newMetrics() *metrics {
 	subsystem := "collector"
+	volumeLabels := []string{"role", "tablespace"}
 	return &metrics{
 		CollectionsTotal: prometheus.NewCounter(prometheus.CounterOpts{
 			Namespace: PrometheusNamespace,
@@ -213,6 +225,76 @@ func newMetrics() *metrics {
 				"implying the absence of High Availability (HA). Ideally this value " +
 				"should match the number of instances in the cluster.",
 		}),
+		SlowQueryDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: PrometheusNamespace,
+			Subsystem: subsystem,
+			Name:      "slow_query_duration_seconds",
+			Help: "Duration of the queries logged by log_min_duration_statement " +
+				"(source=\"duration\") and by auto_explain (source=\"auto_explain\")",
+			Buckets: []float64{0.01, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60, 300},
+		}, []string{"datname", "usename", "source"}),
+		DatabaseXIDAge: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: PrometheusNamespace,
+			Subsystem: subsystem,
+			Name:      "database_xid_age",
+			Help:      "Age of the oldest unfrozen transaction ID of the database, as last computed by the instance",
+		}, []string{"datname"}),
+		DatabaseMultiXactIDAge: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: PrometheusNamespace,
+			Subsystem: subsystem,
+			Name:      "database_mxid_age",
+			Help:      "Age of the oldest multixact ID of the database, as last computed by the instance",
+		}, []string{"datname"}),
+		VolumeSize: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: PrometheusNamespace,
+			Subsystem: subsystem,
+			Name:      "volume_size_bytes",
+			Help:      "Size in bytes of the filesystem of the volume",
+		}, volumeLabels),
+		VolumeUsed: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: PrometheusNamespace,
+			Subsystem: subsystem,
+			Name:      "volume_used_bytes",
+			Help:      "Bytes used in the filesystem of the volume",
+		}, volumeLabels),
+		VolumeAvailable: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: PrometheusNamespace,
+			Subsystem: subsystem,
+			Name:      "volume_available_bytes",
+			Help:      "Bytes available to PostgreSQL in the filesystem of the volume",
+		}, volumeLabels),
+		VolumeInodes: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: PrometheusNamespace,
+			Subsystem: subsystem,
+			Name:      "volume_inodes",
+			Help:      "Number of inodes in the filesystem of the volume",
+		}, volumeLabels),
+		VolumeInodesUsed: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: PrometheusNamespace,
+			Subsystem: subsystem,
+			Name:      "volume_inodes_used",
+			Help:      "Number of used inodes in the filesystem of the volume",
+		}, volumeLabels),
+		VolumeInodesFree: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: PrometheusNamespace,
+			Subsystem: subsystem,
+			Name:      "volume_inodes_free",
+			Help:      "Number of free inodes in the filesystem of the volume",
+		}, volumeLabels),
+		WALDirectorySize: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: PrometheusNamespace,
+			Subsystem: subsystem,
+			Name:      "wal_directory_size_bytes",
+			Help: fmt.Sprintf("Total size in bytes of the files in the '%s' directory, "+
+				"as read from the filesystem", specs.PgWalPath),
+		}),
+		WALOldestSegmentAge: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: PrometheusNamespace,
+			Subsystem: subsystem,
+			Name:      "wal_oldest_segment_age_seconds",
+			Help: fmt.Sprintf("Age in seconds of the oldest WAL segment in the '%s' directory",
+				specs.PgWalPath),
+		}),
 		PgStatWalMetrics: PgStatWalMetrics{
 			WalRecords: prometheus.NewGaugeVec(prometheus.GaugeOpts{
 				Namespace: PrometheusNamespace,
@@ -292,6 +374,17 @@ func (e *Exporter) Describe(ch chan<- *prometheus.Desc) {
 	e.Metrics.LastFailedBackupTimestamp.Describe(ch)
 	e.Metrics.LastAvailableBackupTimestamp.Describe(ch)
 	e.Metrics.NodesUsed.Describe(ch)
+	e.Metrics.SlowQueryDuration.Describe(ch)
+	e.Metrics.DatabaseXIDAge.Describe(ch)
+	e.Metrics.DatabaseMultiXactIDAge.Describe(ch)
+	e.Metrics.VolumeSize.Describe(ch)
+	e.Metrics.VolumeUsed.Describe(ch)
+	e.Metrics.VolumeAvailable.Describe(ch)
+	e.Metrics.VolumeInodes.Describe(ch)
+	e.Metrics.VolumeInodesUsed.Describe(ch)
+	e.Metrics.VolumeInodesFree.Describe(ch)
+	ch <- e.Metrics.WALDirectorySize.Desc()
+	ch <- e.Metrics.WALOldestSegmentAge.Desc()
 
 	if e.queries != nil {
 		e.queries.Describe(ch)
@@ -375,6 +468,17 @@ func (e *Exporter) collectInstanceMetrics(ch chan<- prometheus.Metric) {
 	e.Metrics.LastFailedBackupTimestamp.Collect(ch)
 	e.Metrics.LastAvailableBackupTimestamp.Collect(ch)
 	e.Metrics.NodesUsed.Collect(ch)
+	e.Metrics.SlowQueryDuration.Collect(ch)
+	e.Metrics.DatabaseXIDAge.Collect(ch)
+	e.Metrics.DatabaseMultiXactIDAge.Collect(ch)
+	e.Metrics.VolumeSize.Collect(ch)
+	e.Metrics.VolumeUsed.Collect(ch)
+	e.Metrics.VolumeAvailable.Collect(ch)
+	e.Metrics.VolumeInodes.Collect(ch)
+	e.Metrics.VolumeInodesUsed.Collect(ch)
+	e.Metrics.VolumeInodesFree.Collect(ch)
+	ch <- e.Metrics.WALDirectorySize
+	ch <- e.Metrics.WALOldestSegmentAge
 
 	if version, _ := e.instance.GetPgVersion(); version.Major >= 14 {
 		e.Metrics.PgStatWalMetrics.WalRecords.Collect(ch)
@@ -402,6 +506,11 @@ func (e *Exporter) collectInstanceMetrics(ch chan<- prometheus.Metric) {
 func (e *Exporter) updateInstanceMetrics() {
 	e.Metrics.CollectionsTotal.Inc()
 	collectionStart := time.Now()
+
+	// The usage of the volumes is collected even when PostgreSQL is down,
+	// for example because one of them is full
+	e.collectVolumesUsage()
+
 	if e.instance.IsFenced() {
 		e.Metrics.FencingOn.Set(1)
 		log.Info("metrics collection skipped due to fencing")
@@ -441,6 +550,7 @@ func (e *Exporter) updateInstanceMetrics() {
 	}
 
 	e.collectNodesUsed()
+	e.collectDatabaseAges()
 
 	// metrics collected only on primary server
 	if isPrimary {
@@ -486,6 +596,17 @@ func (e *Exporter) updateInstanceMetrics() {
 	}
 }
 
+// collectDatabaseAges exposes the age of the databases, as last computed
+// by the wraparound monitor
+func (e *Exporter) collectDatabaseAges() {
+	e.Metrics.DatabaseXIDAge.Reset()
+	e.Metrics.DatabaseMultiXactIDAge.Reset()
+	for _, age := range e.instance.GetDatabaseAges() {
+		e.Metrics.DatabaseXIDAge.WithLabelValues(age.Name).Set(float64(age.XIDAge))
+		e.Metrics.DatabaseMultiXactIDAge.WithLabelValues(age.Name).Set(float64(age.MultiXactIDAge))
+	}
+}
+
 func (e *Exporter) setTimestampMetric(
 	gauge prometheus.Gauge,
 	errorLabel string,
@@ -530,6 +651,12 @@ func (e *Exporter) setTimestampMetric(
 	gauge.Set(float64(parsedTS.Unix()))
 }
 
+// ObserveSlowQuery implements the logpipe.SlowQueryObserver interface,
+// recording the duration of a slow query
+func (e *Exporter) ObserveSlowQuery(database, user, source string, duration time.Duration) {
+	e.Metrics.SlowQueryDuration.WithLabelValues(database, user, source).Observe(duration.Seconds())
+}
+
 func (e *Exporter) collectNodesUsed() {
 	const notExtractedValue float64 = -1
 
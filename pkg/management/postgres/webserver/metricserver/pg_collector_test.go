@@ -196,6 +196,47 @@ var _ = Describe("test metrics parsing", func() {
 		}
 	})
 
+	It("records the duration of the slow queries", func() {
+		exporter.ObserveSlowQuery("app", "app_user", "duration", 1500*time.Millisecond)
+		exporter.ObserveSlowQuery("app", "app_user", "duration", 20*time.Millisecond)
+
+		registry := prometheus.NewRegistry()
+		registry.MustRegister(exporter.Metrics.SlowQueryDuration)
+		metrics, err := registry.Gather()
+		Expect(err).ToNot(HaveOccurred())
+
+		slowQueryMetric := getMetric(metrics, "cnpg_collector_slow_query_duration_seconds")
+		Expect(slowQueryMetric).ToNot(BeNil())
+		Expect(slowQueryMetric.GetMetric()).To(HaveLen(1))
+		histogram := slowQueryMetric.GetMetric()[0].GetHistogram()
+		Expect(histogram.GetSampleCount()).To(BeEquivalentTo(2))
+		Expect(histogram.GetSampleSum()).To(BeNumerically("~", 1.52))
+	})
+
+	It("exposes the age of the databases", func() {
+		exporter.instance.SetDatabaseAges([]postgresconf.DatabaseAge{
+			{Name: "app", XIDAge: 1200, MultiXactIDAge: 3},
+			{Name: "postgres", XIDAge: 800, MultiXactIDAge: 1},
+		})
+		exporter.collectDatabaseAges()
+
+		registry := prometheus.NewRegistry()
+		registry.MustRegister(exporter.Metrics.DatabaseXIDAge)
+		registry.MustRegister(exporter.Metrics.DatabaseMultiXactIDAge)
+		metrics, err := registry.Gather()
+		Expect(err).ToNot(HaveOccurred())
+
+		xidAgeMetric := getMetric(metrics, "cnpg_collector_database_xid_age")
+		Expect(xidAgeMetric).ToNot(BeNil())
+		Expect(xidAgeMetric.GetMetric()).To(HaveLen(2))
+		Expect(xidAgeMetric.GetMetric()[0].GetLabel()[0].GetValue()).To(Equal("app"))
+		Expect(xidAgeMetric.GetMetric()[0].GetGauge().GetValue()).To(BeEquivalentTo(1200))
+
+		mxidAgeMetric := getMetric(metrics, "cnpg_collector_database_mxid_age")
+		Expect(mxidAgeMetric).ToNot(BeNil())
+		Expect(mxidAgeMetric.GetMetric()[1].GetGauge().GetValue()).To(BeEquivalentTo(1))
+	})
+
 	Context("collectUsedNodes", func() {
 		const (
 			nodesUsedName         = "cnpg_collector_nodes_used"
@@ -0,0 +1,107 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package metricserver
+
+import (
+	"os"
+	"regexp"
+	"time"
+
+	"github.com/cloudnative-pg/machinery/pkg/log"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
+)
+
+// regexPGWalSegmentName matches the name of the WAL segments, excluding
+// the history, backup and partial files
+var regexPGWalSegmentName = regexp.MustCompile("^[0-9A-F]{24}$")
+
+// collectVolumesUsage exposes the usage of the filesystems of the volumes
+// of the instance. These metrics don't need PostgreSQL to be running
+func (e *Exporter) collectVolumesUsage() {
+	e.setVolumesUsageMetrics(e.instance.GetVolumesUsage())
+
+	size, oldestSegmentAge, err := getWALDirectoryStats(specs.PgWalPath, time.Now())
+	if err != nil {
+		log.Warning("Unable to collect the WAL directory metrics", "path", specs.PgWalPath, "err", err)
+		e.Metrics.PgCollectionErrors.WithLabelValues("Collect.WALDirectory").Inc()
+		e.Metrics.WALDirectorySize.Set(0)
+		e.Metrics.WALOldestSegmentAge.Set(0)
+		return
+	}
+	e.Metrics.WALDirectorySize.Set(float64(size))
+	e.Metrics.WALOldestSegmentAge.Set(oldestSegmentAge.Seconds())
+}
+
+func (e *Exporter) setVolumesUsageMetrics(volumesUsage []postgres.VolumeUsage) {
+	e.Metrics.VolumeSize.Reset()
+	e.Metrics.VolumeUsed.Reset()
+	e.Metrics.VolumeAvailable.Reset()
+	e.Metrics.VolumeInodes.Reset()
+	e.Metrics.VolumeInodesUsed.Reset()
+	e.Metrics.VolumeInodesFree.Reset()
+
+	for _, usage := range volumesUsage {
+		labels := []string{string(usage.Role), usage.Tablespace}
+		e.Metrics.VolumeSize.WithLabelValues(labels...).Set(float64(usage.TotalBytes))
+		e.Metrics.VolumeUsed.WithLabelValues(labels...).Set(float64(usage.UsedBytes))
+		e.Metrics.VolumeAvailable.WithLabelValues(labels...).Set(float64(usage.AvailableBytes))
+		e.Metrics.VolumeInodes.WithLabelValues(labels...).Set(float64(usage.TotalInodes))
+		e.Metrics.VolumeInodesUsed.WithLabelValues(labels...).Set(float64(usage.TotalInodes - usage.FreeInodes))
+		e.Metrics.VolumeInodesFree.WithLabelValues(labels...).Set(float64(usage.FreeInodes))
+	}
+}
+
+// getWALDirectoryStats returns the size of the files in the WAL directory
+// and the age of the oldest WAL segment. The oldest segment is the one with
+// the lowest name, as the recycled segments are renamed to future names
+// while keeping their previous modification time
+func getWALDirectoryStats(walDir string, now time.Time) (size int64, oldestSegmentAge time.Duration, err error) {
+	entries, err := os.ReadDir(walDir)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	foundSegment := false
+	for _, entry := range entries {
+		if !entry.Type().IsRegular() {
+			continue
+		}
+
+		info, err := entry.Info()
+		if os.IsNotExist(err) {
+			// The file has been removed or recycled in the meantime
+			continue
+		}
+		if err != nil {
+			return 0, 0, err
+		}
+		size += info.Size()
+
+		// The entries are sorted by name, the first segment is the oldest one
+		if !foundSegment && regexPGWalSegmentName.MatchString(entry.Name()) {
+			foundSegment = true
+			oldestSegmentAge = max(now.Sub(info.ModTime()), 0)
+		}
+	}
+
+	return size, oldestSegmentAge, nil
+}
@@ -0,0 +1,107 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package metricserver
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
+	postgresconf "github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("volume metrics", func() {
+	It("exposes the usage of the volumes", func() {
+		exporter := NewExporter(postgres.NewInstance(), fakePluginCollector{})
+		exporter.setVolumesUsageMetrics([]postgresconf.VolumeUsage{
+			{
+				Role:           utils.PVCRolePgData,
+				TotalBytes:     1000,
+				UsedBytes:      600,
+				AvailableBytes: 350,
+				TotalInodes:    100,
+				FreeInodes:     70,
+			},
+			{
+				Role:           utils.PVCRolePgTablespace,
+				Tablespace:     "tbs1",
+				TotalBytes:     2000,
+				UsedBytes:      100,
+				AvailableBytes: 1900,
+			},
+		})
+
+		registry := prometheus.NewRegistry()
+		registry.MustRegister(exporter.Metrics.VolumeSize)
+		registry.MustRegister(exporter.Metrics.VolumeAvailable)
+		registry.MustRegister(exporter.Metrics.VolumeInodesUsed)
+		metrics, err := registry.Gather()
+		Expect(err).ToNot(HaveOccurred())
+
+		sizeMetric := getMetric(metrics, "cnpg_collector_volume_size_bytes")
+		Expect(sizeMetric).ToNot(BeNil())
+		Expect(sizeMetric.GetMetric()).To(HaveLen(2))
+		Expect(sizeMetric.GetMetric()[0].GetLabel()[0].GetValue()).To(Equal("PG_DATA"))
+		Expect(sizeMetric.GetMetric()[0].GetGauge().GetValue()).To(BeEquivalentTo(1000))
+		Expect(sizeMetric.GetMetric()[1].GetLabel()[1].GetValue()).To(Equal("tbs1"))
+
+		availableMetric := getMetric(metrics, "cnpg_collector_volume_available_bytes")
+		Expect(availableMetric).ToNot(BeNil())
+		Expect(availableMetric.GetMetric()[0].GetGauge().GetValue()).To(BeEquivalentTo(350))
+
+		inodesUsedMetric := getMetric(metrics, "cnpg_collector_volume_inodes_used")
+		Expect(inodesUsedMetric).ToNot(BeNil())
+		Expect(inodesUsedMetric.GetMetric()[0].GetGauge().GetValue()).To(BeEquivalentTo(30))
+	})
+
+	It("computes the size of the WAL directory and the age of the oldest segment", func() {
+		walDir := GinkgoT().TempDir()
+		now := time.Now()
+
+		writeFile := func(name string, size int, age time.Duration) {
+			fileName := filepath.Join(walDir, name)
+			Expect(os.WriteFile(fileName, make([]byte, size), 0o600)).To(Succeed())
+			Expect(os.Chtimes(fileName, now.Add(-age), now.Add(-age))).To(Succeed())
+		}
+		writeFile("00000002.history", 10, 5*time.Hour)
+		writeFile("000000010000000000000003", 100, time.Hour)
+		writeFile("000000010000000000000004", 100, time.Minute)
+		// A recycled segment keeps the modification time of the one it replaced
+		writeFile("000000010000000000000005", 100, 2*time.Hour)
+		Expect(os.Mkdir(filepath.Join(walDir, "archive_status"), 0o700)).To(Succeed())
+
+		size, oldestSegmentAge, err := getWALDirectoryStats(walDir, now)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(size).To(BeEquivalentTo(310))
+		Expect(oldestSegmentAge).To(BeNumerically("~", time.Hour, time.Second))
+	})
+
+	It("fails when the WAL directory doesn't exist", func() {
+		_, _, err := getWALDirectoryStats(filepath.Join(GinkgoT().TempDir(), "missing"), time.Now())
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -173,6 +173,10 @@ cnpg_pooler_pgbouncer cnpg_pooler_pgbouncer cnpg_pooler_pgbouncer
 	// were pre-archived in parallel
 	SpoolDirectory = ScratchDataDirectory + "/wal-archive-spool"
 
+	// LogBufferDirectory is the directory where the log records are
+	// stored while the OTLP collector is not reachable
+	LogBufferDirectory = ScratchDataDirectory + "/log-buffer"
+
 	// CertificatesDir location to store the certificates
 	CertificatesDir = ScratchDataDirectory + "/certificates/"
 
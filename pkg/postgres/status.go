@@ -82,6 +82,17 @@ type PostgresqlStatus struct {
 	// contains the PgStatBasebackup rows content.
 	PgStatBasebackupsInfo []PgStatBasebackup `json:"pgStatBasebackupsInfo,omitempty"`
 
+	// The transaction ID and multixact ID age of the databases, as last
+	// computed by the wraparound monitor
+	DatabaseAges []DatabaseAge `json:"databaseAges,omitempty"`
+
+	// The usage of the filesystems of the volumes of the instance
+	VolumesUsage []VolumeUsage `json:"volumesUsage,omitempty"`
+
+	// Is true when the instance is in read-only mode because its
+	// volumes are running out of space
+	IsDiskFullProtectionActive bool `json:"isDiskFullProtectionActive,omitempty"`
+
 	// Status of the instance manager
 	ExecutableHash             string `json:"executableHash"`
 	InstanceManagerVersion     string `json:"instanceManagerVersion"`
@@ -99,6 +110,43 @@ type PostgresqlStatus struct {
 	IsPodReady bool `json:"isPodReady"`
 }
 
+// DatabaseAge contains the age of the oldest unfrozen transaction ID and
+// multixact ID of a database
+type DatabaseAge struct {
+	Name           string `json:"name"`
+	XIDAge         int64  `json:"xidAge"`
+	MultiXactIDAge int64  `json:"multiXactIDAge"`
+}
+
+// VolumeUsage contains the usage of the filesystem of a volume of the instance
+type VolumeUsage struct {
+	Role           utils.PVCRole `json:"role"`
+	Tablespace     string        `json:"tablespace,omitempty"`
+	TotalBytes     int64         `json:"totalBytes"`
+	UsedBytes      int64         `json:"usedBytes"`
+	AvailableBytes int64         `json:"availableBytes"`
+	TotalInodes    int64         `json:"totalInodes,omitempty"`
+	FreeInodes     int64         `json:"freeInodes,omitempty"`
+}
+
+// UsedPercentage returns the percentage of used space in the volume. Like
+// `df`, the space reserved to the superuser is not considered
+func (v VolumeUsage) UsedPercentage() float64 {
+	total := v.UsedBytes + v.AvailableBytes
+	if total <= 0 {
+		return 0
+	}
+	return float64(v.UsedBytes) * 100 / float64(total)
+}
+
+// InodesUsedPercentage returns the percentage of used inodes in the volume
+func (v VolumeUsage) InodesUsedPercentage() float64 {
+	if v.TotalInodes <= 0 {
+		return 0
+	}
+	return float64(v.TotalInodes-v.FreeInodes) * 100 / float64(v.TotalInodes)
+}
+
 // PgStatReplication contains the replications of replicas as reported by the primary instance
 type PgStatReplication struct {
 	ApplicationName string    `json:"applicationName,omitempty"`
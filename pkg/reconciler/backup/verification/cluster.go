@@ -0,0 +1,211 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package verification
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+// clusterTypeMeta is the TypeMeta of the Cluster resource, needed
+// to build the owner references
+var clusterTypeMeta = metav1.TypeMeta{
+	APIVersion: apiv1.SchemeGroupVersion.String(),
+	Kind:       apiv1.ClusterKind,
+}
+
+// getLatestBackup gets the most recent completed backup of the cluster
+// which can be restored in the temporary cluster
+func getLatestBackup(cluster *apiv1.Cluster, backups []apiv1.Backup) *apiv1.Backup {
+	majorVersion, majorVersionErr := cluster.GetPostgresqlMajorVersion()
+
+	var result *apiv1.Backup
+	for i := range backups {
+		backup := &backups[i]
+		if backup.Spec.Cluster.Name != cluster.Name ||
+			backup.Status.Phase != apiv1.BackupPhaseCompleted ||
+			backup.Status.StoppedAt == nil ||
+			!backup.DeletionTimestamp.IsZero() {
+			continue
+		}
+
+		if backup.Status.Method != apiv1.BackupMethodBarmanObjectStore &&
+			backup.Status.Method != apiv1.BackupMethodVolumeSnapshot {
+			continue
+		}
+
+		if majorVersionErr == nil && backup.Status.MajorVersion != 0 &&
+			backup.Status.MajorVersion != majorVersion {
+			continue
+		}
+
+		if result == nil || backup.Status.StoppedAt.After(result.Status.StoppedAt.Time) {
+			result = backup
+		}
+	}
+
+	return result
+}
+
+// buildTemporaryCluster builds the single-instance cluster where
+// the passed backup is restored
+func buildTemporaryCluster(cluster *apiv1.Cluster, backup *apiv1.Backup) *apiv1.Cluster {
+	spec := cluster.Spec.DeepCopy()
+
+	// The temporary cluster must not archive its WALs, nor replicate
+	// from other clusters
+	spec.Instances = 1
+	spec.MinSyncReplicas = 0
+	spec.MaxSyncReplicas = 0
+	spec.PostgresConfiguration.Synchronous = nil
+	spec.Backup = nil
+	spec.ReplicaCluster = nil
+	spec.ExternalClusters = nil
+	spec.Managed = nil
+	spec.Monitoring = nil
+	spec.MaintenanceWindow = nil
+	spec.BackupVerification = nil
+	spec.Plugins = slices.DeleteFunc(spec.Plugins, func(plugin apiv1.PluginConfiguration) bool {
+		return ptr.Deref(plugin.IsWALArchiver, false)
+	})
+
+	// The checks are executed with the superuser credentials
+	spec.EnableSuperuserAccess = ptr.To(true)
+	spec.SuperuserSecret = nil
+
+	recovery := &apiv1.BootstrapRecovery{}
+	if cluster.Spec.Bootstrap != nil && cluster.Spec.Bootstrap.InitDB != nil {
+		recovery.Database = cluster.Spec.Bootstrap.InitDB.Database
+		recovery.Owner = cluster.Spec.Bootstrap.InitDB.Owner
+		recovery.Secret = cluster.Spec.Bootstrap.InitDB.Secret
+	}
+
+	switch backup.Status.Method {
+	case apiv1.BackupMethodVolumeSnapshot:
+		recovery.VolumeSnapshots = specs.BuildVolumeSnapshotDataSource(backup)
+	default:
+		recovery.Backup = &apiv1.BackupSource{
+			LocalObjectReference: apiv1.LocalObjectReference{Name: backup.Name},
+		}
+	}
+	spec.Bootstrap = &apiv1.BootstrapConfiguration{Recovery: recovery}
+
+	result := &apiv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      cluster.GetBackupVerificationClusterName(),
+			Namespace: cluster.Namespace,
+		},
+		Spec: *spec,
+	}
+	utils.SetAsOwnedBy(&result.ObjectMeta, cluster.ObjectMeta, clusterTypeMeta)
+
+	return result
+}
+
+// getCheckJobName gets the name of the job executing the checks
+// in the temporary cluster
+func getCheckJobName(temporaryCluster *apiv1.Cluster) string {
+	return fmt.Sprintf("%s-check", temporaryCluster.Name)
+}
+
+// buildCheckJob builds the job executing the checks in the temporary
+// cluster. Every check is passed in its own environment variable, to
+// avoid any quoting issue
+func buildCheckJob(
+	cluster *apiv1.Cluster,
+	temporaryCluster *apiv1.Cluster,
+) *batchv1.Job {
+	configuration := cluster.Spec.BackupVerification
+
+	database := configuration.Database
+	if database == "" {
+		database = cluster.GetApplicationDatabaseName()
+	}
+
+	secretKeyRef := func(key string) *corev1.EnvVarSource {
+		return &corev1.EnvVarSource{
+			SecretKeyRef: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{
+					Name: temporaryCluster.GetSuperuserSecretName(),
+				},
+				Key: key,
+			},
+		}
+	}
+
+	env := []corev1.EnvVar{
+		{Name: "PGHOST", Value: temporaryCluster.GetServiceReadWriteName()},
+		{Name: "PGDATABASE", Value: database},
+		{Name: "PGUSER", ValueFrom: secretKeyRef(corev1.BasicAuthUsernameKey)},
+		{Name: "PGPASSWORD", ValueFrom: secretKeyRef(corev1.BasicAuthPasswordKey)},
+	}
+
+	checks := configuration.GetChecks()
+	script := make([]string, 0, len(checks)+1)
+	script = append(script, "set -e")
+	for i, check := range checks {
+		variable := fmt.Sprintf("CHECK_%d", i)
+		env = append(env, corev1.EnvVar{Name: variable, Value: check})
+		script = append(script, fmt.Sprintf(
+			`result=$(psql -X -v ON_ERROR_STOP=1 -tA -c "$%s")`+
+				`; if [ "$result" = "f" ]; then echo "check %d returned false"; exit 1; fi`,
+			variable, i))
+	}
+
+	result := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      getCheckJobName(temporaryCluster),
+			Namespace: temporaryCluster.Namespace,
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit: ptr.To[int32](0),
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					RestartPolicy:   corev1.RestartPolicyNever,
+					SchedulerName:   cluster.Spec.SchedulerName,
+					SecurityContext: specs.GetPodSecurityContext(cluster),
+					Containers: []corev1.Container{
+						{
+							Name:            "check",
+							Image:           temporaryCluster.Status.Image,
+							ImagePullPolicy: cluster.Spec.ImagePullPolicy,
+							Env:             env,
+							Command:         []string{"sh", "-c", strings.Join(script, "\n")},
+							SecurityContext: specs.GetSecurityContext(cluster),
+						},
+					},
+				},
+			},
+		},
+	}
+	utils.SetAsOwnedBy(&result.ObjectMeta, temporaryCluster.ObjectMeta, clusterTypeMeta)
+
+	return result
+}
@@ -0,0 +1,77 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package verification
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+)
+
+var (
+	lastDuration = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "cnpg",
+		Subsystem: "backup_verification",
+		Name:      "last_duration_seconds",
+		Help:      "Time taken by the last verification of the backups of the cluster.",
+	}, []string{"namespace", "cluster"})
+
+	lastSucceeded = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "cnpg",
+		Subsystem: "backup_verification",
+		Name:      "last_succeeded",
+		Help:      "1 if the last verification of the backups of the cluster succeeded, 0 otherwise.",
+	}, []string{"namespace", "cluster"})
+
+	lastCompletionTime = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "cnpg",
+		Subsystem: "backup_verification",
+		Name:      "last_completion_timestamp_seconds",
+		Help:      "Unix timestamp of the completion of the last verification of the backups of the cluster.",
+	}, []string{"namespace", "cluster"})
+)
+
+func init() {
+	metrics.Registry.MustRegister(lastDuration, lastSucceeded, lastCompletionTime)
+}
+
+// observeResult updates the metrics with the outcome of a verification
+func observeResult(cluster *apiv1.Cluster, result *apiv1.BackupVerificationResult) {
+	succeeded := 0.0
+	if result.Succeeded {
+		succeeded = 1
+	}
+
+	lastDuration.WithLabelValues(cluster.Namespace, cluster.Name).
+		Set(result.CompletedAt.Sub(result.StartedAt.Time).Seconds())
+	lastSucceeded.WithLabelValues(cluster.Namespace, cluster.Name).Set(succeeded)
+	lastCompletionTime.WithLabelValues(cluster.Namespace, cluster.Name).
+		Set(float64(result.CompletedAt.UnixNano()) / float64(time.Second))
+}
+
+// ForgetCluster removes the metrics of a cluster which doesn't exist anymore
+func ForgetCluster(namespace, name string) {
+	lastDuration.DeleteLabelValues(namespace, name)
+	lastSucceeded.DeleteLabelValues(namespace, name)
+	lastCompletionTime.DeleteLabelValues(namespace, name)
+}
@@ -0,0 +1,371 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package verification
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/cloudnative-pg/machinery/pkg/log"
+	"github.com/robfig/cron"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/resources/status"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+// runningRequeueInterval is how often a running verification is checked
+const runningRequeueInterval = 30 * time.Second
+
+// Reconcile starts a new verification of the backups of the cluster when
+// its schedule requires it, and follows the one being executed
+func Reconcile(
+	ctx context.Context,
+	cli client.Client,
+	recorder record.EventRecorder,
+	cluster *apiv1.Cluster,
+	now time.Time,
+) (ctrl.Result, error) {
+	configuration := cluster.Spec.BackupVerification
+	verificationStatus := cluster.Status.BackupVerification
+
+	if verificationStatus != nil && verificationStatus.Running != nil {
+		if configuration == nil {
+			// The verification has been disabled while running
+			return ctrl.Result{}, cancelVerification(ctx, cli, cluster)
+		}
+		return reconcileRunningVerification(ctx, cli, recorder, cluster, now)
+	}
+
+	if configuration == nil || configuration.IsSuspended() {
+		return ctrl.Result{}, nil
+	}
+
+	schedule, err := cron.Parse(configuration.Schedule)
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("invalid backup verification schedule %q: %w", configuration.Schedule, err)
+	}
+
+	if verificationStatus == nil || verificationStatus.LastScheduleTime == nil {
+		// This is the first time we check this schedule, let's
+		// wait until the first verification is due
+		if err := status.PatchWithOptimisticLock(ctx, cli, cluster, func(cluster *apiv1.Cluster) {
+			if cluster.Status.BackupVerification == nil {
+				cluster.Status.BackupVerification = &apiv1.BackupVerificationStatus{}
+			}
+			cluster.Status.BackupVerification.LastScheduleTime = &metav1.Time{Time: now}
+		}); err != nil {
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{RequeueAfter: schedule.Next(now).Sub(now)}, nil
+	}
+
+	nextTime := schedule.Next(verificationStatus.LastScheduleTime.Time)
+	if now.Before(nextTime) {
+		return ctrl.Result{RequeueAfter: nextTime.Sub(now)}, nil
+	}
+
+	return startVerification(ctx, cli, recorder, cluster, schedule, now)
+}
+
+// startVerification restores the latest backup of the cluster
+// in the temporary cluster
+func startVerification(
+	ctx context.Context,
+	cli client.Client,
+	recorder record.EventRecorder,
+	cluster *apiv1.Cluster,
+	schedule cron.Schedule,
+	now time.Time,
+) (ctrl.Result, error) {
+	contextLogger := log.FromContext(ctx)
+
+	var backupList apiv1.BackupList
+	if err := cli.List(ctx, &backupList, client.InNamespace(cluster.Namespace)); err != nil {
+		return ctrl.Result{}, fmt.Errorf("while listing backups: %w", err)
+	}
+
+	var running *apiv1.BackupVerificationRun
+	backup := getLatestBackup(cluster, backupList.Items)
+	if backup == nil {
+		contextLogger.Info("Skipping the backup verification as no completed backup is available")
+		recorder.Event(cluster, "Warning", "BackupVerificationSkipped",
+			"No completed backup is available to be verified")
+	} else {
+		temporaryCluster, err := createTemporaryCluster(ctx, cli, cluster, backup)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+
+		contextLogger.Info("Started the backup verification",
+			"backupName", backup.Name, "clusterName", temporaryCluster.Name)
+		recorder.Eventf(cluster, "Normal", "BackupVerificationStarted",
+			"Restoring backup %s in cluster %s", backup.Name, temporaryCluster.Name)
+
+		running = &apiv1.BackupVerificationRun{
+			ClusterName: temporaryCluster.Name,
+			ClusterUID:  temporaryCluster.UID,
+			BackupName:  backup.Name,
+			StartedAt:   metav1.Time{Time: now},
+		}
+	}
+
+	if err := status.PatchWithOptimisticLock(ctx, cli, cluster, func(cluster *apiv1.Cluster) {
+		if cluster.Status.BackupVerification == nil {
+			cluster.Status.BackupVerification = &apiv1.BackupVerificationStatus{}
+		}
+		cluster.Status.BackupVerification.LastScheduleTime = &metav1.Time{Time: now}
+		cluster.Status.BackupVerification.Running = running
+	}); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if running == nil {
+		return ctrl.Result{RequeueAfter: schedule.Next(now).Sub(now)}, nil
+	}
+
+	return ctrl.Result{RequeueAfter: runningRequeueInterval}, nil
+}
+
+// createTemporaryCluster creates the cluster where the backup is restored.
+// An existing cluster with the same name is reused only when it has been
+// created by a previous verification of the same cluster
+func createTemporaryCluster(
+	ctx context.Context,
+	cli client.Client,
+	cluster *apiv1.Cluster,
+	backup *apiv1.Backup,
+) (*apiv1.Cluster, error) {
+	temporaryCluster := buildTemporaryCluster(cluster, backup)
+	err := cli.Create(ctx, temporaryCluster)
+	if err == nil {
+		return temporaryCluster, nil
+	}
+	if !apierrs.IsAlreadyExists(err) {
+		return nil, fmt.Errorf("while creating the backup verification cluster: %w", err)
+	}
+
+	var existingCluster apiv1.Cluster
+	if err := cli.Get(ctx, client.ObjectKeyFromObject(temporaryCluster), &existingCluster); err != nil {
+		return nil, fmt.Errorf("while getting the backup verification cluster: %w", err)
+	}
+	if !metav1.IsControlledBy(&existingCluster, cluster) {
+		return nil, fmt.Errorf(
+			"cluster %q already exists and is not owned by cluster %q", existingCluster.Name, cluster.Name)
+	}
+
+	return &existingCluster, nil
+}
+
+// reconcileRunningVerification follows the restore of the backup in the
+// temporary cluster, and the execution of the checks
+func reconcileRunningVerification(
+	ctx context.Context,
+	cli client.Client,
+	recorder record.EventRecorder,
+	cluster *apiv1.Cluster,
+	now time.Time,
+) (ctrl.Result, error) {
+	running := cluster.Status.BackupVerification.Running
+	timeout := cluster.Spec.BackupVerification.GetTimeout()
+
+	if now.Sub(running.StartedAt.Time) > timeout {
+		return completeVerification(ctx, cli, recorder, cluster, now, false,
+			fmt.Sprintf("Verification not completed in %s", timeout))
+	}
+
+	var temporaryCluster apiv1.Cluster
+	if err := cli.Get(
+		ctx,
+		client.ObjectKey{Namespace: cluster.Namespace, Name: running.ClusterName},
+		&temporaryCluster,
+	); err != nil {
+		if apierrs.IsNotFound(err) {
+			return completeVerification(ctx, cli, recorder, cluster, now, false,
+				fmt.Sprintf("Cluster %s has been deleted", running.ClusterName))
+		}
+		return ctrl.Result{}, err
+	}
+	if temporaryCluster.UID != running.ClusterUID {
+		return completeVerification(ctx, cli, recorder, cluster, now, false,
+			fmt.Sprintf("Cluster %s has been replaced", running.ClusterName))
+	}
+
+	if temporaryCluster.Status.Phase != apiv1.PhaseHealthy {
+		return ctrl.Result{RequeueAfter: runningRequeueInterval}, nil
+	}
+
+	var job batchv1.Job
+	err := cli.Get(
+		ctx,
+		client.ObjectKey{Namespace: cluster.Namespace, Name: getCheckJobName(&temporaryCluster)},
+		&job,
+	)
+	if apierrs.IsNotFound(err) {
+		if err := cli.Create(ctx, buildCheckJob(cluster, &temporaryCluster)); err != nil &&
+			!apierrs.IsAlreadyExists(err) {
+			return ctrl.Result{}, fmt.Errorf("while creating the backup verification job: %w", err)
+		}
+		return ctrl.Result{RequeueAfter: runningRequeueInterval}, nil
+	}
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if !metav1.IsControlledBy(&job, &temporaryCluster) {
+		return completeVerification(ctx, cli, recorder, cluster, now, false,
+			fmt.Sprintf("Job %s is not owned by cluster %s", job.Name, temporaryCluster.Name))
+	}
+
+	switch {
+	case utils.JobHasOneCompletion(job):
+		return completeVerification(ctx, cli, recorder, cluster, now, true,
+			fmt.Sprintf("Backup %s restored and checked successfully", running.BackupName))
+	case isJobFailed(&job):
+		return completeVerification(ctx, cli, recorder, cluster, now, false,
+			fmt.Sprintf("The checks failed on the restored backup %s", running.BackupName))
+	default:
+		return ctrl.Result{RequeueAfter: runningRequeueInterval}, nil
+	}
+}
+
+// isJobFailed checks if the job failed
+func isJobFailed(job *batchv1.Job) bool {
+	for _, condition := range job.Status.Conditions {
+		if condition.Type == batchv1.JobFailed && condition.Status == corev1.ConditionTrue {
+			return true
+		}
+	}
+	return false
+}
+
+// completeVerification records the outcome of the running verification
+// and deletes the temporary cluster
+func completeVerification(
+	ctx context.Context,
+	cli client.Client,
+	recorder record.EventRecorder,
+	cluster *apiv1.Cluster,
+	now time.Time,
+	succeeded bool,
+	message string,
+) (ctrl.Result, error) {
+	contextLogger := log.FromContext(ctx)
+
+	running := cluster.Status.BackupVerification.Running
+	if err := deleteTemporaryCluster(ctx, cli, cluster.Namespace, running); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	result := &apiv1.BackupVerificationResult{
+		BackupName:  running.BackupName,
+		StartedAt:   running.StartedAt,
+		CompletedAt: metav1.Time{Time: now},
+		Succeeded:   succeeded,
+		Message:     message,
+	}
+
+	condition := metav1.Condition{
+		Type:    string(apiv1.ConditionBackupVerification),
+		Status:  metav1.ConditionTrue,
+		Reason:  string(apiv1.ConditionReasonLastBackupVerificationSucceeded),
+		Message: message,
+	}
+	if !succeeded {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = string(apiv1.ConditionReasonLastBackupVerificationFailed)
+	}
+
+	if err := status.PatchWithOptimisticLock(ctx, cli, cluster, func(cluster *apiv1.Cluster) {
+		cluster.Status.BackupVerification.Running = nil
+		cluster.Status.BackupVerification.LastResult = result
+		meta.SetStatusCondition(&cluster.Status.Conditions, condition)
+	}); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	observeResult(cluster, result)
+
+	contextLogger.Info("Backup verification completed",
+		"backupName", result.BackupName, "succeeded", succeeded, "message", message)
+	if succeeded {
+		recorder.Event(cluster, "Normal", "BackupVerificationSucceeded", message)
+	} else {
+		recorder.Event(cluster, "Warning", "BackupVerificationFailed", message)
+	}
+
+	// Let's go on following the schedule
+	schedule, err := cron.Parse(cluster.Spec.BackupVerification.Schedule)
+	if err != nil {
+		return ctrl.Result{}, nil
+	}
+	nextTime := schedule.Next(cluster.Status.BackupVerification.LastScheduleTime.Time)
+	return ctrl.Result{RequeueAfter: max(nextTime.Sub(now), time.Second)}, nil
+}
+
+// cancelVerification stops the running verification without recording
+// its outcome
+func cancelVerification(ctx context.Context, cli client.Client, cluster *apiv1.Cluster) error {
+	running := cluster.Status.BackupVerification.Running
+	if err := deleteTemporaryCluster(ctx, cli, cluster.Namespace, running); err != nil {
+		return err
+	}
+
+	return status.PatchWithOptimisticLock(ctx, cli, cluster, func(cluster *apiv1.Cluster) {
+		cluster.Status.BackupVerification.Running = nil
+	})
+}
+
+// deleteTemporaryCluster deletes the temporary cluster together with
+// the job executing the checks, which is owned by it. A cluster with the
+// same name which hasn't been created by the verification is left alone
+func deleteTemporaryCluster(
+	ctx context.Context,
+	cli client.Client,
+	namespace string,
+	running *apiv1.BackupVerificationRun,
+) error {
+	var temporaryCluster apiv1.Cluster
+	err := cli.Get(ctx, client.ObjectKey{Namespace: namespace, Name: running.ClusterName}, &temporaryCluster)
+	if apierrs.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("while getting the backup verification cluster: %w", err)
+	}
+	if temporaryCluster.UID != running.ClusterUID {
+		return nil
+	}
+
+	err = cli.Delete(ctx, &temporaryCluster, client.Preconditions{UID: &running.ClusterUID})
+	if err != nil && !apierrs.IsNotFound(err) && !apierrs.IsConflict(err) {
+		return fmt.Errorf("while deleting the backup verification cluster: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,282 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package verification
+
+import (
+	"time"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/scheme"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Backup verification", func() {
+	now := time.Date(2025, time.March, 16, 3, 0, 0, 0, time.UTC)
+
+	var (
+		cluster  *apiv1.Cluster
+		recorder *record.FakeRecorder
+	)
+
+	newBackup := func(name string, method apiv1.BackupMethod, stoppedAt time.Time) *apiv1.Backup {
+		return &apiv1.Backup{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+			Spec: apiv1.BackupSpec{
+				Cluster: apiv1.LocalObjectReference{Name: "cluster-example"},
+				Method:  method,
+			},
+			Status: apiv1.BackupStatus{
+				Method:    method,
+				Phase:     apiv1.BackupPhaseCompleted,
+				StoppedAt: ptr.To(metav1.NewTime(stoppedAt)),
+			},
+		}
+	}
+
+	newClient := func(objects ...client.Object) client.Client {
+		return fake.NewClientBuilder().
+			WithScheme(scheme.BuildWithAllKnownScheme()).
+			WithStatusSubresource(&apiv1.Cluster{}).
+			WithObjects(objects...).
+			Build()
+	}
+
+	BeforeEach(func() {
+		recorder = record.NewFakeRecorder(10)
+		cluster = &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example", Namespace: "default", UID: "uid"},
+			Spec: apiv1.ClusterSpec{
+				Instances: 3,
+				ImageName: "ghcr.io/cloudnative-pg/postgresql:17.4",
+				Bootstrap: &apiv1.BootstrapConfiguration{
+					InitDB: &apiv1.BootstrapInitDB{Database: "app", Owner: "app"},
+				},
+				Backup: &apiv1.BackupConfiguration{
+					BarmanObjectStore: &apiv1.BarmanObjectStoreConfiguration{DestinationPath: "s3://backups/"},
+				},
+				BackupVerification: &apiv1.BackupVerificationConfiguration{
+					Schedule: "0 0 3 * * 0",
+					Database: "app",
+					Checks:   []string{"SELECT count(*) > 0 FROM orders"},
+				},
+			},
+			Status: apiv1.ClusterStatus{
+				BackupVerification: &apiv1.BackupVerificationStatus{
+					LastScheduleTime: ptr.To(metav1.NewTime(now.AddDate(0, 0, -7))),
+				},
+			},
+		}
+	})
+
+	Context("getLatestBackup", func() {
+		It("chooses the most recent completed backup of the cluster", func() {
+			running := newBackup("running", apiv1.BackupMethodBarmanObjectStore, now)
+			running.Status.Phase = apiv1.BackupPhaseRunning
+			otherCluster := newBackup("other-cluster", apiv1.BackupMethodBarmanObjectStore, now)
+			otherCluster.Spec.Cluster.Name = "cluster-other"
+			plugin := newBackup("plugin", apiv1.BackupMethodPlugin, now)
+
+			backup := getLatestBackup(cluster, []apiv1.Backup{
+				*newBackup("old", apiv1.BackupMethodBarmanObjectStore, now.AddDate(0, 0, -2)),
+				*newBackup("recent", apiv1.BackupMethodVolumeSnapshot, now.AddDate(0, 0, -1)),
+				*running, *otherCluster, *plugin,
+			})
+			Expect(backup).ToNot(BeNil())
+			Expect(backup.Name).To(Equal("recent"))
+
+			Expect(getLatestBackup(cluster, []apiv1.Backup{*running})).To(BeNil())
+		})
+	})
+
+	Context("buildTemporaryCluster", func() {
+		It("restores the backup in a single instance cluster", func() {
+			cluster.Spec.Plugins = []apiv1.PluginConfiguration{
+				{Name: "archiver", IsWALArchiver: ptr.To(true)},
+			}
+			temporaryCluster := buildTemporaryCluster(cluster,
+				newBackup("backup", apiv1.BackupMethodBarmanObjectStore, now))
+
+			Expect(temporaryCluster.Name).To(Equal("cluster-example-verification"))
+			Expect(temporaryCluster.OwnerReferences).To(HaveLen(1))
+			Expect(temporaryCluster.OwnerReferences[0].Name).To(Equal("cluster-example"))
+			Expect(temporaryCluster.Spec.Instances).To(Equal(1))
+			Expect(temporaryCluster.Spec.Backup).To(BeNil())
+			Expect(temporaryCluster.Spec.BackupVerification).To(BeNil())
+			Expect(temporaryCluster.Spec.Plugins).To(BeEmpty())
+			Expect(temporaryCluster.GetEnableSuperuserAccess()).To(BeTrue())
+			Expect(temporaryCluster.Spec.Bootstrap.Recovery.Backup.Name).To(Equal("backup"))
+			Expect(temporaryCluster.Spec.Bootstrap.Recovery.Database).To(Equal("app"))
+		})
+	})
+
+	Context("Reconcile", func() {
+		It("waits for the first verification to be due", func(ctx SpecContext) {
+			cluster.Status.BackupVerification = nil
+			cli := newClient(cluster)
+
+			result, err := Reconcile(ctx, cli, recorder, cluster, now)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result.RequeueAfter).To(Equal(7 * 24 * time.Hour))
+			Expect(cluster.Status.BackupVerification.LastScheduleTime.Time).To(BeTemporally("==", now))
+		})
+
+		It("restores the latest backup when the verification is due", func(ctx SpecContext) {
+			cli := newClient(cluster, newBackup("backup", apiv1.BackupMethodBarmanObjectStore, now.Add(-time.Hour)))
+
+			_, err := Reconcile(ctx, cli, recorder, cluster, now)
+			Expect(err).ToNot(HaveOccurred())
+
+			running := cluster.Status.BackupVerification.Running
+			Expect(running).ToNot(BeNil())
+			Expect(running.BackupName).To(Equal("backup"))
+			Expect(running.ClusterName).To(Equal("cluster-example-verification"))
+
+			var temporaryCluster apiv1.Cluster
+			Expect(cli.Get(ctx, client.ObjectKey{Namespace: "default", Name: running.ClusterName},
+				&temporaryCluster)).To(Succeed())
+		})
+
+		It("refuses to reuse a cluster it doesn't own", func(ctx SpecContext) {
+			existingCluster := &apiv1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "cluster-example-verification", Namespace: "default"},
+			}
+			cli := newClient(cluster, existingCluster,
+				newBackup("backup", apiv1.BackupMethodBarmanObjectStore, now.Add(-time.Hour)))
+
+			_, err := Reconcile(ctx, cli, recorder, cluster, now)
+			Expect(err).To(MatchError(ContainSubstring("not owned")))
+			Expect(cluster.Status.BackupVerification.Running).To(BeNil())
+			Expect(cli.Get(ctx, client.ObjectKeyFromObject(existingCluster), &apiv1.Cluster{})).To(Succeed())
+		})
+
+		It("skips the verification when no backup is available", func(ctx SpecContext) {
+			cli := newClient(cluster)
+
+			result, err := Reconcile(ctx, cli, recorder, cluster, now)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result.RequeueAfter).To(Equal(7 * 24 * time.Hour))
+			Expect(cluster.Status.BackupVerification.Running).To(BeNil())
+			Expect(recorder.Events).To(Receive(ContainSubstring("BackupVerificationSkipped")))
+		})
+
+		Context("with a running verification", func() {
+			var temporaryCluster *apiv1.Cluster
+
+			BeforeEach(func() {
+				cluster.Status.BackupVerification.LastScheduleTime = ptr.To(metav1.NewTime(now))
+				cluster.Status.BackupVerification.Running = &apiv1.BackupVerificationRun{
+					ClusterName: "cluster-example-verification",
+					ClusterUID:  "temporary-uid",
+					BackupName:  "backup",
+					StartedAt:   metav1.NewTime(now),
+				}
+				temporaryCluster = buildTemporaryCluster(cluster,
+					newBackup("backup", apiv1.BackupMethodBarmanObjectStore, now))
+				temporaryCluster.UID = "temporary-uid"
+				temporaryCluster.Status.Phase = apiv1.PhaseHealthy
+				temporaryCluster.Status.Image = "ghcr.io/cloudnative-pg/postgresql:17.4"
+			})
+
+			It("runs the checks when the temporary cluster is healthy", func(ctx SpecContext) {
+				cli := newClient(cluster, temporaryCluster)
+
+				_, err := Reconcile(ctx, cli, recorder, cluster, now.Add(10*time.Minute))
+				Expect(err).ToNot(HaveOccurred())
+
+				var job batchv1.Job
+				Expect(cli.Get(ctx, client.ObjectKey{Namespace: "default", Name: "cluster-example-verification-check"},
+					&job)).To(Succeed())
+				container := job.Spec.Template.Spec.Containers[0]
+				Expect(container.Image).To(Equal("ghcr.io/cloudnative-pg/postgresql:17.4"))
+				Expect(container.Env).To(ContainElements(
+					corev1.EnvVar{Name: "PGHOST", Value: "cluster-example-verification-rw"},
+					corev1.EnvVar{Name: "CHECK_0", Value: "SELECT count(*) > 0 FROM orders"},
+				))
+			})
+
+			It("records the success of the checks", func(ctx SpecContext) {
+				job := buildCheckJob(cluster, temporaryCluster)
+				job.Status.Succeeded = 1
+				cli := newClient(cluster, temporaryCluster, job)
+
+				result, err := Reconcile(ctx, cli, recorder, cluster, now.Add(10*time.Minute))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result.RequeueAfter).To(Equal(7*24*time.Hour - 10*time.Minute))
+
+				verificationStatus := cluster.Status.BackupVerification
+				Expect(verificationStatus.Running).To(BeNil())
+				Expect(verificationStatus.LastResult.Succeeded).To(BeTrue())
+				Expect(verificationStatus.LastResult.BackupName).To(Equal("backup"))
+				Expect(meta.IsStatusConditionTrue(cluster.Status.Conditions,
+					string(apiv1.ConditionBackupVerification))).To(BeTrue())
+
+				err = cli.Get(ctx, client.ObjectKeyFromObject(temporaryCluster), &apiv1.Cluster{})
+				Expect(apierrs.IsNotFound(err)).To(BeTrue())
+			})
+
+			It("records the failure of the checks", func(ctx SpecContext) {
+				job := buildCheckJob(cluster, temporaryCluster)
+				job.Status.Conditions = []batchv1.JobCondition{
+					{Type: batchv1.JobFailed, Status: corev1.ConditionTrue},
+				}
+				cli := newClient(cluster, temporaryCluster, job)
+
+				_, err := Reconcile(ctx, cli, recorder, cluster, now.Add(10*time.Minute))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(cluster.Status.BackupVerification.LastResult.Succeeded).To(BeFalse())
+				Expect(meta.IsStatusConditionFalse(cluster.Status.Conditions,
+					string(apiv1.ConditionBackupVerification))).To(BeTrue())
+			})
+
+			It("fails the verification after the timeout", func(ctx SpecContext) {
+				temporaryCluster.Status.Phase = apiv1.PhaseFirstPrimary
+				cli := newClient(cluster, temporaryCluster)
+
+				_, err := Reconcile(ctx, cli, recorder, cluster, now.Add(2*time.Hour))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(cluster.Status.BackupVerification.LastResult.Succeeded).To(BeFalse())
+				Expect(cluster.Status.BackupVerification.LastResult.Message).To(ContainSubstring("not completed"))
+			})
+
+			It("doesn't delete a cluster replacing the temporary one", func(ctx SpecContext) {
+				temporaryCluster.UID = "another-uid"
+				cli := newClient(cluster, temporaryCluster)
+
+				_, err := Reconcile(ctx, cli, recorder, cluster, now.Add(10*time.Minute))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(cluster.Status.BackupVerification.LastResult.Succeeded).To(BeFalse())
+				Expect(cluster.Status.BackupVerification.LastResult.Message).To(ContainSubstring("replaced"))
+				Expect(cli.Get(ctx, client.ObjectKeyFromObject(temporaryCluster), &apiv1.Cluster{})).To(Succeed())
+			})
+		})
+	})
+})
@@ -0,0 +1,193 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package volumesnapshot
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/cloudnative-pg/machinery/pkg/log"
+	volumesnapshotv1 "github.com/kubernetes-csi/external-snapshotter/client/v8/apis/volumesnapshot/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+// EnforceRetentionPolicy deletes the completed volume snapshot backups of the
+// cluster which are not retained by the configured retention policy, together
+// with their VolumeSnapshots. It returns the names of the deleted backups
+func EnforceRetentionPolicy(
+	ctx context.Context,
+	cli client.Client,
+	cluster *apiv1.Cluster,
+	now time.Time,
+) ([]string, error) {
+	contextLogger := log.FromContext(ctx)
+
+	if cluster.Spec.Backup == nil || cluster.Spec.Backup.VolumeSnapshot == nil ||
+		cluster.Spec.Backup.VolumeSnapshot.RetentionPolicy == nil {
+		return nil, nil
+	}
+
+	var backupList apiv1.BackupList
+	if err := cli.List(ctx, &backupList, client.InNamespace(cluster.Namespace)); err != nil {
+		return nil, fmt.Errorf("while listing backups: %w", err)
+	}
+
+	candidates := make([]apiv1.Backup, 0, len(backupList.Items))
+	for _, backup := range backupList.Items {
+		if backup.Spec.Cluster.Name != cluster.Name ||
+			backup.Status.Method != apiv1.BackupMethodVolumeSnapshot ||
+			backup.Status.Phase != apiv1.BackupPhaseCompleted ||
+			!backup.DeletionTimestamp.IsZero() {
+			continue
+		}
+		candidates = append(candidates, backup)
+	}
+
+	expired, err := getExpiredBackups(candidates, cluster.Spec.Backup.VolumeSnapshot.RetentionPolicy, now)
+	if err != nil {
+		return nil, err
+	}
+
+	deleted := make([]string, 0, len(expired))
+	for i := range expired {
+		backup := &expired[i]
+		if err := DeleteBackupWithSnapshots(ctx, cli, backup); err != nil {
+			return deleted, err
+		}
+		contextLogger.Info("Deleted volume snapshot backup according to the retention policy",
+			"backupName", backup.Name)
+		deleted = append(deleted, backup.Name)
+	}
+
+	return deleted, nil
+}
+
+// DeleteBackupWithSnapshots deletes the VolumeSnapshots taken by a backup,
+// and then the backup itself
+func DeleteBackupWithSnapshots(ctx context.Context, cli client.Client, backup *apiv1.Backup) error {
+	if err := cli.DeleteAllOf(
+		ctx,
+		&volumesnapshotv1.VolumeSnapshot{},
+		client.InNamespace(backup.Namespace),
+		client.MatchingLabels{utils.BackupNameLabelName: backup.Name},
+	); err != nil {
+		return fmt.Errorf("while deleting the volume snapshots of backup %q: %w", backup.Name, err)
+	}
+
+	if err := cli.Delete(ctx, backup); err != nil && !apierrs.IsNotFound(err) {
+		return fmt.Errorf("while deleting backup %q: %w", backup.Name, err)
+	}
+
+	return nil
+}
+
+// getExpiredBackups returns the backups which are not selected by any rule of
+// the retention policy. The most recent backup is always retained, as it is
+// the one defining the last recoverability point of the cluster
+func getExpiredBackups(
+	backups []apiv1.Backup,
+	policy *apiv1.VolumeSnapshotRetentionPolicy,
+	now time.Time,
+) ([]apiv1.Backup, error) {
+	if policy == nil || len(backups) == 0 {
+		return nil, nil
+	}
+
+	// Sort the backups from the most recent to the oldest one
+	sorted := slices.Clone(backups)
+	slices.SortStableFunc(sorted, func(a, b apiv1.Backup) int {
+		return getBackupTime(&b).Compare(getBackupTime(&a))
+	})
+
+	retained := make([]bool, len(sorted))
+	retained[0] = true
+
+	if policy.MaxAge != "" {
+		threshold, err := utils.GetRetentionThreshold(policy.MaxAge, now)
+		if err != nil {
+			return nil, err
+		}
+		for i := range sorted {
+			if !getBackupTime(&sorted[i]).Before(threshold) {
+				retained[i] = true
+			}
+		}
+	}
+
+	if policy.KeepLast != nil {
+		for i := 0; i < len(sorted) && i < *policy.KeepLast; i++ {
+			retained[i] = true
+		}
+	}
+
+	keepByPeriod := func(count *int, period func(time.Time) string) {
+		if count == nil {
+			return
+		}
+		seenPeriods := 0
+		lastPeriod := ""
+		for i := range sorted {
+			currentPeriod := period(getBackupTime(&sorted[i]))
+			if currentPeriod == lastPeriod {
+				continue
+			}
+			if seenPeriods >= *count {
+				return
+			}
+			retained[i] = true
+			lastPeriod = currentPeriod
+			seenPeriods++
+		}
+	}
+
+	keepByPeriod(policy.KeepDaily, func(t time.Time) string {
+		return t.UTC().Format(time.DateOnly)
+	})
+	keepByPeriod(policy.KeepWeekly, func(t time.Time) string {
+		year, week := t.UTC().ISOWeek()
+		return fmt.Sprintf("%d-%d", year, week)
+	})
+	keepByPeriod(policy.KeepMonthly, func(t time.Time) string {
+		return t.UTC().Format("2006-01")
+	})
+
+	var result []apiv1.Backup
+	for i := range sorted {
+		if !retained[i] {
+			result = append(result, sorted[i])
+		}
+	}
+
+	return result, nil
+}
+
+// getBackupTime returns the time when the backup was completed
+func getBackupTime(backup *apiv1.Backup) time.Time {
+	if backup.Status.StoppedAt != nil {
+		return backup.Status.StoppedAt.Time
+	}
+	return backup.CreationTimestamp.Time
+}
@@ -0,0 +1,182 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package volumesnapshot
+
+import (
+	"time"
+
+	volumesnapshotv1 "github.com/kubernetes-csi/external-snapshotter/client/v8/apis/volumesnapshot/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/scheme"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Volume snapshot retention policy", func() {
+	now := time.Date(2025, time.March, 15, 12, 0, 0, 0, time.UTC)
+
+	newBackup := func(name string, stoppedAt time.Time) apiv1.Backup {
+		return apiv1.Backup{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+			},
+			Spec: apiv1.BackupSpec{
+				Cluster: apiv1.LocalObjectReference{Name: "cluster-example"},
+				Method:  apiv1.BackupMethodVolumeSnapshot,
+			},
+			Status: apiv1.BackupStatus{
+				Method:    apiv1.BackupMethodVolumeSnapshot,
+				Phase:     apiv1.BackupPhaseCompleted,
+				StoppedAt: ptr.To(metav1.NewTime(stoppedAt)),
+			},
+		}
+	}
+
+	names := func(backups []apiv1.Backup) []string {
+		result := make([]string, len(backups))
+		for i := range backups {
+			result[i] = backups[i].Name
+		}
+		return result
+	}
+
+	Context("getExpiredBackups", func() {
+		backups := []apiv1.Backup{
+			newBackup("day-1-morning", now.Add(-24*time.Hour-2*time.Hour)),
+			newBackup("today", now.Add(-time.Hour)),
+			newBackup("day-1-evening", now.Add(-24*time.Hour+6*time.Hour)),
+			newBackup("day-10", now.AddDate(0, 0, -10)),
+			newBackup("day-40", now.AddDate(0, 0, -40)),
+		}
+
+		It("deletes the backups older than the maximum age", func() {
+			expired, err := getExpiredBackups(backups, &apiv1.VolumeSnapshotRetentionPolicy{MaxAge: "7d"}, now)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(names(expired)).To(ConsistOf("day-10", "day-40"))
+		})
+
+		It("keeps the requested number of recent backups", func() {
+			expired, err := getExpiredBackups(backups, &apiv1.VolumeSnapshotRetentionPolicy{KeepLast: ptr.To(2)}, now)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(names(expired)).To(ConsistOf("day-1-morning", "day-10", "day-40"))
+		})
+
+		It("keeps the most recent backup of each day", func() {
+			expired, err := getExpiredBackups(backups, &apiv1.VolumeSnapshotRetentionPolicy{KeepDaily: ptr.To(3)}, now)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(names(expired)).To(ConsistOf("day-1-morning", "day-40"))
+		})
+
+		It("keeps the most recent backup of each month", func() {
+			expired, err := getExpiredBackups(backups, &apiv1.VolumeSnapshotRetentionPolicy{KeepMonthly: ptr.To(2)}, now)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(names(expired)).To(ConsistOf("day-1-morning", "day-1-evening", "day-10"))
+		})
+
+		It("retains a backup selected by any of the rules", func() {
+			expired, err := getExpiredBackups(backups, &apiv1.VolumeSnapshotRetentionPolicy{
+				MaxAge:     "1d",
+				KeepWeekly: ptr.To(4),
+			}, now)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(names(expired)).To(ConsistOf("day-1-morning"))
+		})
+
+		It("never deletes the most recent backup", func() {
+			expired, err := getExpiredBackups(
+				[]apiv1.Backup{newBackup("old", now.AddDate(0, -6, 0))},
+				&apiv1.VolumeSnapshotRetentionPolicy{MaxAge: "1d"},
+				now,
+			)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(expired).To(BeEmpty())
+		})
+	})
+
+	Context("EnforceRetentionPolicy", func() {
+		It("deletes the expired backups of the cluster and their snapshots", func(ctx SpecContext) {
+			cluster := &apiv1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "cluster-example", Namespace: "default"},
+				Spec: apiv1.ClusterSpec{
+					Backup: &apiv1.BackupConfiguration{
+						VolumeSnapshot: &apiv1.VolumeSnapshotConfiguration{
+							RetentionPolicy: &apiv1.VolumeSnapshotRetentionPolicy{KeepLast: ptr.To(1)},
+						},
+					},
+				},
+			}
+
+			recent := newBackup("recent", now.Add(-time.Hour))
+			old := newBackup("old", now.AddDate(0, 0, -2))
+			otherCluster := newBackup("other-cluster", now.AddDate(0, 0, -2))
+			otherCluster.Spec.Cluster.Name = "cluster-other"
+			running := newBackup("running", now.AddDate(0, 0, -3))
+			running.Status.Phase = apiv1.BackupPhaseRunning
+
+			snapshot := func(name, backupName string) *volumesnapshotv1.VolumeSnapshot {
+				return &volumesnapshotv1.VolumeSnapshot{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      name,
+						Namespace: "default",
+						Labels:    map[string]string{utils.BackupNameLabelName: backupName},
+					},
+				}
+			}
+
+			cli := fake.NewClientBuilder().
+				WithScheme(scheme.BuildWithAllKnownScheme()).
+				WithObjects(
+					cluster, &recent, &old, &otherCluster, &running,
+					snapshot("recent-1", "recent"), snapshot("old-1", "old"), snapshot("old-1-wal", "old"),
+				).
+				Build()
+
+			deleted, err := EnforceRetentionPolicy(ctx, cli, cluster, now)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(deleted).To(ConsistOf("old"))
+
+			var backupList apiv1.BackupList
+			Expect(cli.List(ctx, &backupList)).To(Succeed())
+			Expect(names(backupList.Items)).To(ConsistOf("recent", "other-cluster", "running"))
+
+			var snapshotList volumesnapshotv1.VolumeSnapshotList
+			Expect(cli.List(ctx, &snapshotList, client.InNamespace("default"))).To(Succeed())
+			Expect(snapshotList.Items).To(HaveLen(1))
+			Expect(snapshotList.Items[0].Name).To(Equal("recent-1"))
+		})
+
+		It("does nothing without a retention policy", func(ctx SpecContext) {
+			cluster := &apiv1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "cluster-example", Namespace: "default"},
+			}
+			deleted, err := EnforceRetentionPolicy(ctx, nil, cluster, now)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(deleted).To(BeEmpty())
+		})
+	})
+})
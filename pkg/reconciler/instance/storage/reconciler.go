@@ -21,6 +21,8 @@ package storage
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"io/fs"
 	"os"
 
@@ -58,19 +60,32 @@ func ReconcileWalDirectory(ctx context.Context) error {
 func internalReconcileWalDirectory(ctx context.Context, opts walDirectoryReconcilerOptions) error {
 	contextLogger := log.FromContext(ctx)
 
-	// Important: for now walStorage cannot be disabled once configured
-	if pgWalExists, err := fileutils.FileExists(opts.walVolumeDirectory); err != nil {
+	walVolumeExists, err := fileutils.FileExists(opts.walVolumeDirectory)
+	if err != nil {
 		return err
-	} else if !pgWalExists {
-		return nil
 	}
 
 	// Check if `pg_wal` is already a symbolic link; if so, no further action is needed.
 	pgWalDirInfo, err := os.Lstat(opts.pgWalDirectory)
 	if err != nil {
+		if !walVolumeExists && errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
 		return err
 	}
-	if pgWalDirInfo.Mode().Type() == fs.ModeSymlink {
+	isSymlink := pgWalDirInfo.Mode().Type() == fs.ModeSymlink
+
+	// The WALs are never moved back from the WAL volume: when the WAL storage
+	// is removed, the instances using it are replaced by new ones
+	if !walVolumeExists {
+		if isSymlink {
+			return fmt.Errorf("%s is a symbolic link, but the WAL volume is not mounted in %s",
+				opts.pgWalDirectory, opts.walVolumeDirectory)
+		}
+		return nil
+	}
+
+	if isSymlink {
 		return nil
 	}
 
@@ -54,6 +54,17 @@ var _ = Describe("WAL Storage reconciler", func() {
 		Expect(err).ToNot(HaveOccurred())
 	})
 
+	It("will error out if pg_wal is a symlink but the WAL volume is not mounted", func(ctx SpecContext) {
+		err := fileutils.EnsureDirectoryExists(pgDataDir)
+		Expect(err).ToNot(HaveOccurred())
+
+		err = os.Symlink(separateWALVolumeWALDir, opts.pgWalDirectory)
+		Expect(err).ToNot(HaveOccurred())
+
+		err = internalReconcileWalDirectory(ctx, opts)
+		Expect(err).To(HaveOccurred())
+	})
+
 	It("won't change anything if pg_wal is already a symlink", func(ctx SpecContext) {
 		err := fileutils.EnsureDirectoryExists(pgDataDir)
 		Expect(err).ToNot(HaveOccurred())
@@ -28,6 +28,7 @@ import (
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/utils/ptr"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -35,6 +36,7 @@ import (
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/configuration"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/controller/rollout"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/resources/status"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
@@ -79,6 +81,31 @@ func Reconcile(
 		return nil, err
 	}
 
+	windowResult, err := rollout.EvaluateMaintenanceWindow(cluster.Spec.MaintenanceWindow, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if !windowResult.Open {
+		contextLogger.Info("Major version upgrade waiting for the maintenance window",
+			"requestedMajor", requestedMajor, "nextOpening", windowResult.NextOpening)
+		if err := status.PatchWithOptimisticLock(
+			ctx,
+			c,
+			cluster,
+			status.SetPhase(apiv1.PhaseUpgradeDelayed,
+				fmt.Sprintf("Upgrade to major version %v waiting for the maintenance window opening at %s",
+					requestedMajor, windowResult.NextOpening.Format(time.RFC3339))),
+			func(cluster *apiv1.Cluster) {
+				meta.SetStatusCondition(&cluster.Status.Conditions, rollout.MaintenancePendingCondition(
+					"A major version upgrade", windowResult.NextOpening))
+			},
+			status.SetClusterReadyCondition,
+		); err != nil {
+			return nil, err
+		}
+		return &ctrl.Result{RequeueAfter: time.Until(windowResult.NextOpening)}, nil
+	}
+
 	contextLogger.Info("Reconciling in-place major version upgrades",
 		"primaryNodeSerial", primaryNodeSerial, "requestedMajor", requestedMajor)
 
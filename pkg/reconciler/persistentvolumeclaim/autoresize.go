@@ -0,0 +1,294 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package persistentvolumeclaim
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/cloudnative-pg/machinery/pkg/log"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+// storageClassRequestsSuffix is the suffix of the name of the resource
+// quotas limiting the storage requested for a specific storage class
+const storageClassRequestsSuffix = ".storageclass.storage.k8s.io/requests.storage"
+
+// ReconcileAutoResize expands the PVCs whose volume usage, as last reported
+// by the instances, crossed the threshold of the auto resize policy of
+// their storage configuration
+func ReconcileAutoResize(
+	ctx context.Context,
+	c client.Client,
+	recorder record.EventRecorder,
+	cluster *apiv1.Cluster,
+	pvcs []corev1.PersistentVolumeClaim,
+) error {
+	if !cluster.ShouldResizeInUseVolumes() {
+		return nil
+	}
+
+	for idx := range pvcs {
+		pvc := &pvcs[idx]
+		if !isExpectedByCluster(cluster, pvc) {
+			continue
+		}
+
+		calculator, err := GetExpectedObjectCalculator(pvc.GetLabels())
+		if err != nil {
+			return err
+		}
+
+		storageConfiguration, err := calculator.GetStorageConfiguration(cluster)
+		if err != nil {
+			return err
+		}
+
+		autoResize := storageConfiguration.AutoResize
+		if !autoResize.IsEnabled() {
+			continue
+		}
+
+		usage := getVolumeUsage(cluster, pvc)
+		if usage == nil || usage.UsedPercentage < autoResize.GetUsageThreshold() {
+			continue
+		}
+
+		if err := autoResizePVC(ctx, c, recorder, cluster, pvc, autoResize, usage); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// getVolumeUsage returns the usage of the volume of the PVC, as last
+// reported by the instance using it
+func getVolumeUsage(cluster *apiv1.Cluster, pvc *corev1.PersistentVolumeClaim) *apiv1.VolumeUsage {
+	instanceName := pvc.Labels[utils.InstanceNameLabelName]
+	state, ok := cluster.Status.InstancesReportedState[apiv1.PodName(instanceName)]
+	if !ok {
+		return nil
+	}
+
+	for idx := range state.VolumesUsage {
+		usage := &state.VolumesUsage[idx]
+		if usage.Role == pvc.Labels[utils.PvcRoleLabelName] &&
+			usage.Tablespace == pvc.Labels[utils.TablespaceNameLabelName] {
+			return usage
+		}
+	}
+
+	return nil
+}
+
+func autoResizePVC(
+	ctx context.Context,
+	c client.Client,
+	recorder record.EventRecorder,
+	cluster *apiv1.Cluster,
+	pvc *corev1.PersistentVolumeClaim,
+	autoResize *apiv1.AutoResizeConfiguration,
+	usage *apiv1.VolumeUsage,
+) error {
+	contextLogger := log.FromContext(ctx).WithValues("pvcName", pvc.Name)
+
+	// The filesystem is not expanded yet, so the reported usage
+	// doesn't reflect the new size
+	if isResizeInProgress(pvc) {
+		contextLogger.Debug("PVC expansion in progress, skipping automatic resize")
+		return nil
+	}
+
+	if lastResize, err := time.Parse(time.RFC3339, pvc.Annotations[utils.PVCLastAutoResizeAnnotationName]); err == nil &&
+		time.Since(lastResize) < autoResize.GetCooldown() {
+		contextLogger.Debug("PVC expanded recently, skipping automatic resize", "lastResize", lastResize)
+		return nil
+	}
+
+	currentSize := pvc.Spec.Resources.Requests[corev1.ResourceStorage]
+	nextSize, err := autoResize.GetNextSize(currentSize)
+	if err != nil {
+		recorder.Eventf(cluster, "Warning", "AutoResizeFailed",
+			"Cannot expand PVC %s: %v", pvc.Name, err)
+		return nil
+	}
+
+	if nextSize.Cmp(currentSize) <= 0 {
+		recorder.Eventf(cluster, "Warning", "AutoResizeMaxSizeReached",
+			"The volume of PVC %s is %d%% full, and the PVC already reached the maximum size of %s",
+			pvc.Name, usage.UsedPercentage, autoResize.MaxSize)
+		return nil
+	}
+
+	if err := checkVolumeExpansionAllowed(ctx, c, pvc); err != nil {
+		recorder.Eventf(cluster, "Warning", "AutoResizeNotAllowed",
+			"Cannot expand PVC %s: %v", pvc.Name, err)
+		return nil
+	}
+
+	increment := nextSize.DeepCopy()
+	increment.Sub(currentSize)
+	if err := checkStorageQuota(ctx, c, pvc, increment); err != nil {
+		recorder.Eventf(cluster, "Warning", "AutoResizeQuotaExceeded",
+			"Cannot expand PVC %s: %v", pvc.Name, err)
+		return nil
+	}
+
+	contextLogger.Info("Expanding PVC",
+		"usedPercentage", usage.UsedPercentage,
+		"from", currentSize.String(),
+		"to", nextSize.String())
+	if err := resizePVC(ctx, c, pvc, nextSize, map[string]string{
+		utils.PVCLastAutoResizeAnnotationName: time.Now().Format(time.RFC3339),
+	}); err != nil {
+		return err
+	}
+
+	recorder.Eventf(cluster, "Normal", "AutoResize",
+		"Expanding PVC %s from %s to %s, as its volume is %d%% full",
+		pvc.Name, currentSize.String(), nextSize.String(), usage.UsedPercentage)
+	return nil
+}
+
+// isResizeInProgress checks if the capacity of the PVC didn't reach the
+// requested size yet
+func isResizeInProgress(pvc *corev1.PersistentVolumeClaim) bool {
+	requested := pvc.Spec.Resources.Requests[corev1.ResourceStorage]
+	capacity, ok := pvc.Status.Capacity[corev1.ResourceStorage]
+	return ok && capacity.Cmp(requested) < 0
+}
+
+// checkVolumeExpansionAllowed checks if the storage class of the PVC
+// allows the expansion of the volumes
+func checkVolumeExpansionAllowed(
+	ctx context.Context,
+	c client.Client,
+	pvc *corev1.PersistentVolumeClaim,
+) error {
+	if pvc.Spec.StorageClassName == nil || *pvc.Spec.StorageClassName == "" {
+		return fmt.Errorf("the PVC has no storage class")
+	}
+
+	var storageClass storagev1.StorageClass
+	if err := c.Get(ctx, client.ObjectKey{Name: *pvc.Spec.StorageClassName}, &storageClass); err != nil {
+		return fmt.Errorf("while getting storage class %s: %w", *pvc.Spec.StorageClassName, err)
+	}
+
+	if storageClass.AllowVolumeExpansion == nil || !*storageClass.AllowVolumeExpansion {
+		return fmt.Errorf("storage class %s doesn't allow volume expansion", storageClass.Name)
+	}
+
+	return nil
+}
+
+// checkStorageQuota checks if expanding the PVC by the passed increment
+// would exceed the resource quotas of the namespace
+func checkStorageQuota(
+	ctx context.Context,
+	c client.Client,
+	pvc *corev1.PersistentVolumeClaim,
+	increment resource.Quantity,
+) error {
+	var quotas corev1.ResourceQuotaList
+	if err := c.List(ctx, &quotas, client.InNamespace(pvc.Namespace)); err != nil {
+		return fmt.Errorf("while listing the resource quotas: %w", err)
+	}
+
+	resourceNames := []corev1.ResourceName{corev1.ResourceRequestsStorage}
+	if pvc.Spec.StorageClassName != nil {
+		resourceNames = append(resourceNames,
+			corev1.ResourceName(*pvc.Spec.StorageClassName+storageClassRequestsSuffix))
+	}
+
+	for _, quota := range quotas.Items {
+		for _, resourceName := range resourceNames {
+			hard, ok := quota.Status.Hard[resourceName]
+			if !ok {
+				continue
+			}
+
+			requested := quota.Status.Used[resourceName]
+			requested.Add(increment)
+			if requested.Cmp(hard) > 0 {
+				return fmt.Errorf("the expansion would exceed the %s limit of resource quota %s (%s)",
+					resourceName, quota.Name, hard.String())
+			}
+		}
+	}
+
+	return nil
+}
+
+// getAutoResizedSize returns the size of the largest PVC of the cluster
+// having the passed role, when it's larger than the configured one. New
+// PVCs need to be large enough to contain the data of the instances whose
+// PVCs have been automatically expanded
+func getAutoResizedSize(
+	ctx context.Context,
+	c client.Client,
+	cluster *apiv1.Cluster,
+	configuration *CreateConfiguration,
+) (*resource.Quantity, error) {
+	if !configuration.Storage.AutoResize.IsEnabled() {
+		return nil, nil
+	}
+
+	configuredSize := configuration.Storage.GetSizeOrNil()
+	if configuredSize == nil {
+		return nil, nil
+	}
+
+	labels := client.MatchingLabels{
+		utils.PvcRoleLabelName: configuration.Calculator.GetRoleName(),
+	}
+	if configuration.TablespaceName != "" {
+		labels[utils.TablespaceNameLabelName] = configuration.TablespaceName
+	}
+
+	var pvcs corev1.PersistentVolumeClaimList
+	if err := c.List(ctx, &pvcs, client.InNamespace(cluster.Namespace), labels); err != nil {
+		return nil, err
+	}
+
+	var result *resource.Quantity
+	for idx := range pvcs.Items {
+		pvc := &pvcs.Items[idx]
+		if !metav1.IsControlledBy(pvc, cluster) {
+			continue
+		}
+
+		size := pvc.Spec.Resources.Requests[corev1.ResourceStorage]
+		if size.Cmp(*configuredSize) > 0 && (result == nil || size.Cmp(*result) > 0) {
+			result = &size
+		}
+	}
+
+	return result, nil
+}
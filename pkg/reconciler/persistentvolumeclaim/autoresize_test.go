@@ -0,0 +1,194 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package persistentvolumeclaim
+
+import (
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/scheme"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("PVC automatic resize", func() {
+	const (
+		namespace    = "default"
+		clusterName  = "cluster-example"
+		instanceName = "cluster-example-1"
+		storageClass = "expandable"
+	)
+
+	var (
+		cluster         *apiv1.Cluster
+		pvc             *corev1.PersistentVolumeClaim
+		storageClassObj *storagev1.StorageClass
+		recorder        *record.FakeRecorder
+	)
+
+	BeforeEach(func() {
+		cluster = &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: clusterName, Namespace: namespace},
+			Spec: apiv1.ClusterSpec{
+				StorageConfiguration: apiv1.StorageConfiguration{
+					Size: "10Gi",
+					AutoResize: &apiv1.AutoResizeConfiguration{
+						Enabled:   true,
+						Increment: "5Gi",
+						MaxSize:   "20Gi",
+					},
+				},
+			},
+			Status: apiv1.ClusterStatus{
+				InstancesReportedState: map[apiv1.PodName]apiv1.InstanceReportedState{
+					instanceName: {
+						VolumesUsage: []apiv1.VolumeUsage{
+							{Role: string(utils.PVCRolePgData), UsedPercentage: 85},
+						},
+					},
+				},
+			},
+		}
+
+		pvc = &corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      instanceName,
+				Namespace: namespace,
+				Labels:    NewPgDataCalculator().GetLabels(instanceName),
+			},
+			Spec: corev1.PersistentVolumeClaimSpec{
+				StorageClassName: ptr.To(storageClass),
+				Resources: corev1.VolumeResourceRequirements{
+					Requests: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("10Gi")},
+				},
+			},
+			Status: corev1.PersistentVolumeClaimStatus{
+				Capacity: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("10Gi")},
+			},
+		}
+
+		storageClassObj = &storagev1.StorageClass{
+			ObjectMeta:           metav1.ObjectMeta{Name: storageClass},
+			AllowVolumeExpansion: ptr.To(true),
+		}
+		recorder = record.NewFakeRecorder(10)
+	})
+
+	reconcile := func(ctx SpecContext, objects ...client.Object) *corev1.PersistentVolumeClaim {
+		cli := fake.NewClientBuilder().
+			WithScheme(scheme.BuildWithAllKnownScheme()).
+			WithObjects(append(objects, pvc)...).
+			Build()
+
+		Expect(ReconcileAutoResize(ctx, cli, recorder, cluster, []corev1.PersistentVolumeClaim{*pvc})).To(Succeed())
+
+		var result corev1.PersistentVolumeClaim
+		Expect(cli.Get(ctx, client.ObjectKeyFromObject(pvc), &result)).To(Succeed())
+		return &result
+	}
+
+	requestedSize := func(pvc *corev1.PersistentVolumeClaim) string {
+		size := pvc.Spec.Resources.Requests[corev1.ResourceStorage]
+		return size.String()
+	}
+
+	It("expands the PVCs over the threshold", func(ctx SpecContext) {
+		result := reconcile(ctx, storageClassObj)
+		Expect(requestedSize(result)).To(Equal("15Gi"))
+		Expect(result.Annotations).To(HaveKey(utils.PVCLastAutoResizeAnnotationName))
+		Expect(recorder.Events).To(Receive(ContainSubstring("Expanding PVC cluster-example-1 from 10Gi to 15Gi")))
+	})
+
+	It("doesn't expand the PVCs below the threshold", func(ctx SpecContext) {
+		cluster.Spec.StorageConfiguration.AutoResize.UsageThreshold = ptr.To(int32(90))
+		Expect(requestedSize(reconcile(ctx, storageClassObj))).To(Equal("10Gi"))
+	})
+
+	It("doesn't expand the PVCs during the cooldown period", func(ctx SpecContext) {
+		pvc.Annotations = map[string]string{
+			utils.PVCLastAutoResizeAnnotationName: time.Now().Add(-10 * time.Minute).Format(time.RFC3339),
+		}
+		Expect(requestedSize(reconcile(ctx, storageClassObj))).To(Equal("10Gi"))
+	})
+
+	It("waits for the previous expansion to complete", func(ctx SpecContext) {
+		pvc.Status.Capacity[corev1.ResourceStorage] = resource.MustParse("5Gi")
+		Expect(requestedSize(reconcile(ctx, storageClassObj))).To(Equal("10Gi"))
+	})
+
+	It("doesn't exceed the maximum size", func(ctx SpecContext) {
+		pvc.Spec.Resources.Requests[corev1.ResourceStorage] = resource.MustParse("20Gi")
+		pvc.Status.Capacity[corev1.ResourceStorage] = resource.MustParse("20Gi")
+		Expect(requestedSize(reconcile(ctx, storageClassObj))).To(Equal("20Gi"))
+		Expect(recorder.Events).To(Receive(ContainSubstring("AutoResizeMaxSizeReached")))
+	})
+
+	It("refuses to expand when the storage class doesn't allow it", func(ctx SpecContext) {
+		storageClassObj.AllowVolumeExpansion = nil
+		Expect(requestedSize(reconcile(ctx, storageClassObj))).To(Equal("10Gi"))
+		Expect(recorder.Events).To(Receive(ContainSubstring("AutoResizeNotAllowed")))
+	})
+
+	It("refuses to exceed the resource quotas", func(ctx SpecContext) {
+		quota := &corev1.ResourceQuota{
+			ObjectMeta: metav1.ObjectMeta{Name: "storage", Namespace: namespace},
+			Status: corev1.ResourceQuotaStatus{
+				Hard: corev1.ResourceList{
+					corev1.ResourceName(storageClass + storageClassRequestsSuffix): resource.MustParse("30Gi"),
+				},
+				Used: corev1.ResourceList{
+					corev1.ResourceName(storageClass + storageClassRequestsSuffix): resource.MustParse("28Gi"),
+				},
+			},
+		}
+		Expect(requestedSize(reconcile(ctx, storageClassObj, quota))).To(Equal("10Gi"))
+		Expect(recorder.Events).To(Receive(ContainSubstring("AutoResizeQuotaExceeded")))
+	})
+
+	It("creates the new PVCs with the size of the expanded ones", func(ctx SpecContext) {
+		pvc.Spec.Resources.Requests[corev1.ResourceStorage] = resource.MustParse("15Gi")
+		pvc.OwnerReferences = []metav1.OwnerReference{
+			{Kind: apiv1.ClusterKind, Name: clusterName, Controller: ptr.To(true)},
+		}
+		cli := fake.NewClientBuilder().
+			WithScheme(scheme.BuildWithAllKnownScheme()).
+			WithObjects(pvc).
+			Build()
+
+		size, err := getAutoResizedSize(ctx, cli, cluster, &CreateConfiguration{
+			Calculator: NewPgDataCalculator(),
+			Storage:    cluster.Spec.StorageConfiguration,
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(size).ToNot(BeNil())
+		Expect(size.String()).To(Equal("15Gi"))
+	})
+})
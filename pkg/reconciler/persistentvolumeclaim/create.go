@@ -39,6 +39,18 @@ func createIfNotExists(
 ) error {
 	contextLogger := log.FromContext(ctx)
 
+	autoResizedSize, err := getAutoResizedSize(ctx, c, cluster, configuration)
+	if err != nil {
+		return fmt.Errorf("while detecting the size of the PVCs expanded automatically: %w", err)
+	}
+	if autoResizedSize != nil {
+		contextLogger.Info("Creating the PVC with the size of the PVCs expanded automatically",
+			"size", autoResizedSize.String())
+		resizedConfiguration := *configuration
+		resizedConfiguration.Storage.Size = autoResizedSize.String()
+		configuration = &resizedConfiguration
+	}
+
 	pvc, err := Build(cluster, configuration)
 	if err != nil {
 		if err == ErrorInvalidSize {
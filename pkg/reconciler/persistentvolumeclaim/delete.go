@@ -45,6 +45,12 @@ func EnsureInstancePVCGroupIsDeleted(
 	// todo: this should not rely on expected cluster instance pvc but should fetch every possible pvc name
 	expectedPVCs := getExpectedPVCsFromCluster(cluster, name)
 
+	// The WAL PVC may still exist after the WAL storage has been removed
+	// from the cluster, as instances keep it until they are replaced
+	if !cluster.ShouldCreateWalArchiveVolume() {
+		expectedPVCs = append(expectedPVCs, buildExpectedPVCs(name, []ExpectedObjectCalculator{NewPgWalCalculator()})...)
+	}
+
 	for _, expectedPVC := range expectedPVCs {
 		pvc := corev1.PersistentVolumeClaim{
 			ObjectMeta: metav1.ObjectMeta{
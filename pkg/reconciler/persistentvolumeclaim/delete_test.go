@@ -88,4 +88,22 @@ var _ = Describe("EnsureInstancePVCGroupIsDeleted", func() {
 			Expect(apierrs.IsNotFound(err)).To(BeTrue())
 		}
 	})
+
+	It("should delete the WAL PVC left over from a removed WAL storage", func() {
+		walPVCName := NewPgWalCalculator().GetName(instanceName)
+		err := fakeClient.Create(ctx, &corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      walPVCName,
+				Namespace: namespace,
+			},
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		err = EnsureInstancePVCGroupIsDeleted(ctx, fakeClient, cluster, instanceName, namespace)
+		Expect(err).NotTo(HaveOccurred())
+
+		err = fakeClient.Get(ctx, types.NamespacedName{Name: walPVCName, Namespace: namespace},
+			&corev1.PersistentVolumeClaim{})
+		Expect(apierrs.IsNotFound(err)).To(BeTrue())
+	})
 })
@@ -25,6 +25,7 @@ import (
 
 	"github.com/cloudnative-pg/machinery/pkg/log"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
@@ -67,6 +68,9 @@ func reconcileExistingPVCs(
 
 	for idx := range pvcs {
 		pvc := &pvcs[idx]
+		if !isExpectedByCluster(cluster, pvc) {
+			continue
+		}
 
 		pvcRole, err := GetExpectedObjectCalculator(pvc.GetLabels())
 		if err != nil {
@@ -139,16 +143,37 @@ func reconcilePVCQuantity(
 	case 0:
 		return nil
 	case 1:
-		contextLogger.Warning("cannot decrease storage requirement",
-			"from", currentSize, "to", parsedSize,
-			"pvcName", pvc.Name)
+		// The PVCs expanded automatically are expected to be larger
+		// than the configured size
+		if !storageConfiguration.AutoResize.IsEnabled() {
+			contextLogger.Warning("cannot decrease storage requirement",
+				"from", currentSize, "to", parsedSize,
+				"pvcName", pvc.Name)
+		}
 		return nil
 	}
 
+	return resizePVC(ctx, c, pvc, *parsedSize, nil)
+}
+
+// resizePVC changes the storage requirement of the PVC, adding the
+// passed annotations
+func resizePVC(
+	ctx context.Context,
+	c client.Client,
+	pvc *corev1.PersistentVolumeClaim,
+	size resource.Quantity,
+	annotations map[string]string,
+) error {
+	contextLogger := log.FromContext(ctx)
+
 	oldPVC := pvc.DeepCopy()
 	// right now we reconcile the metadata in a different set of functions, so it's not needed to do it here
 	pvc = resources.NewPersistentVolumeClaimBuilderFromPVC(pvc).
-		WithRequests(corev1.ResourceList{"storage": *parsedSize}).
+		BeginMetadata().
+		WithAnnotations(annotations).
+		EndMetadata().
+		WithRequests(corev1.ResourceList{"storage": size}).
 		Build()
 
 	if err := c.Patch(ctx, pvc, client.MergeFrom(oldPVC)); err != nil {
@@ -190,6 +190,31 @@ var _ = Describe("Reconcile resource requests", func() {
 		)
 		Expect(err).ToNot(HaveOccurred())
 	})
+
+	It("Reconcile resources ignores the WAL PVCs of a removed WAL storage", func() {
+		cluster.Spec = apiv1.ClusterSpec{
+			StorageConfiguration: apiv1.StorageConfiguration{
+				Size: "1Gi",
+			},
+		}
+
+		walPVC := corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: NewPgWalCalculator().GetName("cluster-1"),
+				Labels: map[string]string{
+					utils.InstanceNameLabelName: "cluster-1",
+					utils.PvcRoleLabelName:      string(utils.PVCRolePgWal),
+				},
+			},
+		}
+		err := reconcileExistingPVCs(
+			context.Background(),
+			cli,
+			cluster,
+			[]corev1.PersistentVolumeClaim{walPVC},
+		)
+		Expect(err).ToNot(HaveOccurred())
+	})
 })
 
 var _ = Describe("PVC reconciliation", Ordered, func() {
@@ -443,6 +468,11 @@ var _ = Describe("Storage configuration", func() {
 		Expect(configuration).ToNot(BeNil())
 		Expect(err).ToNot(HaveOccurred())
 	})
+
+	It("Should fail when the WAL storage is not configured", func() {
+		_, err := NewPgWalCalculator().GetStorageConfiguration(&apiv1.Cluster{})
+		Expect(err).To(HaveOccurred())
+	})
 })
 
 var _ = Describe("Reconcile PVC Quantity", func() {
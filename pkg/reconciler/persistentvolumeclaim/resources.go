@@ -28,6 +28,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
 )
 
 // FilterByPodSpec returns all the corev1.PersistentVolumeClaim that are used inside the podSpec
@@ -118,6 +119,24 @@ func InstanceHasMissingMounts(cluster *apiv1.Cluster, instance *corev1.Pod) bool
 	return false
 }
 
+// InstanceHasUnexpectedWalMount returns true if the instance still uses its
+// WAL PVC while the cluster has no separate WAL storage anymore
+func InstanceHasUnexpectedWalMount(cluster *apiv1.Cluster, instance *corev1.Pod) bool {
+	if cluster.ShouldCreateWalArchiveVolume() {
+		return false
+	}
+
+	return IsUsedByPodSpec(instance.Spec, NewPgWalCalculator().GetName(instance.Name))
+}
+
+// isExpectedByCluster checks whether the PVC is part of the storage
+// configuration of the cluster. This is not true for the WAL PVC after the
+// WAL storage has been removed
+func isExpectedByCluster(cluster *apiv1.Cluster, pvc *corev1.PersistentVolumeClaim) bool {
+	instanceName := pvc.Labels[utils.InstanceNameLabelName]
+	return slices.Contains(getExpectedInstancePVCNamesFromCluster(cluster, instanceName), pvc.Name)
+}
+
 type expectedPVC struct {
 	calculator    ExpectedObjectCalculator
 	name          string
@@ -257,7 +257,16 @@ func classifyPVC(
 	pvcNames := getNamesFromPVCList(pvcList)
 
 	// PVC is part of an incomplete group
-	if len(expectedPVCs) > len(pvcNames) || !slices.Contains(expectedPVCs, pvc.Name) {
+	if len(expectedPVCs) > len(pvcNames) {
+		return unusable
+	}
+
+	// PVC is not expected anymore, like the WAL PVC after the WAL storage has been
+	// removed from the cluster. It's still used until the instance is replaced
+	if !slices.Contains(expectedPVCs, pvc.Name) {
+		if hasPod(pvc, podList) {
+			return healthy
+		}
 		return unusable
 	}
 
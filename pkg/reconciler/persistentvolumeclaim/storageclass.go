@@ -0,0 +1,100 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package persistentvolumeclaim
+
+import (
+	"slices"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/ptr"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+// GetInstancesWithOutdatedStorageClass returns the names of the passed
+// instances having at least one PVC whose storage class is different from
+// the one in the storage configuration of the cluster, sorted by name
+func GetInstancesWithOutdatedStorageClass(
+	cluster *apiv1.Cluster,
+	instances []corev1.Pod,
+	pvcs []corev1.PersistentVolumeClaim,
+) []string {
+	var result []string
+	for idx := range instances {
+		instanceName := instances[idx].Name
+		for pvcIdx := range pvcs {
+			pvc := &pvcs[pvcIdx]
+			if pvc.Labels[utils.InstanceNameLabelName] != instanceName {
+				continue
+			}
+
+			if hasOutdatedStorageClass(cluster, pvc) {
+				result = append(result, instanceName)
+				break
+			}
+		}
+	}
+
+	slices.Sort(result)
+	return result
+}
+
+// hasOutdatedStorageClass checks whether the storage class of the PVC is
+// different from the configured one. PVCs whose storage configuration
+// doesn't set the storage class are never outdated, as the default storage
+// class of the Kubernetes cluster may have changed in the meantime, and
+// neither are the PVCs the cluster doesn't expect anymore
+func hasOutdatedStorageClass(cluster *apiv1.Cluster, pvc *corev1.PersistentVolumeClaim) bool {
+	if !isExpectedByCluster(cluster, pvc) {
+		return false
+	}
+
+	calculator, err := GetExpectedObjectCalculator(pvc.GetLabels())
+	if err != nil {
+		return false
+	}
+
+	storageConfiguration, err := calculator.GetStorageConfiguration(cluster)
+	if err != nil {
+		return false
+	}
+
+	expectedStorageClass := getExpectedStorageClass(storageConfiguration)
+	if expectedStorageClass == nil || *expectedStorageClass == "" {
+		return false
+	}
+
+	return ptr.Deref(pvc.Spec.StorageClassName, "") != *expectedStorageClass
+}
+
+// getExpectedStorageClass returns the storage class the PVCs are created
+// with, if set
+func getExpectedStorageClass(storageConfiguration apiv1.StorageConfiguration) *string {
+	if storageConfiguration.StorageClass != nil {
+		return storageConfiguration.StorageClass
+	}
+
+	if storageConfiguration.PersistentVolumeClaimTemplate != nil {
+		return storageConfiguration.PersistentVolumeClaimTemplate.StorageClassName
+	}
+
+	return nil
+}
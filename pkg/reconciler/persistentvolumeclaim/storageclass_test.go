@@ -0,0 +1,118 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package persistentvolumeclaim
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("GetInstancesWithOutdatedStorageClass", func() {
+	var cluster *apiv1.Cluster
+
+	newPod := func(name string) corev1.Pod {
+		return corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: name}}
+	}
+
+	newPVC := func(instanceName string, role utils.PVCRole, storageClass *string) corev1.PersistentVolumeClaim {
+		labels := map[string]string{
+			utils.InstanceNameLabelName: instanceName,
+			utils.PvcRoleLabelName:      string(role),
+		}
+		calculator, err := GetExpectedObjectCalculator(labels)
+		Expect(err).ToNot(HaveOccurred())
+
+		return corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   calculator.GetName(instanceName),
+				Labels: labels,
+			},
+			Spec: corev1.PersistentVolumeClaimSpec{StorageClassName: storageClass},
+		}
+	}
+
+	BeforeEach(func() {
+		cluster = &apiv1.Cluster{
+			Spec: apiv1.ClusterSpec{
+				StorageConfiguration: apiv1.StorageConfiguration{
+					StorageClass: ptr.To("new"),
+				},
+				WalStorage: &apiv1.StorageConfiguration{
+					PersistentVolumeClaimTemplate: &corev1.PersistentVolumeClaimSpec{
+						StorageClassName: ptr.To("new-wal"),
+					},
+				},
+			},
+		}
+	})
+
+	It("returns the instances having a PVC of a different storage class", func() {
+		instances := []corev1.Pod{newPod("cluster-3"), newPod("cluster-1"), newPod("cluster-2")}
+		pvcs := []corev1.PersistentVolumeClaim{
+			newPVC("cluster-1", utils.PVCRolePgData, ptr.To("new")),
+			newPVC("cluster-1", utils.PVCRolePgWal, ptr.To("new-wal")),
+			newPVC("cluster-2", utils.PVCRolePgData, ptr.To("new")),
+			newPVC("cluster-2", utils.PVCRolePgWal, ptr.To("old")),
+			newPVC("cluster-3", utils.PVCRolePgData, ptr.To("old")),
+			newPVC("cluster-3", utils.PVCRolePgWal, ptr.To("old")),
+		}
+
+		Expect(GetInstancesWithOutdatedStorageClass(cluster, instances, pvcs)).
+			To(Equal([]string{"cluster-2", "cluster-3"}))
+	})
+
+	It("ignores the PVCs whose storage class is not configured", func() {
+		cluster.Spec.StorageConfiguration.StorageClass = nil
+		instances := []corev1.Pod{newPod("cluster-1")}
+		pvcs := []corev1.PersistentVolumeClaim{
+			newPVC("cluster-1", utils.PVCRolePgData, ptr.To("standard")),
+			newPVC("cluster-1", utils.PVCRolePgWal, ptr.To("new-wal")),
+		}
+
+		Expect(GetInstancesWithOutdatedStorageClass(cluster, instances, pvcs)).To(BeEmpty())
+	})
+
+	It("ignores the PVCs of a removed WAL storage", func() {
+		cluster.Spec.WalStorage = nil
+		pvcs := []corev1.PersistentVolumeClaim{
+			newPVC("cluster-1", utils.PVCRolePgData, ptr.To("new")),
+			newPVC("cluster-1", utils.PVCRolePgWal, ptr.To("old")),
+		}
+
+		Expect(GetInstancesWithOutdatedStorageClass(cluster, []corev1.Pod{newPod("cluster-1")}, pvcs)).
+			To(BeEmpty())
+	})
+
+	It("ignores the PVCs of the instances that are not passed", func() {
+		pvcs := []corev1.PersistentVolumeClaim{
+			newPVC("cluster-4", utils.PVCRolePgData, ptr.To("old")),
+		}
+
+		Expect(GetInstancesWithOutdatedStorageClass(cluster, []corev1.Pod{newPod("cluster-1")}, pvcs)).
+			To(BeEmpty())
+	})
+})
@@ -0,0 +1,57 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package persistentvolumeclaim
+
+import (
+	"slices"
+
+	corev1 "k8s.io/api/core/v1"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+// GetInstancesWithUnexpectedWalPVC returns the names of the passed instances
+// still having a WAL PVC while the cluster has no separate WAL storage
+// anymore, sorted by name
+func GetInstancesWithUnexpectedWalPVC(
+	cluster *apiv1.Cluster,
+	instances []corev1.Pod,
+	pvcs []corev1.PersistentVolumeClaim,
+) []string {
+	if cluster.ShouldCreateWalArchiveVolume() {
+		return nil
+	}
+
+	var result []string
+	for idx := range instances {
+		instanceName := instances[idx].Name
+		hasWalPVC := slices.ContainsFunc(pvcs, func(pvc corev1.PersistentVolumeClaim) bool {
+			return pvc.Labels[utils.InstanceNameLabelName] == instanceName &&
+				pvc.Labels[utils.PvcRoleLabelName] == string(utils.PVCRolePgWal)
+		})
+		if hasWalPVC {
+			result = append(result, instanceName)
+		}
+	}
+
+	slices.Sort(result)
+	return result
+}
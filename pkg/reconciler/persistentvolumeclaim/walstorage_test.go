@@ -0,0 +1,109 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package persistentvolumeclaim
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("WAL PVCs of a removed WAL storage", func() {
+	var cluster *apiv1.Cluster
+
+	newPVC := func(instanceName string, role utils.PVCRole) corev1.PersistentVolumeClaim {
+		labels := map[string]string{
+			utils.InstanceNameLabelName: instanceName,
+			utils.PvcRoleLabelName:      string(role),
+		}
+		calculator, err := GetExpectedObjectCalculator(labels)
+		Expect(err).ToNot(HaveOccurred())
+
+		return corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   calculator.GetName(instanceName),
+				Labels: labels,
+			},
+		}
+	}
+
+	newPod := func(name string, pvcNames ...string) corev1.Pod {
+		pod := corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: name}}
+		for _, pvcName := range pvcNames {
+			pod.Spec.Volumes = append(pod.Spec.Volumes, corev1.Volume{
+				Name: pvcName,
+				VolumeSource: corev1.VolumeSource{
+					PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: pvcName},
+				},
+			})
+		}
+		return pod
+	}
+
+	BeforeEach(func() {
+		cluster = &apiv1.Cluster{}
+	})
+
+	It("returns the instances still having a WAL PVC", func() {
+		instances := []corev1.Pod{newPod("cluster-2"), newPod("cluster-1"), newPod("cluster-3")}
+		pvcs := []corev1.PersistentVolumeClaim{
+			newPVC("cluster-1", utils.PVCRolePgData),
+			newPVC("cluster-1", utils.PVCRolePgWal),
+			newPVC("cluster-2", utils.PVCRolePgData),
+			newPVC("cluster-2", utils.PVCRolePgWal),
+			newPVC("cluster-3", utils.PVCRolePgData),
+		}
+
+		Expect(GetInstancesWithUnexpectedWalPVC(cluster, instances, pvcs)).
+			To(Equal([]string{"cluster-1", "cluster-2"}))
+
+		cluster.Spec.WalStorage = &apiv1.StorageConfiguration{Size: "1Gi"}
+		Expect(GetInstancesWithUnexpectedWalPVC(cluster, instances, pvcs)).To(BeEmpty())
+	})
+
+	It("detects the instances still mounting the WAL PVC", func() {
+		podWithWal := newPod("cluster-1", "cluster-1", NewPgWalCalculator().GetName("cluster-1"))
+		podWithoutWal := newPod("cluster-1", "cluster-1")
+		Expect(InstanceHasUnexpectedWalMount(cluster, &podWithWal)).To(BeTrue())
+		Expect(InstanceHasUnexpectedWalMount(cluster, &podWithoutWal)).To(BeFalse())
+
+		cluster.Spec.WalStorage = &apiv1.StorageConfiguration{Size: "1Gi"}
+		Expect(InstanceHasUnexpectedWalMount(cluster, &podWithWal)).To(BeFalse())
+	})
+
+	It("classifies the WAL PVC as healthy while the Pod uses it", func(ctx SpecContext) {
+		pgDataPVC := newPVC("cluster-1", utils.PVCRolePgData)
+		walPVC := newPVC("cluster-1", utils.PVCRolePgWal)
+		pvcs := []corev1.PersistentVolumeClaim{pgDataPVC, walPVC}
+
+		podWithWal := newPod("cluster-1", pgDataPVC.Name, walPVC.Name)
+		Expect(classifyPVC(ctx, walPVC, []corev1.Pod{podWithWal}, nil, pvcs, cluster, "cluster-1")).
+			To(Equal(healthy))
+
+		podWithoutWal := newPod("cluster-1", pgDataPVC.Name)
+		Expect(classifyPVC(ctx, walPVC, []corev1.Pod{podWithoutWal}, nil, pvcs, cluster, "cluster-1")).
+			To(Equal(unusable))
+	})
+})
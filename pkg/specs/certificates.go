@@ -0,0 +1,161 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package specs
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/certs"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+// CertManagerCertificateGVK is the GroupVersionKind of the cert-manager
+// certificates. cert-manager is an optional dependency, so its resources
+// are handled as unstructured objects
+var CertManagerCertificateGVK = schema.GroupVersionKind{
+	Group:   "cert-manager.io",
+	Version: "v1",
+	Kind:    "Certificate",
+}
+
+// CertManagerCertificateRequest describes a certificate to be
+// issued by cert-manager for a cluster
+type CertManagerCertificateRequest struct {
+	// The name of the secret where cert-manager will store the certificate,
+	// which is also the name of the Certificate resource
+	SecretName string
+
+	// The common name of the certificate
+	CommonName string
+
+	// The usage of the certificate
+	Usage certs.CertType
+
+	// The DNS names of the certificate
+	DNSNames []string
+
+	// Additional labels to be set on the generated secret
+	SecretLabels map[string]string
+}
+
+// BuildCertManagerCertificate creates the cert-manager Certificate
+// requesting a certificate for the cluster to its issuer
+func BuildCertManagerCertificate(
+	cluster *apiv1.Cluster,
+	request CertManagerCertificateRequest,
+) *unstructured.Unstructured {
+	issuerRef := cluster.Spec.Certificates.IssuerRef
+
+	usages := []any{"digital signature", "key encipherment"}
+	switch request.Usage {
+	case certs.CertTypeServer:
+		usages = append(usages, "server auth")
+	case certs.CertTypeClient:
+		usages = append(usages, "client auth")
+	}
+
+	// The secret is watched by the operator, that propagates
+	// its changes to the instances
+	secretLabels := map[string]any{
+		utils.ClusterLabelName: cluster.Name,
+		utils.WatchedLabelName: "true",
+	}
+	for key, value := range request.SecretLabels {
+		secretLabels[key] = value
+	}
+
+	spec := map[string]any{
+		"secretName": request.SecretName,
+		"commonName": request.CommonName,
+		"usages":     usages,
+		"privateKey": getCertManagerPrivateKey(cluster.GetCertificatesKeyAlgorithm()),
+		"issuerRef": map[string]any{
+			"name":  issuerRef.Name,
+			"kind":  issuerRef.GetKind(),
+			"group": issuerRef.GetGroup(),
+		},
+		"secretTemplate": map[string]any{
+			"labels": secretLabels,
+		},
+	}
+	if len(request.DNSNames) > 0 {
+		dnsNames := make([]any, 0, len(request.DNSNames))
+		for _, name := range request.DNSNames {
+			dnsNames = append(dnsNames, name)
+		}
+		spec["dnsNames"] = dnsNames
+	}
+
+	meta := metav1.ObjectMeta{
+		Name:      request.SecretName,
+		Namespace: cluster.Namespace,
+	}
+	cluster.SetInheritedDataAndOwnership(&meta)
+
+	certificate := &unstructured.Unstructured{Object: map[string]any{"spec": spec}}
+	certificate.SetGroupVersionKind(CertManagerCertificateGVK)
+	certificate.SetName(meta.Name)
+	certificate.SetNamespace(meta.Namespace)
+	certificate.SetLabels(meta.Labels)
+	certificate.SetAnnotations(meta.Annotations)
+	certificate.SetOwnerReferences(meta.OwnerReferences)
+
+	return certificate
+}
+
+// getCertManagerPrivateKey gets the private key configuration of a
+// cert-manager Certificate. The key is generated again at each renewal
+func getCertManagerPrivateKey(algorithm apiv1.CertificateKeyAlgorithm) map[string]any {
+	privateKey := map[string]any{
+		"rotationPolicy": "Always",
+	}
+
+	switch algorithm {
+	case apiv1.CertificateKeyAlgorithmRSA3072:
+		privateKey["algorithm"] = "RSA"
+		privateKey["size"] = int64(3072)
+	case apiv1.CertificateKeyAlgorithmEd25519:
+		privateKey["algorithm"] = "Ed25519"
+	default:
+		privateKey["algorithm"] = "ECDSA"
+		privateKey["size"] = int64(256)
+	}
+
+	return privateKey
+}
+
+// IsCertManagerCertificateReady checks if cert-manager reports the
+// certificate as issued and up to date
+func IsCertManagerCertificateReady(certificate *unstructured.Unstructured) bool {
+	conditions, _, _ := unstructured.NestedSlice(certificate.Object, "status", "conditions")
+	for _, rawCondition := range conditions {
+		condition, ok := rawCondition.(map[string]any)
+		if !ok {
+			continue
+		}
+		if condition["type"] == "Ready" {
+			return condition["status"] == string(metav1.ConditionTrue)
+		}
+	}
+	return false
+}
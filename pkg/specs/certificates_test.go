@@ -0,0 +1,109 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package specs
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/certs"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("cert-manager certificates", func() {
+	cluster := &apiv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster-example", Namespace: "default"},
+		Spec: apiv1.ClusterSpec{
+			Certificates: &apiv1.CertificatesConfiguration{
+				IssuerRef: &apiv1.CertManagerIssuerReference{Name: "corporate-pki", Kind: "ClusterIssuer"},
+			},
+		},
+	}
+
+	It("builds a server certificate", func() {
+		certificate := BuildCertManagerCertificate(cluster, CertManagerCertificateRequest{
+			SecretName: "cluster-example-server",
+			CommonName: "cluster-example-rw",
+			Usage:      certs.CertTypeServer,
+			DNSNames:   []string{"cluster-example-rw", "cluster-example-r"},
+		})
+
+		Expect(certificate.GroupVersionKind()).To(Equal(CertManagerCertificateGVK))
+		Expect(certificate.GetName()).To(Equal("cluster-example-server"))
+		Expect(certificate.GetNamespace()).To(Equal("default"))
+		Expect(certificate.GetLabels()).To(HaveKeyWithValue(utils.ClusterLabelName, "cluster-example"))
+		Expect(certificate.GetOwnerReferences()).To(HaveLen(1))
+
+		spec := certificate.Object["spec"].(map[string]any)
+		Expect(spec["secretName"]).To(Equal("cluster-example-server"))
+		Expect(spec["commonName"]).To(Equal("cluster-example-rw"))
+		Expect(spec["usages"]).To(ContainElement("server auth"))
+		Expect(spec["dnsNames"]).To(ConsistOf("cluster-example-rw", "cluster-example-r"))
+		Expect(spec["issuerRef"]).To(Equal(map[string]any{
+			"name":  "corporate-pki",
+			"kind":  "ClusterIssuer",
+			"group": "cert-manager.io",
+		}))
+		Expect(spec["privateKey"]).To(HaveKeyWithValue("algorithm", "ECDSA"))
+
+		secretLabels, _, err := unstructured.NestedStringMap(certificate.Object, "spec", "secretTemplate", "labels")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(secretLabels).To(HaveKeyWithValue(utils.WatchedLabelName, "true"))
+	})
+
+	It("builds a client certificate with the requested key algorithm", func() {
+		rsaCluster := cluster.DeepCopy()
+		rsaCluster.Spec.Certificates.KeyAlgorithm = apiv1.CertificateKeyAlgorithmRSA3072
+		certificate := BuildCertManagerCertificate(rsaCluster, CertManagerCertificateRequest{
+			SecretName: "cluster-example-replication",
+			CommonName: "streaming_replica",
+			Usage:      certs.CertTypeClient,
+		})
+
+		spec := certificate.Object["spec"].(map[string]any)
+		Expect(spec).ToNot(HaveKey("dnsNames"))
+		Expect(spec["usages"]).To(ContainElement("client auth"))
+		Expect(spec["privateKey"]).To(Equal(map[string]any{
+			"algorithm":      "RSA",
+			"size":           int64(3072),
+			"rotationPolicy": "Always",
+		}))
+	})
+
+	It("detects when a certificate is ready", func() {
+		certificate := &unstructured.Unstructured{Object: map[string]any{}}
+		Expect(IsCertManagerCertificateReady(certificate)).To(BeFalse())
+
+		Expect(unstructured.SetNestedSlice(certificate.Object, []any{
+			map[string]any{"type": "Issuing", "status": "True"},
+			map[string]any{"type": "Ready", "status": "False"},
+		}, "status", "conditions")).To(Succeed())
+		Expect(IsCertManagerCertificateReady(certificate)).To(BeFalse())
+
+		Expect(unstructured.SetNestedSlice(certificate.Object, []any{
+			map[string]any{"type": "Ready", "status": "True"},
+		}, "status", "conditions")).To(Succeed())
+		Expect(IsCertManagerCertificateReady(certificate)).To(BeTrue())
+	})
+})
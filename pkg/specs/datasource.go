@@ -0,0 +1,55 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package specs
+
+import (
+	volumesnapshotv1 "github.com/kubernetes-csi/external-snapshotter/client/v8/apis/volumesnapshot/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/ptr"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+// BuildVolumeSnapshotDataSource builds the data source referencing
+// the volume snapshots taken by a backup
+func BuildVolumeSnapshotDataSource(backup *apiv1.Backup) *apiv1.DataSource {
+	var result apiv1.DataSource
+	for _, element := range backup.Status.BackupSnapshotStatus.Elements {
+		reference := corev1.TypedLocalObjectReference{
+			APIGroup: ptr.To(volumesnapshotv1.GroupName),
+			Kind:     apiv1.VolumeSnapshotKind,
+			Name:     element.Name,
+		}
+		switch utils.PVCRole(element.Type) {
+		case utils.PVCRolePgData:
+			result.Storage = reference
+		case utils.PVCRolePgWal:
+			result.WalStorage = &reference
+		case utils.PVCRolePgTablespace:
+			if result.TablespaceStorage == nil {
+				result.TablespaceStorage = map[string]corev1.TypedLocalObjectReference{}
+			}
+			result.TablespaceStorage[element.TablespaceName] = reference
+		}
+	}
+
+	return &result
+}
@@ -0,0 +1,76 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package specs
+
+import (
+	volumesnapshotv1 "github.com/kubernetes-csi/external-snapshotter/client/v8/apis/volumesnapshot/v1"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("BuildVolumeSnapshotDataSource", func() {
+	It("maps every snapshot of the backup to its volume", func() {
+		backup := &apiv1.Backup{
+			Status: apiv1.BackupStatus{
+				BackupSnapshotStatus: apiv1.BackupSnapshotStatus{
+					Elements: []apiv1.BackupSnapshotElementStatus{
+						{Name: "cluster-example-1", Type: string(utils.PVCRolePgData)},
+						{Name: "cluster-example-1-wal", Type: string(utils.PVCRolePgWal)},
+						{
+							Name:           "cluster-example-1-tbs-atablespace",
+							Type:           string(utils.PVCRolePgTablespace),
+							TablespaceName: "atablespace",
+						},
+					},
+				},
+			},
+		}
+
+		dataSource := BuildVolumeSnapshotDataSource(backup)
+		Expect(dataSource.Storage.Name).To(Equal("cluster-example-1"))
+		Expect(dataSource.Storage.Kind).To(Equal(apiv1.VolumeSnapshotKind))
+		Expect(*dataSource.Storage.APIGroup).To(Equal(volumesnapshotv1.GroupName))
+		Expect(dataSource.WalStorage).ToNot(BeNil())
+		Expect(dataSource.WalStorage.Name).To(Equal("cluster-example-1-wal"))
+		Expect(dataSource.TablespaceStorage).To(HaveKey("atablespace"))
+		Expect(dataSource.TablespaceStorage["atablespace"].Name).To(Equal("cluster-example-1-tbs-atablespace"))
+	})
+
+	It("leaves the optional volumes empty when they have no snapshot", func() {
+		backup := &apiv1.Backup{
+			Status: apiv1.BackupStatus{
+				BackupSnapshotStatus: apiv1.BackupSnapshotStatus{
+					Elements: []apiv1.BackupSnapshotElementStatus{
+						{Name: "cluster-example-1", Type: string(utils.PVCRolePgData)},
+					},
+				},
+			},
+		}
+
+		dataSource := BuildVolumeSnapshotDataSource(backup)
+		Expect(dataSource.Storage.Name).To(Equal("cluster-example-1"))
+		Expect(dataSource.WalStorage).To(BeNil())
+		Expect(dataSource.TablespaceStorage).To(BeEmpty())
+	})
+})
@@ -149,7 +149,7 @@ func Deployment(pooler *apiv1.Pooler, cluster *apiv1.Cluster) (*appsv1.Deploymen
 			},
 		},
 		Spec: appsv1.DeploymentSpec{
-			Replicas: pooler.Spec.Instances,
+			Replicas: pooler.GetDesiredInstances(),
 			Selector: &metav1.LabelSelector{
 				MatchLabels: map[string]string{
 					utils.PgbouncerNameLabel: pooler.Name,
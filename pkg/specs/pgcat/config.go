@@ -0,0 +1,291 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package pgcat
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+const (
+	// ConfigFileName is the name of the PgCat configuration file
+	ConfigFileName = "pgcat.toml"
+
+	// AdminPasswordKey is the key of the configuration secret
+	// containing the password of the PgCat administrator
+	AdminPasswordKey = "adminPassword"
+
+	// AdminUser is the user of the PgCat administration console
+	AdminUser = "pgcat"
+
+	// MetricsPort is the port of the PgCat Prometheus exporter
+	MetricsPort int32 = 9930
+
+	// configSecretSuffix is the suffix of the name of the secret
+	// containing the PgCat configuration
+	configSecretSuffix = "-pgcat"
+
+	// autoreloadInterval is how often, in milliseconds, PgCat checks
+	// the configuration file for changes
+	autoreloadInterval = 15000
+)
+
+// User contains the credentials of a PgCat user
+type User struct {
+	Username string
+	Password string
+}
+
+// ConfigSecretName returns the name of the secret containing
+// the configuration of a PgCat Pooler
+func ConfigSecretName(pooler *apiv1.Pooler) string {
+	return pooler.Name + configSecretSuffix
+}
+
+// UserFromSecret extracts the credentials of a PgCat user from a
+// basic-auth secret
+func UserFromSecret(secret *corev1.Secret) (User, error) {
+	username, hasUsername := secret.Data[corev1.BasicAuthUsernameKey]
+	password, hasPassword := secret.Data[corev1.BasicAuthPasswordKey]
+	if !hasUsername || !hasPassword {
+		return User{}, fmt.Errorf("secret %s must contain the %q and %q keys",
+			secret.Name, corev1.BasicAuthUsernameKey, corev1.BasicAuthPasswordKey)
+	}
+
+	return User{Username: string(username), Password: string(password)}, nil
+}
+
+// BuildConfiguration generates the PgCat configuration file for a Pooler.
+// The users map contains the credentials of the users, indexed by the name
+// of the secret they have been read from.
+func BuildConfiguration(
+	pooler *apiv1.Pooler,
+	cluster *apiv1.Cluster,
+	users map[string]User,
+	adminPassword string,
+) ([]byte, error) {
+	spec := pooler.Spec.PgCat
+	if spec == nil {
+		return nil, fmt.Errorf("missing pgcat configuration section")
+	}
+
+	var config strings.Builder
+
+	config.WriteString("[general]\n")
+	general := map[string]string{
+		"host":                       tomlString("0.0.0.0"),
+		"port":                       strconv.Itoa(postgres.ServerPort),
+		"admin_username":             tomlString(AdminUser),
+		"admin_password":             tomlString(adminPassword),
+		"enable_prometheus_exporter": "true",
+		"prometheus_exporter_port":   strconv.Itoa(int(MetricsPort)),
+		"autoreload":                 strconv.Itoa(autoreloadInterval),
+		"tls_certificate":            tomlString(clientTLSDir + "/" + corev1.TLSCertKey),
+		"tls_private_key":            tomlString(clientTLSDir + "/" + corev1.TLSPrivateKeyKey),
+		"server_tls":                 "true",
+		"verify_server_certificate":  "true",
+	}
+	for key, value := range spec.Parameters {
+		if _, forced := general[key]; forced {
+			continue
+		}
+		general[key] = tomlValue(value)
+	}
+	writeTomlEntries(&config, general)
+
+	servers := buildServers(pooler, cluster)
+	for _, database := range spec.Databases {
+		poolMode := spec.PoolMode
+		if database.PoolMode != "" {
+			poolMode = database.PoolMode
+		}
+		if poolMode == "" {
+			poolMode = apiv1.PgBouncerPoolModeTransaction
+		}
+
+		poolKey := "pools." + tomlString(database.Name)
+		_, _ = fmt.Fprintf(&config, "\n[%s]\n", poolKey)
+		writeTomlEntries(&config, map[string]string{
+			"pool_mode":                         tomlString(string(poolMode)),
+			"load_balancing_mode":               tomlString(string(getLoadBalancingMode(spec))),
+			"default_role":                      tomlString(string(getDefaultRole(spec))),
+			"query_parser_enabled":              strconv.FormatBool(spec.ReadWriteSplitting),
+			"query_parser_read_write_splitting": strconv.FormatBool(spec.ReadWriteSplitting),
+			"primary_reads_enabled":             strconv.FormatBool(spec.IsPrimaryReadsEnabled()),
+		})
+
+		for idx, user := range database.Users {
+			credentials, ok := users[user.Secret.Name]
+			if !ok {
+				return nil, fmt.Errorf("missing credentials from secret %s", user.Secret.Name)
+			}
+
+			poolSize := user.PoolSize
+			if poolSize == 0 {
+				poolSize = 10
+			}
+
+			entries := map[string]string{
+				"username":  tomlString(credentials.Username),
+				"password":  tomlString(credentials.Password),
+				"pool_size": strconv.Itoa(int(poolSize)),
+			}
+			if user.MinPoolSize != nil {
+				entries["min_pool_size"] = strconv.Itoa(int(*user.MinPoolSize))
+			}
+
+			_, _ = fmt.Fprintf(&config, "\n[%s.users.%d]\n", poolKey, idx)
+			writeTomlEntries(&config, entries)
+		}
+
+		dbname := database.DBName
+		if dbname == "" {
+			dbname = database.Name
+		}
+		_, _ = fmt.Fprintf(&config, "\n[%s.shards.0]\n", poolKey)
+		writeTomlEntries(&config, map[string]string{
+			"database": tomlString(dbname),
+			"servers":  servers,
+		})
+	}
+
+	return []byte(config.String()), nil
+}
+
+// buildServers generates the list of servers of a PgCat shard, pointing
+// to the services of the Cluster depending on the Pooler type
+func buildServers(pooler *apiv1.Pooler, cluster *apiv1.Cluster) string {
+	server := func(host, role string) string {
+		return fmt.Sprintf("[%s, %d, %s]", tomlString(host), postgres.ServerPort, tomlString(role))
+	}
+
+	var servers []string
+	switch pooler.Spec.Type {
+	case apiv1.PoolerTypeRO:
+		servers = append(servers, server(cluster.GetServiceReadOnlyName(), string(apiv1.PgCatRoleReplica)))
+	case apiv1.PoolerTypeR:
+		servers = append(servers, server(cluster.GetServiceReadName(), string(apiv1.PgCatRoleReplica)))
+	default:
+		servers = append(servers, server(cluster.GetServiceReadWriteName(), string(apiv1.PgCatRolePrimary)))
+		if pooler.Spec.PgCat.ReadWriteSplitting {
+			servers = append(servers, server(cluster.GetServiceReadOnlyName(), string(apiv1.PgCatRoleReplica)))
+		}
+	}
+
+	return "[" + strings.Join(servers, ", ") + "]"
+}
+
+func getLoadBalancingMode(spec *apiv1.PgCatSpec) apiv1.PgCatLoadBalancingMode {
+	if spec.LoadBalancingMode == "" {
+		return apiv1.PgCatLoadBalancingModeRandom
+	}
+	return spec.LoadBalancingMode
+}
+
+func getDefaultRole(spec *apiv1.PgCatSpec) apiv1.PgCatRole {
+	if spec.DefaultRole == "" {
+		return apiv1.PgCatRoleAny
+	}
+	return spec.DefaultRole
+}
+
+// writeTomlEntries writes the passed entries sorted by key, to keep the
+// generated configuration stable
+func writeTomlEntries(config *strings.Builder, entries map[string]string) {
+	keys := make([]string, 0, len(entries))
+	for key := range entries {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		_, _ = fmt.Fprintf(config, "%s = %s\n", key, entries[key])
+	}
+}
+
+// tomlValue converts a parameter value to TOML, keeping booleans
+// and integers unquoted
+func tomlValue(value string) string {
+	if value == "true" || value == "false" {
+		return value
+	}
+	if _, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return value
+	}
+	return tomlString(value)
+}
+
+// tomlString quotes a value as a TOML basic string
+func tomlString(value string) string {
+	var result strings.Builder
+	result.WriteRune('"')
+	for _, r := range value {
+		switch {
+		case r == '"':
+			result.WriteString(`\"`)
+		case r == '\\':
+			result.WriteString(`\\`)
+		case r == '\n':
+			result.WriteString(`\n`)
+		case r == '\t':
+			result.WriteString(`\t`)
+		case r == '\r':
+			result.WriteString(`\r`)
+		case r < 0x20 || r == 0x7f:
+			_, _ = fmt.Fprintf(&result, `\u%04X`, r)
+		default:
+			result.WriteRune(r)
+		}
+	}
+	result.WriteRune('"')
+	return result.String()
+}
+
+// ConfigSecret creates the secret containing the PgCat configuration
+func ConfigSecret(pooler *apiv1.Pooler, cluster *apiv1.Cluster, config []byte, adminPassword string) *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      ConfigSecretName(pooler),
+			Namespace: pooler.Namespace,
+			Labels: map[string]string{
+				utils.ClusterLabelName:                cluster.Name,
+				utils.PgbouncerNameLabel:              pooler.Name,
+				utils.KubernetesAppLabelName:          utils.AppName,
+				utils.KubernetesAppInstanceLabelName:  cluster.Name,
+				utils.KubernetesAppComponentLabelName: utils.PoolerComponentName,
+				utils.KubernetesAppManagedByLabelName: utils.ManagerName,
+			},
+		},
+		Type: corev1.SecretTypeOpaque,
+		Data: map[string][]byte{
+			ConfigFileName:   config,
+			AdminPasswordKey: []byte(adminPassword),
+		},
+	}
+}
@@ -0,0 +1,157 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package pgcat
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("PgCat configuration", func() {
+	var (
+		pooler  *apiv1.Pooler
+		cluster *apiv1.Cluster
+		users   map[string]User
+	)
+
+	BeforeEach(func() {
+		pooler = &apiv1.Pooler{
+			ObjectMeta: metav1.ObjectMeta{Name: "pooler", Namespace: "default"},
+			Spec: apiv1.PoolerSpec{
+				Cluster:        apiv1.LocalObjectReference{Name: "cluster"},
+				Type:           apiv1.PoolerTypeRW,
+				Implementation: apiv1.PoolerImplementationPgCat,
+				PgCat: &apiv1.PgCatSpec{
+					Parameters: map[string]string{
+						"connect_timeout":        "5000",
+						"log_client_connections": "true",
+						"port":                   "6432",
+					},
+					Databases: []apiv1.PgCatDatabase{
+						{
+							Name: "app",
+							Users: []apiv1.PgCatUser{
+								{Secret: apiv1.LocalObjectReference{Name: "app-user"}, PoolSize: 20},
+							},
+						},
+					},
+				},
+			},
+		}
+		cluster = &apiv1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "cluster", Namespace: "default"}}
+		users = map[string]User{"app-user": {Username: "app", Password: `pa"ss`}}
+	})
+
+	It("generates the general section", func() {
+		config, err := BuildConfiguration(pooler, cluster, users, "secret")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(config)).To(HavePrefix("[general]\n"))
+		Expect(string(config)).To(ContainSubstring("admin_password = \"secret\"\n"))
+		Expect(string(config)).To(ContainSubstring("connect_timeout = 5000\n"))
+		Expect(string(config)).To(ContainSubstring("log_client_connections = true\n"))
+		Expect(string(config)).To(ContainSubstring("prometheus_exporter_port = 9930\n"))
+		// Forced parameters cannot be overridden
+		Expect(string(config)).To(ContainSubstring("port = 5432\n"))
+		Expect(string(config)).ToNot(ContainSubstring("6432"))
+	})
+
+	It("verifies the certificate of the server", func() {
+		config, err := BuildConfiguration(pooler, cluster, users, "secret")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(config)).To(ContainSubstring("server_tls = true\n"))
+		Expect(string(config)).To(ContainSubstring("verify_server_certificate = true\n"))
+
+		pooler.Spec.PgCat.Parameters = map[string]string{"verify_server_certificate": "false"}
+		config, err = BuildConfiguration(pooler, cluster, users, "secret")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(config)).To(ContainSubstring("verify_server_certificate = true\n"))
+	})
+
+	It("generates the pools", func() {
+		config, err := BuildConfiguration(pooler, cluster, users, "secret")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(config)).To(ContainSubstring("\n[pools.\"app\"]\n"))
+		Expect(string(config)).To(ContainSubstring("pool_mode = \"transaction\"\n"))
+		Expect(string(config)).To(ContainSubstring("\n[pools.\"app\".users.0]\n"))
+		Expect(string(config)).To(ContainSubstring("password = \"pa\\\"ss\"\n"))
+		Expect(string(config)).To(ContainSubstring("pool_size = 20\n"))
+		Expect(string(config)).To(ContainSubstring("\n[pools.\"app\".shards.0]\n"))
+		Expect(string(config)).To(ContainSubstring("database = \"app\"\n"))
+		Expect(string(config)).To(ContainSubstring(`servers = [["cluster-rw", 5432, "primary"]]`))
+	})
+
+	It("adds the replicas when splitting reads and writes", func() {
+		pooler.Spec.PgCat.ReadWriteSplitting = true
+		pooler.Spec.PgCat.PrimaryReadsEnabled = ptr.To(false)
+		pooler.Spec.PgCat.Databases[0].DBName = "appdb"
+		config, err := BuildConfiguration(pooler, cluster, users, "secret")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(config)).To(ContainSubstring("query_parser_read_write_splitting = true\n"))
+		Expect(string(config)).To(ContainSubstring("primary_reads_enabled = false\n"))
+		Expect(string(config)).To(ContainSubstring("database = \"appdb\"\n"))
+		Expect(string(config)).To(ContainSubstring(
+			`servers = [["cluster-rw", 5432, "primary"], ["cluster-ro", 5432, "replica"]]`))
+	})
+
+	It("uses only the replicas for read-only poolers", func() {
+		pooler.Spec.Type = apiv1.PoolerTypeRO
+		config, err := BuildConfiguration(pooler, cluster, users, "secret")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(config)).To(ContainSubstring(`servers = [["cluster-ro", 5432, "replica"]]`))
+	})
+
+	It("fails when the credentials of a user are missing", func() {
+		_, err := BuildConfiguration(pooler, cluster, map[string]User{}, "secret")
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("generates a stable configuration", func() {
+		first, err := BuildConfiguration(pooler, cluster, users, "secret")
+		Expect(err).ToNot(HaveOccurred())
+		second, err := BuildConfiguration(pooler, cluster, users, "secret")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(first).To(Equal(second))
+	})
+
+	It("reads the credentials from a basic-auth secret", func() {
+		user, err := UserFromSecret(&corev1.Secret{Data: map[string][]byte{
+			corev1.BasicAuthUsernameKey: []byte("app"),
+			corev1.BasicAuthPasswordKey: []byte("password"),
+		}})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(user).To(Equal(User{Username: "app", Password: "password"}))
+
+		_, err = UserFromSecret(&corev1.Secret{Data: map[string][]byte{}})
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("escapes TOML strings", func() {
+		Expect(tomlString("a\"b\\c\nd\x01")).To(Equal(`"a\"b\\c\nd\u0001"`))
+		Expect(tomlValue("10")).To(Equal("10"))
+		Expect(tomlValue("false")).To(Equal("false"))
+		Expect(tomlValue("md5")).To(Equal(`"md5"`))
+	})
+})
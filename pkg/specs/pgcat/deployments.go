@@ -0,0 +1,229 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package pgcat contains the specification of the K8s resources
+// generated by the CloudNativePG operator related to pgcat poolers
+package pgcat
+
+import (
+	"path"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	config "github.com/cloudnative-pg/cloudnative-pg/internal/configuration"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/certs"
+	pgBouncerConfig "github.com/cloudnative-pg/cloudnative-pg/pkg/management/pgbouncer/config"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/podspec"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils/hash"
+)
+
+const (
+	// ContainerName is the name of the container running PgCat
+	ContainerName = "pgcat"
+
+	// configDir is where the PgCat configuration is mounted
+	configDir = "/etc/pgcat"
+
+	// clientTLSDir is where the certificate used to accept
+	// client connections is mounted
+	clientTLSDir = "/etc/pgcat-tls"
+
+	// serverCADir is where the CA used to verify the certificate
+	// of the PostgreSQL server is mounted
+	serverCADir = "/etc/pgcat-server-ca"
+
+	// The user and group running PgCat
+	pgcatUser  int64 = 1000
+	pgcatGroup int64 = 1000
+)
+
+// Deployment creates the deployment of pgcat, given
+// the configurations we have in the pooler specifications
+func Deployment(pooler *apiv1.Pooler, cluster *apiv1.Cluster) (*appsv1.Deployment, error) {
+	poolerHash, err := computeTemplateHash(pooler, cluster.GetServerCASecretName())
+	if err != nil {
+		return nil, err
+	}
+
+	podTemplate := podspec.NewFrom(pooler.Spec.Template).
+		WithLabel(utils.PgbouncerNameLabel, pooler.Name).
+		WithLabel(utils.ClusterLabelName, cluster.Name).
+		WithLabel(utils.PodRoleLabelName, string(utils.PodRolePooler)).
+		WithLabel(utils.KubernetesAppLabelName, utils.AppName).
+		WithLabel(utils.KubernetesAppInstanceLabelName, cluster.Name).
+		WithLabel(utils.KubernetesAppComponentLabelName, utils.PoolerComponentName).
+		WithLabel(utils.KubernetesAppManagedByLabelName, utils.ManagerName).
+		WithVolume(&corev1.Volume{
+			Name: "config",
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{
+					SecretName: ConfigSecretName(pooler),
+					Items: []corev1.KeyToPath{
+						{Key: ConfigFileName, Path: ConfigFileName},
+					},
+				},
+			},
+		}).
+		WithVolume(&corev1.Volume{
+			Name: "client-tls",
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{
+					SecretName: pooler.GetClientTLSSecretNameOrDefault(cluster),
+				},
+			},
+		}).
+		WithVolume(&corev1.Volume{
+			Name: "server-ca",
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{
+					SecretName: cluster.GetServerCASecretName(),
+					Items: []corev1.KeyToPath{
+						{Key: certs.CACertKey, Path: certs.CACertKey},
+					},
+				},
+			},
+		}).
+		WithSecurityContext(createPodSecurityContext(cluster.GetSeccompProfile()), true).
+		WithContainerImage(ContainerName, config.Current.PgCatImageName, false).
+		WithContainerCommand(ContainerName, []string{
+			"pgcat",
+			path.Join(configDir, ConfigFileName),
+		}, false).
+		WithContainerPort(ContainerName, &corev1.ContainerPort{
+			// The Service is shared among the pooler implementations
+			// and targets the port by name
+			Name:          pgBouncerConfig.PgBouncerPortName,
+			ContainerPort: pgBouncerConfig.PgBouncerPort,
+		}).
+		WithContainerPort(ContainerName, &corev1.ContainerPort{
+			Name:          "metrics",
+			ContainerPort: MetricsPort,
+		}).
+		WithContainerVolumeMount(ContainerName, &corev1.VolumeMount{
+			Name:      "config",
+			MountPath: configDir,
+		}, true).
+		WithContainerVolumeMount(ContainerName, &corev1.VolumeMount{
+			Name:      "client-tls",
+			MountPath: clientTLSDir,
+		}, true).
+		WithContainerVolumeMount(ContainerName, &corev1.VolumeMount{
+			Name:      "server-ca",
+			MountPath: serverCADir,
+		}, true).
+		// PgCat verifies the certificate of the server against the
+		// system trust store, which is replaced by the server CA
+		WithContainerEnv(ContainerName, corev1.EnvVar{
+			Name:  "SSL_CERT_FILE",
+			Value: path.Join(serverCADir, certs.CACertKey),
+		}, true).
+		WithContainerSecurityContext(ContainerName, specs.GetSecurityContext(cluster), true).
+		WithReadinessProbe(ContainerName, &corev1.Probe{
+			TimeoutSeconds: 5,
+			ProbeHandler: corev1.ProbeHandler{
+				TCPSocket: &corev1.TCPSocketAction{
+					Port: intstr.FromInt32(pgBouncerConfig.PgBouncerPort),
+				},
+			},
+		}, false).
+		Build()
+
+	podTemplate.Spec.AutomountServiceAccountToken = ptr.To(false)
+
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      pooler.Name,
+			Namespace: pooler.Namespace,
+			Labels: map[string]string{
+				utils.ClusterLabelName:                cluster.Name,
+				utils.PgbouncerNameLabel:              pooler.Name,
+				utils.PodRoleLabelName:                string(utils.PodRolePooler),
+				utils.KubernetesAppLabelName:          utils.AppName,
+				utils.KubernetesAppInstanceLabelName:  cluster.Name,
+				utils.KubernetesAppComponentLabelName: utils.PoolerComponentName,
+				utils.KubernetesAppManagedByLabelName: utils.ManagerName,
+			},
+			Annotations: map[string]string{
+				utils.PoolerSpecHashAnnotationName: poolerHash,
+			},
+		},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: pooler.GetDesiredInstances(),
+			Selector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{
+					utils.PgbouncerNameLabel: pooler.Name,
+				},
+			},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: podTemplate.ObjectMeta.Annotations,
+					Labels:      podTemplate.ObjectMeta.Labels,
+				},
+				Spec: podTemplate.Spec,
+			},
+			Strategy: getDeploymentStrategy(pooler.Spec.DeploymentStrategy),
+		},
+	}, nil
+}
+
+func computeTemplateHash(pooler *apiv1.Pooler, serverCASecretName string) (string, error) {
+	type deploymentHash struct {
+		poolerSpec                      apiv1.PoolerSpec
+		imageName                       string
+		serverCASecretName              string
+		isPodSpecReconciliationDisabled bool
+	}
+
+	return hash.ComputeHash(deploymentHash{
+		poolerSpec:                      pooler.Spec,
+		imageName:                       config.Current.PgCatImageName,
+		serverCASecretName:              serverCASecretName,
+		isPodSpecReconciliationDisabled: utils.IsPodSpecReconciliationDisabled(&pooler.ObjectMeta),
+	})
+}
+
+// createPodSecurityContext defines the security context under which the containers are running
+func createPodSecurityContext(seccompProfile *corev1.SeccompProfile) *corev1.PodSecurityContext {
+	// Under Openshift we inherit SecurityContext from the restricted security context constraint
+	if utils.HaveSecurityContextConstraints() {
+		return nil
+	}
+
+	return &corev1.PodSecurityContext{
+		RunAsNonRoot:   ptr.To(true),
+		RunAsUser:      ptr.To(pgcatUser),
+		RunAsGroup:     ptr.To(pgcatGroup),
+		FSGroup:        ptr.To(pgcatGroup),
+		SeccompProfile: seccompProfile,
+	}
+}
+
+func getDeploymentStrategy(strategy *appsv1.DeploymentStrategy) appsv1.DeploymentStrategy {
+	if strategy != nil {
+		return *strategy.DeepCopy()
+	}
+	return appsv1.DeploymentStrategy{}
+}
@@ -0,0 +1,110 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package pgcat
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	config "github.com/cloudnative-pg/cloudnative-pg/internal/configuration"
+	pgBouncerConfig "github.com/cloudnative-pg/cloudnative-pg/pkg/management/pgbouncer/config"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Deployment", func() {
+	var (
+		pooler  *apiv1.Pooler
+		cluster *apiv1.Cluster
+	)
+
+	BeforeEach(func() {
+		pooler = &apiv1.Pooler{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-pooler",
+				Namespace: "test-namespace",
+			},
+			Spec: apiv1.PoolerSpec{
+				Cluster:        apiv1.LocalObjectReference{Name: "test-cluster"},
+				Type:           apiv1.PoolerTypeRW,
+				Instances:      ptr.To(int32(2)),
+				Implementation: apiv1.PoolerImplementationPgCat,
+				PgCat: &apiv1.PgCatSpec{
+					Databases: []apiv1.PgCatDatabase{{Name: "app"}},
+				},
+			},
+		}
+
+		cluster = &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-cluster",
+				Namespace: "test-namespace",
+			},
+		}
+	})
+
+	It("creates a Deployment correctly", func() {
+		deployment, err := Deployment(pooler, cluster)
+		Expect(err).ToNot(HaveOccurred())
+
+		expectedHash, err := computeTemplateHash(pooler, cluster.GetServerCASecretName())
+		Expect(err).ToNot(HaveOccurred())
+		Expect(deployment.Annotations[utils.PoolerSpecHashAnnotationName]).To(Equal(expectedHash))
+
+		Expect(deployment.Name).To(Equal(pooler.Name))
+		Expect(deployment.Spec.Replicas).To(HaveValue(BeEquivalentTo(2)))
+		Expect(deployment.Spec.Selector.MatchLabels).To(HaveKeyWithValue(utils.PgbouncerNameLabel, pooler.Name))
+
+		podSpec := deployment.Spec.Template.Spec
+		Expect(podSpec.Containers).To(HaveLen(1))
+		container := podSpec.Containers[0]
+		Expect(container.Name).To(Equal(ContainerName))
+		Expect(container.Image).To(Equal(config.Current.PgCatImageName))
+		Expect(container.Command).To(Equal([]string{"pgcat", "/etc/pgcat/pgcat.toml"}))
+		Expect(container.Ports).To(ContainElements(
+			HaveField("Name", pgBouncerConfig.PgBouncerPortName),
+			HaveField("ContainerPort", MetricsPort),
+		))
+
+		Expect(podSpec.Volumes).To(ContainElements(
+			HaveField("Secret.SecretName", ConfigSecretName(pooler)),
+			HaveField("Secret.SecretName", cluster.GetServerTLSSecretName()),
+			HaveField("Secret.SecretName", cluster.GetServerCASecretName()),
+		))
+		Expect(container.Env).To(ContainElement(corev1.EnvVar{
+			Name:  "SSL_CERT_FILE",
+			Value: "/etc/pgcat-server-ca/ca.crt",
+		}))
+	})
+
+	It("changes the hash when the pooler specification changes", func() {
+		before, err := computeTemplateHash(pooler, cluster.GetServerCASecretName())
+		Expect(err).ToNot(HaveOccurred())
+
+		pooler.Spec.PgCat.ReadWriteSplitting = true
+		after, err := computeTemplateHash(pooler, cluster.GetServerCASecretName())
+		Expect(err).ToNot(HaveOccurred())
+		Expect(after).ToNot(Equal(before))
+	})
+})
@@ -22,6 +22,7 @@ package specs
 import (
 	"encoding/json"
 	"strconv"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
@@ -1049,6 +1050,58 @@ var _ = Describe("NewInstance", func() {
 		Expect(pod.Spec.Containers[0].Image).To(Equal("new-image:latest"))
 	})
 
+	It("doesn't pass the OTLP metrics configuration to the instance manager", func(ctx SpecContext) {
+		cluster := apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-cluster",
+				Namespace: "default",
+			},
+			Spec: apiv1.ClusterSpec{
+				ImageName: "postgres:18.0",
+				Monitoring: &apiv1.MonitoringConfiguration{
+					OTLP: &apiv1.OTLPMetricsConfiguration{
+						Endpoint: "http://otel-collector:4318",
+						Interval: &metav1.Duration{Duration: time.Minute},
+					},
+				},
+			},
+		}
+
+		pod, err := NewInstance(ctx, cluster, 1, true)
+		Expect(err).NotTo(HaveOccurred())
+		// The configuration is read from the Cluster by the instance
+		// manager, so that changing it doesn't require a rollout
+		Expect(pod.Spec.Containers[0].Command).NotTo(ContainElement(HavePrefix("--otlp-metrics")))
+	})
+
+	It("doesn't pass the logging configuration to the instance manager", func(ctx SpecContext) {
+		bufferSize := resource.MustParse("1Mi")
+		cluster := apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-cluster",
+				Namespace: "default",
+			},
+			Spec: apiv1.ClusterSpec{
+				ImageName: "postgres:18.0",
+				Logging: &apiv1.ClusterLoggingConfiguration{
+					OTLP: &apiv1.OTLPLogsConfiguration{
+						Endpoint:      "http://otel-collector:4317",
+						DisableStdout: true,
+						BufferSize:    &bufferSize,
+					},
+					SlowQueryText: apiv1.SlowQueryTextNormalized,
+				},
+			},
+		}
+
+		pod, err := NewInstance(ctx, cluster, 1, true)
+		Expect(err).NotTo(HaveOccurred())
+		// The configuration is read from the Cluster by the instance
+		// manager, so that changing it doesn't require a rollout
+		Expect(pod.Spec.Containers[0].Command).NotTo(ContainElement(HavePrefix("--otlp-logs")))
+		Expect(pod.Spec.Containers[0].Command).NotTo(ContainElement("--slow-query-text"))
+	})
+
 	It("returns error if JSON patch is invalid", func(ctx SpecContext) {
 		cluster := apiv1.Cluster{
 			ObjectMeta: metav1.ObjectMeta{
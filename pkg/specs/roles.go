@@ -30,8 +30,9 @@ import (
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
 )
 
-// CreateRole create a role with the permissions needed by the instance manager
-func CreateRole(cluster apiv1.Cluster, backupOrigin *apiv1.Backup) rbacv1.Role {
+// CreateRole create a role with the permissions needed by the instance manager.
+// The passed list of Role objects is used to grant access to their password Secrets
+func CreateRole(cluster apiv1.Cluster, backupOrigin *apiv1.Backup, declarativeRoles []apiv1.Role) rbacv1.Role {
 	rules := []rbacv1.PolicyRule{
 		{
 			APIGroups: []string{
@@ -57,7 +58,7 @@ func CreateRole(cluster apiv1.Cluster, backupOrigin *apiv1.Backup) rbacv1.Role {
 				"get",
 				"watch",
 			},
-			ResourceNames: getInvolvedSecretNames(cluster, backupOrigin),
+			ResourceNames: getInvolvedSecretNames(cluster, backupOrigin, declarativeRoles),
 		},
 		{
 			APIGroups: []string{
@@ -214,6 +215,34 @@ func CreateRole(cluster apiv1.Cluster, backupOrigin *apiv1.Backup) rbacv1.Role {
 				"update",
 			},
 		},
+		{
+			APIGroups: []string{
+				"postgresql.cnpg.io",
+			},
+			Resources: []string{
+				"roles",
+			},
+			Verbs: []string{
+				"get",
+				"update",
+				"list",
+				"watch",
+			},
+			ResourceNames: []string{},
+		},
+		{
+			APIGroups: []string{
+				"postgresql.cnpg.io",
+			},
+			Resources: []string{
+				"roles/status",
+			},
+			Verbs: []string{
+				"get",
+				"patch",
+				"update",
+			},
+		},
 		{
 			APIGroups: []string{
 				"postgresql.cnpg.io",
@@ -261,7 +290,11 @@ func CreateRole(cluster apiv1.Cluster, backupOrigin *apiv1.Backup) rbacv1.Role {
 	}
 }
 
-func getInvolvedSecretNames(cluster apiv1.Cluster, backupOrigin *apiv1.Backup) []string {
+func getInvolvedSecretNames(
+	cluster apiv1.Cluster,
+	backupOrigin *apiv1.Backup,
+	declarativeRoles []apiv1.Role,
+) []string {
 	involvedSecretNames := []string{
 		cluster.GetReplicationSecretName(),
 		cluster.GetClientCASecretName(),
@@ -281,6 +314,7 @@ func getInvolvedSecretNames(cluster apiv1.Cluster, backupOrigin *apiv1.Backup) [
 	involvedSecretNames = append(involvedSecretNames, backupSecrets(cluster, backupOrigin)...)
 	involvedSecretNames = append(involvedSecretNames, externalClusterSecrets(cluster)...)
 	involvedSecretNames = append(involvedSecretNames, managedRolesSecrets(cluster)...)
+	involvedSecretNames = append(involvedSecretNames, declarativeRolesSecrets(cluster, declarativeRoles)...)
 
 	return cleanupResourceList(involvedSecretNames)
 }
@@ -474,3 +508,20 @@ func managedRolesSecrets(cluster apiv1.Cluster) []string {
 
 	return secretNames
 }
+
+func declarativeRolesSecrets(cluster apiv1.Cluster, declarativeRoles []apiv1.Role) []string {
+	secretNames := make([]string, 0, len(declarativeRoles))
+	for _, role := range declarativeRoles {
+		if role.Spec.ClusterRef.Name != cluster.Name || role.Namespace != cluster.Namespace {
+			continue
+		}
+		if role.Spec.DisablePassword || role.Spec.PasswordSecret == nil {
+			continue
+		}
+		if secretName := role.Spec.PasswordSecret.Name; secretName != "" {
+			secretNames = append(secretNames, secretName)
+		}
+	}
+
+	return secretNames
+}
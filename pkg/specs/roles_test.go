@@ -165,14 +165,14 @@ var _ = Describe("Roles", func() {
 	}
 
 	It("are created with the cluster name for pure k8s", func() {
-		serviceAccount := CreateRole(cluster, nil)
+		serviceAccount := CreateRole(cluster, nil, nil)
 		Expect(serviceAccount.Name).To(Equal(cluster.Name))
 		Expect(serviceAccount.Namespace).To(Equal(cluster.Namespace))
-		Expect(serviceAccount.Rules).To(HaveLen(15))
+		Expect(serviceAccount.Rules).To(HaveLen(17))
 	})
 
 	It("should contain every secret of the origin backup and backup configuration of every external cluster", func() {
-		serviceAccount := CreateRole(cluster, &backupOrigin)
+		serviceAccount := CreateRole(cluster, &backupOrigin, nil)
 		Expect(serviceAccount.Name).To(Equal(cluster.Name))
 		Expect(serviceAccount.Namespace).To(Equal(cluster.Namespace))
 		Expect(serviceAccount.Rules[0].ResourceNames).To(ConsistOf("thisTest", "testConfigMapKeySelector"))
@@ -281,7 +281,7 @@ var _ = Describe("Secrets", func() {
 	})
 
 	It("should contain default secrets only", func() {
-		Expect(getInvolvedSecretNames(cluster, nil)).To(Equal([]string{
+		Expect(getInvolvedSecretNames(cluster, nil, nil)).To(Equal([]string{
 			"thisTest-app",
 			"thisTest-ca",
 			"thisTest-replication",
@@ -291,7 +291,7 @@ var _ = Describe("Secrets", func() {
 	})
 
 	It("should created an ordered string list with the backup secrets", func() {
-		Expect(getInvolvedSecretNames(cluster, &backup)).To(Equal([]string{
+		Expect(getInvolvedSecretNames(cluster, &backup, nil)).To(Equal([]string{
 			"aws-status-secret-test",
 			"azure-storage-key-secret-test",
 			"google-application-secret-test",
@@ -346,7 +346,7 @@ var _ = Describe("Managed Roles", func() {
 	It("gets the list of secrets needed by the managed roles", func() {
 		Expect(managedRolesSecrets(cluster)).
 			To(ConsistOf("my_secret1", "my_secret3"))
-		serviceAccount := CreateRole(cluster, nil)
+		serviceAccount := CreateRole(cluster, nil, nil)
 		Expect(serviceAccount.Name).To(Equal(cluster.Name))
 		Expect(serviceAccount.Namespace).To(Equal(cluster.Namespace))
 		var secretsPolicy rbacv1.PolicyRule
@@ -358,3 +358,42 @@ var _ = Describe("Managed Roles", func() {
 		Expect(secretsPolicy.ResourceNames).To(ContainElements("my_secret1", "my_secret3"))
 	})
 })
+
+var _ = Describe("Declarative Roles", func() {
+	cluster := apiv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "thisTest",
+			Namespace: "default",
+		},
+	}
+
+	newRole := func(clusterName, secretName string, disablePassword bool) apiv1.Role {
+		role := apiv1.Role{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default"},
+			Spec: apiv1.RoleSpec{
+				ClusterRef: corev1.LocalObjectReference{Name: clusterName},
+				RoleConfiguration: apiv1.RoleConfiguration{
+					DisablePassword: disablePassword,
+				},
+			},
+		}
+		if secretName != "" {
+			role.Spec.PasswordSecret = &apiv1.LocalObjectReference{Name: secretName}
+		}
+		return role
+	}
+
+	It("gets the list of secrets needed by the Role objects of the cluster", func() {
+		declarativeRoles := []apiv1.Role{
+			newRole("thisTest", "my_secret1", false),
+			newRole("thisTest", "", false),
+			newRole("thisTest", "my_secret3", true),
+			newRole("anotherCluster", "my_secret4", false),
+		}
+		Expect(declarativeRolesSecrets(cluster, declarativeRoles)).To(ConsistOf("my_secret1"))
+
+		serviceAccount := CreateRole(cluster, nil, declarativeRoles)
+		Expect(serviceAccount.Rules[1].ResourceNames).To(ContainElement("my_secret1"))
+		Expect(serviceAccount.Rules[1].ResourceNames).ToNot(ContainElement("my_secret4"))
+	})
+})
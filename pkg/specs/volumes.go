@@ -23,6 +23,7 @@ import (
 	"fmt"
 	"path"
 	"path/filepath"
+	"slices"
 	"sort"
 	"strings"
 
@@ -115,15 +116,7 @@ func createPostgresVolumes(cluster *apiv1.Cluster, podName string) []corev1.Volu
 	}
 
 	if cluster.ShouldCreateWalArchiveVolume() {
-		result = append(result,
-			corev1.Volume{
-				Name: "pg-wal",
-				VolumeSource: corev1.VolumeSource{
-					PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
-						ClaimName: podName + apiv1.WalArchiveVolumeSuffix,
-					},
-				},
-			})
+		result = append(result, createPgWalVolume(podName))
 	}
 
 	// we should create volumeMounts in fixed sequence as podSpec will store it in annotation and
@@ -154,6 +147,49 @@ func createPostgresVolumes(cluster *apiv1.Cluster, podName string) []corev1.Volu
 	return result
 }
 
+func createPgWalVolume(podName string) corev1.Volume {
+	return corev1.Volume{
+		Name: "pg-wal",
+		VolumeSource: corev1.VolumeSource{
+			PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+				ClaimName: podName + apiv1.WalArchiveVolumeSuffix,
+			},
+		},
+	}
+}
+
+func createPgWalVolumeMount() corev1.VolumeMount {
+	return corev1.VolumeMount{
+		Name:      "pg-wal",
+		MountPath: PgWalVolumePath,
+	}
+}
+
+// AddPgWalVolume attaches the WAL PVC to an instance Pod whose cluster has
+// no separate WAL storage anymore. This keeps the WALs available to the
+// instances created before the WAL storage was removed, until they are replaced
+func AddPgWalVolume(pod *corev1.Pod) {
+	if slices.ContainsFunc(pod.Spec.Volumes, func(volume corev1.Volume) bool {
+		return volume.Name == "pg-wal"
+	}) {
+		return
+	}
+
+	pod.Spec.Volumes = append(pod.Spec.Volumes, createPgWalVolume(pod.Name))
+
+	addMount := func(containers []corev1.Container) {
+		for idx := range containers {
+			if slices.ContainsFunc(containers[idx].VolumeMounts, func(mount corev1.VolumeMount) bool {
+				return mount.Name == "pgdata"
+			}) {
+				containers[idx].VolumeMounts = append(containers[idx].VolumeMounts, createPgWalVolumeMount())
+			}
+		}
+	}
+	addMount(pod.Spec.InitContainers)
+	addMount(pod.Spec.Containers)
+}
+
 func createVolumesAndVolumeMountsForSQLRefs(
 	folder postInitFolder,
 	refs *apiv1.SQLRefs,
@@ -249,12 +285,7 @@ func CreatePostgresVolumeMounts(cluster apiv1.Cluster) []corev1.VolumeMount {
 	}
 
 	if cluster.ShouldCreateWalArchiveVolume() {
-		volumeMounts = append(volumeMounts,
-			corev1.VolumeMount{
-				Name:      "pg-wal",
-				MountPath: PgWalVolumePath,
-			},
-		)
+		volumeMounts = append(volumeMounts, createPgWalVolumeMount())
 	}
 
 	if cluster.ShouldCreateProjectedVolume() {
@@ -524,6 +524,24 @@ var _ = Describe("createEphemeralVolume", func() {
 	})
 })
 
+var _ = Describe("AddPgWalVolume", func() {
+	It("attaches the WAL PVC to the instance Pod", func(ctx SpecContext) {
+		cluster := apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example"},
+		}
+		pod, err := NewInstance(ctx, cluster, 1, true)
+		Expect(err).ToNot(HaveOccurred())
+
+		AddPgWalVolume(pod)
+		AddPgWalVolume(pod)
+
+		Expect(pod.Spec.Volumes).To(ContainElement(createPgWalVolume("cluster-example-1")))
+		Expect(pod.Spec.Volumes).To(HaveLen(len(createPostgresVolumes(&cluster, pod.Name)) + 1))
+		Expect(pod.Spec.InitContainers[0].VolumeMounts).To(ContainElement(createPgWalVolumeMount()))
+		Expect(pod.Spec.Containers[0].VolumeMounts).To(ContainElement(createPgWalVolumeMount()))
+	})
+})
+
 var _ = Describe("ImageVolume Extensions", func() {
 	var cluster apiv1.Cluster
 
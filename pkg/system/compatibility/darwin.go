@@ -25,3 +25,8 @@ package compatibility
 func SetCoredumpFilter(_ string) error {
 	return nil
 }
+
+// GetFilesystemUsage for darwin compatibility
+func GetFilesystemUsage(_ string) (FilesystemUsage, error) {
+	return FilesystemUsage{}, ErrFilesystemUsageNotSupported
+}
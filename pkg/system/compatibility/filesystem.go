@@ -0,0 +1,44 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package compatibility
+
+import "errors"
+
+// ErrFilesystemUsageNotSupported is returned when the usage of a filesystem
+// can't be computed on the current operating system
+var ErrFilesystemUsageNotSupported = errors.New("filesystem usage is not supported on this operating system")
+
+// FilesystemUsage is the usage of a filesystem
+type FilesystemUsage struct {
+	// The size of the filesystem, in bytes
+	TotalBytes int64
+
+	// The space available to unprivileged users, in bytes
+	AvailableBytes int64
+
+	// The used space, in bytes
+	UsedBytes int64
+
+	// The number of inodes of the filesystem
+	TotalInodes int64
+
+	// The number of free inodes
+	FreeInodes int64
+}
@@ -24,6 +24,7 @@ package compatibility
 
 import (
 	"os"
+	"syscall"
 )
 
 // SetCoredumpFilter set the value of /proc/self/coredump_filter
@@ -31,3 +32,22 @@ func SetCoredumpFilter(coredumpFilter string) error {
 	coredumpFilterFile := "/proc/self/coredump_filter"
 	return os.WriteFile(coredumpFilterFile, []byte(coredumpFilter), 0o600)
 }
+
+// GetFilesystemUsage returns the usage of the filesystem containing the
+// passed path
+func GetFilesystemUsage(path string) (FilesystemUsage, error) {
+	var stat syscall.Statfs_t
+	if err := syscall.Statfs(path, &stat); err != nil {
+		return FilesystemUsage{}, err
+	}
+
+	// The type of the block size depends on the architecture
+	blockSize := uint64(stat.Bsize) //nolint:gosec
+	return FilesystemUsage{
+		TotalBytes:     int64(stat.Blocks * blockSize),                //nolint:gosec
+		AvailableBytes: int64(stat.Bavail * blockSize),                //nolint:gosec
+		UsedBytes:      int64((stat.Blocks - stat.Bfree) * blockSize), //nolint:gosec
+		TotalInodes:    int64(stat.Files),                             //nolint:gosec
+		FreeInodes:     int64(stat.Ffree),                             //nolint:gosec
+	}, nil
+}
@@ -26,3 +26,8 @@ package compatibility
 func SetCoredumpFilter(_ string) error {
 	return nil
 }
+
+// GetFilesystemUsage for Windows compatibility
+func GetFilesystemUsage(_ string) (FilesystemUsage, error) {
+	return FilesystemUsage{}, ErrFilesystemUsageNotSupported
+}
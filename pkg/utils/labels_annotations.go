@@ -119,6 +119,10 @@ const (
 	// scheduled backup if a backup is created by a scheduled backup
 	ParentScheduledBackupLabelName = MetadataNamespace + "/scheduled-backup"
 
+	// ScheduledBackupTierLabelName is the name of the label applied to the
+	// backups taken by a tier of a scheduled backup
+	ScheduledBackupTierLabelName = MetadataNamespace + "/scheduled-backup-tier"
+
 	// WatchedLabelName the name of the label which tells if a resource change will be automatically reloaded by instance
 	// or not, use for Secrets or ConfigMaps
 	WatchedLabelName = MetadataNamespace + "/reload"
@@ -212,10 +216,18 @@ const (
 	// latest reload time trigger by external
 	ClusterReloadAnnotationName = MetadataNamespace + "/reloadedAt"
 
+	// CARotationRequestedAnnotationName is the name of the annotation containing
+	// the latest time a rotation of the CA managed by the operator has been requested
+	CARotationRequestedAnnotationName = MetadataNamespace + "/caRotationRequestedAt"
+
 	// PVCStatusAnnotationName is the name of the annotation that shows the current status of the PVC.
 	// The status can be "initializing", "ready" or "detached"
 	PVCStatusAnnotationName = MetadataNamespace + "/pvcStatus"
 
+	// PVCLastAutoResizeAnnotationName is the name of the annotation containing
+	// the time, in RFC3339 format, of the latest automatic expansion of the PVC
+	PVCLastAutoResizeAnnotationName = MetadataNamespace + "/lastAutoResize"
+
 	// LegacyBackupAnnotationName is the name of the annotation represents whether taking a backup without passing
 	// the name argument even on barman version 3.3.0+. The value can be "true" or "false"
 	LegacyBackupAnnotationName = MetadataNamespace + "/forceLegacyBackup"
@@ -224,6 +236,10 @@ const (
 	// PostgreSQL cluster
 	HibernationAnnotationName = MetadataNamespace + "/hibernation"
 
+	// StorageMigrationAnnotationName is the name of the annotation controlling
+	// the migration of the instances to PVCs of a new storage class
+	StorageMigrationAnnotationName = MetadataNamespace + "/storageMigration"
+
 	// PoolerSpecHashAnnotationName is the name of the annotation added to the deployment to tell
 	// the hash of the Pooler Specification
 	PoolerSpecHashAnnotationName = MetadataNamespace + "/poolerSpecHash"
@@ -515,6 +531,11 @@ func IsReconciliationDisabled(object *metav1.ObjectMeta) bool {
 	return object.Annotations[ReconciliationLoopAnnotationName] == string(annotationStatusDisabled)
 }
 
+// IsStorageMigrationDisabled checks if the storage migration has been paused on the given resource
+func IsStorageMigrationDisabled(object *metav1.ObjectMeta) bool {
+	return object.Annotations[StorageMigrationAnnotationName] == string(annotationStatusDisabled)
+}
+
 // IsPodSpecReconciliationDisabled checks if the pod spec reconciliation is disabled
 func IsPodSpecReconciliationDisabled(object *metav1.ObjectMeta) bool {
 	if object.Annotations == nil {
@@ -0,0 +1,50 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package utils
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// GetRetentionThreshold parses a period in the `XXu` format, where `u`
+// is in `[dwm]`, and returns the time preceding `now` by that period
+func GetRetentionThreshold(period string, now time.Time) (time.Time, error) {
+	if len(period) < 2 {
+		return time.Time{}, fmt.Errorf("invalid retention period: %q", period)
+	}
+
+	value, err := strconv.Atoi(period[:len(period)-1])
+	if err != nil || value <= 0 {
+		return time.Time{}, fmt.Errorf("invalid retention period: %q", period)
+	}
+
+	switch period[len(period)-1] {
+	case 'd':
+		return now.AddDate(0, 0, -value), nil
+	case 'w':
+		return now.AddDate(0, 0, -7*value), nil
+	case 'm':
+		return now.AddDate(0, -value, 0), nil
+	default:
+		return time.Time{}, fmt.Errorf("invalid retention period: %q", period)
+	}
+}
@@ -0,0 +1,44 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package utils
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Retention threshold", func() {
+	now := time.Date(2025, time.March, 15, 12, 0, 0, 0, time.UTC)
+
+	It("parses days, weeks and months", func() {
+		Expect(GetRetentionThreshold("3d", now)).To(Equal(now.AddDate(0, 0, -3)))
+		Expect(GetRetentionThreshold("2w", now)).To(Equal(now.AddDate(0, 0, -14)))
+		Expect(GetRetentionThreshold("1m", now)).To(Equal(now.AddDate(0, -1, 0)))
+	})
+
+	It("rejects invalid periods", func() {
+		for _, period := range []string{"", "d", "0d", "3y", "xd"} {
+			_, err := GetRetentionThreshold(period, now)
+			Expect(err).To(HaveOccurred(), period)
+		}
+	})
+})
@@ -33,6 +33,9 @@ const (
 
 	// DefaultPgbouncerImage is the name of the pgbouncer image used by default
 	DefaultPgbouncerImage = "ghcr.io/cloudnative-pg/pgbouncer:1.25.1"
+
+	// DefaultPgCatImage is the name of the pgcat image used by default
+	DefaultPgCatImage = "ghcr.io/postgresml/pgcat:v1.2.0"
 )
 
 // BuildInfo is a struct containing all the info about the build